@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"azure-network-analyzer/pkg/analyzer"
@@ -16,24 +19,52 @@ import (
 )
 
 var (
-	subscriptionID       string
-	resourceGroup        string
-	outputFormat         string
-	outputPath           string
-	includeViz           bool
-	vizFormat            string
-	dryRun               bool
-	excludePrivateLinks  bool
+	subscriptionIDs     []string
+	managementGroup     string
+	resourceGroups      []string
+	allResourceGroups   bool
+	outputFormat        string
+	outputPath          string
+	includeViz          bool
+	vizFormat           string
+	dryRun              bool
+	excludePrivateLinks bool
+	concurrency         int
+	tagFilters          []string
+	groupByTag          string
+	strict              bool
+	timeout             time.Duration
+	requestTimeout      time.Duration
+	maxRetries          int
+	authMode            string
+	tenantID            string
+	clientID            string
+	clientCertificate   string
+	federatedTokenFile  string
+	cloudName           string
+	recordDir           string
+	replayDir           string
+	collectorBackend    string
+	useCache            bool
+	cacheDir            string
+	cacheTTL            time.Duration
+	refreshCache        bool
+	subnetWarning       float64
+	subnetCritical      float64
 )
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze",
-	Short: "Analyze network topology for a resource group",
-	Long: `Collect and analyze Azure network resources in a specified resource group.
+	Short: "Analyze network topology for one or more resource groups",
+	Long: `Collect and analyze Azure network resources in the specified resource groups,
+or across the whole subscription with --all-resource-groups.
+
+Several subscriptions can be analyzed together by repeating --subscription or
+by naming a management group; the results are merged into a single report.
 
 This command will:
-1. Connect to Azure using DefaultAzureCredential
-2. Collect all network resources from the specified resource group
+1. Connect to Azure using the credential selected by --auth
+2. Collect all network resources from the requested scope
 3. Analyze the topology and identify security findings
 4. Generate reports in the specified format
 5. Optionally create network topology visualizations`,
@@ -43,184 +74,206 @@ This command will:
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
 
-	analyzeCmd.Flags().StringVarP(&subscriptionID, "subscription", "s", "", "Azure subscription ID (required)")
-	analyzeCmd.Flags().StringVarP(&resourceGroup, "resource-group", "g", "", "Resource group name (required)")
+	analyzeCmd.Flags().StringSliceVarP(&subscriptionIDs, "subscription", "s", nil, "Azure subscription ID (repeatable)")
+	analyzeCmd.Flags().StringVar(&managementGroup, "management-group", "", "Analyze every subscription beneath this management group")
+	analyzeCmd.Flags().StringSliceVarP(&resourceGroups, "resource-group", "g", nil, "Resource group name (repeatable)")
+	analyzeCmd.Flags().BoolVar(&allResourceGroups, "all-resource-groups", false, "Analyze every resource group in the subscription")
 	analyzeCmd.Flags().StringVarP(&outputFormat, "output-format", "o", "markdown", "Output format (json|markdown|html)")
 	analyzeCmd.Flags().StringVarP(&outputPath, "output", "f", "", "Output file path (defaults to stdout)")
 	analyzeCmd.Flags().BoolVar(&includeViz, "visualize", true, "Generate network topology diagram")
 	analyzeCmd.Flags().StringVar(&vizFormat, "viz-format", "svg", "Visualization format (svg|png|pdf|jpg|dot)")
 	analyzeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Use mock data instead of connecting to Azure (for testing)")
 	analyzeCmd.Flags().BoolVar(&excludePrivateLinks, "exclude-private-links", false, "Exclude private endpoints from visualization (reduces clutter for large topologies)")
-
-	analyzeCmd.MarkFlagRequired("subscription")
-	analyzeCmd.MarkFlagRequired("resource-group")
+	analyzeCmd.Flags().IntVar(&concurrency, "concurrency", azure.DefaultConcurrency, "Maximum number of parallel Azure API requests")
+	analyzeCmd.Flags().StringSliceVar(&tagFilters, "tag", nil, "Only analyze resources with this tag, as key=value (repeatable; all must match)")
+	analyzeCmd.Flags().StringVar(&groupByTag, "group-by-tag", "", "Group resources by this tag key in Markdown and HTML reports")
+	analyzeCmd.Flags().BoolVar(&strict, "strict", false, "Abort when any resource type cannot be collected instead of reporting it as missing")
+	analyzeCmd.Flags().DurationVar(&timeout, "timeout", 0, "Deadline for the whole collection, e.g. 30m (0 for none)")
+	analyzeCmd.Flags().DurationVar(&requestTimeout, "request-timeout", azure.DefaultClientOptions().RequestTimeout, "Timeout for each Azure API request attempt")
+	analyzeCmd.Flags().IntVar(&maxRetries, "max-retries", azure.DefaultClientOptions().MaxRetries, "Retries for throttled or failed Azure API requests (honours Retry-After)")
+	analyzeCmd.Flags().StringVar(&authMode, "auth", azure.CredentialDefault, "Credential mode ("+strings.Join(azure.CredentialModes, "|")+")")
+	analyzeCmd.Flags().StringVar(&tenantID, "tenant-id", "", "Microsoft Entra tenant ID (defaults to AZURE_TENANT_ID)")
+	analyzeCmd.Flags().StringVar(&clientID, "client-id", "", "Client ID of the service principal, workload identity or user-assigned managed identity (defaults to AZURE_CLIENT_ID)")
+	analyzeCmd.Flags().StringVar(&clientCertificate, "client-certificate", "", "PEM or PKCS#12 certificate for service principal auth (defaults to AZURE_CLIENT_CERTIFICATE_PATH; the secret is read from AZURE_CLIENT_SECRET)")
+	analyzeCmd.Flags().StringVar(&federatedTokenFile, "federated-token-file", "", "Federated token file for workload identity auth (defaults to AZURE_FEDERATED_TOKEN_FILE)")
+	analyzeCmd.Flags().StringVar(&cloudName, "cloud", "public", "Azure cloud (public|usgovernment|china) or a custom https ARM endpoint")
+	analyzeCmd.Flags().StringVar(&recordDir, "record", "", "Save scrubbed Azure API responses as fixtures in this directory")
+	analyzeCmd.Flags().StringVar(&collectorBackend, "collector", azure.CollectorARM, "How to list resources: arm (per-type API calls) or graph (bulk Azure Resource Graph queries)")
+	analyzeCmd.Flags().StringVar(&replayDir, "replay", "", "Answer Azure API requests from fixtures saved with --record instead of calling Azure")
+	analyzeCmd.Flags().BoolVar(&useCache, "cache", false, "Reuse collected resources cached on disk by earlier runs, and cache new ones")
+	analyzeCmd.Flags().StringVar(&cacheDir, "cache-dir", azure.DefaultCacheDir(), "Directory for cached resources")
+	analyzeCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", azure.DefaultCacheOptions().TTL, "How long cached resources are reused, e.g. 15m")
+	analyzeCmd.Flags().BoolVar(&refreshCache, "refresh", false, "Ignore cached resources and collect fresh ones, updating the cache")
+	analyzeCmd.Flags().Float64Var(&subnetWarning, "subnet-warning-percent", analyzer.DefaultAnalyzeOptions().SubnetWarningPercent, "Raise a Medium finding when a subnet has this share of its usable IPs in use")
+	analyzeCmd.Flags().Float64Var(&subnetCritical, "subnet-critical-percent", analyzer.DefaultAnalyzeOptions().SubnetCriticalPercent, "Raise a High finding when a subnet has this share of its usable IPs in use")
+
+	analyzeCmd.MarkFlagsOneRequired("subscription", "management-group")
+	analyzeCmd.MarkFlagsOneRequired("resource-group", "all-resource-groups")
+	analyzeCmd.MarkFlagsMutuallyExclusive("resource-group", "all-resource-groups")
+	analyzeCmd.MarkFlagsMutuallyExclusive("record", "replay", "dry-run")
+	analyzeCmd.MarkFlagsMutuallyExclusive("cache", "record", "replay", "dry-run")
+	analyzeCmd.MarkFlagsMutuallyExclusive("collector", "dry-run")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	// Cancel in-flight Azure requests on Ctrl-C or when --timeout expires
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	scope := azure.CollectionScope{
+		SubscriptionIDs:   subscriptionIDs,
+		ResourceGroups:    resourceGroups,
+		AllResourceGroups: allResourceGroups,
+	}
+
+	tagFilter, err := models.ParseTagFilter(tagFilters)
+	if err != nil {
+		return err
+	}
+	if collectorBackend != azure.CollectorARM && collectorBackend != azure.CollectorGraph {
+		return fmt.Errorf("invalid --collector %q (expected %s or %s)", collectorBackend, azure.CollectorARM, azure.CollectorGraph)
+	}
+	if refreshCache && !useCache {
+		return fmt.Errorf("--refresh requires --cache")
+	}
+	if subnetWarning <= 0 || subnetWarning > subnetCritical || subnetCritical > 100 {
+		return fmt.Errorf("invalid subnet thresholds: need 0 < --subnet-warning-percent (%g) <= --subnet-critical-percent (%g) <= 100", subnetWarning, subnetCritical)
+	}
 
 	fmt.Println("Azure Network Topology Analyzer")
 	fmt.Println("================================")
-	fmt.Printf("Subscription: %s\n", subscriptionID)
-	fmt.Printf("Resource Group: %s\n", resourceGroup)
+	if managementGroup != "" {
+		fmt.Printf("Management Group: %s\n", managementGroup)
+	}
+	if len(subscriptionIDs) > 0 {
+		fmt.Printf("Subscriptions: %s\n", strings.Join(subscriptionIDs, ", "))
+	}
+	fmt.Printf("Resource Groups: %s\n", scope.Description())
 	fmt.Printf("Output Format: %s\n", outputFormat)
+	if len(tagFilter) > 0 {
+		fmt.Printf("Tag Filter: %s\n", tagFilter)
+	}
 	if dryRun {
 		fmt.Println("Mode: DRY-RUN (using mock data)")
 	}
+	if collectorBackend == azure.CollectorGraph {
+		fmt.Println("Collector: Azure Resource Graph")
+	}
+	if replayDir != "" {
+		fmt.Printf("Mode: REPLAY (using responses recorded in %s)\n", replayDir)
+	}
+	if useCache {
+		fmt.Printf("Cache: %s (TTL %s", cacheDir, cacheTTL)
+		if refreshCache {
+			fmt.Print(", refreshing")
+		}
+		fmt.Println(")")
+	}
 	fmt.Println()
 
-	var topology *models.NetworkTopology
-
+	// 1. Select the collector - mock data for dry runs, live Azure otherwise
+	var collector azure.Collector
+	var client *azure.AzureClient
+	var cache *azure.CachingCollector
 	if dryRun {
-		// Use mock data for testing
-		fmt.Println("Generating mock network topology...")
-		topology = azure.GenerateMockTopology(subscriptionID, resourceGroup)
-		fmt.Printf("  - Generated %d VNets\n", len(topology.VirtualNetworks))
-		fmt.Printf("  - Generated %d NSGs\n", len(topology.NSGs))
-		fmt.Printf("  - Generated %d Private Endpoints\n", len(topology.PrivateEndpoints))
-		fmt.Printf("  - Generated %d Private DNS Zones\n", len(topology.PrivateDNSZones))
-		fmt.Printf("  - Generated %d Route Tables\n", len(topology.RouteTables))
-		fmt.Printf("  - Generated %d NAT Gateways\n", len(topology.NATGateways))
-		fmt.Printf("  - Generated %d VPN Gateways\n", len(topology.VPNGateways))
-		fmt.Printf("  - Generated %d ExpressRoute Circuits\n", len(topology.ERCircuits))
-		fmt.Printf("  - Generated %d Load Balancers\n", len(topology.LoadBalancers))
-		fmt.Printf("  - Generated %d Application Gateways\n", len(topology.AppGateways))
-		fmt.Printf("  - Generated %d Azure Firewalls\n", len(topology.AzureFirewalls))
-		if topology.NetworkWatcher != nil {
-			fmt.Printf("  - Generated Network Watcher insights\n")
+		if managementGroup != "" {
+			return fmt.Errorf("--management-group cannot be used with --dry-run")
 		}
+		fmt.Println("Using mock network topology...")
+		collector = azure.NewMockAzureClient(subscriptionIDs[0])
 	} else {
-		// 1. Initialize Azure client
 		fmt.Println("Initializing Azure client...")
-		client, err := azure.NewAzureClient(subscriptionID)
-		if err != nil {
-			return fmt.Errorf("failed to create Azure client: %w", err)
-		}
-
-		// 2. Collect all network resources
-		fmt.Println("Collecting network resources...")
-		topology = &models.NetworkTopology{
-			SubscriptionID: subscriptionID,
-			ResourceGroup:  resourceGroup,
-			Timestamp:      time.Now(),
-		}
-
-		// Collect VNets
-		fmt.Println("  - Collecting Virtual Networks...")
-		vnets, err := client.GetVirtualNetworks(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get virtual networks: %w", err)
-		}
-		topology.VirtualNetworks = vnets
-		fmt.Printf("    Found %d VNets\n", len(vnets))
-
-		// Collect NSGs
-		fmt.Println("  - Collecting Network Security Groups...")
-		nsgs, err := client.GetNetworkSecurityGroups(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get NSGs: %w", err)
-		}
-		topology.NSGs = nsgs
-		fmt.Printf("    Found %d NSGs\n", len(nsgs))
-
-		// Collect Private Endpoints
-		fmt.Println("  - Collecting Private Endpoints...")
-		privateEndpoints, err := client.GetPrivateEndpoints(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get private endpoints: %w", err)
-		}
-		topology.PrivateEndpoints = privateEndpoints
-		fmt.Printf("    Found %d Private Endpoints\n", len(privateEndpoints))
-
-		// Collect Private DNS Zones
-		fmt.Println("  - Collecting Private DNS Zones...")
-		dnsZones, err := client.GetPrivateDNSZones(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get private DNS zones: %w", err)
-		}
-		topology.PrivateDNSZones = dnsZones
-		fmt.Printf("    Found %d Private DNS Zones\n", len(dnsZones))
-
-		// Collect Route Tables
-		fmt.Println("  - Collecting Route Tables...")
-		routeTables, err := client.GetRouteTables(ctx, resourceGroup)
+		clientOpts := azure.DefaultClientOptions()
+		clientOpts.MaxRetries = maxRetries
+		clientOpts.RequestTimeout = requestTimeout
+		clientOpts.Cloud, err = azure.ParseCloud(cloudName)
 		if err != nil {
-			return fmt.Errorf("failed to get route tables: %w", err)
-		}
-		topology.RouteTables = routeTables
-		fmt.Printf("    Found %d Route Tables\n", len(routeTables))
-
-		// Collect NAT Gateways
-		fmt.Println("  - Collecting NAT Gateways...")
-		natGateways, err := client.GetNATGateways(ctx, resourceGroup)
+			return err
+		}
+		clientOpts.Authentication = azure.CredentialOptions{
+			Mode:               authMode,
+			TenantID:           tenantID,
+			ClientID:           clientID,
+			CertificatePath:    clientCertificate,
+			FederatedTokenFile: federatedTokenFile,
+		}
+		clientOpts.RecordDir = recordDir
+		clientOpts.ReplayDir = replayDir
+		client, err = azure.NewAzureClientWithOptions(clientOpts, subscriptionIDs...)
 		if err != nil {
-			return fmt.Errorf("failed to get NAT gateways: %w", err)
-		}
-		topology.NATGateways = natGateways
-		fmt.Printf("    Found %d NAT Gateways\n", len(natGateways))
-
-		// Collect VPN Gateways
-		fmt.Println("  - Collecting VPN Gateways...")
-		vpnGateways, err := client.GetVPNGateways(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get VPN gateways: %w", err)
-		}
-		topology.VPNGateways = vpnGateways
-		fmt.Printf("    Found %d VPN Gateways\n", len(vpnGateways))
-
-		// Collect ExpressRoute Circuits
-		fmt.Println("  - Collecting ExpressRoute Circuits...")
-		erCircuits, err := client.GetExpressRouteCircuits(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get ExpressRoute circuits: %w", err)
+			return fmt.Errorf("failed to create Azure client: %w", err)
 		}
-		topology.ERCircuits = erCircuits
-		fmt.Printf("    Found %d ExpressRoute Circuits\n", len(erCircuits))
-
-		// Collect Load Balancers
-		fmt.Println("  - Collecting Load Balancers...")
-		loadBalancers, err := client.GetLoadBalancers(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get load balancers: %w", err)
+		client.SetConcurrency(concurrency)
+		if collectorBackend == azure.CollectorGraph {
+			client.UseResourceGraph()
 		}
-		topology.LoadBalancers = loadBalancers
-		fmt.Printf("    Found %d Load Balancers\n", len(loadBalancers))
 
-		// Collect Application Gateways
-		fmt.Println("  - Collecting Application Gateways...")
-		appGateways, err := client.GetApplicationGateways(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get application gateways: %w", err)
+		if managementGroup != "" {
+			fmt.Printf("Resolving subscriptions in management group %s...\n", managementGroup)
+			if err := client.AddManagementGroup(ctx, managementGroup); err != nil {
+				return err
+			}
+			scope.SubscriptionIDs = client.SubscriptionIDs()
+			fmt.Printf("  - Found %d subscriptions\n", len(scope.SubscriptionIDs))
+		}
+		collector = client
+		if useCache {
+			cache = azure.NewCachingCollector(client, scope.SubscriptionIDs[0], azure.CacheOptions{
+				Dir:     cacheDir,
+				TTL:     cacheTTL,
+				Refresh: refreshCache,
+			})
+			collector = cache
 		}
-		topology.AppGateways = appGateways
-		fmt.Printf("    Found %d Application Gateways\n", len(appGateways))
+	}
 
-		// Collect Azure Firewalls
-		fmt.Println("  - Collecting Azure Firewalls...")
-		azureFirewalls, err := client.GetAzureFirewalls(ctx, resourceGroup)
-		if err != nil {
-			return fmt.Errorf("failed to get azure firewalls: %w", err)
+	// 2. Collect all network resources
+	fmt.Println("Collecting network resources...")
+	collectOpts := azure.CollectOptions{
+		Concurrency: concurrency,
+		Strict:      strict,
+	}
+	topology, err := azure.CollectTopologyWithOptions(ctx, collector, scope, collectOpts)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			return fmt.Errorf("collection did not finish within --timeout %s: %w", timeout, err)
 		}
-		topology.AzureFirewalls = azureFirewalls
-		fmt.Printf("    Found %d Azure Firewalls\n", len(azureFirewalls))
-
-		// Collect Network Watcher insights
-		fmt.Println("  - Collecting Network Watcher insights...")
-		nwInsights, err := client.GetNetworkWatcherInsights(ctx, resourceGroup)
-		if err != nil {
-			fmt.Printf("    Warning: Could not get Network Watcher insights: %v\n", err)
-		} else {
-			topology.NetworkWatcher = nwInsights
-			fmt.Println("    Network Watcher insights collected")
+		return err
+	}
+	if cache != nil {
+		if cachedAt, ok := cache.ServedFromCache(); ok {
+			topology.CachedAt = &cachedAt
+			fmt.Printf("  - Served from cache at %s (use --refresh to collect fresh data)\n", cachedAt.Format("2006-01-02 15:04:05 MST"))
 		}
 	}
+	displayCollectionResults(topology)
+	if client != nil {
+		displayAPICallStats(client.APICallStats())
+	}
 
 	fmt.Println()
 	fmt.Printf("Collection complete! Total resources: %d\n", countResources(topology))
 
+	// Restrict analysis, reports and diagrams to the tagged resources
+	if len(tagFilter) > 0 {
+		topology = topology.FilterByTags(tagFilter)
+		fmt.Printf("Resources matching tag filter: %d\n", countResources(topology))
+	}
+
+	// Name output files after the scope so multi-group reports don't collide
+	fileScope := outputFileScope()
+
 	// 3. Analyze topology
 	fmt.Println("\nAnalyzing topology...")
-	analysisReport := analyzer.Analyze(topology)
+	analysisReport := analyzer.AnalyzeWithOptions(topology, analyzer.AnalyzeOptions{
+		SubnetWarningPercent:  subnetWarning,
+		SubnetCriticalPercent: subnetCritical,
+	})
 
 	// Display analysis results
 	displayAnalysisResults(analysisReport)
@@ -229,6 +282,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	fmt.Println("Generating reports...")
 	var reportContent []byte
 	var reportExt string
+	reportOpts := reporter.ReportOptions{GroupByTag: groupByTag}
 
 	switch outputFormat {
 	case "json":
@@ -241,12 +295,12 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		reportExt = ".json"
 	case "markdown":
 		fmt.Println("  Generating Markdown report...")
-		content := reporter.GenerateMarkdown(topology, analysisReport)
+		content := reporter.GenerateMarkdownWithOptions(topology, analysisReport, reportOpts)
 		reportContent = []byte(content)
 		reportExt = ".md"
 	case "html":
 		fmt.Println("  Generating HTML report...")
-		content := reporter.GenerateHTML(topology, analysisReport)
+		content := reporter.GenerateHTMLWithOptions(topology, analysisReport, reportOpts)
 		reportContent = []byte(content)
 		reportExt = ".html"
 	default:
@@ -263,7 +317,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	} else {
 		// Auto-generate filename
 		timestamp := time.Now().Format("20060102-150405")
-		filename := fmt.Sprintf("network-report-%s-%s%s", resourceGroup, timestamp, reportExt)
+		filename := fmt.Sprintf("network-report-%s-%s%s", fileScope, timestamp, reportExt)
 		err := os.WriteFile(filename, reportContent, 0644)
 		if err != nil {
 			return fmt.Errorf("failed to write report: %w", err)
@@ -303,9 +357,9 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 				fmt.Printf("  Warning: Could not render SVG: %v\n", err)
 				fmt.Println("  Falling back to DOT file...")
 				vizContent = []byte(dotContent)
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", fileScope, timestamp))
 			} else {
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.svg", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.svg", fileScope, timestamp))
 			}
 		case "png":
 			fmt.Println("  Rendering PNG...")
@@ -314,9 +368,9 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 				fmt.Printf("  Warning: Could not render PNG: %v\n", err)
 				fmt.Println("  Falling back to DOT file...")
 				vizContent = []byte(dotContent)
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", fileScope, timestamp))
 			} else {
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.png", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.png", fileScope, timestamp))
 			}
 		case "pdf":
 			fmt.Println("  Rendering PDF...")
@@ -325,9 +379,9 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 				fmt.Printf("  Warning: Could not render PDF: %v\n", err)
 				fmt.Println("  Falling back to DOT file...")
 				vizContent = []byte(dotContent)
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", fileScope, timestamp))
 			} else {
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.pdf", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.pdf", fileScope, timestamp))
 			}
 		case "jpg", "jpeg":
 			fmt.Println("  Rendering JPEG...")
@@ -336,14 +390,14 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 				fmt.Printf("  Warning: Could not render JPEG: %v\n", err)
 				fmt.Println("  Falling back to DOT file...")
 				vizContent = []byte(dotContent)
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", fileScope, timestamp))
 			} else {
-				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.jpg", resourceGroup, timestamp))
+				vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.jpg", fileScope, timestamp))
 			}
 		case "dot":
 			fmt.Println("  Generating DOT file...")
 			vizContent = []byte(dotContent)
-			vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", resourceGroup, timestamp))
+			vizFilename = filepath.Join(outputDir, fmt.Sprintf("network-topology-%s-%s.dot", fileScope, timestamp))
 		default:
 			return fmt.Errorf("unsupported visualization format: %s", vizFormat)
 		}
@@ -359,19 +413,96 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// outputFileScope returns the scope component of generated report and diagram file names
+func outputFileScope() string {
+	if allResourceGroups {
+		return "all-resource-groups"
+	}
+	return strings.Join(resourceGroups, "-")
+}
+
+// displayCollectionResults prints the number of resources found per type
+func displayCollectionResults(topology *models.NetworkTopology) {
+	fmt.Printf("  - Found %d VNets\n", len(topology.VirtualNetworks))
+	fmt.Printf("  - Found %d NSGs\n", len(topology.NSGs))
+	fmt.Printf("  - Found %d Application Security Groups\n", len(topology.ASGs))
+	fmt.Printf("  - Found %d Private Endpoints\n", len(topology.PrivateEndpoints))
+	fmt.Printf("  - Found %d Private Link Services\n", len(topology.PrivateLinkServices))
+	fmt.Printf("  - Found %d Network Interfaces\n", len(topology.NetworkInterfaces))
+	fmt.Printf("  - Found %d Public IP Addresses\n", len(topology.PublicIPAddresses))
+	fmt.Printf("  - Found %d Private DNS Zones\n", len(topology.PrivateDNSZones))
+	fmt.Printf("  - Found %d Route Tables\n", len(topology.RouteTables))
+	fmt.Printf("  - Found %d NAT Gateways\n", len(topology.NATGateways))
+	fmt.Printf("  - Found %d VPN Gateways\n", len(topology.VPNGateways))
+	fmt.Printf("  - Found %d Local Network Gateways\n", len(topology.LocalNetworkGateways))
+	fmt.Printf("  - Found %d ExpressRoute Circuits\n", len(topology.ERCircuits))
+	fmt.Printf("  - Found %d Load Balancers\n", len(topology.LoadBalancers))
+	fmt.Printf("  - Found %d Application Gateways\n", len(topology.AppGateways))
+	fmt.Printf("  - Found %d Azure Firewalls\n", len(topology.AzureFirewalls))
+	fmt.Printf("  - Found %d Firewall Policies\n", len(topology.FirewallPolicies))
+	fmt.Printf("  - Found %d Bastion Hosts\n", len(topology.BastionHosts))
+	fmt.Printf("  - Found %d Virtual WANs\n", len(topology.VirtualWANs))
+	fmt.Printf("  - Found %d Virtual Hubs\n", len(topology.VirtualHubs))
+	if nw := topology.NetworkWatcher; nw != nil {
+		fmt.Printf("  - Found %d Network Watchers (%d flow logs, %d connection monitors)\n",
+			len(nw.Watchers), len(nw.FlowLogs), len(nw.ConnectionMonitors))
+		if len(nw.MissingRegions) > 0 {
+			fmt.Printf("  - No Network Watcher in: %s\n", strings.Join(nw.MissingRegions, ", "))
+		}
+	} else {
+		fmt.Println("  - Network Watcher insights not available")
+	}
+
+	if len(topology.CollectionErrors) > 0 {
+		fmt.Printf("\nWARNING: %d resource collections failed; the report will be incomplete:\n", len(topology.CollectionErrors))
+		for _, e := range topology.CollectionErrors {
+			fmt.Printf("  - %s (%s): %s - %s\n", e.ResourceType, e.Scope, e.Class, e.Message)
+		}
+	}
+	if len(topology.IntegrityWarnings) > 0 {
+		fmt.Printf("\nWARNING: %d subnet associations are inconsistent:\n", len(topology.IntegrityWarnings))
+		for _, w := range topology.IntegrityWarnings {
+			fmt.Printf("  - %s\n", w.Message)
+		}
+	}
+}
+
+// displayAPICallStats summarizes the Azure API calls made during collection
+func displayAPICallStats(stats []azure.APICallStats) {
+	var calls, retries, throttled int
+	for _, s := range stats {
+		calls += s.Calls
+		retries += s.Retries
+		throttled += s.Throttled
+	}
+	fmt.Printf("\nAzure API calls: %d (%d retries, %d throttled)\n", calls, retries, throttled)
+	for _, s := range stats {
+		fmt.Printf("  - %-45s %4d calls, %3d retries, %3d throttled\n", s.ResourceType, s.Calls, s.Retries, s.Throttled)
+	}
+}
+
 // countResources returns the total number of resources in the topology
 func countResources(topology *models.NetworkTopology) int {
 	count := len(topology.VirtualNetworks)
 	count += len(topology.NSGs)
+	count += len(topology.ASGs)
 	count += len(topology.PrivateEndpoints)
+	count += len(topology.PrivateLinkServices)
+	count += len(topology.NetworkInterfaces)
+	count += len(topology.PublicIPAddresses)
 	count += len(topology.PrivateDNSZones)
 	count += len(topology.RouteTables)
 	count += len(topology.NATGateways)
 	count += len(topology.VPNGateways)
+	count += len(topology.LocalNetworkGateways)
 	count += len(topology.ERCircuits)
 	count += len(topology.LoadBalancers)
 	count += len(topology.AppGateways)
 	count += len(topology.AzureFirewalls)
+	count += len(topology.FirewallPolicies)
+	count += len(topology.BastionHosts)
+	count += len(topology.VirtualWANs)
+	count += len(topology.VirtualHubs)
 	return count
 }
 
@@ -393,15 +524,24 @@ func displayAnalysisResults(report *analyzer.AnalysisReport) {
 	fmt.Printf("Subnets: %d\n", report.Summary.TotalSubnets)
 	fmt.Printf("Network Security Groups: %d\n", report.Summary.TotalNSGs)
 	fmt.Printf("Security Rules: %d\n", report.Summary.TotalSecurityRules)
+	fmt.Printf("Application Security Groups: %d\n", report.Summary.TotalASGs)
 	fmt.Printf("Route Tables: %d\n", report.Summary.TotalRouteTables)
 	fmt.Printf("Routes: %d\n", report.Summary.TotalRoutes)
 	fmt.Printf("Private Endpoints: %d\n", report.Summary.TotalPrivateEndpoints)
+	fmt.Printf("Private Link Services: %d\n", report.Summary.TotalPrivateLinkServices)
 	fmt.Printf("NAT Gateways: %d\n", report.Summary.TotalNATGateways)
 	fmt.Printf("VPN Gateways: %d\n", report.Summary.TotalVPNGateways)
+	fmt.Printf("Local Network Gateways: %d\n", report.Summary.TotalLocalNetworkGateways)
 	fmt.Printf("ExpressRoute Circuits: %d\n", report.Summary.TotalERCircuits)
 	fmt.Printf("Load Balancers: %d\n", report.Summary.TotalLoadBalancers)
 	fmt.Printf("Application Gateways: %d\n", report.Summary.TotalAppGateways)
 	fmt.Printf("Azure Firewalls: %d\n", report.Summary.TotalAzureFirewalls)
+	fmt.Printf("Firewall Policies: %d\n", report.Summary.TotalFirewallPolicies)
+	fmt.Printf("Bastion Hosts: %d\n", report.Summary.TotalBastionHosts)
+	fmt.Printf("Virtual WANs: %d\n", report.Summary.TotalVirtualWANs)
+	fmt.Printf("Virtual Hubs: %d (%d VNet connections)\n", report.Summary.TotalVirtualHubs, report.Summary.TotalHubConnections)
+	fmt.Printf("Network Interfaces: %d\n", report.Summary.TotalNetworkInterfaces)
+	fmt.Printf("Public IP Addresses: %d\n", report.Summary.TotalPublicIPs)
 	if len(report.Summary.TotalIPAddressSpace) > 0 {
 		fmt.Printf("Address Spaces: %v\n", report.Summary.TotalIPAddressSpace)
 	}
@@ -487,7 +627,8 @@ func displayAnalysisResults(report *analyzer.AnalysisReport) {
 	hasOrphaned := len(report.OrphanedResources.UnattachedNSGs) > 0 ||
 		len(report.OrphanedResources.UnusedRouteTables) > 0 ||
 		len(report.OrphanedResources.UnusedNATGateways) > 0 ||
-		len(report.OrphanedResources.IsolatedSubnets) > 0
+		len(report.OrphanedResources.IsolatedSubnets) > 0 ||
+		len(report.OrphanedResources.UnassociatedPublicIPs) > 0
 
 	if hasOrphaned {
 		fmt.Println("\n--- ORPHANED/UNUSED RESOURCES ---")
@@ -503,6 +644,9 @@ func displayAnalysisResults(report *analyzer.AnalysisReport) {
 		if len(report.OrphanedResources.IsolatedSubnets) > 0 {
 			fmt.Printf("Subnets without NSG: %v\n", report.OrphanedResources.IsolatedSubnets)
 		}
+		if len(report.OrphanedResources.UnassociatedPublicIPs) > 0 {
+			fmt.Printf("Unassociated Public IPs: %v\n", report.OrphanedResources.UnassociatedPublicIPs)
+		}
 	}
 
 	// Display recommendations
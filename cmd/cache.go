@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"azure-network-analyzer/pkg/azure"
+	"github.com/spf13/cobra"
+)
+
+var clearCacheDir string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk cache used by analyze --cache",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := azure.ClearCache(clearCacheDir); err != nil {
+			return err
+		}
+		fmt.Printf("Cleared cache %s\n", clearCacheDir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	cacheClearCmd.Flags().StringVar(&clearCacheDir, "cache-dir", azure.DefaultCacheDir(), "Directory for cached resources")
+}
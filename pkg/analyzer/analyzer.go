@@ -1,18 +1,65 @@
 package analyzer
 
 import (
+	"fmt"
+	"strings"
+
 	"azure-network-analyzer/pkg/models"
 )
 
-// Analyze performs comprehensive analysis on the network topology
+// AnalyzeOptions controls the thresholds the analysis applies
+type AnalyzeOptions struct {
+	// SubnetWarningPercent and SubnetCriticalPercent are the shares of a subnet's
+	// usable IPs in use at which a Medium and a High capacity finding are raised.
+	// Zero uses the default threshold.
+	SubnetWarningPercent  float64
+	SubnetCriticalPercent float64
+}
+
+// DefaultAnalyzeOptions returns the options used by Analyze
+func DefaultAnalyzeOptions() AnalyzeOptions {
+	return AnalyzeOptions{
+		SubnetWarningPercent:  80,
+		SubnetCriticalPercent: 95,
+	}
+}
+
+// Analyze performs comprehensive analysis on the network topology using the
+// default options
 func Analyze(topology *models.NetworkTopology) *AnalysisReport {
+	return AnalyzeWithOptions(topology, DefaultAnalyzeOptions())
+}
+
+// withDefaults fills in the zero fields of opts from DefaultAnalyzeOptions
+func (opts AnalyzeOptions) withDefaults() AnalyzeOptions {
+	defaults := DefaultAnalyzeOptions()
+	if opts.SubnetWarningPercent == 0 {
+		opts.SubnetWarningPercent = defaults.SubnetWarningPercent
+	}
+	if opts.SubnetCriticalPercent == 0 {
+		opts.SubnetCriticalPercent = defaults.SubnetCriticalPercent
+	}
+	return opts
+}
+
+// AnalyzeWithOptions performs comprehensive analysis on the network topology,
+// raising subnet capacity findings at the thresholds in opts
+func AnalyzeWithOptions(topology *models.NetworkTopology, opts AnalyzeOptions) *AnalysisReport {
+	opts = opts.withDefaults()
 	report := &AnalysisReport{
 		Summary:           generateSummary(topology),
 		SecurityFindings:  AnalyzeSecurityRisks(topology),
 		OrphanedResources: findOrphanedResources(topology),
+		FlowLogCoverage:   analyzeFlowLogCoverage(topology),
+		BastionCoverage:   analyzeBastionCoverage(topology),
+		HubConnectivity:   analyzeHubConnectivity(topology),
+		SubnetUtilization: analyzeSubnetUtilization(topology, opts),
 		Recommendations:   []string{},
 	}
 
+	// Check subnets against the IP usage thresholds
+	report.SecurityFindings = append(report.SecurityFindings, subnetUtilizationFindings(report.SubnetUtilization, opts)...)
+
 	// Generate high-level recommendations based on findings
 	report.Recommendations = generateRecommendations(report)
 
@@ -22,25 +69,43 @@ func Analyze(topology *models.NetworkTopology) *AnalysisReport {
 // generateSummary creates statistics about the topology
 func generateSummary(topology *models.NetworkTopology) TopologySummary {
 	summary := TopologySummary{
-		TotalVNets:            len(topology.VirtualNetworks),
-		TotalNSGs:             len(topology.NSGs),
-		TotalRouteTables:      len(topology.RouteTables),
-		TotalPrivateEndpoints: len(topology.PrivateEndpoints),
-		TotalPrivateDNSZones:  len(topology.PrivateDNSZones),
-		TotalNATGateways:      len(topology.NATGateways),
-		TotalVPNGateways:      len(topology.VPNGateways),
-		TotalERCircuits:       len(topology.ERCircuits),
-		TotalLoadBalancers:    len(topology.LoadBalancers),
-		TotalAppGateways:      len(topology.AppGateways),
-		TotalAzureFirewalls:   len(topology.AzureFirewalls),
-		TotalIPAddressSpace:   []string{},
+		TotalVNets:                len(topology.VirtualNetworks),
+		TotalNSGs:                 len(topology.NSGs),
+		TotalASGs:                 len(topology.ASGs),
+		TotalRouteTables:          len(topology.RouteTables),
+		TotalPrivateEndpoints:     len(topology.PrivateEndpoints),
+		TotalPrivateLinkServices:  len(topology.PrivateLinkServices),
+		TotalPrivateDNSZones:      len(topology.PrivateDNSZones),
+		TotalNATGateways:          len(topology.NATGateways),
+		TotalVPNGateways:          len(topology.VPNGateways),
+		TotalLocalNetworkGateways: len(topology.LocalNetworkGateways),
+		TotalERCircuits:           len(topology.ERCircuits),
+		TotalLoadBalancers:        len(topology.LoadBalancers),
+		TotalAppGateways:          len(topology.AppGateways),
+		TotalAzureFirewalls:       len(topology.AzureFirewalls),
+		TotalFirewallPolicies:     len(topology.FirewallPolicies),
+		TotalBastionHosts:         len(topology.BastionHosts),
+		TotalVirtualWANs:          len(topology.VirtualWANs),
+		TotalVirtualHubs:          len(topology.VirtualHubs),
+		TotalNetworkInterfaces:    len(topology.NetworkInterfaces),
+		TotalPublicIPs:            len(topology.PublicIPAddresses),
+		TotalIPAddressSpace:       []string{},
 	}
 
 	// Count subnets and collect address spaces
 	for _, vnet := range topology.VirtualNetworks {
 		summary.TotalSubnets += len(vnet.Subnets)
 		summary.TotalIPAddressSpace = append(summary.TotalIPAddressSpace, vnet.AddressSpace...)
-		summary.VNetPeeringCount += len(vnet.Peerings)
+		// Peerings created by hub connections are counted as hub connections
+		for _, peering := range vnet.Peerings {
+			if peering.RemoteVirtualHubID == "" {
+				summary.VNetPeeringCount++
+			}
+		}
+	}
+
+	for _, hub := range topology.VirtualHubs {
+		summary.TotalHubConnections += len(hub.VNetConnections)
 	}
 
 	// Count security rules
@@ -62,11 +127,12 @@ func generateSummary(topology *models.NetworkTopology) TopologySummary {
 // findOrphanedResources identifies resources that are not attached or used
 func findOrphanedResources(topology *models.NetworkTopology) OrphanedResources {
 	orphaned := OrphanedResources{
-		UnattachedNSGs:       []string{},
-		UnusedRouteTables:    []string{},
-		UnusedNATGateways:    []string{},
-		IsolatedSubnets:      []string{},
-		SubnetsWithoutRoutes: []string{},
+		UnattachedNSGs:        []string{},
+		UnusedRouteTables:     []string{},
+		UnusedNATGateways:     []string{},
+		IsolatedSubnets:       []string{},
+		SubnetsWithoutRoutes:  []string{},
+		UnassociatedPublicIPs: []string{},
 	}
 
 	// Build maps of what's used
@@ -74,6 +140,10 @@ func findOrphanedResources(topology *models.NetworkTopology) OrphanedResources {
 	usedRouteTables := make(map[string]bool)
 	usedNATGateways := make(map[string]bool)
 
+	// VNets connected to a Virtual WAN hub with internet security learn their
+	// routes from the hub
+	hubRouted := hubRoutedVNets(topology)
+
 	// Check each subnet for associations
 	for _, vnet := range topology.VirtualNetworks {
 		for _, subnet := range vnet.Subnets {
@@ -89,7 +159,7 @@ func findOrphanedResources(topology *models.NetworkTopology) OrphanedResources {
 			// Track Route Table usage
 			if subnet.RouteTable != nil {
 				usedRouteTables[*subnet.RouteTable] = true
-			} else {
+			} else if !hubRouted[strings.ToLower(vnet.ID)] {
 				orphaned.SubnetsWithoutRoutes = append(orphaned.SubnetsWithoutRoutes,
 					vnet.Name+"/"+subnet.Name)
 			}
@@ -122,9 +192,54 @@ func findOrphanedResources(topology *models.NetworkTopology) OrphanedResources {
 		}
 	}
 
+	// Find Public IPs that are not attached to any resource
+	for _, pip := range topology.PublicIPAddresses {
+		if pip.AssociatedResourceID == "" && pip.IPConfigurationID == "" {
+			orphaned.UnassociatedPublicIPs = append(orphaned.UnassociatedPublicIPs, pip.Name)
+		}
+	}
+
 	return orphaned
 }
 
+// analyzeFlowLogCoverage matches Network Watcher flow logs to NSGs. It returns nil
+// when Network Watcher insights were not collected, since coverage is then unknown.
+func analyzeFlowLogCoverage(topology *models.NetworkTopology) []NSGFlowLogCoverage {
+	if topology.NetworkWatcher == nil {
+		return nil
+	}
+
+	flowLogs := make(map[string]models.FlowLog)
+	for _, fl := range topology.NetworkWatcher.FlowLogs {
+		if fl.NSGId == "" {
+			continue
+		}
+		// Prefer an enabled flow log if an NSG somehow has several
+		key := strings.ToLower(fl.NSGId)
+		if existing, ok := flowLogs[key]; ok && existing.Enabled {
+			continue
+		}
+		flowLogs[key] = fl
+	}
+
+	coverage := make([]NSGFlowLogCoverage, 0, len(topology.NSGs))
+	for _, nsg := range topology.NSGs {
+		c := NSGFlowLogCoverage{
+			NSG:   nsg.Name,
+			NSGID: nsg.ID,
+		}
+		if fl, ok := flowLogs[strings.ToLower(nsg.ID)]; ok {
+			c.FlowLog = fl.Name
+			c.Enabled = fl.Enabled
+			c.RetentionDays = fl.RetentionDays
+			c.TrafficAnalytics = fl.TrafficAnalytics
+		}
+		coverage = append(coverage, c)
+	}
+
+	return coverage
+}
+
 // generateRecommendations creates actionable recommendations based on findings
 func generateRecommendations(report *AnalysisReport) []string {
 	recommendations := []string{}
@@ -168,8 +283,46 @@ func generateRecommendations(report *AnalysisReport) []string {
 			"Remove unused Route Tables to reduce configuration complexity")
 	}
 
+	if len(report.OrphanedResources.UnassociatedPublicIPs) > 0 {
+		recommendations = append(recommendations,
+			"Delete unassociated Public IPs; they are billed while allocated and widen the internet-facing footprint")
+	}
+
+	uncovered := 0
+	for _, c := range report.FlowLogCoverage {
+		if !c.Enabled {
+			uncovered++
+		}
+	}
+	if uncovered > 0 {
+		recommendations = append(recommendations,
+			"Enable NSG flow logs on NSGs without flow log coverage to retain traffic records for investigations")
+	}
+
+	nearlyFull := 0
+	for _, u := range report.SubnetUtilization {
+		if u.Status != UtilizationOK {
+			nearlyFull++
+		}
+	}
+	if nearlyFull > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Plan more address space for %d subnet(s) running out of IPs before the next scale-out", nearlyFull))
+	}
+
+	withoutBastion := 0
+	for _, c := range report.BastionCoverage {
+		if c.Bastion == "" && c.VMs > 0 {
+			withoutBastion++
+		}
+	}
+	if withoutBastion > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Deploy Azure Bastion, or peer with a VNet that has one, so VMs in %d VNet(s) can be managed without exposing SSH/RDP", withoutBastion))
+	}
+
 	// General recommendations
-	if report.Summary.TotalVNets > 0 && report.Summary.VNetPeeringCount == 0 {
+	if report.Summary.TotalVNets > 0 && report.Summary.VNetPeeringCount == 0 && report.Summary.TotalHubConnections == 0 {
 		recommendations = append(recommendations,
 			"Consider VNet peering for connectivity between virtual networks if needed")
 	}
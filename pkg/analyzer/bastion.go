@@ -0,0 +1,322 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// bastionReach is a Bastion host that can reach a VNet, either from inside the VNet
+// or through a direct peering with the VNet that hosts it
+type bastionReach struct {
+	bastion models.BastionHost
+	via     string // Name of the VNet hosting the Bastion, empty when it is in the VNet itself
+}
+
+// bastionReachability maps lower-cased VNet IDs to a Bastion host that can reach them.
+// Bastion works across direct peerings only, so peerings are not followed transitively.
+func bastionReachability(topology *models.NetworkTopology) map[string]bastionReach {
+	reach := make(map[string]bastionReach)
+	if len(topology.BastionHosts) == 0 {
+		return reach
+	}
+
+	vnetNames := make(map[string]string, len(topology.VirtualNetworks))
+	for _, vnet := range topology.VirtualNetworks {
+		vnetNames[strings.ToLower(vnet.ID)] = vnet.Name
+	}
+
+	bastionVNets := make(map[string]models.BastionHost)
+	for _, bastion := range topology.BastionHosts {
+		vnetID := strings.ToLower(bastion.VNetID)
+		if vnetID == "" {
+			vnetID = strings.ToLower(vnetIDFromSubnet(bastion.SubnetID))
+		}
+		if vnetID == "" {
+			continue
+		}
+		if _, ok := bastionVNets[vnetID]; !ok {
+			bastionVNets[vnetID] = bastion
+			reach[vnetID] = bastionReach{bastion: bastion}
+		}
+	}
+
+	// A peering may only have been collected on one side, so check both
+	for _, vnet := range topology.VirtualNetworks {
+		vnetID := strings.ToLower(vnet.ID)
+		for _, peering := range vnet.Peerings {
+			if !strings.EqualFold(peering.PeeringState, "Connected") {
+				continue
+			}
+			remoteID := strings.ToLower(peering.RemoteVNetID)
+
+			if bastion, ok := bastionVNets[vnetID]; ok {
+				if _, reached := reach[remoteID]; !reached {
+					reach[remoteID] = bastionReach{bastion: bastion, via: vnet.Name}
+				}
+			}
+			if bastion, ok := bastionVNets[remoteID]; ok {
+				if _, reached := reach[vnetID]; !reached {
+					reach[vnetID] = bastionReach{bastion: bastion, via: valueOr(vnetNames[remoteID], resourceName(remoteID))}
+				}
+			}
+		}
+	}
+
+	return reach
+}
+
+// analyzeBastionCoverage reports for each VNet which Bastion host, if any, can reach it
+func analyzeBastionCoverage(topology *models.NetworkTopology) []VNetBastionCoverage {
+	reach := bastionReachability(topology)
+
+	vms := make(map[string]int)
+	for _, nic := range topology.NetworkInterfaces {
+		if nic.VirtualMachineID != "" {
+			vms[strings.ToLower(vnetIDFromSubnet(nic.SubnetID))]++
+		}
+	}
+
+	coverage := make([]VNetBastionCoverage, 0, len(topology.VirtualNetworks))
+	for _, vnet := range topology.VirtualNetworks {
+		c := VNetBastionCoverage{
+			VNet:   vnet.Name,
+			VNetID: vnet.ID,
+			VMs:    vms[strings.ToLower(vnet.ID)],
+		}
+		if r, ok := reach[strings.ToLower(vnet.ID)]; ok {
+			c.Bastion = r.bastion.Name
+			c.Via = r.via
+		}
+		coverage = append(coverage, c)
+	}
+	return coverage
+}
+
+// nsgBastions maps lower-cased NSG IDs to the name of a Bastion host that can reach
+// the VNets the NSG is applied in, through its subnets or NICs
+func nsgBastions(topology *models.NetworkTopology) map[string]string {
+	reach := bastionReachability(topology)
+	bastions := make(map[string]string)
+	if len(reach) == 0 {
+		return bastions
+	}
+
+	nicSubnets := make(map[string]string, len(topology.NetworkInterfaces))
+	for _, nic := range topology.NetworkInterfaces {
+		nicSubnets[strings.ToLower(nic.ID)] = nic.SubnetID
+	}
+
+	for _, nsg := range topology.NSGs {
+		subnetIDs := append([]string{}, nsg.Associations.Subnets...)
+		for _, vnet := range topology.VirtualNetworks {
+			for _, subnet := range vnet.Subnets {
+				if subnet.NetworkSecurityGroup != nil && strings.EqualFold(*subnet.NetworkSecurityGroup, nsg.ID) {
+					subnetIDs = append(subnetIDs, subnet.ID)
+				}
+			}
+		}
+		for _, nicID := range nsg.Associations.NetworkInterfaces {
+			subnetIDs = append(subnetIDs, nicSubnets[strings.ToLower(nicID)])
+		}
+		for _, nic := range topology.NetworkInterfaces {
+			if strings.EqualFold(nic.NetworkSecurityGroupID, nsg.ID) {
+				subnetIDs = append(subnetIDs, nic.SubnetID)
+			}
+		}
+
+		for _, subnetID := range subnetIDs {
+			if r, ok := reach[strings.ToLower(vnetIDFromSubnet(subnetID))]; ok {
+				bastions[strings.ToLower(nsg.ID)] = r.bastion.Name
+				break
+			}
+		}
+	}
+
+	return bastions
+}
+
+// analyzeBastionHosts checks Bastion hosts for risky settings and for NSGs on
+// AzureBastionSubnet that block the traffic Bastion needs
+func analyzeBastionHosts(topology *models.NetworkTopology) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	for _, bastion := range topology.BastionHosts {
+		if bastion.ShareableLink {
+			findings = append(findings, SecurityFinding{
+				Severity:       SeverityMedium,
+				Category:       CategoryConfiguration,
+				Resource:       bastion.Name,
+				ResourceID:     bastion.ID,
+				Description:    fmt.Sprintf("Bastion '%s' has shareable links enabled; anyone holding a link can sign in to the linked VMs without Azure portal access", bastion.Name),
+				Recommendation: "Disable shareable links unless required, and review and delete existing links regularly",
+			})
+		}
+
+		subnet, nsg := bastionSubnetNSG(topology, bastion)
+		if nsg == nil {
+			continue
+		}
+
+		if blocked := blockedBastionFlows(*nsg, subnet.AddressPrefix); len(blocked) > 0 {
+			findings = append(findings, SecurityFinding{
+				Severity:   SeverityHigh,
+				Category:   CategoryNSGRule,
+				Resource:   nsg.Name,
+				ResourceID: nsg.ID,
+				Description: fmt.Sprintf("NSG '%s' on the AzureBastionSubnet of Bastion '%s' blocks traffic Bastion requires: %s",
+					nsg.Name, bastion.Name, strings.Join(blocked, ", ")),
+				Recommendation: "Add the inbound and outbound rules Azure Bastion requires (see https://learn.microsoft.com/azure/bastion/bastion-nsg)",
+			})
+		}
+	}
+
+	return findings
+}
+
+// bastionSubnetNSG returns the Bastion's subnet and the NSG attached to it, if both
+// were collected
+func bastionSubnetNSG(topology *models.NetworkTopology, bastion models.BastionHost) (models.Subnet, *models.NetworkSecurityGroup) {
+	for _, vnet := range topology.VirtualNetworks {
+		for _, subnet := range vnet.Subnets {
+			if !strings.EqualFold(subnet.ID, bastion.SubnetID) || subnet.NetworkSecurityGroup == nil {
+				continue
+			}
+			for i := range topology.NSGs {
+				if strings.EqualFold(topology.NSGs[i].ID, *subnet.NetworkSecurityGroup) {
+					return subnet, &topology.NSGs[i]
+				}
+			}
+		}
+	}
+	return models.Subnet{}, nil
+}
+
+// bastionFlow is a flow Azure Bastion needs on AzureBastionSubnet. The bastion
+// subnet itself is represented by the VirtualNetwork service tag.
+type bastionFlow struct {
+	direction   string
+	source      string
+	destination string
+	ports       []int
+	description string
+}
+
+var bastionRequiredFlows = []bastionFlow{
+	{"Inbound", "Internet", "VirtualNetwork", []int{443}, "inbound 443 from Internet"},
+	{"Inbound", "GatewayManager", "VirtualNetwork", []int{443}, "inbound 443 from GatewayManager"},
+	{"Inbound", "AzureLoadBalancer", "VirtualNetwork", []int{443}, "inbound 443 from AzureLoadBalancer"},
+	{"Inbound", "VirtualNetwork", "VirtualNetwork", []int{8080, 5701}, "inbound 8080/5701 from VirtualNetwork"},
+	{"Outbound", "VirtualNetwork", "VirtualNetwork", []int{22, 3389}, "outbound 22/3389 to VirtualNetwork"},
+	{"Outbound", "VirtualNetwork", "VirtualNetwork", []int{8080, 5701}, "outbound 8080/5701 to VirtualNetwork"},
+	{"Outbound", "VirtualNetwork", "AzureCloud", []int{443}, "outbound 443 to AzureCloud"},
+	{"Outbound", "VirtualNetwork", "Internet", []int{80}, "outbound 80 to Internet"},
+}
+
+// azureDefaultRules are the built-in rules every NSG ends with. They are used when
+// the collected NSG does not include its default rules.
+var azureDefaultRules = []models.SecurityRule{
+	{Name: "AllowVnetInBound", Priority: 65000, Direction: "Inbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "VirtualNetwork", DestinationAddressPrefix: "VirtualNetwork", DestinationPortRange: "*", IsDefault: true},
+	{Name: "AllowAzureLoadBalancerInBound", Priority: 65001, Direction: "Inbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "AzureLoadBalancer", DestinationAddressPrefix: "*", DestinationPortRange: "*", IsDefault: true},
+	{Name: "DenyAllInBound", Priority: 65500, Direction: "Inbound", Access: "Deny", Protocol: "*", SourceAddressPrefix: "*", DestinationAddressPrefix: "*", DestinationPortRange: "*", IsDefault: true},
+	{Name: "AllowVnetOutBound", Priority: 65000, Direction: "Outbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "VirtualNetwork", DestinationAddressPrefix: "VirtualNetwork", DestinationPortRange: "*", IsDefault: true},
+	{Name: "AllowInternetOutBound", Priority: 65001, Direction: "Outbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "*", DestinationAddressPrefix: "Internet", DestinationPortRange: "*", IsDefault: true},
+	{Name: "DenyAllOutBound", Priority: 65500, Direction: "Outbound", Access: "Deny", Protocol: "*", SourceAddressPrefix: "*", DestinationAddressPrefix: "*", DestinationPortRange: "*", IsDefault: true},
+}
+
+// blockedBastionFlows evaluates the NSG rules in priority order and describes the
+// required Bastion flows whose first matching rule denies them
+func blockedBastionFlows(nsg models.NetworkSecurityGroup, subnetPrefix string) []string {
+	rules := append([]models.SecurityRule{}, nsg.SecurityRules...)
+	hasDefaults := false
+	for _, rule := range rules {
+		if rule.IsDefault {
+			hasDefaults = true
+			break
+		}
+	}
+	if !hasDefaults {
+		rules = append(rules, azureDefaultRules...)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+
+	var blocked []string
+	for _, flow := range bastionRequiredFlows {
+		for _, port := range flow.ports {
+			if !flowAllowed(rules, flow, port, subnetPrefix) {
+				blocked = append(blocked, flow.description)
+				break
+			}
+		}
+	}
+	return blocked
+}
+
+func flowAllowed(rules []models.SecurityRule, flow bastionFlow, port int, subnetPrefix string) bool {
+	for _, rule := range rules {
+		if !strings.EqualFold(rule.Direction, flow.direction) {
+			continue
+		}
+		if rule.Protocol != "*" && !strings.EqualFold(rule.Protocol, "Tcp") {
+			continue
+		}
+		if !matchesAddress(rule.SourcePrefixes(), flow.source, subnetPrefix) ||
+			!matchesAddress(rule.DestinationPrefixes(), flow.destination, subnetPrefix) {
+			continue
+		}
+
+		for _, portRange := range rule.DestinationPorts() {
+			if portInRange(port, portRange) {
+				return strings.EqualFold(rule.Access, "Allow")
+			}
+		}
+	}
+	// No rule matched; NSGs deny by default
+	return false
+}
+
+// matchesAddress reports whether any of the rule prefixes covers the given service
+// tag. VirtualNetwork stands for the Bastion subnet, so prefixes that contain the
+// subnet match it too.
+func matchesAddress(prefixes []string, tag, subnetPrefix string) bool {
+	for _, prefix := range prefixes {
+		switch {
+		case prefix == "*" || strings.EqualFold(prefix, "Any") || strings.EqualFold(prefix, tag):
+			return true
+		case tag == "Internet" && prefix == "0.0.0.0/0":
+			return true
+		// AzureCloud addresses are outside the VNet, so the Internet tag covers them
+		case tag == "AzureCloud" && (strings.EqualFold(prefix, "Internet") || prefix == "0.0.0.0/0"):
+			return true
+		case tag == "VirtualNetwork" && prefixContains(prefix, subnetPrefix):
+			return true
+		}
+	}
+	return false
+}
+
+// prefixContains reports whether the CIDR outer contains the CIDR inner
+func prefixContains(outer, inner string) bool {
+	outerPrefix, err := netip.ParsePrefix(outer)
+	if err != nil {
+		return false
+	}
+	innerPrefix, err := netip.ParsePrefix(inner)
+	if err != nil {
+		return false
+	}
+	return outerPrefix.Bits() <= innerPrefix.Bits() && outerPrefix.Contains(innerPrefix.Addr())
+}
+
+// vnetIDFromSubnet returns the VNet part of a subnet resource ID
+func vnetIDFromSubnet(subnetID string) string {
+	if i := strings.Index(strings.ToLower(subnetID), "/subnets/"); i >= 0 {
+		return subnetID[:i]
+	}
+	return ""
+}
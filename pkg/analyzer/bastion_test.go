@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+const testProviders = "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network"
+
+// allowRule builds a TCP rule for the given direction, addresses and ports
+func allowRule(name string, priority int32, direction, source, destination string, ports ...string) models.SecurityRule {
+	return models.SecurityRule{Name: name, Priority: priority, Direction: direction, Access: "Allow", Protocol: "Tcp",
+		SourceAddressPrefix: source, DestinationAddressPrefix: destination, DestinationPortRanges: ports}
+}
+
+func denyRule(name string, priority int32, direction, source, destination string, ports ...string) models.SecurityRule {
+	rule := allowRule(name, priority, direction, source, destination, ports...)
+	rule.Access = "Deny"
+	return rule
+}
+
+// bastionInboundRules allow the inbound flows the default rules do not
+var bastionInboundRules = []models.SecurityRule{
+	allowRule("AllowHttpsInbound", 120, "Inbound", "Internet", "*", "443"),
+	allowRule("AllowGatewayManagerInbound", 130, "Inbound", "GatewayManager", "*", "443"),
+}
+
+func TestBlockedBastionFlows(t *testing.T) {
+	const subnetPrefix = "10.0.0.0/26"
+
+	tests := []struct {
+		name  string
+		rules []models.SecurityRule
+		want  []string
+	}{
+		{
+			name: "Default rules only",
+			want: []string{"inbound 443 from Internet", "inbound 443 from GatewayManager"},
+		},
+		{
+			name:  "All required flows allowed",
+			rules: bastionInboundRules,
+		},
+		{
+			name:  "GatewayManager denied first",
+			rules: append([]models.SecurityRule{denyRule("DenyGatewayManager", 100, "Inbound", "GatewayManager", "*", "*")}, bastionInboundRules...),
+			want:  []string{"inbound 443 from GatewayManager"},
+		},
+		{
+			name:  "Allow rule for UDP only",
+			rules: []models.SecurityRule{bastionInboundRules[0], {Name: "Udp", Priority: 130, Direction: "Inbound", Access: "Allow", Protocol: "Udp", SourceAddressPrefix: "GatewayManager", DestinationAddressPrefix: "*", DestinationPortRange: "443"}},
+			want:  []string{"inbound 443 from GatewayManager"},
+		},
+		{
+			name:  "Data plane port denied",
+			rules: append([]models.SecurityRule{denyRule("DenyHazelcast", 200, "Inbound", "VirtualNetwork", "VirtualNetwork", "5701")}, bastionInboundRules...),
+			want:  []string{"inbound 8080/5701 from VirtualNetwork"},
+		},
+		{
+			name: "Custom deny-all with the required flows allowed by prefix",
+			rules: append([]models.SecurityRule{
+				allowRule("AllowLoadBalancer", 140, "Inbound", "AzureLoadBalancer", "*", "443"),
+				allowRule("AllowDataPlane", 150, "Inbound", "10.0.0.0/16", "10.0.0.0/24", "8080", "5701"),
+				denyRule("DenyAll", 4000, "Inbound", "*", "*", "*"),
+			}, bastionInboundRules...),
+		},
+		{
+			name: "Custom deny-all without the load balancer",
+			rules: append([]models.SecurityRule{
+				denyRule("DenyAll", 4000, "Inbound", "*", "*", "*"),
+			}, bastionInboundRules...),
+			want: []string{"inbound 443 from AzureLoadBalancer", "inbound 8080/5701 from VirtualNetwork"},
+		},
+		{
+			name: "Outbound to Azure and the internet denied",
+			rules: append([]models.SecurityRule{
+				denyRule("DenyInternet", 100, "Outbound", "*", "Internet", "*"),
+			}, bastionInboundRules...),
+			want: []string{"outbound 443 to AzureCloud", "outbound 80 to Internet"},
+		},
+		{
+			// With the default rules collected, the fallback is not added, so
+			// leaving out AllowAzureLoadBalancerInBound blocks the load balancer
+			name: "Collected default rules are used as is",
+			rules: append([]models.SecurityRule{
+				{Name: "AllowVnetInBound", Priority: 65000, Direction: "Inbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "VirtualNetwork", DestinationAddressPrefix: "VirtualNetwork", DestinationPortRange: "*", IsDefault: true},
+				{Name: "AllowVnetOutBound", Priority: 65000, Direction: "Outbound", Access: "Allow", Protocol: "*", SourceAddressPrefix: "VirtualNetwork", DestinationAddressPrefix: "VirtualNetwork", DestinationPortRange: "*", IsDefault: true},
+			}, bastionInboundRules...),
+			want: []string{"inbound 443 from AzureLoadBalancer", "outbound 443 to AzureCloud", "outbound 80 to Internet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsg := models.NetworkSecurityGroup{Name: "nsg-bastion", SecurityRules: tt.rules}
+			if blocked := blockedBastionFlows(nsg, subnetPrefix); !reflect.DeepEqual(blocked, tt.want) {
+				t.Errorf("blockedBastionFlows() = %q, want %q", blocked, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeBastionHosts(t *testing.T) {
+	nsgID := testProviders + "/networkSecurityGroups/nsg-bastion"
+	subnetID := testProviders + "/virtualNetworks/vnet-hub/subnets/AzureBastionSubnet"
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{{
+			ID:      testProviders + "/virtualNetworks/vnet-hub",
+			Name:    "vnet-hub",
+			Subnets: []models.Subnet{{ID: subnetID, Name: "AzureBastionSubnet", AddressPrefix: "10.0.0.0/26", NetworkSecurityGroup: &nsgID}},
+		}},
+		NSGs:         []models.NetworkSecurityGroup{{ID: nsgID, Name: "nsg-bastion", SecurityRules: bastionInboundRules[:1]}},
+		BastionHosts: []models.BastionHost{{ID: testProviders + "/bastionHosts/bas-hub", Name: "bas-hub", SubnetID: subnetID, ShareableLink: true}},
+	}
+
+	checkFindings(t, analyzeBastionHosts(topology),
+		wantFinding{SeverityMedium, "Bastion 'bas-hub' has shareable links enabled"},
+		wantFinding{SeverityHigh, "NSG 'nsg-bastion' on the AzureBastionSubnet of Bastion 'bas-hub' blocks traffic Bastion requires: inbound 443 from GatewayManager"},
+	)
+
+	topology.NSGs[0].SecurityRules = bastionInboundRules
+	topology.BastionHosts[0].ShareableLink = false
+	checkFindings(t, analyzeBastionHosts(topology))
+}
+
+func TestBastionReachability(t *testing.T) {
+	vnetID := func(name string) string { return testProviders + "/virtualNetworks/" + name }
+	peering := func(remote, state string) models.VNetPeering {
+		return models.VNetPeering{Name: "to-" + remote, RemoteVNetID: vnetID(remote), PeeringState: state}
+	}
+
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{ID: vnetID("vnet-hub"), Name: "vnet-hub"},
+			// Only the spoke side of this peering was collected
+			{ID: vnetID("vnet-spoke1"), Name: "vnet-spoke1", Peerings: []models.VNetPeering{peering("vnet-hub", "Connected"), peering("vnet-spoke3", "Connected")}},
+			{ID: vnetID("vnet-spoke2"), Name: "vnet-spoke2", Peerings: []models.VNetPeering{peering("vnet-hub", "Disconnected")}},
+			// Peered with spoke1 only; Bastion does not cross two peerings
+			{ID: vnetID("vnet-spoke3"), Name: "vnet-spoke3"},
+		},
+		// VNetID is derived from the subnet when it was not collected
+		BastionHosts: []models.BastionHost{{Name: "bas-hub", SubnetID: vnetID("vnet-hub") + "/subnets/AzureBastionSubnet"}},
+		NetworkInterfaces: []models.NetworkInterface{
+			{SubnetID: vnetID("vnet-spoke1") + "/subnets/snet-app", VirtualMachineID: "vm1"},
+			{SubnetID: vnetID("vnet-spoke1") + "/subnets/snet-app", VirtualMachineID: "vm2"},
+			{SubnetID: vnetID("vnet-spoke1") + "/subnets/snet-pe", PrivateEndpointID: "pe1"},
+		},
+	}
+
+	want := []VNetBastionCoverage{
+		{VNet: "vnet-hub", VNetID: vnetID("vnet-hub"), Bastion: "bas-hub"},
+		{VNet: "vnet-spoke1", VNetID: vnetID("vnet-spoke1"), VMs: 2, Bastion: "bas-hub", Via: "vnet-hub"},
+		{VNet: "vnet-spoke2", VNetID: vnetID("vnet-spoke2")},
+		{VNet: "vnet-spoke3", VNetID: vnetID("vnet-spoke3")},
+	}
+	if coverage := analyzeBastionCoverage(topology); !reflect.DeepEqual(coverage, want) {
+		t.Errorf("analyzeBastionCoverage() =\n%+v\nwant\n%+v", coverage, want)
+	}
+
+	// An NSG on a peered spoke's subnet can point at the hub's Bastion
+	nsgID := testProviders + "/networkSecurityGroups/nsg-app"
+	topology.VirtualNetworks[1].Subnets = []models.Subnet{{ID: vnetID("vnet-spoke1") + "/subnets/snet-app", NetworkSecurityGroup: &nsgID}}
+	topology.NSGs = []models.NetworkSecurityGroup{{ID: nsgID}, {ID: testProviders + "/networkSecurityGroups/nsg-other"}}
+	if bastions := nsgBastions(topology); !reflect.DeepEqual(bastions, map[string]string{strings.ToLower(nsgID): "bas-hub"}) {
+		t.Errorf("nsgBastions() = %v", bastions)
+	}
+}
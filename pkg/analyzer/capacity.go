@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// analyzeSubnetUtilization reports how many usable IPs each subnet has in use,
+// fullest first. Subnets whose usage was not collected are left out.
+func analyzeSubnetUtilization(topology *models.NetworkTopology, opts AnalyzeOptions) []SubnetUtilization {
+	utilization := []SubnetUtilization{}
+	for _, vnet := range topology.VirtualNetworks {
+		for _, subnet := range vnet.Subnets {
+			usage := subnet.IPUsage
+			if usage == nil {
+				continue
+			}
+			usable := usage.TotalIPs - usage.ReservedIPs
+			if usable <= 0 {
+				continue
+			}
+
+			u := SubnetUtilization{
+				VNet:          vnet.Name,
+				VNetID:        vnet.ID,
+				Subnet:        subnet.Name,
+				SubnetID:      subnet.ID,
+				AddressPrefix: subnet.AddressPrefix,
+				UsableIPs:     usable,
+				UsedIPs:       usage.UsedIPs,
+				AvailableIPs:  usage.AvailableIPs,
+				PercentUsed:   float64(usage.UsedIPs) * 100 / float64(usable),
+				Status:        UtilizationOK,
+			}
+			switch {
+			case u.PercentUsed >= opts.SubnetCriticalPercent:
+				u.Status = UtilizationCritical
+			case u.PercentUsed >= opts.SubnetWarningPercent:
+				u.Status = UtilizationWarning
+			}
+			utilization = append(utilization, u)
+		}
+	}
+
+	sort.SliceStable(utilization, func(i, j int) bool {
+		return utilization[i].PercentUsed > utilization[j].PercentUsed
+	})
+	return utilization
+}
+
+// subnetUtilizationFindings raises a finding for each subnet past a threshold.
+// A full subnet fails the next scale-out, so these are High at the critical
+// threshold and Medium at the warning threshold.
+func subnetUtilizationFindings(utilization []SubnetUtilization, opts AnalyzeOptions) []SecurityFinding {
+	findings := []SecurityFinding{}
+	for _, u := range utilization {
+		var severity string
+		var threshold float64
+		switch u.Status {
+		case UtilizationCritical:
+			severity, threshold = SeverityHigh, opts.SubnetCriticalPercent
+		case UtilizationWarning:
+			severity, threshold = SeverityMedium, opts.SubnetWarningPercent
+		default:
+			continue
+		}
+
+		findings = append(findings, SecurityFinding{
+			Severity:   severity,
+			Category:   CategoryCapacity,
+			Resource:   u.VNet + "/" + u.Subnet,
+			ResourceID: u.SubnetID,
+			Description: fmt.Sprintf("Subnet '%s/%s' (%s) has %d of %d usable IPs in use (%.1f%%, threshold %g%%); only %d remain for new NICs, endpoints and scale-out",
+				u.VNet, u.Subnet, u.AddressPrefix, u.UsedIPs, u.UsableIPs, u.PercentUsed, threshold, u.AvailableIPs),
+			Recommendation: "Expand the subnet's address prefix or move workloads to a larger subnet before the next scale-out; Azure reserves 5 addresses in every subnet",
+		})
+	}
+	return findings
+}
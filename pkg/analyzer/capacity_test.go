@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// capacityTopology returns a VNet whose subnets each have 100 usable IPs, with
+// the given number in use
+func capacityTopology(used ...int64) *models.NetworkTopology {
+	vnet := models.VirtualNetwork{ID: "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet", Name: "vnet"}
+	for i, n := range used {
+		name := string(rune('a' + i))
+		vnet.Subnets = append(vnet.Subnets, models.Subnet{
+			ID:            vnet.ID + "/subnets/" + name,
+			Name:          name,
+			AddressPrefix: "10.0.0.0/25",
+			IPUsage:       &models.SubnetIPUsage{TotalIPs: 105, ReservedIPs: 5, UsedIPs: n, AvailableIPs: 100 - n},
+		})
+	}
+	return &models.NetworkTopology{VirtualNetworks: []models.VirtualNetwork{vnet}}
+}
+
+func TestAnalyzeSubnetUtilization(t *testing.T) {
+	topology := capacityTopology(79, 80, 94, 95, 100)
+	topology.VirtualNetworks[0].Subnets = append(topology.VirtualNetworks[0].Subnets,
+		models.Subnet{Name: "unmeasured"},
+		models.Subnet{Name: "no-usable-ips", IPUsage: &models.SubnetIPUsage{TotalIPs: 5, ReservedIPs: 5}},
+	)
+
+	utilization := analyzeSubnetUtilization(topology, DefaultAnalyzeOptions())
+
+	want := []struct {
+		subnet string
+		status string
+	}{
+		{"e", UtilizationCritical},
+		{"d", UtilizationCritical},
+		{"c", UtilizationWarning},
+		{"b", UtilizationWarning},
+		{"a", UtilizationOK},
+	}
+	if len(utilization) != len(want) {
+		t.Fatalf("Expected %d measured subnets, got %+v", len(want), utilization)
+	}
+	for i, w := range want {
+		if u := utilization[i]; u.Subnet != w.subnet || u.Status != w.status {
+			t.Errorf("utilization[%d] = %s %s (%.0f%%), want %s %s", i, u.Subnet, u.Status, u.PercentUsed, w.subnet, w.status)
+		}
+	}
+
+	checkFindings(t, subnetUtilizationFindings(utilization, DefaultAnalyzeOptions()),
+		wantFinding{SeverityHigh, "has 100 of 100 usable IPs in use (100.0%, threshold 95%); only 0 remain"},
+		wantFinding{SeverityHigh, "has 95 of 100 usable IPs in use (95.0%, threshold 95%)"},
+		wantFinding{SeverityMedium, "has 94 of 100 usable IPs in use (94.0%, threshold 80%)"},
+		wantFinding{SeverityMedium, "has 80 of 100 usable IPs in use (80.0%, threshold 80%)"},
+	)
+}
+
+func TestAnalyzeWithOptionsThresholds(t *testing.T) {
+	capacity := func(report *AnalysisReport) []SecurityFinding {
+		var findings []SecurityFinding
+		for _, f := range report.SecurityFindings {
+			if f.Category == CategoryCapacity {
+				findings = append(findings, f)
+			}
+		}
+		return findings
+	}
+
+	t.Run("Zero options use the defaults", func(t *testing.T) {
+		report := AnalyzeWithOptions(capacityTopology(10, 85), AnalyzeOptions{})
+		if status := report.SubnetUtilization[1].Status; status != UtilizationOK {
+			t.Errorf("A subnet 10%% full should be OK, got %s", status)
+		}
+		checkFindings(t, capacity(report), wantFinding{SeverityMedium, "threshold 80%"})
+	})
+
+	t.Run("Custom thresholds", func(t *testing.T) {
+		report := AnalyzeWithOptions(capacityTopology(10, 55, 85), AnalyzeOptions{SubnetWarningPercent: 50, SubnetCriticalPercent: 60})
+		checkFindings(t, capacity(report),
+			wantFinding{SeverityHigh, "threshold 60%"},
+			wantFinding{SeverityMedium, "threshold 50%"},
+		)
+	})
+
+	t.Run("Only the critical threshold set", func(t *testing.T) {
+		report := AnalyzeWithOptions(capacityTopology(85, 90), AnalyzeOptions{SubnetCriticalPercent: 90})
+		checkFindings(t, capacity(report),
+			wantFinding{SeverityHigh, "threshold 90%"},
+			wantFinding{SeverityMedium, "threshold 80%"},
+		)
+	})
+}
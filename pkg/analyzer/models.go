@@ -2,53 +2,121 @@ package analyzer
 
 // AnalysisReport contains the results of topology and security analysis
 type AnalysisReport struct {
-	Summary          TopologySummary    `json:"summary"`
-	SecurityFindings []SecurityFinding  `json:"security_findings"`
-	OrphanedResources OrphanedResources `json:"orphaned_resources"`
-	Recommendations  []string           `json:"recommendations"`
+	Summary           TopologySummary       `json:"summary"`
+	SecurityFindings  []SecurityFinding     `json:"security_findings"`
+	OrphanedResources OrphanedResources     `json:"orphaned_resources"`
+	FlowLogCoverage   []NSGFlowLogCoverage  `json:"flow_log_coverage,omitempty"` // Only set when Network Watcher insights were collected
+	BastionCoverage   []VNetBastionCoverage `json:"bastion_coverage"`
+	HubConnectivity   []HubConnectivity     `json:"hub_connectivity"`
+	SubnetUtilization []SubnetUtilization   `json:"subnet_utilization"` // Subnets whose IP usage was collected, fullest first
+	Recommendations   []string              `json:"recommendations"`
 }
 
 // TopologySummary provides statistics about the network topology
 type TopologySummary struct {
-	TotalVNets            int      `json:"total_vnets"`
-	TotalSubnets          int      `json:"total_subnets"`
-	TotalNSGs             int      `json:"total_nsgs"`
-	TotalSecurityRules    int      `json:"total_security_rules"`
-	TotalRouteTables      int      `json:"total_route_tables"`
-	TotalRoutes           int      `json:"total_routes"`
-	TotalPrivateEndpoints int      `json:"total_private_endpoints"`
-	TotalPrivateDNSZones  int      `json:"total_private_dns_zones"`
-	TotalNATGateways      int      `json:"total_nat_gateways"`
-	TotalVPNGateways      int      `json:"total_vpn_gateways"`
-	TotalERCircuits       int      `json:"total_er_circuits"`
-	TotalLoadBalancers    int      `json:"total_load_balancers"`
-	TotalAppGateways      int      `json:"total_app_gateways"`
-	TotalAzureFirewalls   int      `json:"total_azure_firewalls"`
-	TotalIPAddressSpace   []string `json:"total_ip_address_space"`
-	VNetPeeringCount      int      `json:"vnet_peering_count"`
-	CrossRGDependencies   int      `json:"cross_rg_dependencies"`
+	TotalVNets                int      `json:"total_vnets"`
+	TotalSubnets              int      `json:"total_subnets"`
+	TotalNSGs                 int      `json:"total_nsgs"`
+	TotalASGs                 int      `json:"total_asgs"`
+	TotalSecurityRules        int      `json:"total_security_rules"`
+	TotalRouteTables          int      `json:"total_route_tables"`
+	TotalRoutes               int      `json:"total_routes"`
+	TotalPrivateEndpoints     int      `json:"total_private_endpoints"`
+	TotalPrivateLinkServices  int      `json:"total_private_link_services"`
+	TotalPrivateDNSZones      int      `json:"total_private_dns_zones"`
+	TotalNATGateways          int      `json:"total_nat_gateways"`
+	TotalVPNGateways          int      `json:"total_vpn_gateways"`
+	TotalLocalNetworkGateways int      `json:"total_local_network_gateways"`
+	TotalERCircuits           int      `json:"total_er_circuits"`
+	TotalLoadBalancers        int      `json:"total_load_balancers"`
+	TotalAppGateways          int      `json:"total_app_gateways"`
+	TotalAzureFirewalls       int      `json:"total_azure_firewalls"`
+	TotalFirewallPolicies     int      `json:"total_firewall_policies"`
+	TotalBastionHosts         int      `json:"total_bastion_hosts"`
+	TotalVirtualWANs          int      `json:"total_virtual_wans"`
+	TotalVirtualHubs          int      `json:"total_virtual_hubs"`
+	TotalHubConnections       int      `json:"total_hub_connections"`
+	TotalNetworkInterfaces    int      `json:"total_network_interfaces"`
+	TotalPublicIPs            int      `json:"total_public_ips"`
+	TotalIPAddressSpace       []string `json:"total_ip_address_space"`
+	VNetPeeringCount          int      `json:"vnet_peering_count"`
+	CrossRGDependencies       int      `json:"cross_rg_dependencies"`
 }
 
 // SecurityFinding represents a potential security issue
 type SecurityFinding struct {
-	Severity       string `json:"severity"`        // Critical, High, Medium, Low, Info
-	Category       string `json:"category"`        // e.g., "NSG Rule", "Network Exposure"
-	Resource       string `json:"resource"`        // Resource name (e.g., NSG name)
-	ResourceID     string `json:"resource_id"`     // Full resource ID
-	Rule           string `json:"rule"`            // Rule name if applicable
-	Description    string `json:"description"`     // What the issue is
-	Recommendation string `json:"recommendation"`  // How to fix it
+	Severity       string `json:"severity"`       // Critical, High, Medium, Low, Info
+	Category       string `json:"category"`       // e.g., "NSG Rule", "Network Exposure"
+	Resource       string `json:"resource"`       // Resource name (e.g., NSG name)
+	ResourceID     string `json:"resource_id"`    // Full resource ID
+	Rule           string `json:"rule"`           // Rule name if applicable
+	Description    string `json:"description"`    // What the issue is
+	Recommendation string `json:"recommendation"` // How to fix it
 }
 
 // OrphanedResources contains resources that are not attached or used
 type OrphanedResources struct {
-	UnattachedNSGs       []string `json:"unattached_nsgs"`
-	UnusedRouteTables    []string `json:"unused_route_tables"`
-	UnusedNATGateways    []string `json:"unused_nat_gateways"`
-	IsolatedSubnets      []string `json:"isolated_subnets"`       // Subnets with no NSG
-	SubnetsWithoutRoutes []string `json:"subnets_without_routes"` // Subnets with no route table
+	UnattachedNSGs        []string `json:"unattached_nsgs"`
+	UnusedRouteTables     []string `json:"unused_route_tables"`
+	UnusedNATGateways     []string `json:"unused_nat_gateways"`
+	IsolatedSubnets       []string `json:"isolated_subnets"`        // Subnets with no NSG
+	SubnetsWithoutRoutes  []string `json:"subnets_without_routes"`  // Subnets with no route table
+	UnassociatedPublicIPs []string `json:"unassociated_public_ips"` // Public IPs not attached to any resource
 }
 
+// NSGFlowLogCoverage describes the flow log configured for an NSG
+type NSGFlowLogCoverage struct {
+	NSG              string `json:"nsg"`
+	NSGID            string `json:"nsg_id"`
+	FlowLog          string `json:"flow_log"`       // Flow log name, empty if none
+	Enabled          bool   `json:"enabled"`        // Flow log exists and is enabled
+	RetentionDays    int32  `json:"retention_days"` // 0 means retention is not enforced
+	TrafficAnalytics bool   `json:"traffic_analytics"`
+}
+
+// VNetBastionCoverage describes which Bastion host, if any, can reach a VNet
+type VNetBastionCoverage struct {
+	VNet    string `json:"vnet"`
+	VNetID  string `json:"vnet_id"`
+	VMs     int    `json:"vms"`     // VMs with a NIC in the VNet
+	Bastion string `json:"bastion"` // Bastion host name, empty if none can reach the VNet
+	Via     string `json:"via"`     // VNet hosting the Bastion when reached through peering
+}
+
+// HubConnectivity describes how a VNet connected to a Virtual WAN hub sends its
+// traffic and which other connected VNets it can reach
+type HubConnectivity struct {
+	VNet           string   `json:"vnet"`
+	VNetID         string   `json:"vnet_id"`
+	Hub            string   `json:"hub"`
+	HubID          string   `json:"hub_id"`
+	Connection     string   `json:"connection"`
+	InternetEgress string   `json:"internet_egress"` // Next hop for internet traffic, e.g. "fw-vhub (routing intent)"
+	PrivateTraffic string   `json:"private_traffic"` // Next hop for traffic to other VNets and branches
+	ReachableVNets []string `json:"reachable_vnets"` // Other VNets connected to the same Virtual WAN
+}
+
+// SubnetUtilization describes how many of a subnet's usable IPs are in use
+type SubnetUtilization struct {
+	VNet          string  `json:"vnet"`
+	VNetID        string  `json:"vnet_id"`
+	Subnet        string  `json:"subnet"`
+	SubnetID      string  `json:"subnet_id"`
+	AddressPrefix string  `json:"address_prefix"`
+	UsableIPs     int64   `json:"usable_ips"` // Addresses in the prefix less the 5 Azure reserves
+	UsedIPs       int64   `json:"used_ips"`
+	AvailableIPs  int64   `json:"available_ips"`
+	PercentUsed   float64 `json:"percent_used"` // Share of the usable IPs in use
+	Status        string  `json:"status"`       // OK, Warning or Critical against the configured thresholds
+}
+
+// Subnet utilization statuses
+const (
+	UtilizationOK       = "OK"
+	UtilizationWarning  = "Warning"
+	UtilizationCritical = "Critical"
+)
+
 // Severity levels
 const (
 	SeverityCritical = "Critical"
@@ -60,8 +128,10 @@ const (
 
 // Security finding categories
 const (
-	CategoryNSGRule          = "NSG Rule"
-	CategoryNetworkExposure  = "Network Exposure"
+	CategoryNSGRule           = "NSG Rule"
+	CategoryFirewallRule      = "Firewall Rule"
+	CategoryNetworkExposure   = "Network Exposure"
 	CategoryMissingProtection = "Missing Protection"
-	CategoryConfiguration    = "Configuration"
+	CategoryConfiguration     = "Configuration"
+	CategoryCapacity          = "Capacity"
 )
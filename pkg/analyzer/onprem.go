@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// onPremisesVNets returns the lower-cased IDs of the VNets an on-premises site can
+// reach through its local network gateway: the VNets of the VPN gateways connected
+// to it and the VNets peered with those that use the remote gateway
+func onPremisesVNets(topology *models.NetworkTopology, lng models.LocalNetworkGateway) map[string]bool {
+	connections := make(map[string]bool, len(lng.Connections))
+	for _, id := range lng.Connections {
+		connections[strings.ToLower(id)] = true
+	}
+
+	reached := make(map[string]bool)
+	for _, gw := range topology.VPNGateways {
+		for _, conn := range gw.Connections {
+			if connections[strings.ToLower(conn.ID)] && gw.VNetID != "" {
+				reached[strings.ToLower(gw.VNetID)] = true
+			}
+		}
+	}
+
+	gatewayVNets := make(map[string]bool, len(reached))
+	for id := range reached {
+		gatewayVNets[id] = true
+	}
+	for _, vnet := range topology.VirtualNetworks {
+		for _, peering := range vnet.Peerings {
+			if peering.UseRemoteGateways && gatewayVNets[strings.ToLower(peering.RemoteVNetID)] {
+				reached[strings.ToLower(vnet.ID)] = true
+			}
+		}
+	}
+	return reached
+}
+
+// analyzeLocalNetworkGateways checks the on-premises address space behind each local
+// network gateway against the VNet address spaces. Overlapping ranges cannot be
+// routed to both sides, so part of either network becomes unreachable.
+func analyzeLocalNetworkGateways(topology *models.NetworkTopology) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	for _, lng := range topology.LocalNetworkGateways {
+		reached := onPremisesVNets(topology, lng)
+
+		for _, vnet := range topology.VirtualNetworks {
+			for _, space := range vnet.AddressSpace {
+				for _, prefix := range lng.AddressPrefixes {
+					if !prefixesOverlap(prefix, space) {
+						continue
+					}
+
+					finding := SecurityFinding{
+						Severity:   SeverityMedium,
+						Category:   CategoryConfiguration,
+						Resource:   lng.Name,
+						ResourceID: lng.ID,
+						Description: fmt.Sprintf("On-premises prefix %s behind local network gateway '%s' overlaps address space %s of VNet '%s'; the VNet could not reach that part of the on-premises network if connected",
+							prefix, lng.Name, space, vnet.Name),
+						Recommendation: "Re-address the overlapping range on one side, or narrow the local network gateway's address prefixes",
+					}
+					if reached[strings.ToLower(vnet.ID)] {
+						finding.Severity = SeverityHigh
+						finding.Description = fmt.Sprintf("On-premises prefix %s behind local network gateway '%s' overlaps address space %s of VNet '%s', which is connected to that site; traffic to the overlapping range stays in Azure and never reaches on-premises",
+							prefix, lng.Name, space, vnet.Name)
+					}
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// prefixesOverlap reports whether two CIDR prefixes share any address
+func prefixesOverlap(a, b string) bool {
+	pa, err := netip.ParsePrefix(a)
+	if err != nil {
+		return false
+	}
+	pb, err := netip.ParsePrefix(b)
+	if err != nil {
+		return false
+	}
+	return pa.Overlaps(pb)
+}
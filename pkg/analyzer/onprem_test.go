@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+func TestAnalyzeLocalNetworkGateways(t *testing.T) {
+	vnetID := func(name string) string { return testProviders + "/virtualNetworks/" + name }
+	connectionID := testProviders + "/connections/to-onprem"
+
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{ID: vnetID("vnet-hub"), Name: "vnet-hub", AddressSpace: []string{"10.0.0.0/16"}},
+			{ID: vnetID("vnet-spoke"), Name: "vnet-spoke", AddressSpace: []string{"10.1.0.0/16"},
+				Peerings: []models.VNetPeering{{RemoteVNetID: vnetID("vnet-hub"), PeeringState: "Connected", UseRemoteGateways: true}}},
+			// Peered with the hub but routed through its own gateway, if any
+			{ID: vnetID("vnet-peer"), Name: "vnet-peer", AddressSpace: []string{"10.2.0.0/16"},
+				Peerings: []models.VNetPeering{{RemoteVNetID: vnetID("vnet-hub"), PeeringState: "Connected"}}},
+			{ID: vnetID("vnet-isolated"), Name: "vnet-isolated", AddressSpace: []string{"172.16.0.0/16", "10.3.0.0/16"}},
+		},
+		VPNGateways: []models.VPNGateway{{
+			Name:        "vgw-hub",
+			VNetID:      vnetID("vnet-hub"),
+			Connections: []models.VPNConnection{{ID: strings.ToUpper(connectionID), Name: "to-onprem"}},
+		}},
+		LocalNetworkGateways: []models.LocalNetworkGateway{{
+			ID:              testProviders + "/localNetworkGateways/lng-dc",
+			Name:            "lng-dc",
+			AddressPrefixes: []string{"10.0.128.0/17", "10.1.0.0/24", "10.2.0.0/16", "10.3.5.0/24", "192.168.0.0/16"},
+			Connections:     []string{connectionID},
+		}},
+	}
+
+	reached := onPremisesVNets(topology, topology.LocalNetworkGateways[0])
+	want := map[string]bool{strings.ToLower(vnetID("vnet-hub")): true, strings.ToLower(vnetID("vnet-spoke")): true}
+	if !reflect.DeepEqual(reached, want) {
+		t.Errorf("onPremisesVNets() = %v, want %v", reached, want)
+	}
+
+	checkFindings(t, analyzeLocalNetworkGateways(topology),
+		wantFinding{SeverityHigh, "10.0.128.0/17 behind local network gateway 'lng-dc' overlaps address space 10.0.0.0/16 of VNet 'vnet-hub', which is connected to that site"},
+		wantFinding{SeverityHigh, "10.1.0.0/24 behind local network gateway 'lng-dc' overlaps address space 10.1.0.0/16 of VNet 'vnet-spoke', which is connected to that site"},
+		wantFinding{SeverityMedium, "10.2.0.0/16 behind local network gateway 'lng-dc' overlaps address space 10.2.0.0/16 of VNet 'vnet-peer'; the VNet could not reach"},
+		wantFinding{SeverityMedium, "10.3.5.0/24 behind local network gateway 'lng-dc' overlaps address space 10.3.0.0/16 of VNet 'vnet-isolated'; the VNet could not reach"},
+	)
+
+	t.Run("Unconnected site", func(t *testing.T) {
+		topology.LocalNetworkGateways[0].Connections = nil
+		findings := analyzeLocalNetworkGateways(topology)
+		if len(findings) != 4 {
+			t.Fatalf("Expected the same 4 overlaps, got %+v", findings)
+		}
+		for _, f := range findings {
+			if f.Severity != SeverityMedium {
+				t.Errorf("Overlaps with an unconnected site should be Medium, got %s: %s", f.Severity, f.Description)
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// Private endpoint connection states reported by a private link service
+const (
+	connectionPending      = "Pending"
+	connectionRejected     = "Rejected"
+	connectionDisconnected = "Disconnected"
+)
+
+// analyzePrivateLinkServices checks who can discover and connect to the private link
+// services published from the topology, and flags consumer connections that still
+// need a decision or were refused
+func analyzePrivateLinkServices(topology *models.NetworkTopology) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	for _, pls := range topology.PrivateLinkServices {
+		if allSubscriptions(pls.Visibility) {
+			severity := SeverityMedium
+			description := fmt.Sprintf("Private link service '%s' is visible to every Azure subscription; anyone who learns its alias can request a connection", pls.Name)
+			if allSubscriptions(pls.AutoApproval) {
+				severity = SeverityHigh
+				description = fmt.Sprintf("Private link service '%s' is visible to and auto-approves every Azure subscription; anyone who learns its alias gets a private path to the service", pls.Name)
+			}
+			findings = append(findings, SecurityFinding{
+				Severity:       severity,
+				Category:       CategoryNetworkExposure,
+				Resource:       pls.Name,
+				ResourceID:     pls.ID,
+				Description:    description,
+				Recommendation: "Restrict visibility and auto-approval to the subscriptions of known consumers",
+			})
+		}
+
+		for _, conn := range pls.Connections {
+			consumer := fmt.Sprintf("private endpoint '%s'", resourceName(conn.PrivateEndpointID))
+			if sub := subscriptionOf(conn.PrivateEndpointID); sub != "" {
+				consumer += fmt.Sprintf(" in subscription '%s'", sub)
+			}
+
+			switch {
+			case strings.EqualFold(conn.Status, connectionPending):
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityLow,
+					Category:       CategoryConfiguration,
+					Resource:       pls.Name,
+					ResourceID:     conn.ID,
+					Rule:           conn.Name,
+					Description:    fmt.Sprintf("Connection from %s to private link service '%s' is pending approval", consumer, pls.Name),
+					Recommendation: "Approve the connection if the consumer is expected, otherwise reject it",
+				})
+			case strings.EqualFold(conn.Status, connectionRejected), strings.EqualFold(conn.Status, connectionDisconnected):
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityLow,
+					Category:       CategoryConfiguration,
+					Resource:       pls.Name,
+					ResourceID:     conn.ID,
+					Rule:           conn.Name,
+					Description:    fmt.Sprintf("Connection from %s to private link service '%s' is %s", consumer, pls.Name, strings.ToLower(conn.Status)),
+					Recommendation: "Remove the stale connection; the consumer has to delete its private endpoint and request a new connection to retry",
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// allSubscriptions reports whether a visibility or auto-approval list covers every subscription
+func allSubscriptions(subscriptions []string) bool {
+	for _, s := range subscriptions {
+		if s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptionOf returns the subscription ID segment of a resource ID
+func subscriptionOf(resourceID string) string {
+	parts := strings.Split(resourceID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "subscriptions") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
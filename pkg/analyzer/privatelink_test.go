@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+func TestAnalyzePrivateLinkServices(t *testing.T) {
+	const consumerEndpoint = "/subscriptions/consumer-sub/resourceGroups/rg/providers/Microsoft.Network/privateEndpoints/pe-consumer"
+	connection := func(status, endpointID string) []models.PrivateLinkServiceConnection {
+		return []models.PrivateLinkServiceConnection{{ID: testProviders + "/privateLinkServices/pls/privateEndpointConnections/c1", Name: "c1", PrivateEndpointID: endpointID, Status: status}}
+	}
+
+	tests := []struct {
+		name         string
+		visibility   []string
+		autoApproval []string
+		connections  []models.PrivateLinkServiceConnection
+		want         []wantFinding
+	}{
+		{
+			name:       "Visible to every subscription",
+			visibility: []string{"*"},
+			want:       []wantFinding{{SeverityMedium, "is visible to every Azure subscription"}},
+		},
+		{
+			name:         "Visible to and auto-approving every subscription",
+			visibility:   []string{"sub-a", "*"},
+			autoApproval: []string{"*"},
+			want:         []wantFinding{{SeverityHigh, "is visible to and auto-approves every Azure subscription"}},
+		},
+		{
+			// Auto-approval only applies to subscriptions that can see the service
+			name:         "Auto-approving every subscription with restricted visibility",
+			visibility:   []string{"sub-a"},
+			autoApproval: []string{"*"},
+		},
+		{
+			name:         "Restricted to known subscriptions",
+			visibility:   []string{"sub-a", "sub-b"},
+			autoApproval: []string{"sub-a"},
+		},
+		{
+			name:        "Pending connection",
+			connections: connection("Pending", consumerEndpoint),
+			want:        []wantFinding{{SeverityLow, "Connection from private endpoint 'pe-consumer' in subscription 'consumer-sub' to private link service 'pls' is pending approval"}},
+		},
+		{
+			name:        "Rejected connection",
+			connections: connection("Rejected", consumerEndpoint),
+			want:        []wantFinding{{SeverityLow, "to private link service 'pls' is rejected"}},
+		},
+		{
+			name:        "Disconnected connection without a subscription",
+			connections: connection("disconnected", "pe-orphan"),
+			want:        []wantFinding{{SeverityLow, "Connection from private endpoint 'pe-orphan' to private link service 'pls' is disconnected"}},
+		},
+		{
+			name:        "Approved connection",
+			connections: connection("Approved", consumerEndpoint),
+		},
+		{
+			name:        "Public service with a pending connection",
+			visibility:  []string{"*"},
+			connections: connection("Pending", consumerEndpoint),
+			want:        []wantFinding{{SeverityMedium, "is visible to every Azure subscription"}, {SeverityLow, "is pending approval"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology := &models.NetworkTopology{PrivateLinkServices: []models.PrivateLinkService{{
+				ID:           testProviders + "/privateLinkServices/pls",
+				Name:         "pls",
+				Visibility:   tt.visibility,
+				AutoApproval: tt.autoApproval,
+				Connections:  tt.connections,
+			}}}
+			checkFindings(t, analyzePrivateLinkServices(topology), tt.want...)
+		})
+	}
+}
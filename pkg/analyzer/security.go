@@ -2,6 +2,8 @@ package analyzer
 
 import (
 	"fmt"
+	"net/netip"
+	"strconv"
 	"strings"
 
 	"azure-network-analyzer/pkg/models"
@@ -12,7 +14,7 @@ func AnalyzeSecurityRisks(topology *models.NetworkTopology) []SecurityFinding {
 	findings := []SecurityFinding{}
 
 	// Analyze NSG rules
-	findings = append(findings, analyzeNSGRules(topology.NSGs)...)
+	findings = append(findings, analyzeNSGRules(topology.NSGs, nsgBastions(topology))...)
 
 	// Analyze subnet security
 	findings = append(findings, analyzeSubnetSecurity(topology.VirtualNetworks)...)
@@ -20,11 +22,30 @@ func AnalyzeSecurityRisks(topology *models.NetworkTopology) []SecurityFinding {
 	// Analyze gateway configurations
 	findings = append(findings, analyzeGatewaySecurity(topology)...)
 
+	// Analyze Azure Firewall policy rules
+	findings = append(findings, analyzeFirewallPolicies(topology.FirewallPolicies)...)
+
+	// Cross-check private endpoint IPs against subnets and private DNS
+	findings = append(findings, analyzePrivateEndpoints(topology)...)
+
+	// Check Bastion settings and the NSGs on AzureBastionSubnet
+	findings = append(findings, analyzeBastionHosts(topology)...)
+
+	// Check on-premises address space against the VNets
+	findings = append(findings, analyzeLocalNetworkGateways(topology)...)
+
+	// Check who can reach the private link services published from this network
+	findings = append(findings, analyzePrivateLinkServices(topology)...)
+
+	// Check that secured Virtual WAN hubs route traffic through their firewall
+	findings = append(findings, analyzeVirtualHubs(topology)...)
+
 	return findings
 }
 
-// analyzeNSGRules checks NSG rules for security risks
-func analyzeNSGRules(nsgs []models.NetworkSecurityGroup) []SecurityFinding {
+// analyzeNSGRules checks NSG rules for security risks. bastions maps lower-cased
+// NSG IDs to a Bastion host that can already reach the workloads behind the NSG.
+func analyzeNSGRules(nsgs []models.NetworkSecurityGroup, bastions map[string]string) []SecurityFinding {
 	findings := []SecurityFinding{}
 
 	for _, nsg := range nsgs {
@@ -34,9 +55,14 @@ func analyzeNSGRules(nsgs []models.NetworkSecurityGroup) []SecurityFinding {
 				continue
 			}
 
+			// Skip the built-in default rules - they cannot be changed or removed
+			if rule.IsDefault {
+				continue
+			}
+
 			// Check for internet-exposed sensitive ports
-			if isInternetSource(rule.SourceAddressPrefix) {
-				findings = append(findings, checkSensitivePorts(nsg, rule)...)
+			if hasInternetSource(rule) {
+				findings = append(findings, checkSensitivePorts(nsg, rule, bastions[strings.ToLower(nsg.ID)])...)
 			}
 
 			// Check for overly permissive rules
@@ -73,60 +99,85 @@ func analyzeNSGRules(nsgs []models.NetworkSecurityGroup) []SecurityFinding {
 	return findings
 }
 
-// checkSensitivePorts checks if sensitive ports are exposed to the internet
-func checkSensitivePorts(nsg models.NetworkSecurityGroup, rule models.SecurityRule) []SecurityFinding {
+// checkSensitivePorts checks if sensitive ports are exposed to the internet. bastion
+// names a Bastion host that can reach the NSG's workloads, if there is one.
+func checkSensitivePorts(nsg models.NetworkSecurityGroup, rule models.SecurityRule, bastion string) []SecurityFinding {
 	findings := []SecurityFinding{}
 
-	// Map of sensitive ports and their risks
-	sensitivePorts := map[string]struct {
-		name     string
-		severity string
-	}{
-		"22":    {"SSH", SeverityCritical},
-		"3389":  {"RDP", SeverityCritical},
-		"23":    {"Telnet", SeverityCritical},
-		"21":    {"FTP", SeverityHigh},
-		"445":   {"SMB", SeverityCritical},
-		"1433":  {"SQL Server", SeverityCritical},
-		"3306":  {"MySQL", SeverityCritical},
-		"5432":  {"PostgreSQL", SeverityCritical},
-		"27017": {"MongoDB", SeverityCritical},
-		"6379":  {"Redis", SeverityHigh},
-		"9200":  {"Elasticsearch", SeverityHigh},
+	// All ports open is reported once rather than once per sensitive port
+	if hasAllPortsRange(rule.DestinationPorts()) {
+		findings = append(findings, SecurityFinding{
+			Severity:       SeverityCritical,
+			Category:       CategoryNetworkExposure,
+			Resource:       nsg.Name,
+			ResourceID:     nsg.ID,
+			Rule:           rule.Name,
+			Description:    fmt.Sprintf("All ports are exposed to the internet via rule '%s'", rule.Name),
+			Recommendation: "Restrict to specific ports required for your application",
+		})
+		return findings
 	}
 
-	// Check destination port range
-	ports := parsePortRange(rule.DestinationPortRange)
+	// Check each destination port range, including ranges such as "20-25"
+	// that contain a sensitive port
+	reported := make(map[int]bool)
+	for _, portRange := range rule.DestinationPorts() {
+		for _, sp := range sensitivePorts {
+			if reported[sp.port] || !portInRange(sp.port, portRange) {
+				continue
+			}
+			reported[sp.port] = true
 
-	for _, port := range ports {
-		if info, found := sensitivePorts[port]; found {
+			port := strconv.Itoa(sp.port)
+			if portRange != port {
+				port = fmt.Sprintf("%d in range %s", sp.port, portRange)
+			}
 			findings = append(findings, SecurityFinding{
-				Severity:   info.severity,
+				Severity:   sp.severity,
 				Category:   CategoryNetworkExposure,
 				Resource:   nsg.Name,
 				ResourceID: nsg.ID,
 				Rule:       rule.Name,
 				Description: fmt.Sprintf("%s (port %s) is exposed to the internet via rule '%s'",
-					info.name, port, rule.Name),
-				Recommendation: fmt.Sprintf("Restrict %s access to specific IP addresses or use Azure Bastion/VPN for remote access", info.name),
+					sp.name, port, rule.Name),
+				Recommendation: sensitivePortRecommendation(sp.name, rule.Name, bastion),
 			})
 		}
 	}
 
-	// Check for all ports open
-	if rule.DestinationPortRange == "*" || rule.DestinationPortRange == "0-65535" {
-		findings = append(findings, SecurityFinding{
-			Severity:       SeverityCritical,
-			Category:       CategoryNetworkExposure,
-			Resource:       nsg.Name,
-			ResourceID:     nsg.ID,
-			Rule:           rule.Name,
-			Description:    fmt.Sprintf("All ports are exposed to the internet via rule '%s'", rule.Name),
-			Recommendation: "Restrict to specific ports required for your application",
-		})
+	return findings
+}
+
+// sensitivePortRecommendation recommends Bastion for remote access ports, pointing at
+// the Bastion host that is already available when there is one
+func sensitivePortRecommendation(service, ruleName, bastion string) string {
+	switch {
+	case service != "SSH" && service != "RDP":
+		return fmt.Sprintf("Restrict %s access to specific IP addresses or reach it over a VPN or private endpoint", service)
+	case bastion != "":
+		return fmt.Sprintf("Remove rule '%s' and connect through Azure Bastion '%s', which can already reach this network", ruleName, bastion)
+	default:
+		return fmt.Sprintf("Restrict %s access to specific IP addresses, or deploy Azure Bastion in this VNet or a peered hub for remote access", service)
 	}
+}
 
-	return findings
+// sensitivePorts lists the ports that should never be reachable from the internet
+var sensitivePorts = []struct {
+	port     int
+	name     string
+	severity string
+}{
+	{22, "SSH", SeverityCritical},
+	{3389, "RDP", SeverityCritical},
+	{23, "Telnet", SeverityCritical},
+	{21, "FTP", SeverityHigh},
+	{445, "SMB", SeverityCritical},
+	{1433, "SQL Server", SeverityCritical},
+	{3306, "MySQL", SeverityCritical},
+	{5432, "PostgreSQL", SeverityCritical},
+	{27017, "MongoDB", SeverityCritical},
+	{6379, "Redis", SeverityHigh},
+	{9200, "Elasticsearch", SeverityHigh},
 }
 
 // checkOverlyPermissive checks for overly permissive rules
@@ -147,14 +198,20 @@ func checkOverlyPermissive(nsg models.NetworkSecurityGroup, rule models.Security
 	}
 
 	// Check for wide port ranges
-	if isWidePortRange(rule.DestinationPortRange) {
+	var wide []string
+	for _, portRange := range rule.DestinationPorts() {
+		if isWidePortRange(portRange) {
+			wide = append(wide, portRange)
+		}
+	}
+	if len(wide) > 0 {
 		findings = append(findings, SecurityFinding{
 			Severity:       SeverityMedium,
 			Category:       CategoryNSGRule,
 			Resource:       nsg.Name,
 			ResourceID:     nsg.ID,
 			Rule:           rule.Name,
-			Description:    fmt.Sprintf("Rule '%s' allows a wide range of ports (%s)", rule.Name, rule.DestinationPortRange),
+			Description:    fmt.Sprintf("Rule '%s' allows a wide range of ports (%s)", rule.Name, strings.Join(wide, ", ")),
 			Recommendation: "Restrict to specific ports required for your application",
 		})
 	}
@@ -237,48 +294,370 @@ func analyzeGatewaySecurity(topology *models.NetworkTopology) []SecurityFinding
 	return findings
 }
 
+// analyzeFirewallPolicies checks firewall policy rules for security risks. Rules are
+// reported on the policy that defines them; policies that inherit them through
+// a base policy are named in the description.
+func analyzeFirewallPolicies(policies []models.FirewallPolicy) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	names := make(map[string]string)
+	for _, policy := range policies {
+		names[strings.ToLower(policy.ID)] = policy.Name
+	}
+
+	for _, policy := range policies {
+		// Inherited rules can only be checked if the base policy was collected
+		if policy.BasePolicyID != "" && names[strings.ToLower(policy.BasePolicyID)] == "" {
+			findings = append(findings, SecurityFinding{
+				Severity:       SeverityInfo,
+				Category:       CategoryConfiguration,
+				Resource:       policy.Name,
+				ResourceID:     policy.ID,
+				Rule:           "",
+				Description:    fmt.Sprintf("Firewall policy '%s' inherits rules from base policy '%s', which is outside the analyzed scope", policy.Name, resourceName(policy.BasePolicyID)),
+				Recommendation: "Include the base policy's resource group or subscription to analyze the inherited rules",
+			})
+		}
+
+		var inheritedBy string
+		if len(policy.ChildPolicies) > 0 {
+			children := make([]string, 0, len(policy.ChildPolicies))
+			for _, id := range policy.ChildPolicies {
+				children = append(children, resourceName(id))
+			}
+			inheritedBy = fmt.Sprintf(" (inherited by %s)", strings.Join(children, ", "))
+		}
+
+		for _, group := range policy.RuleCollectionGroups {
+			for _, collection := range group.RuleCollections {
+				for _, rule := range collection.Rules {
+					for _, f := range checkFirewallRule(policy, collection, rule) {
+						f.Description += inheritedBy
+						findings = append(findings, f)
+					}
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkFirewallRule checks a single firewall policy rule
+func checkFirewallRule(policy models.FirewallPolicy, collection models.FirewallRuleCollection, rule models.FirewallRule) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	finding := func(severity, category, description, recommendation string) SecurityFinding {
+		return SecurityFinding{
+			Severity:       severity,
+			Category:       category,
+			Resource:       policy.Name,
+			ResourceID:     policy.ID,
+			Rule:           collection.Name + "/" + rule.Name,
+			Description:    description,
+			Recommendation: recommendation,
+		}
+	}
+
+	switch rule.RuleType {
+	case "NetworkRule":
+		if collection.Action != "Allow" || !isAnyFirewallSource(rule) ||
+			len(rule.DestinationIPGroups) > 0 || len(rule.DestinationFQDNs) > 0 ||
+			!containsAny(rule.DestinationAddresses) {
+			break
+		}
+		// "*", "1-65535" and any other range wider than 100 ports count as open
+		for _, port := range rule.DestinationPorts {
+			if !isWidePortRange(port) {
+				continue
+			}
+			ports := "ports " + port
+			if port == "*" {
+				ports = "all ports"
+			}
+			findings = append(findings, finding(SeverityHigh, CategoryFirewallRule,
+				fmt.Sprintf("Network rule '%s' in collection '%s' allows traffic from any source to any destination on %s", rule.Name, collection.Name, ports),
+				"Restrict the rule to the sources, destinations and ports the workload needs"))
+			break
+		}
+
+	case "NatRule":
+		// The translated port is what reaches the backend; fall back to the
+		// public port when no translation is configured
+		ports := rule.DestinationPorts
+		if rule.TranslatedPort != "" {
+			ports = []string{rule.TranslatedPort}
+		}
+		for _, port := range ports {
+			service, ok := managementPorts[port]
+			if !ok {
+				continue
+			}
+			severity := SeverityHigh
+			if isAnyFirewallSource(rule) {
+				severity = SeverityCritical
+			}
+			findings = append(findings, finding(severity, CategoryNetworkExposure,
+				fmt.Sprintf("DNAT rule '%s' exposes %s (port %s) on %s", rule.Name, service, port, valueOr(rule.TranslatedAddress, rule.TranslatedFQDN)),
+				fmt.Sprintf("Remove the DNAT rule and use Azure Bastion or a VPN for %s access", service)))
+		}
+
+	case "ApplicationRule":
+		if collection.Action != "Allow" {
+			break
+		}
+		for _, fqdn := range rule.TargetFQDNs {
+			switch {
+			case fqdn == "*":
+				findings = append(findings, finding(SeverityHigh, CategoryFirewallRule,
+					fmt.Sprintf("Application rule '%s' in collection '%s' allows any FQDN", rule.Name, collection.Name),
+					"List the FQDNs the workload needs, or use FQDN tags for Azure services"))
+			case strings.HasPrefix(fqdn, "*.") && !strings.Contains(fqdn[2:], "."):
+				findings = append(findings, finding(SeverityMedium, CategoryFirewallRule,
+					fmt.Sprintf("Application rule '%s' in collection '%s' allows every domain under '%s'", rule.Name, collection.Name, fqdn[2:]),
+					"Narrow the wildcard to the domains the workload needs"))
+			case strings.HasPrefix(fqdn, "*"):
+				// Wildcards under shared domains such as *.blob.core.windows.net or
+				// *.azurewebsites.net also reach hosts owned by other tenants
+				findings = append(findings, finding(SeverityLow, CategoryFirewallRule,
+					fmt.Sprintf("Application rule '%s' in collection '%s' allows any host matching '%s', which may include hosts outside your organization", rule.Name, collection.Name, fqdn),
+					"List the specific hosts the workload needs, e.g. the storage account or app FQDN, instead of a wildcard"))
+			}
+		}
+	}
+
+	return findings
+}
+
+// managementPorts maps remote management ports to the service that uses them
+var managementPorts = map[string]string{
+	"22":   "SSH",
+	"23":   "Telnet",
+	"3389": "RDP",
+	"5985": "WinRM",
+	"5986": "WinRM",
+}
+
+// isAnyFirewallSource reports whether a firewall rule matches traffic from any source
+func isAnyFirewallSource(rule models.FirewallRule) bool {
+	return len(rule.SourceIPGroups) == 0 && containsAny(rule.SourceAddresses)
+}
+
+// containsAny reports whether a firewall rule field contains a wildcard value
+func containsAny(values []string) bool {
+	for _, v := range values {
+		if v == "*" || v == "0.0.0.0/0" || strings.EqualFold(v, "Any") {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzePrivateEndpoints cross-checks private endpoint IPs against their subnets
+// and the private DNS records that should resolve to them
+func analyzePrivateEndpoints(topology *models.NetworkTopology) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	subnetPrefixes := make(map[string]string)
+	for _, vnet := range topology.VirtualNetworks {
+		for _, subnet := range vnet.Subnets {
+			subnetPrefixes[strings.ToLower(subnet.ID)] = subnet.AddressPrefix
+		}
+	}
+
+	for _, pe := range topology.PrivateEndpoints {
+		// Check that every private IP lies inside the endpoint's subnet
+		if prefix, err := netip.ParsePrefix(subnetPrefixes[strings.ToLower(pe.SubnetID)]); err == nil {
+			for _, ip := range pe.PrivateIPAddresses {
+				addr, err := netip.ParseAddr(ip)
+				if err != nil || prefix.Contains(addr) {
+					continue
+				}
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityMedium,
+					Category:       CategoryConfiguration,
+					Resource:       pe.Name,
+					ResourceID:     pe.ID,
+					Rule:           "",
+					Description:    fmt.Sprintf("Private endpoint '%s' has IP %s outside its subnet %s", pe.Name, ip, prefix),
+					Recommendation: "Verify the endpoint's network interface; the collected data may be stale or the endpoint misconfigured",
+				})
+			}
+		}
+
+		// Check that private DNS resolves each FQDN to the endpoint's IPs
+		for _, config := range pe.DNSConfigs {
+			zone, record := findPrivateDNSRecord(topology.PrivateDNSZones, config.FQDN)
+			switch {
+			case zone == nil:
+				// The zone may live outside the analyzed scope
+				continue
+			case record == nil:
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityMedium,
+					Category:       CategoryConfiguration,
+					Resource:       pe.Name,
+					ResourceID:     pe.ID,
+					Rule:           "",
+					Description:    fmt.Sprintf("Private DNS zone '%s' has no A record for '%s' served by private endpoint '%s'", zone.Name, config.FQDN, pe.Name),
+					Recommendation: "Add a private DNS zone group to the endpoint or create the A record so clients do not resolve the public address",
+				})
+			case !sameIPs(record.IPAddresses, config.IPAddresses):
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityMedium,
+					Category:       CategoryConfiguration,
+					Resource:       pe.Name,
+					ResourceID:     pe.ID,
+					Rule:           "",
+					Description:    fmt.Sprintf("DNS record '%s' in zone '%s' resolves to %s but private endpoint '%s' uses %s", record.Name, zone.Name, strings.Join(record.IPAddresses, ", "), pe.Name, strings.Join(config.IPAddresses, ", ")),
+					Recommendation: "Update the stale A record to the endpoint's current private IP",
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
 // Helper functions
 
+// findPrivateDNSRecord finds the private DNS zone that serves fqdn and its A record
+// for it, if any. Private endpoint FQDNs use the public name (e.g.
+// "db.database.windows.net") while the zone is "privatelink.database.windows.net".
+func findPrivateDNSRecord(zones []models.PrivateDNSZone, fqdn string) (*models.PrivateDNSZone, *models.DNSARecord) {
+	fqdn = strings.ToLower(strings.TrimSuffix(fqdn, "."))
+
+	var matched *models.PrivateDNSZone
+	for i := range zones {
+		zone := &zones[i]
+		name := strings.ToLower(zone.Name)
+		host, ok := strings.CutSuffix(fqdn, "."+name)
+		if !ok {
+			public := strings.TrimPrefix(name, "privatelink.")
+			if public == name {
+				continue
+			}
+			if host, ok = strings.CutSuffix(fqdn, "."+public); !ok {
+				continue
+			}
+		}
+
+		matched = zone
+		for j := range zone.ARecords {
+			if strings.EqualFold(zone.ARecords[j].Name, host) {
+				return zone, &zone.ARecords[j]
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// sameIPs reports whether two IP lists contain the same addresses in any order
+func sameIPs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, ip := range a {
+		set[ip] = true
+	}
+	for _, ip := range b {
+		if !set[ip] {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceName returns the last segment of an Azure resource ID
+func resourceName(resourceID string) string {
+	return resourceID[strings.LastIndex(resourceID, "/")+1:]
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
 func isInternetSource(source string) bool {
 	return source == "*" || source == "0.0.0.0/0" || source == "Internet" || source == "Any"
 }
 
-func isWideOpen(rule models.SecurityRule) bool {
-	return isInternetSource(rule.SourceAddressPrefix) &&
-		(rule.DestinationAddressPrefix == "*" || rule.DestinationAddressPrefix == "0.0.0.0/0") &&
-		(rule.DestinationPortRange == "*" || rule.DestinationPortRange == "0-65535")
+// hasInternetSource reports whether any of the rule's source prefixes is the internet.
+// Rules whose source is an application security group never are.
+func hasInternetSource(rule models.SecurityRule) bool {
+	for _, prefix := range rule.SourcePrefixes() {
+		if isInternetSource(prefix) {
+			return true
+		}
+	}
+	return false
 }
 
-func isWidePortRange(portRange string) bool {
-	if portRange == "*" || portRange == "0-65535" {
-		return true
+// isWideOpen reports whether the rule allows any source to reach any destination on
+// all ports. A destination ASG limits the rule to its members, so it is never wide open.
+func isWideOpen(rule models.SecurityRule) bool {
+	if !hasInternetSource(rule) || len(rule.DestinationASGs) > 0 {
+		return false
 	}
-	// Check for ranges like "1-1000" or "80-8080"
-	if strings.Contains(portRange, "-") {
-		parts := strings.Split(portRange, "-")
-		if len(parts) == 2 {
-			// Simple heuristic: if range spans more than 100 ports, it's wide
-			// A more sophisticated check would parse the numbers
-			return len(portRange) > 5 // rough check
+
+	anyDestination := false
+	for _, prefix := range rule.DestinationPrefixes() {
+		if prefix == "*" || prefix == "0.0.0.0/0" {
+			anyDestination = true
+			break
+		}
+	}
+	return anyDestination && hasAllPortsRange(rule.DestinationPorts())
+}
+
+// hasAllPortsRange reports whether any of the port ranges covers every port,
+// such as "*", "0-65535" or "1-65535"
+func hasAllPortsRange(portRanges []string) bool {
+	for _, portRange := range portRanges {
+		if low, high, ok := portRangeBounds(portRange); ok && low <= 1 && high == 65535 {
+			return true
 		}
 	}
 	return false
 }
 
-func parsePortRange(portRange string) []string {
+// isWidePortRange reports whether a port range spans more than 100 ports
+func isWidePortRange(portRange string) bool {
+	low, high, ok := portRangeBounds(portRange)
+	return ok && high-low+1 > 100
+}
+
+// portInRange reports whether port falls within a port range such as "443", "20-25" or "*"
+func portInRange(port int, portRange string) bool {
+	low, high, ok := portRangeBounds(portRange)
+	return ok && port >= low && port <= high
+}
+
+// portRangeBounds parses a single port, a "low-high" range or "*"
+func portRangeBounds(portRange string) (int, int, bool) {
+	portRange = strings.TrimSpace(portRange)
 	if portRange == "*" {
-		return []string{"*"}
+		return 0, 65535, true
+	}
+
+	lowStr, highStr, isRange := strings.Cut(portRange, "-")
+	low, err := strconv.Atoi(strings.TrimSpace(lowStr))
+	if err != nil {
+		return 0, 0, false
 	}
-	// Handle comma-separated ports
-	if strings.Contains(portRange, ",") {
-		return strings.Split(portRange, ",")
+	if !isRange {
+		return low, low, true
 	}
-	// Handle single port
-	if !strings.Contains(portRange, "-") {
-		return []string{portRange}
+	high, err := strconv.Atoi(strings.TrimSpace(highStr))
+	if err != nil || high < low {
+		return 0, 0, false
 	}
-	// For ranges, return the range string itself
-	return []string{portRange}
+	return low, high, true
 }
 
 func isLargeSubnet(addressPrefix string) bool {
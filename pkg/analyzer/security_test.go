@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// wantFinding matches a finding by severity and a fragment of its description
+type wantFinding struct {
+	severity string
+	contains string
+}
+
+// checkFindings fails the test unless findings match want one for one, in order
+func checkFindings(t *testing.T, findings []SecurityFinding, want ...wantFinding) {
+	t.Helper()
+
+	if len(findings) != len(want) {
+		t.Fatalf("Expected %d findings, got %d: %+v", len(want), len(findings), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Severity != w.severity || !strings.Contains(f.Description, w.contains) {
+			t.Errorf("Finding %d = %s %q, want %s containing %q", i, f.Severity, f.Description, w.severity, w.contains)
+		}
+	}
+}
+
+func TestCheckFirewallRule(t *testing.T) {
+	policy := models.FirewallPolicy{ID: "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/firewallPolicies/fwp", Name: "fwp"}
+
+	networkRule := func(sources, destinations, ports []string) models.FirewallRule {
+		return models.FirewallRule{Name: "net", RuleType: "NetworkRule", SourceAddresses: sources, DestinationAddresses: destinations, DestinationPorts: ports, Protocols: []string{"Any"}}
+	}
+	natRule := func(sources []string, port, translatedPort string) models.FirewallRule {
+		return models.FirewallRule{Name: "dnat", RuleType: "NatRule", SourceAddresses: sources, DestinationPorts: []string{port}, TranslatedAddress: "10.0.1.4", TranslatedPort: translatedPort}
+	}
+	appRule := func(fqdns ...string) models.FirewallRule {
+		return models.FirewallRule{Name: "app", RuleType: "ApplicationRule", SourceAddresses: []string{"10.0.0.0/16"}, TargetFQDNs: fqdns, Protocols: []string{"Https:443"}}
+	}
+	anywhere := []string{"*"}
+
+	tests := []struct {
+		name   string
+		action string
+		rule   models.FirewallRule
+		want   []wantFinding
+	}{
+		{"Any-any on all ports", "Allow", networkRule(anywhere, anywhere, anywhere), []wantFinding{{SeverityHigh, "on all ports"}}},
+		{"Any-any on 1-65535", "Allow", networkRule(anywhere, []string{"0.0.0.0/0"}, []string{"1-65535"}), []wantFinding{{SeverityHigh, "on ports 1-65535"}}},
+		{"Any-any on 0-65535", "Allow", networkRule([]string{"Any"}, anywhere, []string{"0-65535"}), []wantFinding{{SeverityHigh, "on ports 0-65535"}}},
+		{"Any-any reported once", "Allow", networkRule(anywhere, anywhere, []string{"1-1000", "*"}), []wantFinding{{SeverityHigh, "on ports 1-1000"}}},
+		{"Any-any on a single port", "Allow", networkRule(anywhere, anywhere, []string{"443"}), nil},
+		{"Any-any on a narrow range", "Allow", networkRule(anywhere, anywhere, []string{"8000-8080"}), nil},
+		{"Specific destination", "Allow", networkRule(anywhere, []string{"10.0.1.4"}, anywhere), nil},
+		{"Specific source", "Allow", networkRule([]string{"10.0.0.0/16"}, anywhere, anywhere), nil},
+		{"Source IP group", "Allow", models.FirewallRule{Name: "net", RuleType: "NetworkRule", SourceIPGroups: []string{"ipg"}, DestinationAddresses: anywhere, DestinationPorts: anywhere}, nil},
+		{"Destination FQDN", "Allow", models.FirewallRule{Name: "net", RuleType: "NetworkRule", SourceAddresses: anywhere, DestinationFQDNs: []string{"db.contoso.com"}, DestinationPorts: anywhere}, nil},
+		{"Any-any deny", "Deny", networkRule(anywhere, anywhere, anywhere), nil},
+
+		{"DNAT RDP from anywhere", "DNAT", natRule(anywhere, "50000", "3389"), []wantFinding{{SeverityCritical, "RDP (port 3389)"}}},
+		{"DNAT SSH from a known source", "DNAT", natRule([]string{"203.0.113.10"}, "22", ""), []wantFinding{{SeverityHigh, "SSH (port 22)"}}},
+		{"DNAT Telnet", "DNAT", natRule(anywhere, "23", "23"), []wantFinding{{SeverityCritical, "Telnet (port 23)"}}},
+		{"DNAT WinRM HTTP", "DNAT", natRule(anywhere, "5985", ""), []wantFinding{{SeverityCritical, "WinRM (port 5985)"}}},
+		{"DNAT WinRM HTTPS", "DNAT", natRule(anywhere, "443", "5986"), []wantFinding{{SeverityCritical, "WinRM (port 5986)"}}},
+		{"DNAT management port translated away", "DNAT", natRule(anywhere, "3389", "8443"), nil},
+		{"DNAT web traffic", "DNAT", natRule(anywhere, "443", "443"), nil},
+
+		{"Any FQDN", "Allow", appRule("*"), []wantFinding{{SeverityHigh, "allows any FQDN"}}},
+		{"Top-level domain wildcard", "Allow", appRule("*.com"), []wantFinding{{SeverityMedium, "every domain under 'com'"}}},
+		{"Shared storage wildcard", "Allow", appRule("*.blob.core.windows.net"), []wantFinding{{SeverityLow, "'*.blob.core.windows.net'"}}},
+		{"Shared web app wildcard", "Allow", appRule("*.azurewebsites.net"), []wantFinding{{SeverityLow, "'*.azurewebsites.net'"}}},
+		{"One finding per wildcard", "Allow", appRule("www.contoso.com", "*", "*.org"), []wantFinding{{SeverityHigh, "any FQDN"}, {SeverityMedium, "under 'org'"}}},
+		{"Exact FQDN", "Allow", appRule("www.contoso.com"), nil},
+		{"Any FQDN denied", "Deny", appRule("*"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := models.FirewallRuleCollection{Name: "rc", Action: tt.action, Rules: []models.FirewallRule{tt.rule}}
+			findings := checkFirewallRule(policy, collection, tt.rule)
+			checkFindings(t, findings, tt.want...)
+			for _, f := range findings {
+				if f.Resource != "fwp" || f.Rule != "rc/"+tt.rule.Name {
+					t.Errorf("Finding should name the policy and rule, got %s %s", f.Resource, f.Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeFirewallPolicies(t *testing.T) {
+	const prefix = "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/firewallPolicies/"
+	anyAny := models.FirewallRule{Name: "any-any", RuleType: "NetworkRule", SourceAddresses: []string{"*"}, DestinationAddresses: []string{"*"}, DestinationPorts: []string{"*"}}
+
+	policies := []models.FirewallPolicy{
+		{
+			ID:            prefix + "base",
+			Name:          "base",
+			ChildPolicies: []string{prefix + "child"},
+			RuleCollectionGroups: []models.FirewallRuleCollectionGroup{{
+				Name:            "rcg",
+				RuleCollections: []models.FirewallRuleCollection{{Name: "rc", Action: "Allow", Rules: []models.FirewallRule{anyAny}}},
+			}},
+		},
+		{ID: prefix + "child", Name: "child", BasePolicyID: prefix + "base"},
+		{ID: prefix + "orphan", Name: "orphan", BasePolicyID: prefix + "elsewhere"},
+	}
+
+	checkFindings(t, analyzeFirewallPolicies(policies),
+		wantFinding{SeverityHigh, "(inherited by child)"},
+		wantFinding{SeverityInfo, "base policy 'elsewhere', which is outside the analyzed scope"},
+	)
+}
+
+func TestAnalyzeNSGRules(t *testing.T) {
+	nsg := func(rules ...models.SecurityRule) []models.NetworkSecurityGroup {
+		return []models.NetworkSecurityGroup{{ID: "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/nsg", Name: "nsg", SecurityRules: rules}}
+	}
+
+	tests := []struct {
+		name string
+		rule models.SecurityRule
+		want []wantFinding
+	}{
+		{
+			name: "Plural port ranges",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Allow", Description: "d", SourceAddressPrefix: "Internet", DestinationAddressPrefix: "*", DestinationPortRanges: []string{"443", "22"}},
+			want: []wantFinding{{SeverityCritical, "SSH (port 22)"}},
+		},
+		{
+			name: "Plural source prefixes",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Allow", Description: "d", SourceAddressPrefixes: []string{"10.0.0.0/8", "0.0.0.0/0"}, DestinationAddressPrefix: "10.0.1.4", DestinationPortRange: "3389"},
+			want: []wantFinding{{SeverityCritical, "RDP (port 3389)"}},
+		},
+		{
+			name: "Sensitive port inside a range",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Allow", Description: "d", SourceAddressPrefix: "*", DestinationAddressPrefix: "10.0.1.4", DestinationPortRange: "20-25"},
+			want: []wantFinding{{SeverityCritical, "SSH (port 22 in range 20-25)"}, {SeverityCritical, "Telnet (port 23 in range 20-25)"}, {SeverityHigh, "FTP (port 21 in range 20-25)"}},
+		},
+		{
+			name: "Wide open on 1-65535",
+			rule: models.SecurityRule{Name: "r", Priority: 100, Access: "Allow", SourceAddressPrefix: "*", DestinationAddressPrefix: "*", DestinationPortRange: "1-65535"},
+			want: []wantFinding{
+				{SeverityCritical, "All ports are exposed"},
+				{SeverityHigh, "any source to any destination on all ports"},
+				{SeverityMedium, "wide range of ports (1-65535)"},
+				{SeverityLow, "has no description"},
+				{SeverityMedium, "High priority (100)"},
+			},
+		},
+		{
+			name: "Internal source",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Allow", Description: "d", SourceAddressPrefixes: []string{"10.0.0.0/8"}, DestinationAddressPrefix: "*", DestinationPortRanges: []string{"22", "3389"}},
+		},
+		{
+			name: "Source application security group",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Allow", Description: "d", SourceASGs: []string{"asg"}, DestinationAddressPrefix: "*", DestinationPortRange: "22"},
+		},
+		{
+			name: "Deny rule",
+			rule: models.SecurityRule{Name: "r", Priority: 300, Access: "Deny", SourceAddressPrefix: "*", DestinationAddressPrefix: "*", DestinationPortRange: "*"},
+		},
+		{
+			name: "Default rule",
+			rule: models.SecurityRule{Name: "AllowVnetInBound", Priority: 65000, Access: "Allow", SourceAddressPrefix: "*", DestinationAddressPrefix: "*", DestinationPortRange: "*", IsDefault: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkFindings(t, analyzeNSGRules(nsg(tt.rule), nil), tt.want...)
+		})
+	}
+}
+
+func TestPortRanges(t *testing.T) {
+	tests := []struct {
+		portRange string
+		allPorts  bool
+		wide      bool
+		has22     bool
+	}{
+		{"*", true, true, true},
+		{"0-65535", true, true, true},
+		{"1-65535", true, true, true},
+		{"2-65535", false, true, true},
+		{"1-100", false, false, true},
+		{"1-101", false, true, true},
+		{"22", false, false, true},
+		{" 20 - 25 ", false, false, true},
+		{"25-20", false, false, false},
+		{"Any", false, false, false},
+		{"", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.portRange, func(t *testing.T) {
+			if got := hasAllPortsRange([]string{"443", tt.portRange}); got != tt.allPorts {
+				t.Errorf("hasAllPortsRange(%q) = %v, want %v", tt.portRange, got, tt.allPorts)
+			}
+			if got := isWidePortRange(tt.portRange); got != tt.wide {
+				t.Errorf("isWidePortRange(%q) = %v, want %v", tt.portRange, got, tt.wide)
+			}
+			if got := portInRange(22, tt.portRange); got != tt.has22 {
+				t.Errorf("portInRange(22, %q) = %v, want %v", tt.portRange, got, tt.has22)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// privateRangeDestinations are the destinations a hub route table uses to send
+// RFC 1918 traffic to a next hop, as routing intent does for private traffic
+var privateRangeDestinations = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+
+// hubNextHop is where a hub sends one kind of traffic: the next hop resource ID and
+// what configured it
+type hubNextHop struct {
+	id     string
+	source string // "routing intent" or "route table <name>"
+}
+
+// hubInternetNextHop returns where the hub sends internet traffic from connections
+// associated with routeTableID. Routing intent takes precedence over static routes.
+func hubInternetNextHop(hub models.VirtualHub, routeTableID string) hubNextHop {
+	if id := hub.RoutingIntent.NextHop(models.RoutingIntentInternet); id != "" {
+		return hubNextHop{id: id, source: "routing intent"}
+	}
+	return hubRouteNextHop(hub, routeTableID, func(destinations []string) bool {
+		for _, d := range destinations {
+			if d == "0.0.0.0/0" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// hubPrivateNextHop returns where the hub sends private traffic from connections
+// associated with routeTableID, or an empty next hop when the hub router forwards it directly
+func hubPrivateNextHop(hub models.VirtualHub, routeTableID string) hubNextHop {
+	if id := hub.RoutingIntent.NextHop(models.RoutingIntentPrivateTraffic); id != "" {
+		return hubNextHop{id: id, source: "routing intent"}
+	}
+	return hubRouteNextHop(hub, routeTableID, func(destinations []string) bool {
+		for _, d := range destinations {
+			for _, private := range privateRangeDestinations {
+				if prefixContains(private, d) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// hubRouteNextHop returns the next hop of the first route in the hub route table
+// whose destinations match
+func hubRouteNextHop(hub models.VirtualHub, routeTableID string, match func([]string) bool) hubNextHop {
+	for _, rt := range hub.RouteTables {
+		if routeTableID != "" && !strings.EqualFold(rt.ID, routeTableID) {
+			continue
+		}
+		for _, route := range rt.Routes {
+			if route.NextHop != "" && match(route.Destinations) {
+				return hubNextHop{id: route.NextHop, source: "route table " + rt.Name}
+			}
+		}
+	}
+	return hubNextHop{}
+}
+
+// describeNextHop names a next hop for reports, e.g. "fw-vhub (routing intent)"
+func describeNextHop(hop hubNextHop) string {
+	return fmt.Sprintf("%s (%s)", resourceName(hop.id), hop.source)
+}
+
+// hubRoutedVNets returns the lower-cased IDs of the VNets that learn their default
+// route from a Virtual WAN hub, i.e. whose connection has internet security enabled
+// and whose hub sends internet traffic to a next hop
+func hubRoutedVNets(topology *models.NetworkTopology) map[string]bool {
+	routed := make(map[string]bool)
+	for _, hub := range topology.VirtualHubs {
+		for _, conn := range hub.VNetConnections {
+			if conn.EnableInternetSecurity && hubInternetNextHop(hub, conn.AssociatedRouteTable).id != "" {
+				routed[strings.ToLower(conn.RemoteVNetID)] = true
+			}
+		}
+	}
+	return routed
+}
+
+// analyzeHubConnectivity describes, for every VNet connected to a Virtual WAN hub, how
+// its internet and private traffic leaves the hub and which other connected VNets
+// it can reach through the Virtual WAN
+func analyzeHubConnectivity(topology *models.NetworkTopology) []HubConnectivity {
+	vnetNames := make(map[string]string, len(topology.VirtualNetworks))
+	for _, vnet := range topology.VirtualNetworks {
+		vnetNames[strings.ToLower(vnet.ID)] = vnet.Name
+	}
+	vnetName := func(id string) string {
+		return valueOr(vnetNames[strings.ToLower(id)], resourceName(id))
+	}
+
+	// VNets connected to hubs of the same Virtual WAN can reach each other unless
+	// the Virtual WAN disallows VNet-to-VNet traffic
+	wans := make(map[string]models.VirtualWAN, len(topology.VirtualWANs))
+	for _, wan := range topology.VirtualWANs {
+		wans[strings.ToLower(wan.ID)] = wan
+	}
+	wanVNets := make(map[string][]string)
+	for _, hub := range topology.VirtualHubs {
+		key := strings.ToLower(valueOr(hub.VirtualWANID, hub.ID))
+		for _, conn := range hub.VNetConnections {
+			wanVNets[key] = append(wanVNets[key], conn.RemoteVNetID)
+		}
+	}
+
+	connectivity := []HubConnectivity{}
+	for _, hub := range topology.VirtualHubs {
+		key := strings.ToLower(valueOr(hub.VirtualWANID, hub.ID))
+		wan, wanCollected := wans[key]
+
+		for _, conn := range hub.VNetConnections {
+			c := HubConnectivity{
+				VNet:           vnetName(conn.RemoteVNetID),
+				VNetID:         conn.RemoteVNetID,
+				Hub:            hub.Name,
+				HubID:          hub.ID,
+				Connection:     conn.Name,
+				ReachableVNets: []string{},
+			}
+
+			internet := hubInternetNextHop(hub, conn.AssociatedRouteTable)
+			switch {
+			case internet.id == "":
+				c.InternetEgress = "VNet's own egress (no hub default route)"
+			case !conn.EnableInternetSecurity:
+				c.InternetEgress = "VNet's own egress (internet security disabled)"
+			default:
+				c.InternetEgress = describeNextHop(internet)
+			}
+
+			if private := hubPrivateNextHop(hub, conn.AssociatedRouteTable); private.id != "" {
+				c.PrivateTraffic = describeNextHop(private)
+			} else {
+				c.PrivateTraffic = "hub router (not inspected)"
+			}
+
+			if !wanCollected || wan.AllowVNetToVNetTraffic {
+				seen := map[string]bool{strings.ToLower(conn.RemoteVNetID): true}
+				for _, id := range wanVNets[key] {
+					if seen[strings.ToLower(id)] {
+						continue
+					}
+					seen[strings.ToLower(id)] = true
+					c.ReachableVNets = append(c.ReachableVNets, vnetName(id))
+				}
+				sort.Strings(c.ReachableVNets)
+			}
+
+			connectivity = append(connectivity, c)
+		}
+	}
+
+	return connectivity
+}
+
+// analyzeVirtualHubs checks that secured hubs actually send traffic through their
+// firewall and that connections do not bypass the hub's internet routing
+func analyzeVirtualHubs(topology *models.NetworkTopology) []SecurityFinding {
+	findings := []SecurityFinding{}
+
+	vnetNames := make(map[string]string, len(topology.VirtualNetworks))
+	for _, vnet := range topology.VirtualNetworks {
+		vnetNames[strings.ToLower(vnet.ID)] = vnet.Name
+	}
+
+	for _, hub := range topology.VirtualHubs {
+		if hub.AzureFirewallID != "" {
+			firewall := resourceName(hub.AzureFirewallID)
+			internet := hubInternetNextHop(hub, "")
+			private := hubPrivateNextHop(hub, "")
+
+			switch {
+			case !strings.EqualFold(internet.id, hub.AzureFirewallID) && !strings.EqualFold(private.id, hub.AzureFirewallID):
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityMedium,
+					Category:       CategoryMissingProtection,
+					Resource:       hub.Name,
+					ResourceID:     hub.ID,
+					Description:    fmt.Sprintf("Virtual hub '%s' is secured by Azure Firewall '%s', but neither routing intent nor a hub route table sends traffic to it, so no traffic is inspected", hub.Name, firewall),
+					Recommendation: "Configure routing intent on the hub with the firewall as next hop for internet and private traffic",
+				})
+			case hub.RoutingIntent.NextHop(models.RoutingIntentInternet) != "" && hub.RoutingIntent.NextHop(models.RoutingIntentPrivateTraffic) == "":
+				findings = append(findings, SecurityFinding{
+					Severity:       SeverityLow,
+					Category:       CategoryConfiguration,
+					Resource:       hub.Name,
+					ResourceID:     hub.ID,
+					Description:    fmt.Sprintf("Routing intent on virtual hub '%s' only covers internet traffic; traffic between connected VNets and branches is not inspected by '%s'", hub.Name, firewall),
+					Recommendation: "Add a private traffic routing policy if east-west traffic should be inspected",
+				})
+			}
+		}
+
+		internet := hubInternetNextHop(hub, "")
+		if internet.id == "" {
+			continue
+		}
+
+		// Connections without internet security are not sent the hub's default route
+		for _, conn := range hub.VNetConnections {
+			if conn.EnableInternetSecurity {
+				continue
+			}
+			vnet := valueOr(vnetNames[strings.ToLower(conn.RemoteVNetID)], resourceName(conn.RemoteVNetID))
+			findings = append(findings, SecurityFinding{
+				Severity:   SeverityMedium,
+				Category:   CategoryNetworkExposure,
+				Resource:   hub.Name,
+				ResourceID: conn.ID,
+				Rule:       conn.Name,
+				Description: fmt.Sprintf("VNet '%s' is connected to virtual hub '%s' with internet security disabled, so its internet traffic bypasses '%s'",
+					vnet, hub.Name, resourceName(internet.id)),
+				Recommendation: "Enable internet security on the hub connection so the VNet learns the hub's default route",
+			})
+		}
+		for _, gw := range hub.Gateways {
+			for _, conn := range gw.Connections {
+				if conn.EnableInternetSecurity {
+					continue
+				}
+				findings = append(findings, SecurityFinding{
+					Severity:   SeverityLow,
+					Category:   CategoryConfiguration,
+					Resource:   gw.Name,
+					ResourceID: conn.ID,
+					Rule:       conn.Name,
+					Description: fmt.Sprintf("%s connection '%s' on virtual hub '%s' does not propagate the hub's default route, so internet traffic from the branch is not sent through '%s'",
+						gw.Type, conn.Name, hub.Name, resourceName(internet.id)),
+					Recommendation: "Enable internet security on the connection if branch internet traffic should be inspected in Azure",
+				})
+			}
+		}
+	}
+
+	return findings
+}
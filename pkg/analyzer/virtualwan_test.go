@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// hubTopology has three hubs: one routed by routing intent despite a static default
+// route, one routed by static route tables only, and a secured hub that sends
+// nothing to its firewall
+func hubTopology() *models.NetworkTopology {
+	id := func(kind, name string) string { return testProviders + "/" + kind + "/" + name }
+	conn := func(hub, name, vnet, routeTable string, internetSecurity bool) models.HubVNetConnection {
+		c := models.HubVNetConnection{ID: id("virtualHubs", hub) + "/hubVirtualNetworkConnections/" + name, Name: name, RemoteVNetID: id("virtualNetworks", vnet), EnableInternetSecurity: internetSecurity}
+		if routeTable != "" {
+			c.AssociatedRouteTable = id("virtualHubs", hub) + "/hubRouteTables/" + routeTable
+		}
+		return c
+	}
+	route := func(nextHop string, destinations ...string) models.HubRoute {
+		return models.HubRoute{Name: "to-" + nextHop, DestinationType: "CIDR", Destinations: destinations, NextHopType: "ResourceId", NextHop: id("networkVirtualAppliances", nextHop)}
+	}
+
+	return &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{ID: id("virtualNetworks", "vnet-a"), Name: "vnet-a"},
+			{ID: id("virtualNetworks", "vnet-c"), Name: "vnet-c"},
+		},
+		VirtualWANs: []models.VirtualWAN{{ID: id("virtualWans", "wan1"), Name: "wan1", AllowVNetToVNetTraffic: true}},
+		VirtualHubs: []models.VirtualHub{
+			{
+				ID:              id("virtualHubs", "hub-intent"),
+				Name:            "hub-intent",
+				VirtualWANID:    id("virtualWans", "wan1"),
+				AzureFirewallID: id("azureFirewalls", "fw-intent"),
+				RoutingIntent: &models.HubRoutingIntent{RoutingPolicies: []models.HubRoutingPolicy{
+					{Name: "internet", Destinations: []string{"Internet"}, NextHop: id("azureFirewalls", "fw-intent")},
+					{Name: "private", Destinations: []string{"PrivateTraffic"}, NextHop: id("azureFirewalls", "fw-intent")},
+				}},
+				// Routing intent overrides this static default route
+				RouteTables: []models.HubRouteTable{{ID: id("virtualHubs", "hub-intent") + "/hubRouteTables/defaultRouteTable", Name: "defaultRouteTable",
+					Routes: []models.HubRoute{route("nva-legacy", "0.0.0.0/0", "10.0.0.0/8")}}},
+				VNetConnections: []models.HubVNetConnection{
+					conn("hub-intent", "conn-a", "vnet-a", "", true),
+					conn("hub-intent", "conn-b", "vnet-b", "", false),
+				},
+			},
+			{
+				ID:           id("virtualHubs", "hub-static"),
+				Name:         "hub-static",
+				VirtualWANID: id("virtualWans", "wan1"),
+				RouteTables: []models.HubRouteTable{
+					{ID: id("virtualHubs", "hub-static") + "/hubRouteTables/rt-egress", Name: "rt-egress", Routes: []models.HubRoute{
+						// 100.64.0.0/10 is not an RFC 1918 range
+						route("nva-cgnat", "100.64.0.0/10"),
+						route("nva-private", "10.1.0.0/16", "192.168.10.0/24"),
+						route("nva-egress", "0.0.0.0/0"),
+					}},
+					{ID: id("virtualHubs", "hub-static") + "/hubRouteTables/rt-other", Name: "rt-other", Routes: []models.HubRoute{
+						route("nva-other", "0.0.0.0/0"),
+						// 172.0.0.0/8 is wider than 172.16.0.0/12, so it is not private traffic
+						route("nva-wide", "172.0.0.0/8"),
+					}},
+				},
+				VNetConnections: []models.HubVNetConnection{
+					conn("hub-static", "conn-c", "vnet-c", "rt-egress", true),
+					conn("hub-static", "conn-d", "vnet-d", "rt-other", true),
+				},
+				Gateways: []models.HubGateway{{Name: "vpngw", Type: models.HubGatewayVPN, Connections: []models.HubGatewayConnection{
+					{ID: id("vpnGateways", "vpngw") + "/vpnConnections/branch", Name: "branch"},
+				}}},
+			},
+			{
+				ID:              id("virtualHubs", "hub-unrouted"),
+				Name:            "hub-unrouted",
+				VirtualWANID:    id("virtualWans", "wan2"),
+				AzureFirewallID: id("azureFirewalls", "fw-unrouted"),
+				VNetConnections: []models.HubVNetConnection{conn("hub-unrouted", "conn-e", "vnet-e", "", true)},
+			},
+		},
+	}
+}
+
+func TestAnalyzeHubConnectivity(t *testing.T) {
+	topology := hubTopology()
+	hubID := func(name string) string { return testProviders + "/virtualHubs/" + name }
+	vnetID := func(name string) string { return testProviders + "/virtualNetworks/" + name }
+
+	want := []HubConnectivity{
+		{VNet: "vnet-a", VNetID: vnetID("vnet-a"), Hub: "hub-intent", HubID: hubID("hub-intent"), Connection: "conn-a",
+			InternetEgress: "fw-intent (routing intent)", PrivateTraffic: "fw-intent (routing intent)",
+			ReachableVNets: []string{"vnet-b", "vnet-c", "vnet-d"}},
+		{VNet: "vnet-b", VNetID: vnetID("vnet-b"), Hub: "hub-intent", HubID: hubID("hub-intent"), Connection: "conn-b",
+			InternetEgress: "VNet's own egress (internet security disabled)", PrivateTraffic: "fw-intent (routing intent)",
+			ReachableVNets: []string{"vnet-a", "vnet-c", "vnet-d"}},
+		{VNet: "vnet-c", VNetID: vnetID("vnet-c"), Hub: "hub-static", HubID: hubID("hub-static"), Connection: "conn-c",
+			InternetEgress: "nva-egress (route table rt-egress)", PrivateTraffic: "nva-private (route table rt-egress)",
+			ReachableVNets: []string{"vnet-a", "vnet-b", "vnet-d"}},
+		{VNet: "vnet-d", VNetID: vnetID("vnet-d"), Hub: "hub-static", HubID: hubID("hub-static"), Connection: "conn-d",
+			InternetEgress: "nva-other (route table rt-other)", PrivateTraffic: "hub router (not inspected)",
+			ReachableVNets: []string{"vnet-a", "vnet-b", "vnet-c"}},
+		// wan2 was not collected, so its VNet-to-VNet setting is unknown
+		{VNet: "vnet-e", VNetID: vnetID("vnet-e"), Hub: "hub-unrouted", HubID: hubID("hub-unrouted"), Connection: "conn-e",
+			InternetEgress: "VNet's own egress (no hub default route)", PrivateTraffic: "hub router (not inspected)",
+			ReachableVNets: []string{}},
+	}
+
+	connectivity := analyzeHubConnectivity(topology)
+	if len(connectivity) != len(want) {
+		t.Fatalf("Expected %d connections, got %+v", len(want), connectivity)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(connectivity[i], want[i]) {
+			t.Errorf("connectivity[%d] =\n%+v\nwant\n%+v", i, connectivity[i], want[i])
+		}
+	}
+
+	t.Run("VNet-to-VNet traffic disallowed", func(t *testing.T) {
+		topology.VirtualWANs[0].AllowVNetToVNetTraffic = false
+		for _, c := range analyzeHubConnectivity(topology) {
+			if len(c.ReachableVNets) != 0 {
+				t.Errorf("%s should not reach other VNets, got %v", c.VNet, c.ReachableVNets)
+			}
+		}
+	})
+}
+
+func TestAnalyzeVirtualHubs(t *testing.T) {
+	topology := hubTopology()
+	checkFindings(t, analyzeVirtualHubs(topology),
+		wantFinding{SeverityMedium, "VNet 'vnet-b' is connected to virtual hub 'hub-intent' with internet security disabled, so its internet traffic bypasses 'fw-intent'"},
+		wantFinding{SeverityLow, "VPN connection 'branch' on virtual hub 'hub-static' does not propagate the hub's default route"},
+		wantFinding{SeverityMedium, "Virtual hub 'hub-unrouted' is secured by Azure Firewall 'fw-unrouted', but neither routing intent nor a hub route table sends traffic to it"},
+	)
+
+	t.Run("Routing intent for internet traffic only", func(t *testing.T) {
+		hub := &topology.VirtualHubs[0]
+		hub.RoutingIntent.RoutingPolicies = hub.RoutingIntent.RoutingPolicies[:1]
+		hub.VNetConnections = hub.VNetConnections[:1]
+		topology.VirtualHubs = topology.VirtualHubs[:1]
+
+		checkFindings(t, analyzeVirtualHubs(topology),
+			wantFinding{SeverityLow, "Routing intent on virtual hub 'hub-intent' only covers internet traffic"})
+	})
+
+	t.Run("Static route to the firewall", func(t *testing.T) {
+		hub := &topology.VirtualHubs[0]
+		hub.RoutingIntent = nil
+		hub.RouteTables[0].Routes[0].NextHop = hub.AzureFirewallID
+
+		checkFindings(t, analyzeVirtualHubs(topology))
+	})
+}
@@ -0,0 +1,48 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// GetBastionHosts retrieves all Azure Bastion hosts in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
+func (c *AzureClient) GetBastionHosts(ctx context.Context, resourceGroup string) ([]models.BastionHost, error) {
+	client, err := c.getBastionHostsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var bastions []models.BastionHost
+	var pager itemPager[armnetwork.BastionHost]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.BastionHost](c, graphBastionHosts, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListPager(nil), func(r armnetwork.BastionHostsClientListResponse) []*armnetwork.BastionHost {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListByResourceGroupPager(resourceGroup, nil), func(r armnetwork.BastionHostsClientListByResourceGroupResponse) []*armnetwork.BastionHost {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Bastion Hosts: %w", err)
+		}
+
+		for _, bastion := range page {
+			if bastion != nil {
+				bastions = append(bastions, c.extractBastionHost(bastion))
+			}
+		}
+	}
+
+	return bastions, nil
+}
@@ -0,0 +1,454 @@
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// CacheOptions configures the on-disk response cache
+type CacheOptions struct {
+	// Dir holds the cache files, one per cloud and collector backend,
+	// subscription, scope and resource type
+	Dir string
+
+	// TTL is how long a cached response is served before it is collected again
+	TTL time.Duration
+
+	// Refresh ignores cached responses but still stores fresh ones
+	Refresh bool
+}
+
+// DefaultCacheOptions returns a one-hour cache in the user's cache directory
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{Dir: DefaultCacheDir(), TTL: time.Hour}
+}
+
+// DefaultCacheDir returns the cache directory under the user's cache directory,
+// or the system temporary directory when there is none
+func DefaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "az-network-analyzer")
+}
+
+// cacheDepth is how deep cache entries sit below the cache directory:
+// namespace/subscription/scope/resourceType.json
+const cacheDepth = 4
+
+// ClearCache removes every cached response in dir. Only files that decode as
+// cache entries are removed, along with the directories they leave empty, so
+// other files in a mistaken directory are left alone.
+func ClearCache(dir string) error {
+	dir = filepath.Clean(dir)
+	var entries []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // unreadable directories cannot hold our entries
+		}
+		rel, _ := filepath.Rel(dir, path)
+		depth := len(strings.Split(rel, string(filepath.Separator)))
+		if d.IsDir() {
+			if path != dir && depth >= cacheDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if depth == cacheDepth && d.Type().IsRegular() && filepath.Ext(path) == ".json" && isCacheEntry(path) {
+			entries = append(entries, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to clear cache %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if err := os.Remove(entry); err != nil {
+			return fmt.Errorf("failed to clear cache %s: %w", dir, err)
+		}
+	}
+
+	// Drop the scope, subscription and namespace directories the entries lived
+	// in once they are empty
+	for _, entry := range entries {
+		for d := filepath.Dir(entry); d != dir && strings.HasPrefix(d, dir); d = filepath.Dir(d) {
+			if os.Remove(d) != nil {
+				break // not empty
+			}
+		}
+	}
+	return nil
+}
+
+// cacheEntry is the file format of one cached response
+type cacheEntry struct {
+	CachedAt time.Time       `json:"cachedAt"`
+	Items    json.RawMessage `json:"items"`
+}
+
+// isCacheEntry reports whether the file at path was written by the cache: a
+// JSON object with exactly the fields of a cacheEntry
+func isCacheEntry(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	var entry cacheEntry
+	if err := decoder.Decode(&entry); err != nil || decoder.More() {
+		return false
+	}
+	return !entry.CachedAt.IsZero() && len(entry.Items) > 0
+}
+
+// cacheHits records the oldest cached response served, shared by every
+// subscription view of a CachingCollector
+type cacheHits struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+func (h *cacheHits) record(at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.oldest.IsZero() || at.Before(h.oldest) {
+		h.oldest = at
+	}
+}
+
+// CachingCollector wraps a Collector and stores each response on disk, keyed by
+// the collector's cloud and backend, subscription, scope (resource group or whole
+// subscription) and resource type.
+// Responses younger than the TTL are served from disk instead of Azure. Failed
+// requests are never cached.
+type CachingCollector struct {
+	collector      Collector
+	subscriptionID string
+	opts           CacheOptions
+	hits           *cacheHits
+}
+
+// NewCachingCollector caches the responses of collector, which queries the
+// given subscription
+func NewCachingCollector(collector Collector, subscriptionID string, opts CacheOptions) *CachingCollector {
+	return &CachingCollector{
+		collector:      collector,
+		subscriptionID: subscriptionID,
+		opts:           opts,
+		hits:           &cacheHits{},
+	}
+}
+
+// Compile-time check that the cache preserves multi-subscription collection
+var _ SubscriptionCollector = (*CachingCollector)(nil)
+
+// ForSubscription returns a caching view of the wrapped collector for another
+// subscription
+func (c *CachingCollector) ForSubscription(subscriptionID string) (Collector, error) {
+	sc, ok := c.collector.(SubscriptionCollector)
+	if !ok {
+		return nil, fmt.Errorf("collector does not support multiple subscriptions")
+	}
+	collector, err := sc.ForSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return &CachingCollector{
+		collector:      collector,
+		subscriptionID: subscriptionID,
+		opts:           c.opts,
+		hits:           c.hits,
+	}, nil
+}
+
+// ServedFromCache returns the time the oldest cached response used so far was
+// collected, and false when everything came fresh from Azure
+func (c *CachingCollector) ServedFromCache() (time.Time, bool) {
+	c.hits.mu.Lock()
+	defer c.hits.mu.Unlock()
+	return c.hits.oldest, !c.hits.oldest.IsZero()
+}
+
+// cacheNamespacer is implemented by collectors whose responses depend on more
+// than the subscription and scope, such as the cloud an AzureClient talks to
+type cacheNamespacer interface {
+	cacheNamespace() string
+}
+
+// cacheNamespace identifies the cloud and collector backend the client reads
+// from, e.g. "management.azure.com-arm", so that switching either never serves
+// the other's responses
+func (c *AzureClient) cacheNamespace() string {
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if svc, ok := c.options.Cloud.Services[cloud.ResourceManager]; ok && svc.Endpoint != "" {
+		endpoint = svc.Endpoint
+	}
+	endpoint = strings.TrimSuffix(strings.TrimPrefix(endpoint, "https://"), "/")
+
+	backend := CollectorARM
+	if c.graph != nil {
+		backend = CollectorGraph
+	}
+	return endpoint + "-" + backend
+}
+
+// path returns the cache file for a resource type in a scope
+func (c *CachingCollector) path(scope, resourceType string) string {
+	namespace := "default"
+	if n, ok := c.collector.(cacheNamespacer); ok {
+		namespace = n.cacheNamespace()
+	}
+	return filepath.Join(c.opts.Dir, cacheName(namespace), cacheName(c.subscriptionID), cacheName(scope), resourceType+".json")
+}
+
+// cacheName turns a namespace, subscription ID or resource group name into a
+// single path segment. Resource group names are case-insensitive, so names are
+// lower-cased; any byte other than a-z, 0-9, '-' and a '.' after the first is
+// escaped as _XX so that distinct names never share a directory.
+func cacheName(name string) string {
+	var b strings.Builder
+	for i, ch := range []byte(strings.ToLower(name)) {
+		if ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '.' && i > 0 {
+			b.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&b, "_%02x", ch)
+		}
+	}
+	return b.String()
+}
+
+// cached serves fetch's result from the cache when a fresh entry exists, and
+// otherwise calls fetch and stores its result
+func cached[T any](ctx context.Context, c *CachingCollector, scope, resourceType string, fetch func(context.Context) (T, error)) (T, error) {
+	path := c.path(scope, resourceType)
+
+	if !c.opts.Refresh {
+		if items, at, ok := readCache[T](path, c.opts.TTL); ok {
+			c.hits.record(at)
+			return items, nil
+		}
+	}
+
+	items, err := fetch(ctx)
+	if err != nil {
+		return items, err
+	}
+	// A cache that cannot be written only costs the next run a fresh collection
+	_ = writeCache(path, items)
+	return items, nil
+}
+
+// readCache returns the cached items at path when they are younger than ttl
+func readCache[T any](path string, ttl time.Duration) (T, time.Time, bool) {
+	var items T
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return items, time.Time{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.CachedAt) > ttl {
+		return items, time.Time{}, false
+	}
+	if err := json.Unmarshal(entry.Items, &items); err != nil {
+		return items, time.Time{}, false
+	}
+	return items, entry.CachedAt, true
+}
+
+// writeCache stores items at path, replacing any previous entry atomically
+func writeCache(path string, items any) error {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cacheEntry{CachedAt: time.Now(), Items: raw})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// resourceGroupScope names the cache scope of a resource group, or of the whole
+// subscription when resourceGroup is empty
+func resourceGroupScope(resourceGroup string) string {
+	if resourceGroup == "" {
+		return "subscription"
+	}
+	return "rg-" + resourceGroup
+}
+
+func (c *CachingCollector) GetVirtualNetworks(ctx context.Context, resourceGroup string) ([]models.VirtualNetwork, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "virtualNetworks", func(ctx context.Context) ([]models.VirtualNetwork, error) {
+		return c.collector.GetVirtualNetworks(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetNetworkSecurityGroups(ctx context.Context, resourceGroup string) ([]models.NetworkSecurityGroup, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "networkSecurityGroups", func(ctx context.Context) ([]models.NetworkSecurityGroup, error) {
+		return c.collector.GetNetworkSecurityGroups(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetApplicationSecurityGroups(ctx context.Context, resourceGroup string) ([]models.ApplicationSecurityGroup, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "applicationSecurityGroups", func(ctx context.Context) ([]models.ApplicationSecurityGroup, error) {
+		return c.collector.GetApplicationSecurityGroups(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetPrivateEndpoints(ctx context.Context, resourceGroup string) ([]models.PrivateEndpoint, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "privateEndpoints", func(ctx context.Context) ([]models.PrivateEndpoint, error) {
+		return c.collector.GetPrivateEndpoints(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetPrivateLinkServices(ctx context.Context, resourceGroup string) ([]models.PrivateLinkService, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "privateLinkServices", func(ctx context.Context) ([]models.PrivateLinkService, error) {
+		return c.collector.GetPrivateLinkServices(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetNetworkInterfaces(ctx context.Context, resourceGroup string) ([]models.NetworkInterface, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "networkInterfaces", func(ctx context.Context) ([]models.NetworkInterface, error) {
+		return c.collector.GetNetworkInterfaces(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetPublicIPAddresses(ctx context.Context, resourceGroup string) ([]models.PublicIPAddress, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "publicIPAddresses", func(ctx context.Context) ([]models.PublicIPAddress, error) {
+		return c.collector.GetPublicIPAddresses(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetPrivateDNSZones(ctx context.Context, resourceGroup string) ([]models.PrivateDNSZone, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "privateDnsZones", func(ctx context.Context) ([]models.PrivateDNSZone, error) {
+		return c.collector.GetPrivateDNSZones(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetRouteTables(ctx context.Context, resourceGroup string) ([]models.RouteTable, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "routeTables", func(ctx context.Context) ([]models.RouteTable, error) {
+		return c.collector.GetRouteTables(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetNATGateways(ctx context.Context, resourceGroup string) ([]models.NATGateway, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "natGateways", func(ctx context.Context) ([]models.NATGateway, error) {
+		return c.collector.GetNATGateways(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetVPNGateways(ctx context.Context, resourceGroup string) ([]models.VPNGateway, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "vpnGateways", func(ctx context.Context) ([]models.VPNGateway, error) {
+		return c.collector.GetVPNGateways(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetLocalNetworkGateways(ctx context.Context, resourceGroup string) ([]models.LocalNetworkGateway, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "localNetworkGateways", func(ctx context.Context) ([]models.LocalNetworkGateway, error) {
+		return c.collector.GetLocalNetworkGateways(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetExpressRouteCircuits(ctx context.Context, resourceGroup string) ([]models.ExpressRouteCircuit, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "expressRouteCircuits", func(ctx context.Context) ([]models.ExpressRouteCircuit, error) {
+		return c.collector.GetExpressRouteCircuits(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetLoadBalancers(ctx context.Context, resourceGroup string) ([]models.LoadBalancer, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "loadBalancers", func(ctx context.Context) ([]models.LoadBalancer, error) {
+		return c.collector.GetLoadBalancers(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetApplicationGateways(ctx context.Context, resourceGroup string) ([]models.ApplicationGateway, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "applicationGateways", func(ctx context.Context) ([]models.ApplicationGateway, error) {
+		return c.collector.GetApplicationGateways(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetAzureFirewalls(ctx context.Context, resourceGroup string) ([]models.AzureFirewall, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "azureFirewalls", func(ctx context.Context) ([]models.AzureFirewall, error) {
+		return c.collector.GetAzureFirewalls(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetFirewallPolicies(ctx context.Context, resourceGroup string) ([]models.FirewallPolicy, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "firewallPolicies", func(ctx context.Context) ([]models.FirewallPolicy, error) {
+		return c.collector.GetFirewallPolicies(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetBastionHosts(ctx context.Context, resourceGroup string) ([]models.BastionHost, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "bastionHosts", func(ctx context.Context) ([]models.BastionHost, error) {
+		return c.collector.GetBastionHosts(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetVirtualWANs(ctx context.Context, resourceGroup string) ([]models.VirtualWAN, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "virtualWans", func(ctx context.Context) ([]models.VirtualWAN, error) {
+		return c.collector.GetVirtualWANs(ctx, resourceGroup)
+	})
+}
+
+func (c *CachingCollector) GetVirtualHubs(ctx context.Context, resourceGroup string) ([]models.VirtualHub, error) {
+	return cached(ctx, c, resourceGroupScope(resourceGroup), "virtualHubs", func(ctx context.Context) ([]models.VirtualHub, error) {
+		return c.collector.GetVirtualHubs(ctx, resourceGroup)
+	})
+}
+
+// GetNetworkWatcherInsights is cached per set of locations
+func (c *CachingCollector) GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error) {
+	normalized := make([]string, len(locations))
+	for i, location := range locations {
+		normalized[i] = normalizeLocation(location)
+	}
+	sort.Strings(normalized)
+	sum := sha256.Sum256([]byte(strings.Join(normalized, ",")))
+	resourceType := "networkWatcher-" + hex.EncodeToString(sum[:6])
+
+	return cached(ctx, c, resourceGroupScope(""), resourceType, func(ctx context.Context) (*models.NetworkWatcherInsights, error) {
+		return c.collector.GetNetworkWatcherInsights(ctx, locations)
+	})
+}
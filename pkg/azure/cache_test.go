@@ -0,0 +1,239 @@
+package azure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+func TestCachingCollector(t *testing.T) {
+	srv := newFakeARM(t)
+	client := newFakeARMClient(t, srv, testRetryOptions())
+	fresh := topologyJSON(t, collectFakeTopology(t, client))
+
+	newCache := func(opts CacheOptions) *CachingCollector {
+		return NewCachingCollector(newFakeARMClient(t, srv, testRetryOptions()), fakeSubscription, opts)
+	}
+	collect := func(cache *CachingCollector) (string, int) {
+		before := len(srv.Requests())
+		topology := collectFakeTopology(t, cache)
+		return topologyJSON(t, topology), len(srv.Requests()) - before
+	}
+
+	t.Run("Second run is served from the cache", func(t *testing.T) {
+		opts := CacheOptions{Dir: t.TempDir(), TTL: time.Hour}
+
+		first := newCache(opts)
+		if got, requests := collect(first); got != fresh || requests == 0 {
+			t.Fatalf("First run should collect from ARM (%d requests) and match an uncached run", requests)
+		}
+		if _, ok := first.ServedFromCache(); ok {
+			t.Error("First run should not report cached data")
+		}
+
+		second := newCache(opts)
+		got, requests := collect(second)
+		if requests != 0 {
+			t.Errorf("Expected no ARM requests from a warm cache, got %d", requests)
+		}
+		if got != fresh {
+			t.Errorf("Cached topology differs from a fresh one:\n%s\nwant:\n%s", got, fresh)
+		}
+		cachedAt, ok := second.ServedFromCache()
+		if !ok || time.Since(cachedAt) > time.Minute {
+			t.Errorf("Expected a recent cache time, got %v, %v", cachedAt, ok)
+		}
+
+		entry := second.path("rg-rg-network", "virtualNetworks")
+		if _, err := os.Stat(entry); err != nil {
+			t.Errorf("Expected cache entry %s: %v", entry, err)
+		}
+	})
+
+	t.Run("Expired entries are collected again", func(t *testing.T) {
+		dir := t.TempDir()
+		collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour}))
+
+		expired := newCache(CacheOptions{Dir: dir, TTL: 0})
+		if _, requests := collect(expired); requests == 0 {
+			t.Error("Expected ARM requests once the TTL has passed")
+		}
+		if _, ok := expired.ServedFromCache(); ok {
+			t.Error("Expired entries should not count as cached data")
+		}
+	})
+
+	t.Run("Refresh ignores the cache but updates it", func(t *testing.T) {
+		dir := t.TempDir()
+		collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour}))
+
+		refreshed := newCache(CacheOptions{Dir: dir, TTL: time.Hour, Refresh: true})
+		if _, requests := collect(refreshed); requests == 0 {
+			t.Error("Expected ARM requests with Refresh")
+		}
+		if _, ok := refreshed.ServedFromCache(); ok {
+			t.Error("Refreshed run should not report cached data")
+		}
+		if _, requests := collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour})); requests != 0 {
+			t.Errorf("Expected the refreshed entries to be served, got %d requests", requests)
+		}
+	})
+
+	t.Run("Corrupt entries are collected again", func(t *testing.T) {
+		dir := t.TempDir()
+		collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour}))
+		entry := newCache(CacheOptions{Dir: dir}).path("rg-rg-network", "virtualNetworks")
+		if err := os.WriteFile(entry, []byte("{not json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, requests := collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour}))
+		if requests == 0 || got != fresh {
+			t.Errorf("Expected the corrupt entry to be collected again (%d requests)", requests)
+		}
+	})
+
+	t.Run("Clear removes cached entries", func(t *testing.T) {
+		dir := t.TempDir()
+		collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour}))
+		unrelated := filepath.Join(dir, "notes.txt")
+		if err := os.WriteFile(unrelated, []byte("keep"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		// JSON laid out like a cache entry but not written by the cache
+		project := filepath.Join(dir, "projects", "app")
+		if err := os.MkdirAll(project, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		userJSON := filepath.Join(project, "package.json")
+		if err := os.WriteFile(userJSON, []byte(`{"name": "app", "cachedAt": "2024-01-01T00:00:00Z"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		emptyDir := filepath.Join(dir, "empty", "dir")
+		if err := os.MkdirAll(emptyDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ClearCache(dir); err != nil {
+			t.Fatalf("ClearCache failed: %v", err)
+		}
+		namespace := filepath.Dir(filepath.Dir(filepath.Dir(newCache(CacheOptions{Dir: dir}).path("rg-rg-network", "virtualNetworks"))))
+		if _, err := os.Stat(namespace); !os.IsNotExist(err) {
+			t.Errorf("Expected the cached entries and their directories to be removed, got %v", err)
+		}
+		for _, path := range []string{unrelated, userJSON, emptyDir} {
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("ClearCache removed %s, which it did not write: %v", path, err)
+			}
+		}
+		if _, requests := collect(newCache(CacheOptions{Dir: dir, TTL: time.Hour})); requests == 0 {
+			t.Error("Expected ARM requests after clearing the cache")
+		}
+	})
+}
+
+func TestClearCacheMissingDir(t *testing.T) {
+	if err := ClearCache(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("Clearing a cache that was never written should succeed, got %v", err)
+	}
+}
+
+func TestCachingCollectorErrors(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-network"}}
+
+	failing := NewCachingCollector(&failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNSGs: true}, "test-sub", CacheOptions{Dir: dir, TTL: time.Hour})
+	topology, err := CollectTopology(ctx, failing, scope)
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+	if len(topology.CollectionErrors) != 1 {
+		t.Fatalf("Expected the NSG failure to be recorded, got %v", topology.CollectionErrors)
+	}
+
+	if _, err := os.Stat(failing.path("rg-rg-network", "networkSecurityGroups")); !os.IsNotExist(err) {
+		t.Errorf("Failed requests should not be cached, got %v", err)
+	}
+	if _, err := os.Stat(failing.path("rg-rg-network", "virtualNetworks")); err != nil {
+		t.Errorf("Successful requests should be cached: %v", err)
+	}
+}
+
+func TestCachingCollectorSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	scope := CollectionScope{SubscriptionIDs: []string{"sub-conn", "sub-app"}, ResourceGroups: []string{"rg-network"}}
+
+	if _, err := CollectTopology(ctx, NewCachingCollector(NewMockAzureClient("sub-conn"), "sub-conn", CacheOptions{Dir: dir, TTL: time.Hour}), scope); err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+	for _, sub := range scope.SubscriptionIDs {
+		view := NewCachingCollector(NewMockAzureClient(sub), sub, CacheOptions{Dir: dir})
+		if _, err := os.Stat(view.path("rg-rg-network", "virtualNetworks")); err != nil {
+			t.Errorf("Expected cache entries for %s: %v", sub, err)
+		}
+	}
+
+	cache := NewCachingCollector(NewMockAzureClient("sub-conn"), "sub-conn", CacheOptions{Dir: dir, TTL: time.Hour})
+	if _, err := CollectTopology(ctx, cache, scope); err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+	if _, ok := cache.ServedFromCache(); !ok {
+		t.Error("Expected cache hits from every subscription view to be reported")
+	}
+}
+
+func TestCachingCollectorNamespaces(t *testing.T) {
+	srv := newFakeARM(t)
+	dir := t.TempDir()
+	opts := CacheOptions{Dir: dir, TTL: time.Hour}
+
+	collectFakeTopology(t, NewCachingCollector(newFakeARMClient(t, srv, testRetryOptions()), fakeSubscription, opts))
+
+	graphClient := newFakeARMClient(t, srv, testRetryOptions())
+	graphClient.UseResourceGraph()
+	graph := NewCachingCollector(graphClient, fakeSubscription, opts)
+	collectFakeTopology(t, graph)
+	if _, ok := graph.ServedFromCache(); ok {
+		t.Error("The Resource Graph collector should not be served responses cached by the ARM collector")
+	}
+
+	public, err := NewAzureClientWithOptions(ClientOptions{Credential: staticCredential{}}, fakeSubscription)
+	if err != nil {
+		t.Fatal(err)
+	}
+	government, err := NewAzureClientWithOptions(ClientOptions{Credential: staticCredential{}, Cloud: cloud.AzureGovernment}, fakeSubscription)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if public.cacheNamespace() != "management.azure.com-arm" {
+		t.Errorf("Unexpected namespace for the public cloud: %q", public.cacheNamespace())
+	}
+	if public.cacheNamespace() == government.cacheNamespace() {
+		t.Errorf("Clouds should not share cached responses, both use %q", public.cacheNamespace())
+	}
+}
+
+func TestCacheName(t *testing.T) {
+	names := []string{"rg_app", "rg.app", "rg(app)", "rg)app(", "rg app", "rg-app", "rg-äpp", "rg-_e4pp", ".", ".."}
+	seen := make(map[string]string)
+	for _, name := range names {
+		got := cacheName(name)
+		if other, ok := seen[got]; ok {
+			t.Errorf("cacheName(%q) and cacheName(%q) both give %q", name, other, got)
+		}
+		seen[got] = name
+		if strings.ContainsAny(got, "/\\ ()") || strings.HasPrefix(got, ".") {
+			t.Errorf("cacheName(%q) = %q is not a safe path segment", name, got)
+		}
+	}
+	if cacheName("RG-App") != cacheName("rg-app") {
+		t.Error("Resource group names are case-insensitive and should share a directory")
+	}
+}
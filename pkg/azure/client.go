@@ -1,227 +1,841 @@
 package azure
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"azure-network-analyzer/pkg/models"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 )
 
-// AzureClient wraps Azure SDK clients for network resource operations
+// AzureClient wraps Azure SDK clients for network resource operations.
+// It holds one set of ARM clients per subscription; the Get* collectors query
+// the current subscription and ForSubscription switches between them.
 type AzureClient struct {
-	cred           *azidentity.DefaultAzureCredential
-	subscriptionID string
+	cred            azcore.TokenCredential
+	subscriptionID  string
+	subscriptionIDs []string
+
+	// concurrency bounds the goroutines fetching per-item sub-resources such as
+	// gateway connections; limiter bounds the requests they have in flight
+	concurrency int
+	limiter     *requestLimiter
+
+	// options configures the retry policy and endpoint of every ARM client, and
+	// stats counts the requests they make across all subscriptions
+	options *arm.ClientOptions
+	stats   *callStats
+
+	// graph, when set, lists top-level resources from a Resource Graph snapshot
+	// instead of the per-type ARM list operations
+	graph *graphSnapshot
+
+	// clients holds the ARM clients for every subscription, keyed by lower-cased
+	// subscription ID, and is shared by every view returned from ForSubscription
+	clients map[string]*armClients
+	arm     *armClients
+}
+
+// armClients caches the ARM clients for a single subscription
+type armClients struct {
+	// mu guards lazy client initialization when collectors run concurrently
+	mu sync.Mutex
 
 	// Cached clients - lazily initialized
 	vnetsClient            *armnetwork.VirtualNetworksClient
 	subnetsClient          *armnetwork.SubnetsClient
 	peeringsClient         *armnetwork.VirtualNetworkPeeringsClient
 	nsgsClient             *armnetwork.SecurityGroupsClient
+	asgsClient             *armnetwork.ApplicationSecurityGroupsClient
 	privateEndpointsClient *armnetwork.PrivateEndpointsClient
+	privateLinkSvcsClient  *armnetwork.PrivateLinkServicesClient
+	interfacesClient       *armnetwork.InterfacesClient
+	publicIPsClient        *armnetwork.PublicIPAddressesClient
 	routeTablesClient      *armnetwork.RouteTablesClient
 	routesClient           *armnetwork.RoutesClient
 	natGatewaysClient      *armnetwork.NatGatewaysClient
 	vpnGatewaysClient      *armnetwork.VirtualNetworkGatewaysClient
 	connectionsClient      *armnetwork.VirtualNetworkGatewayConnectionsClient
+	localNetworkGWsClient  *armnetwork.LocalNetworkGatewaysClient
 	erCircuitsClient       *armnetwork.ExpressRouteCircuitsClient
 	erPeeringsClient       *armnetwork.ExpressRouteCircuitPeeringsClient
 	erAuthorizationsClient *armnetwork.ExpressRouteCircuitAuthorizationsClient
+	routeFiltersClient     *armnetwork.RouteFiltersClient
 	loadBalancersClient    *armnetwork.LoadBalancersClient
 	appGatewaysClient      *armnetwork.ApplicationGatewaysClient
 	azureFirewallsClient   *armnetwork.AzureFirewallsClient
+	fwPoliciesClient       *armnetwork.FirewallPoliciesClient
+	fwRuleGroupsClient     *armnetwork.FirewallPolicyRuleCollectionGroupsClient
+	bastionHostsClient     *armnetwork.BastionHostsClient
+	virtualWANsClient      *armnetwork.VirtualWansClient
+	virtualHubsClient      *armnetwork.VirtualHubsClient
+	hubVNetConnsClient     *armnetwork.HubVirtualNetworkConnectionsClient
+	hubRouteTablesClient   *armnetwork.HubRouteTablesClient
+	routingIntentClient    *armnetwork.RoutingIntentClient
+	hubVPNGatewaysClient   *armnetwork.VPNGatewaysClient
+	erGatewaysClient       *armnetwork.ExpressRouteGatewaysClient
+	watchersClient         *armnetwork.WatchersClient
+	flowLogsClient         *armnetwork.FlowLogsClient
+	connMonitorsClient     *armnetwork.ConnectionMonitorsClient
+	packetCapturesClient   *armnetwork.PacketCapturesClient
+	resourceGroupsClient   *armresources.ResourceGroupsClient
+	privateZonesClient     *armprivatedns.PrivateZonesClient
+	dnsVNetLinksClient     *armprivatedns.VirtualNetworkLinksClient
+	dnsRecordSetsClient    *armprivatedns.RecordSetsClient
 }
 
-// NewAzureClient creates a new Azure client with DefaultAzureCredential
-func NewAzureClient(subscriptionID string) (*AzureClient, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+// NewAzureClient creates a new Azure client with DefaultAzureCredential for one or
+// more subscriptions using the default options. The first subscription is the one
+// queried by the Get* methods; more can be added later with AddManagementGroup.
+func NewAzureClient(subscriptionIDs ...string) (*AzureClient, error) {
+	return NewAzureClientWithOptions(DefaultClientOptions(), subscriptionIDs...)
+}
+
+// NewAzureClientWithOptions creates a new Azure client for one or more subscriptions
+// with the given retry policy, timeouts, credential and endpoint
+func NewAzureClientWithOptions(opts ClientOptions, subscriptionIDs ...string) (*AzureClient, error) {
+	if opts.ReplayDir != "" {
+		replay, err := newReplayTransport(opts.ReplayDir)
+		if err != nil {
+			return nil, err
+		}
+		opts.Transport = replay
+		opts.Credential = replayCredential{}
+	}
+
+	cred := opts.Credential
+	if cred == nil {
+		newCred, err := NewCredential(opts.Authentication, policy.ClientOptions{Cloud: opts.Cloud, Transport: opts.Transport})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+		}
+		cred = newCred
+	}
+
+	// Wrap the transport after creating the credential so tokens are never recorded
+	if opts.RecordDir != "" && opts.ReplayDir == "" {
+		recorder, err := newRecordingTransport(opts.RecordDir, opts.Transport)
+		if err != nil {
+			return nil, err
+		}
+		opts.Transport = recorder
+	}
+
+	stats := newCallStats()
+	limiter := newRequestLimiter(DefaultConcurrency)
+	c := &AzureClient{
+		cred:        cred,
+		concurrency: DefaultConcurrency,
+		limiter:     limiter,
+		options:     opts.armOptions(stats, limiter),
+		stats:       stats,
+		clients:     make(map[string]*armClients),
+		arm:         &armClients{},
+	}
+	for _, id := range subscriptionIDs {
+		c.addSubscription(id)
+	}
+
+	return c, nil
+}
+
+// addSubscription registers a subscription, ignoring duplicates. The first
+// subscription added becomes the current one.
+func (c *AzureClient) addSubscription(subscriptionID string) {
+	subscriptionID = strings.TrimSpace(subscriptionID)
+	key := strings.ToLower(subscriptionID)
+	if key == "" {
+		return
+	}
+	if _, exists := c.clients[key]; exists {
+		return
+	}
+
+	c.clients[key] = &armClients{}
+	c.subscriptionIDs = append(c.subscriptionIDs, subscriptionID)
+
+	if c.subscriptionID == "" {
+		c.subscriptionID = subscriptionID
+		c.arm = c.clients[key]
+	}
+}
+
+// SubscriptionIDs returns every subscription the client can collect from
+func (c *AzureClient) SubscriptionIDs() []string {
+	return append([]string(nil), c.subscriptionIDs...)
+}
+
+// ForSubscription returns a collector that queries the given subscription,
+// sharing this client's credential and cached ARM clients
+func (c *AzureClient) ForSubscription(subscriptionID string) (Collector, error) {
+	arm, exists := c.clients[strings.ToLower(subscriptionID)]
+	if !exists {
+		return nil, fmt.Errorf("subscription %s is not configured on this client", subscriptionID)
 	}
 
 	return &AzureClient{
-		cred:           cred,
-		subscriptionID: subscriptionID,
+		cred:            c.cred,
+		subscriptionID:  subscriptionID,
+		subscriptionIDs: c.subscriptionIDs,
+		concurrency:     c.concurrency,
+		limiter:         c.limiter,
+		options:         c.options,
+		stats:           c.stats,
+		graph:           c.graph,
+		clients:         c.clients,
+		arm:             arm,
 	}, nil
 }
 
+// AddManagementGroup resolves every subscription beneath a management group,
+// including those in nested groups, and adds them to the client
+func (c *AzureClient) AddManagementGroup(ctx context.Context, managementGroupID string) error {
+	client, err := armmanagementgroups.NewClient(c.cred, c.options)
+	if err != nil {
+		return fmt.Errorf("failed to create Management Groups client: %w", err)
+	}
+
+	found := 0
+	pager := client.NewGetDescendantsPager(managementGroupID, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get next page of management group %s descendants: %w", managementGroupID, err)
+		}
+
+		for _, d := range page.Value {
+			if subscriptionID := extractSubscriptionID(safeString(d.ID)); subscriptionID != "" {
+				c.addSubscription(subscriptionID)
+				found++
+			}
+		}
+	}
+
+	if found == 0 {
+		return fmt.Errorf("management group %s contains no subscriptions", managementGroupID)
+	}
+	return nil
+}
+
+// APICallStats returns the number of ARM calls, retries and throttled responses
+// per resource type made so far, across every subscription
+func (c *AzureClient) APICallStats() []APICallStats {
+	if c.stats == nil {
+		return nil
+	}
+	return c.stats.snapshot()
+}
+
+// SetConcurrency limits how many ARM requests are in flight at once across the
+// client and every view returned from ForSubscription. Call it before collecting.
+func (c *AzureClient) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.concurrency = n
+	if c.limiter != nil {
+		c.limiter.setLimit(n)
+	}
+}
+
 // Client factory methods - lazily initialize clients as needed
 
 func (c *AzureClient) getVNetsClient() (*armnetwork.VirtualNetworksClient, error) {
-	if c.vnetsClient == nil {
-		client, err := armnetwork.NewVirtualNetworksClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.vnetsClient == nil {
+		client, err := armnetwork.NewVirtualNetworksClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create VNets client: %w", err)
 		}
-		c.vnetsClient = client
+		c.arm.vnetsClient = client
 	}
-	return c.vnetsClient, nil
+	return c.arm.vnetsClient, nil
 }
 
 func (c *AzureClient) getSubnetsClient() (*armnetwork.SubnetsClient, error) {
-	if c.subnetsClient == nil {
-		client, err := armnetwork.NewSubnetsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.subnetsClient == nil {
+		client, err := armnetwork.NewSubnetsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Subnets client: %w", err)
 		}
-		c.subnetsClient = client
+		c.arm.subnetsClient = client
 	}
-	return c.subnetsClient, nil
+	return c.arm.subnetsClient, nil
 }
 
 func (c *AzureClient) getPeeringsClient() (*armnetwork.VirtualNetworkPeeringsClient, error) {
-	if c.peeringsClient == nil {
-		client, err := armnetwork.NewVirtualNetworkPeeringsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.peeringsClient == nil {
+		client, err := armnetwork.NewVirtualNetworkPeeringsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Peerings client: %w", err)
 		}
-		c.peeringsClient = client
+		c.arm.peeringsClient = client
 	}
-	return c.peeringsClient, nil
+	return c.arm.peeringsClient, nil
 }
 
 func (c *AzureClient) getNSGsClient() (*armnetwork.SecurityGroupsClient, error) {
-	if c.nsgsClient == nil {
-		client, err := armnetwork.NewSecurityGroupsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.nsgsClient == nil {
+		client, err := armnetwork.NewSecurityGroupsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create NSGs client: %w", err)
 		}
-		c.nsgsClient = client
+		c.arm.nsgsClient = client
 	}
-	return c.nsgsClient, nil
+	return c.arm.nsgsClient, nil
+}
+
+func (c *AzureClient) getASGsClient() (*armnetwork.ApplicationSecurityGroupsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.asgsClient == nil {
+		client, err := armnetwork.NewApplicationSecurityGroupsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Application Security Groups client: %w", err)
+		}
+		c.arm.asgsClient = client
+	}
+	return c.arm.asgsClient, nil
 }
 
 func (c *AzureClient) getPrivateEndpointsClient() (*armnetwork.PrivateEndpointsClient, error) {
-	if c.privateEndpointsClient == nil {
-		client, err := armnetwork.NewPrivateEndpointsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.privateEndpointsClient == nil {
+		client, err := armnetwork.NewPrivateEndpointsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Private Endpoints client: %w", err)
 		}
-		c.privateEndpointsClient = client
+		c.arm.privateEndpointsClient = client
+	}
+	return c.arm.privateEndpointsClient, nil
+}
+
+func (c *AzureClient) getPrivateLinkServicesClient() (*armnetwork.PrivateLinkServicesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.privateLinkSvcsClient == nil {
+		client, err := armnetwork.NewPrivateLinkServicesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Private Link Services client: %w", err)
+		}
+		c.arm.privateLinkSvcsClient = client
+	}
+	return c.arm.privateLinkSvcsClient, nil
+}
+
+func (c *AzureClient) getInterfacesClient() (*armnetwork.InterfacesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.interfacesClient == nil {
+		client, err := armnetwork.NewInterfacesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Network Interfaces client: %w", err)
+		}
+		c.arm.interfacesClient = client
+	}
+	return c.arm.interfacesClient, nil
+}
+
+func (c *AzureClient) getPublicIPAddressesClient() (*armnetwork.PublicIPAddressesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.publicIPsClient == nil {
+		client, err := armnetwork.NewPublicIPAddressesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Public IP Addresses client: %w", err)
+		}
+		c.arm.publicIPsClient = client
 	}
-	return c.privateEndpointsClient, nil
+	return c.arm.publicIPsClient, nil
 }
 
 func (c *AzureClient) getRouteTablesClient() (*armnetwork.RouteTablesClient, error) {
-	if c.routeTablesClient == nil {
-		client, err := armnetwork.NewRouteTablesClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.routeTablesClient == nil {
+		client, err := armnetwork.NewRouteTablesClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Route Tables client: %w", err)
 		}
-		c.routeTablesClient = client
+		c.arm.routeTablesClient = client
 	}
-	return c.routeTablesClient, nil
+	return c.arm.routeTablesClient, nil
 }
 
 func (c *AzureClient) getRoutesClient() (*armnetwork.RoutesClient, error) {
-	if c.routesClient == nil {
-		client, err := armnetwork.NewRoutesClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.routesClient == nil {
+		client, err := armnetwork.NewRoutesClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Routes client: %w", err)
 		}
-		c.routesClient = client
+		c.arm.routesClient = client
 	}
-	return c.routesClient, nil
+	return c.arm.routesClient, nil
 }
 
 func (c *AzureClient) getNATGatewaysClient() (*armnetwork.NatGatewaysClient, error) {
-	if c.natGatewaysClient == nil {
-		client, err := armnetwork.NewNatGatewaysClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.natGatewaysClient == nil {
+		client, err := armnetwork.NewNatGatewaysClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create NAT Gateways client: %w", err)
 		}
-		c.natGatewaysClient = client
+		c.arm.natGatewaysClient = client
 	}
-	return c.natGatewaysClient, nil
+	return c.arm.natGatewaysClient, nil
 }
 
 func (c *AzureClient) getVPNGatewaysClient() (*armnetwork.VirtualNetworkGatewaysClient, error) {
-	if c.vpnGatewaysClient == nil {
-		client, err := armnetwork.NewVirtualNetworkGatewaysClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.vpnGatewaysClient == nil {
+		client, err := armnetwork.NewVirtualNetworkGatewaysClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create VPN Gateways client: %w", err)
 		}
-		c.vpnGatewaysClient = client
+		c.arm.vpnGatewaysClient = client
 	}
-	return c.vpnGatewaysClient, nil
+	return c.arm.vpnGatewaysClient, nil
 }
 
 func (c *AzureClient) getConnectionsClient() (*armnetwork.VirtualNetworkGatewayConnectionsClient, error) {
-	if c.connectionsClient == nil {
-		client, err := armnetwork.NewVirtualNetworkGatewayConnectionsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.connectionsClient == nil {
+		client, err := armnetwork.NewVirtualNetworkGatewayConnectionsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create VPN Connections client: %w", err)
 		}
-		c.connectionsClient = client
+		c.arm.connectionsClient = client
+	}
+	return c.arm.connectionsClient, nil
+}
+
+func (c *AzureClient) getLocalNetworkGatewaysClient() (*armnetwork.LocalNetworkGatewaysClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.localNetworkGWsClient == nil {
+		client, err := armnetwork.NewLocalNetworkGatewaysClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Local Network Gateways client: %w", err)
+		}
+		c.arm.localNetworkGWsClient = client
 	}
-	return c.connectionsClient, nil
+	return c.arm.localNetworkGWsClient, nil
 }
 
 func (c *AzureClient) getERCircuitsClient() (*armnetwork.ExpressRouteCircuitsClient, error) {
-	if c.erCircuitsClient == nil {
-		client, err := armnetwork.NewExpressRouteCircuitsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.erCircuitsClient == nil {
+		client, err := armnetwork.NewExpressRouteCircuitsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ExpressRoute Circuits client: %w", err)
 		}
-		c.erCircuitsClient = client
+		c.arm.erCircuitsClient = client
 	}
-	return c.erCircuitsClient, nil
+	return c.arm.erCircuitsClient, nil
 }
 
 func (c *AzureClient) getERPeeringsClient() (*armnetwork.ExpressRouteCircuitPeeringsClient, error) {
-	if c.erPeeringsClient == nil {
-		client, err := armnetwork.NewExpressRouteCircuitPeeringsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.erPeeringsClient == nil {
+		client, err := armnetwork.NewExpressRouteCircuitPeeringsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ExpressRoute Peerings client: %w", err)
 		}
-		c.erPeeringsClient = client
+		c.arm.erPeeringsClient = client
 	}
-	return c.erPeeringsClient, nil
+	return c.arm.erPeeringsClient, nil
 }
 
 func (c *AzureClient) getERAuthorizationsClient() (*armnetwork.ExpressRouteCircuitAuthorizationsClient, error) {
-	if c.erAuthorizationsClient == nil {
-		client, err := armnetwork.NewExpressRouteCircuitAuthorizationsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.erAuthorizationsClient == nil {
+		client, err := armnetwork.NewExpressRouteCircuitAuthorizationsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ExpressRoute Authorizations client: %w", err)
 		}
-		c.erAuthorizationsClient = client
+		c.arm.erAuthorizationsClient = client
+	}
+	return c.arm.erAuthorizationsClient, nil
+}
+
+func (c *AzureClient) getRouteFiltersClient() (*armnetwork.RouteFiltersClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.routeFiltersClient == nil {
+		client, err := armnetwork.NewRouteFiltersClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Route Filters client: %w", err)
+		}
+		c.arm.routeFiltersClient = client
 	}
-	return c.erAuthorizationsClient, nil
+	return c.arm.routeFiltersClient, nil
 }
 
 func (c *AzureClient) getLoadBalancersClient() (*armnetwork.LoadBalancersClient, error) {
-	if c.loadBalancersClient == nil {
-		client, err := armnetwork.NewLoadBalancersClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.loadBalancersClient == nil {
+		client, err := armnetwork.NewLoadBalancersClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Load Balancers client: %w", err)
 		}
-		c.loadBalancersClient = client
+		c.arm.loadBalancersClient = client
 	}
-	return c.loadBalancersClient, nil
+	return c.arm.loadBalancersClient, nil
 }
 
 func (c *AzureClient) getAppGatewaysClient() (*armnetwork.ApplicationGatewaysClient, error) {
-	if c.appGatewaysClient == nil {
-		client, err := armnetwork.NewApplicationGatewaysClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.appGatewaysClient == nil {
+		client, err := armnetwork.NewApplicationGatewaysClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Application Gateways client: %w", err)
 		}
-		c.appGatewaysClient = client
+		c.arm.appGatewaysClient = client
 	}
-	return c.appGatewaysClient, nil
+	return c.arm.appGatewaysClient, nil
 }
 
 func (c *AzureClient) getAzureFirewallsClient() (*armnetwork.AzureFirewallsClient, error) {
-	if c.azureFirewallsClient == nil {
-		client, err := armnetwork.NewAzureFirewallsClient(c.subscriptionID, c.cred, nil)
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.azureFirewallsClient == nil {
+		client, err := armnetwork.NewAzureFirewallsClient(c.subscriptionID, c.cred, c.options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure Firewalls client: %w", err)
 		}
-		c.azureFirewallsClient = client
+		c.arm.azureFirewallsClient = client
+	}
+	return c.arm.azureFirewallsClient, nil
+}
+
+func (c *AzureClient) getBastionHostsClient() (*armnetwork.BastionHostsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.bastionHostsClient == nil {
+		client, err := armnetwork.NewBastionHostsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Bastion Hosts client: %w", err)
+		}
+		c.arm.bastionHostsClient = client
+	}
+	return c.arm.bastionHostsClient, nil
+}
+
+func (c *AzureClient) getVirtualWANsClient() (*armnetwork.VirtualWansClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.virtualWANsClient == nil {
+		client, err := armnetwork.NewVirtualWansClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Virtual WANs client: %w", err)
+		}
+		c.arm.virtualWANsClient = client
+	}
+	return c.arm.virtualWANsClient, nil
+}
+
+func (c *AzureClient) getVirtualHubsClient() (*armnetwork.VirtualHubsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.virtualHubsClient == nil {
+		client, err := armnetwork.NewVirtualHubsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Virtual Hubs client: %w", err)
+		}
+		c.arm.virtualHubsClient = client
+	}
+	return c.arm.virtualHubsClient, nil
+}
+
+func (c *AzureClient) getHubVNetConnectionsClient() (*armnetwork.HubVirtualNetworkConnectionsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.hubVNetConnsClient == nil {
+		client, err := armnetwork.NewHubVirtualNetworkConnectionsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Hub Virtual Network Connections client: %w", err)
+		}
+		c.arm.hubVNetConnsClient = client
 	}
-	return c.azureFirewallsClient, nil
+	return c.arm.hubVNetConnsClient, nil
+}
+
+func (c *AzureClient) getHubRouteTablesClient() (*armnetwork.HubRouteTablesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.hubRouteTablesClient == nil {
+		client, err := armnetwork.NewHubRouteTablesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Hub Route Tables client: %w", err)
+		}
+		c.arm.hubRouteTablesClient = client
+	}
+	return c.arm.hubRouteTablesClient, nil
+}
+
+func (c *AzureClient) getRoutingIntentClient() (*armnetwork.RoutingIntentClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.routingIntentClient == nil {
+		client, err := armnetwork.NewRoutingIntentClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Routing Intent client: %w", err)
+		}
+		c.arm.routingIntentClient = client
+	}
+	return c.arm.routingIntentClient, nil
+}
+
+func (c *AzureClient) getHubVPNGatewaysClient() (*armnetwork.VPNGatewaysClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.hubVPNGatewaysClient == nil {
+		client, err := armnetwork.NewVPNGatewaysClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Hub VPN Gateways client: %w", err)
+		}
+		c.arm.hubVPNGatewaysClient = client
+	}
+	return c.arm.hubVPNGatewaysClient, nil
+}
+
+func (c *AzureClient) getERGatewaysClient() (*armnetwork.ExpressRouteGatewaysClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.erGatewaysClient == nil {
+		client, err := armnetwork.NewExpressRouteGatewaysClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ExpressRoute Gateways client: %w", err)
+		}
+		c.arm.erGatewaysClient = client
+	}
+	return c.arm.erGatewaysClient, nil
+}
+
+func (c *AzureClient) getFirewallPoliciesClient() (*armnetwork.FirewallPoliciesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.fwPoliciesClient == nil {
+		client, err := armnetwork.NewFirewallPoliciesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Firewall Policies client: %w", err)
+		}
+		c.arm.fwPoliciesClient = client
+	}
+	return c.arm.fwPoliciesClient, nil
+}
+
+func (c *AzureClient) getFirewallRuleCollectionGroupsClient() (*armnetwork.FirewallPolicyRuleCollectionGroupsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.fwRuleGroupsClient == nil {
+		client, err := armnetwork.NewFirewallPolicyRuleCollectionGroupsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Firewall Policy Rule Collection Groups client: %w", err)
+		}
+		c.arm.fwRuleGroupsClient = client
+	}
+	return c.arm.fwRuleGroupsClient, nil
+}
+
+func (c *AzureClient) getWatchersClient() (*armnetwork.WatchersClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.watchersClient == nil {
+		client, err := armnetwork.NewWatchersClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Network Watchers client: %w", err)
+		}
+		c.arm.watchersClient = client
+	}
+	return c.arm.watchersClient, nil
+}
+
+func (c *AzureClient) getFlowLogsClient() (*armnetwork.FlowLogsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.flowLogsClient == nil {
+		client, err := armnetwork.NewFlowLogsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Flow Logs client: %w", err)
+		}
+		c.arm.flowLogsClient = client
+	}
+	return c.arm.flowLogsClient, nil
+}
+
+func (c *AzureClient) getConnectionMonitorsClient() (*armnetwork.ConnectionMonitorsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.connMonitorsClient == nil {
+		client, err := armnetwork.NewConnectionMonitorsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Connection Monitors client: %w", err)
+		}
+		c.arm.connMonitorsClient = client
+	}
+	return c.arm.connMonitorsClient, nil
+}
+
+func (c *AzureClient) getPacketCapturesClient() (*armnetwork.PacketCapturesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.packetCapturesClient == nil {
+		client, err := armnetwork.NewPacketCapturesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Packet Captures client: %w", err)
+		}
+		c.arm.packetCapturesClient = client
+	}
+	return c.arm.packetCapturesClient, nil
+}
+
+func (c *AzureClient) getResourceGroupsClient() (*armresources.ResourceGroupsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.resourceGroupsClient == nil {
+		client, err := armresources.NewResourceGroupsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Resource Groups client: %w", err)
+		}
+		c.arm.resourceGroupsClient = client
+	}
+	return c.arm.resourceGroupsClient, nil
+}
+
+func (c *AzureClient) getPrivateZonesClient() (*armprivatedns.PrivateZonesClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.privateZonesClient == nil {
+		client, err := armprivatedns.NewPrivateZonesClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Private DNS Zones client: %w", err)
+		}
+		c.arm.privateZonesClient = client
+	}
+	return c.arm.privateZonesClient, nil
+}
+
+func (c *AzureClient) getDNSVNetLinksClient() (*armprivatedns.VirtualNetworkLinksClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.dnsVNetLinksClient == nil {
+		client, err := armprivatedns.NewVirtualNetworkLinksClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Private DNS VNet Links client: %w", err)
+		}
+		c.arm.dnsVNetLinksClient = client
+	}
+	return c.arm.dnsVNetLinksClient, nil
+}
+
+func (c *AzureClient) getDNSRecordSetsClient() (*armprivatedns.RecordSetsClient, error) {
+	c.arm.mu.Lock()
+	defer c.arm.mu.Unlock()
+
+	if c.arm.dnsRecordSetsClient == nil {
+		client, err := armprivatedns.NewRecordSetsClient(c.subscriptionID, c.cred, c.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Private DNS Record Sets client: %w", err)
+		}
+		c.arm.dnsRecordSetsClient = client
+	}
+	return c.arm.dnsRecordSetsClient, nil
+}
+
+// listResourceGroups returns the names of every resource group in the subscription.
+// Used for resource types that have no subscription-level list operation.
+func (c *AzureClient) listResourceGroups(ctx context.Context) ([]string, error) {
+	client, err := c.getResourceGroupsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	pager := client.NewListPager(nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of resource groups: %w", err)
+		}
+
+		for _, rg := range page.Value {
+			if rg.Name != nil {
+				groups = append(groups, *rg.Name)
+			}
+		}
+	}
+
+	return groups, nil
 }
 
 // Helper functions for extracting data from Azure SDK types
@@ -234,6 +848,50 @@ func safeString(s *string) string {
 	return *s
 }
 
+// safeStrings dereferences a slice of string pointers, skipping nil entries
+func safeStrings(values []*string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, *v)
+		}
+	}
+	return result
+}
+
+// safeBool safely dereferences a bool pointer
+func safeBool(b *bool) bool {
+	return b != nil && *b
+}
+
+// safeInt64 safely dereferences an int64 pointer
+func safeInt64(n *int64) int64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// safeFloat64 safely dereferences a float64 pointer
+func safeFloat64(n *float64) float64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// safeTags dereferences resource tags, returning nil when the resource has none
+func safeTags(tags map[string]*string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(tags))
+	for k, v := range tags {
+		result[k] = safeString(v)
+	}
+	return result
+}
+
 // extractResourceName extracts the resource name from an Azure resource ID
 func extractResourceName(resourceID string) string {
 	if resourceID == "" {
@@ -257,6 +915,35 @@ func extractResourceName(resourceID string) string {
 	return resourceID
 }
 
+// extractNICIDFromIPConfig extracts the network interface ID from the ID of one of
+// its IP configurations. IDs of other resources' IP configurations (load balancer
+// frontends, gateways) return an empty string.
+func extractNICIDFromIPConfig(ipConfigID string) string {
+	lower := strings.ToLower(ipConfigID)
+	if !strings.Contains(lower, "/providers/microsoft.network/networkinterfaces/") {
+		return ""
+	}
+
+	if i := strings.Index(lower, "/ipconfigurations/"); i > 0 {
+		return ipConfigID[:i]
+	}
+	return ""
+}
+
+// extractParentResourceID returns the ID of the resource that owns a child
+// resource, e.g. the load balancer of a frontend IP configuration
+func extractParentResourceID(childID string) string {
+	// A top-level resource ID ends in /providers/<namespace>/<type>/<name>, so
+	// anything after those three segments belongs to a child resource
+	parts := strings.Split(childID, "/")
+	for i, part := range parts {
+		if strings.EqualFold(part, "providers") && i+3 < len(parts) {
+			return strings.Join(parts[:i+4], "/")
+		}
+	}
+	return childID
+}
+
 // extractVNetIDFromSubnet extracts the VNet ID from a subnet ID
 func extractVNetIDFromSubnet(subnetID string) string {
 	if subnetID == "" {
@@ -283,16 +970,42 @@ func extractVNetIDFromSubnet(subnetID string) string {
 	return subnetID
 }
 
-// Extractor methods for complex Azure SDK types
+// extractResourceGroup extracts the resource group name from an Azure resource ID
+func extractResourceGroup(resourceID string) string {
+	return extractIDSegment(resourceID, "resourceGroups")
+}
 
-func (c *AzureClient) extractSubnet(subnet *armnetwork.Subnet) models.Subnet {
-	s := models.Subnet{
-		ID:               safeString(subnet.ID),
+// extractSubscriptionID returns the subscription ID from a resource ID, or ""
+// if the ID has no subscriptions segment
+func extractSubscriptionID(resourceID string) string {
+	return extractIDSegment(resourceID, "subscriptions")
+}
+
+// extractIDSegment returns the value following the given key in a resource ID,
+// e.g. "resourceGroups" -> "rg1". The key match is case-insensitive because ARM
+// does not guarantee the casing of resource ID segments.
+func extractIDSegment(resourceID, key string) string {
+	parts := strings.Split(resourceID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], key) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// Extractor methods for complex Azure SDK types
+
+func (c *AzureClient) extractSubnet(subnet *armnetwork.Subnet) models.Subnet {
+	s := models.Subnet{
+		ID:               safeString(subnet.ID),
 		Name:             safeString(subnet.Name),
 		AddressPrefix:    "",
 		PrivateEndpoints: []string{},
 		ServiceEndpoints: []string{},
 		Delegations:      []string{},
+
+		NetworkInterfaces: []string{},
 	}
 
 	if subnet.Properties != nil {
@@ -322,6 +1035,19 @@ func (c *AzureClient) extractSubnet(subnet *armnetwork.Subnet) models.Subnet {
 			}
 		}
 
+		// Network interfaces, from the IP configurations that use the subnet
+		seen := make(map[string]bool)
+		for _, ipConfig := range subnet.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.ID == nil {
+				continue
+			}
+			nicID := extractNICIDFromIPConfig(*ipConfig.ID)
+			if nicID != "" && !seen[strings.ToLower(nicID)] {
+				seen[strings.ToLower(nicID)] = true
+				s.NetworkInterfaces = append(s.NetworkInterfaces, nicID)
+			}
+		}
+
 		// Service endpoints
 		for _, se := range subnet.Properties.ServiceEndpoints {
 			if se.Service != nil {
@@ -403,6 +1129,931 @@ func (c *AzureClient) extractRoute(route *armnetwork.Route) models.Route {
 	return r
 }
 
+func (c *AzureClient) extractVPNConnection(conn *armnetwork.VirtualNetworkGatewayConnectionListEntity) models.VPNConnection {
+	vc := models.VPNConnection{
+		ID:   safeString(conn.ID),
+		Name: safeString(conn.Name),
+	}
+
+	if conn.Properties != nil {
+		if conn.Properties.ConnectionType != nil {
+			vc.ConnectionType = string(*conn.Properties.ConnectionType)
+		}
+
+		if conn.Properties.ConnectionStatus != nil {
+			vc.ConnectionStatus = string(*conn.Properties.ConnectionStatus)
+		}
+
+		vc.SharedKey = conn.Properties.SharedKey != nil && *conn.Properties.SharedKey != ""
+
+		if conn.Properties.EnableBgp != nil {
+			vc.EnableBGP = *conn.Properties.EnableBgp
+		}
+
+		// Get remote entity ID
+		if conn.Properties.VirtualNetworkGateway2 != nil && conn.Properties.VirtualNetworkGateway2.ID != nil {
+			vc.RemoteEntityID = *conn.Properties.VirtualNetworkGateway2.ID
+		} else if conn.Properties.LocalNetworkGateway2 != nil && conn.Properties.LocalNetworkGateway2.ID != nil {
+			vc.RemoteEntityID = *conn.Properties.LocalNetworkGateway2.ID
+		} else if conn.Properties.Peer != nil && conn.Properties.Peer.ID != nil {
+			vc.RemoteEntityID = *conn.Properties.Peer.ID
+		}
+
+		if vc.ConnectionType == string(armnetwork.VirtualNetworkGatewayConnectionTypeExpressRoute) {
+			vc.CircuitID = extractCircuitID(vc.RemoteEntityID)
+		}
+		if conn.Properties.ExpressRouteGatewayBypass != nil {
+			vc.FastPath = *conn.Properties.ExpressRouteGatewayBypass
+		}
+	}
+
+	return vc
+}
+
+func (c *AzureClient) extractLocalNetworkGateway(lng *armnetwork.LocalNetworkGateway) models.LocalNetworkGateway {
+	g := models.LocalNetworkGateway{
+		ID:              safeString(lng.ID),
+		Name:            safeString(lng.Name),
+		ResourceGroup:   extractResourceGroup(safeString(lng.ID)),
+		SubscriptionID:  extractSubscriptionID(safeString(lng.ID)),
+		Location:        safeString(lng.Location),
+		Tags:            safeTags(lng.Tags),
+		AddressPrefixes: []string{},
+		Connections:     []string{},
+	}
+
+	if lng.Properties != nil {
+		g.GatewayIPAddress = safeString(lng.Properties.GatewayIPAddress)
+		g.FQDN = safeString(lng.Properties.Fqdn)
+		if lng.Properties.LocalNetworkAddressSpace != nil {
+			g.AddressPrefixes = append(g.AddressPrefixes, safeStrings(lng.Properties.LocalNetworkAddressSpace.AddressPrefixes)...)
+		}
+		g.BGPSettings = c.extractBGPSettings(lng.Properties.BgpSettings)
+		if lng.Properties.ProvisioningState != nil {
+			g.ProvisioningState = string(*lng.Properties.ProvisioningState)
+		}
+	}
+
+	return g
+}
+
+// extractBGPSettings converts gateway BGP settings, returning nil when BGP is not configured
+func (c *AzureClient) extractBGPSettings(bgp *armnetwork.BgpSettings) *models.BGPSettings {
+	if bgp == nil {
+		return nil
+	}
+
+	settings := &models.BGPSettings{}
+	if bgp.Asn != nil {
+		settings.ASN = *bgp.Asn
+	}
+	if bgp.BgpPeeringAddress != nil {
+		settings.BGPPeeringAddress = *bgp.BgpPeeringAddress
+	}
+	if bgp.PeerWeight != nil {
+		settings.PeerWeight = *bgp.PeerWeight
+	}
+	return settings
+}
+
+func (c *AzureClient) extractVNetLink(link *armprivatedns.VirtualNetworkLink) models.VNetLink {
+	l := models.VNetLink{
+		ID:   safeString(link.ID),
+		Name: safeString(link.Name),
+	}
+
+	if link.Properties != nil {
+		if link.Properties.VirtualNetwork != nil && link.Properties.VirtualNetwork.ID != nil {
+			l.VNetID = *link.Properties.VirtualNetwork.ID
+			l.VNetName = extractResourceName(l.VNetID)
+		}
+		if link.Properties.RegistrationEnabled != nil {
+			l.RegistrationEnabled = *link.Properties.RegistrationEnabled
+		}
+		if link.Properties.VirtualNetworkLinkState != nil {
+			l.LinkState = string(*link.Properties.VirtualNetworkLinkState)
+		}
+	}
+
+	return l
+}
+
+func (c *AzureClient) extractARecord(recordSet *armprivatedns.RecordSet) models.DNSARecord {
+	r := models.DNSARecord{
+		Name:        safeString(recordSet.Name),
+		IPAddresses: []string{},
+	}
+
+	if recordSet.Properties != nil {
+		r.FQDN = strings.TrimSuffix(safeString(recordSet.Properties.Fqdn), ".")
+		if recordSet.Properties.TTL != nil {
+			r.TTL = *recordSet.Properties.TTL
+		}
+		if recordSet.Properties.IsAutoRegistered != nil {
+			r.AutoRegistered = *recordSet.Properties.IsAutoRegistered
+		}
+		for _, a := range recordSet.Properties.ARecords {
+			if a != nil && a.IPv4Address != nil {
+				r.IPAddresses = append(r.IPAddresses, *a.IPv4Address)
+			}
+		}
+	}
+
+	return r
+}
+
+func (c *AzureClient) extractFlowLog(flowLog *armnetwork.FlowLog) models.FlowLog {
+	fl := models.FlowLog{
+		ID:       safeString(flowLog.ID),
+		Name:     safeString(flowLog.Name),
+		Location: safeString(flowLog.Location),
+		Tags:     safeTags(flowLog.Tags),
+	}
+
+	if flowLog.Properties != nil {
+		fl.TargetResourceID = safeString(flowLog.Properties.TargetResourceID)
+		if strings.Contains(strings.ToLower(fl.TargetResourceID), "/networksecuritygroups/") {
+			fl.NSGId = fl.TargetResourceID
+		}
+		fl.StorageAccountID = safeString(flowLog.Properties.StorageID)
+		if flowLog.Properties.Enabled != nil {
+			fl.Enabled = *flowLog.Properties.Enabled
+		}
+		// Retention days only apply when the retention policy is enabled
+		if rp := flowLog.Properties.RetentionPolicy; rp != nil && rp.Enabled != nil && *rp.Enabled && rp.Days != nil {
+			fl.RetentionDays = *rp.Days
+		}
+		if fa := flowLog.Properties.FlowAnalyticsConfiguration; fa != nil && fa.NetworkWatcherFlowAnalyticsConfiguration != nil {
+			if enabled := fa.NetworkWatcherFlowAnalyticsConfiguration.Enabled; enabled != nil {
+				fl.TrafficAnalytics = *enabled
+			}
+		}
+	}
+
+	return fl
+}
+
+func (c *AzureClient) extractConnectionMonitor(monitor *armnetwork.ConnectionMonitorResult) models.ConnectionMonitor {
+	cm := models.ConnectionMonitor{
+		ID:       safeString(monitor.ID),
+		Name:     safeString(monitor.Name),
+		Location: safeString(monitor.Location),
+		Tags:     safeTags(monitor.Tags),
+	}
+
+	if monitor.Properties != nil {
+		cm.MonitoringStatus = safeString(monitor.Properties.MonitoringStatus)
+
+		// Classic monitors have a single source and destination; newer ones
+		// describe them as named endpoints in test groups
+		if monitor.Properties.Source != nil {
+			cm.Source = extractResourceName(safeString(monitor.Properties.Source.ResourceID))
+		}
+		if dest := monitor.Properties.Destination; dest != nil {
+			cm.Destination = safeString(dest.Address)
+			if cm.Destination == "" {
+				cm.Destination = extractResourceName(safeString(dest.ResourceID))
+			}
+		}
+
+		var sources, destinations []string
+		for _, group := range monitor.Properties.TestGroups {
+			if group == nil {
+				continue
+			}
+			for _, src := range group.Sources {
+				sources = append(sources, safeString(src))
+			}
+			for _, dst := range group.Destinations {
+				destinations = append(destinations, safeString(dst))
+			}
+		}
+		if cm.Source == "" {
+			cm.Source = strings.Join(uniqueFold(sources), ", ")
+		}
+		if cm.Destination == "" {
+			cm.Destination = strings.Join(uniqueFold(destinations), ", ")
+		}
+	}
+
+	return cm
+}
+
+func (c *AzureClient) extractPacketCapture(capture *armnetwork.PacketCaptureResult) models.PacketCapture {
+	pc := models.PacketCapture{
+		ID:   safeString(capture.ID),
+		Name: safeString(capture.Name),
+	}
+
+	if capture.Properties != nil {
+		pc.Target = extractResourceName(safeString(capture.Properties.Target))
+		if capture.Properties.ProvisioningState != nil {
+			pc.Status = string(*capture.Properties.ProvisioningState)
+		}
+	}
+
+	return pc
+}
+
+func (c *AzureClient) extractCustomDNSConfig(config *armnetwork.CustomDNSConfigPropertiesFormat) models.PrivateEndpointDNSConfig {
+	dc := models.PrivateEndpointDNSConfig{
+		FQDN:        safeString(config.Fqdn),
+		IPAddresses: []string{},
+	}
+
+	for _, ip := range config.IPAddresses {
+		if ip != nil {
+			dc.IPAddresses = append(dc.IPAddresses, *ip)
+		}
+	}
+
+	return dc
+}
+
+// extractNICDNSConfigs reads the private IPs and FQDNs of a private endpoint's
+// network interface. Each IP configuration serves one member of the target
+// resource, so its IP is paired with that member's FQDNs.
+func (c *AzureClient) extractNICDNSConfigs(nic *armnetwork.Interface) ([]string, []models.PrivateEndpointDNSConfig) {
+	ips := []string{}
+	configs := []models.PrivateEndpointDNSConfig{}
+	if nic.Properties == nil {
+		return ips, configs
+	}
+
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PrivateIPAddress == nil {
+			continue
+		}
+		ip := *ipConfig.Properties.PrivateIPAddress
+		ips = append(ips, ip)
+
+		if plc := ipConfig.Properties.PrivateLinkConnectionProperties; plc != nil {
+			for _, fqdn := range plc.Fqdns {
+				if fqdn != nil {
+					configs = append(configs, models.PrivateEndpointDNSConfig{FQDN: *fqdn, IPAddresses: []string{ip}})
+				}
+			}
+		}
+	}
+
+	return ips, configs
+}
+
+func (c *AzureClient) extractPrivateLinkService(pls *armnetwork.PrivateLinkService) models.PrivateLinkService {
+	s := models.PrivateLinkService{
+		ID:                      safeString(pls.ID),
+		Name:                    safeString(pls.Name),
+		ResourceGroup:           extractResourceGroup(safeString(pls.ID)),
+		SubscriptionID:          extractSubscriptionID(safeString(pls.ID)),
+		Location:                safeString(pls.Location),
+		Tags:                    safeTags(pls.Tags),
+		LoadBalancerFrontendIDs: []string{},
+		IPConfigurations:        []models.PrivateLinkServiceIPConfig{},
+		Visibility:              []string{},
+		AutoApproval:            []string{},
+		FQDNs:                   []string{},
+		Connections:             []models.PrivateLinkServiceConnection{},
+	}
+
+	if pls.Properties == nil {
+		return s
+	}
+
+	s.Alias = safeString(pls.Properties.Alias)
+	s.EnableProxyProtocol = safeBool(pls.Properties.EnableProxyProtocol)
+	s.FQDNs = append(s.FQDNs, safeStrings(pls.Properties.Fqdns)...)
+	if pls.Properties.Visibility != nil {
+		s.Visibility = append(s.Visibility, safeStrings(pls.Properties.Visibility.Subscriptions)...)
+	}
+	if pls.Properties.AutoApproval != nil {
+		s.AutoApproval = append(s.AutoApproval, safeStrings(pls.Properties.AutoApproval.Subscriptions)...)
+	}
+	if pls.Properties.ProvisioningState != nil {
+		s.ProvisioningState = string(*pls.Properties.ProvisioningState)
+	}
+
+	// All frontends belong to the same internal load balancer
+	for _, fe := range pls.Properties.LoadBalancerFrontendIPConfigurations {
+		if fe != nil && fe.ID != nil {
+			s.LoadBalancerFrontendIDs = append(s.LoadBalancerFrontendIDs, *fe.ID)
+			if s.LoadBalancerID == "" {
+				s.LoadBalancerID = extractParentResourceID(*fe.ID)
+			}
+		}
+	}
+
+	for _, ipConfig := range pls.Properties.IPConfigurations {
+		if ipConfig == nil {
+			continue
+		}
+		ic := models.PrivateLinkServiceIPConfig{Name: safeString(ipConfig.Name)}
+		if props := ipConfig.Properties; props != nil {
+			ic.PrivateIPAddress = safeString(props.PrivateIPAddress)
+			ic.Primary = safeBool(props.Primary)
+			if props.PrivateIPAllocationMethod != nil {
+				ic.AllocationMethod = string(*props.PrivateIPAllocationMethod)
+			}
+			if props.Subnet != nil {
+				ic.SubnetID = safeString(props.Subnet.ID)
+			}
+		}
+		s.IPConfigurations = append(s.IPConfigurations, ic)
+	}
+
+	for _, conn := range pls.Properties.PrivateEndpointConnections {
+		if conn == nil {
+			continue
+		}
+		pc := models.PrivateLinkServiceConnection{
+			ID:   safeString(conn.ID),
+			Name: safeString(conn.Name),
+		}
+		if props := conn.Properties; props != nil {
+			if props.PrivateEndpoint != nil {
+				pc.PrivateEndpointID = safeString(props.PrivateEndpoint.ID)
+			}
+			if state := props.PrivateLinkServiceConnectionState; state != nil {
+				pc.Status = safeString(state.Status)
+				pc.Description = safeString(state.Description)
+			}
+		}
+		s.Connections = append(s.Connections, pc)
+	}
+
+	return s
+}
+
+func (c *AzureClient) extractNICIPConfiguration(ipConfig *armnetwork.InterfaceIPConfiguration) models.NICIPConfiguration {
+	ic := models.NICIPConfiguration{
+		ID:                        safeString(ipConfig.ID),
+		Name:                      safeString(ipConfig.Name),
+		LoadBalancerBackendPools:  []string{},
+		AppGatewayBackendPools:    []string{},
+		ApplicationSecurityGroups: []string{},
+	}
+
+	if ipConfig.Properties != nil {
+		if ipConfig.Properties.Primary != nil {
+			ic.Primary = *ipConfig.Properties.Primary
+		}
+		ic.PrivateIPAddress = safeString(ipConfig.Properties.PrivateIPAddress)
+		if ipConfig.Properties.PrivateIPAllocationMethod != nil {
+			ic.PrivateIPAllocationMethod = string(*ipConfig.Properties.PrivateIPAllocationMethod)
+		}
+		if ipConfig.Properties.Subnet != nil {
+			ic.SubnetID = safeString(ipConfig.Properties.Subnet.ID)
+		}
+		if ipConfig.Properties.PublicIPAddress != nil {
+			ic.PublicIPAddressID = safeString(ipConfig.Properties.PublicIPAddress.ID)
+		}
+		for _, pool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+			if pool != nil && pool.ID != nil {
+				ic.LoadBalancerBackendPools = append(ic.LoadBalancerBackendPools, *pool.ID)
+			}
+		}
+		for _, pool := range ipConfig.Properties.ApplicationGatewayBackendAddressPools {
+			if pool != nil && pool.ID != nil {
+				ic.AppGatewayBackendPools = append(ic.AppGatewayBackendPools, *pool.ID)
+			}
+		}
+		ic.ApplicationSecurityGroups = asgIDs(ipConfig.Properties.ApplicationSecurityGroups)
+	}
+
+	return ic
+}
+
+func (c *AzureClient) extractPublicIPAddress(pip *armnetwork.PublicIPAddress) models.PublicIPAddress {
+	p := models.PublicIPAddress{
+		ID:             safeString(pip.ID),
+		Name:           safeString(pip.Name),
+		ResourceGroup:  extractResourceGroup(safeString(pip.ID)),
+		SubscriptionID: extractSubscriptionID(safeString(pip.ID)),
+		Location:       safeString(pip.Location),
+		Tags:           safeTags(pip.Tags),
+		Zones:          []string{},
+	}
+
+	if pip.SKU != nil {
+		if pip.SKU.Name != nil {
+			p.SKU = string(*pip.SKU.Name)
+		}
+		if pip.SKU.Tier != nil {
+			p.Tier = string(*pip.SKU.Tier)
+		}
+	}
+	for _, zone := range pip.Zones {
+		if zone != nil {
+			p.Zones = append(p.Zones, *zone)
+		}
+	}
+
+	if pip.Properties != nil {
+		p.IPAddress = safeString(pip.Properties.IPAddress)
+		if pip.Properties.PublicIPAllocationMethod != nil {
+			p.AllocationMethod = string(*pip.Properties.PublicIPAllocationMethod)
+		}
+		if pip.Properties.PublicIPAddressVersion != nil {
+			p.IPVersion = string(*pip.Properties.PublicIPAddressVersion)
+		}
+		if dns := pip.Properties.DNSSettings; dns != nil {
+			p.DNSLabel = safeString(dns.DomainNameLabel)
+			p.FQDN = safeString(dns.Fqdn)
+		}
+		if ddos := pip.Properties.DdosSettings; ddos != nil && ddos.ProtectionCoverage != nil {
+			p.DDoSProtection = string(*ddos.ProtectionCoverage)
+		}
+
+		// An address is used either through an IP configuration of another
+		// resource or directly by a NAT gateway
+		if pip.Properties.IPConfiguration != nil {
+			p.IPConfigurationID = safeString(pip.Properties.IPConfiguration.ID)
+			p.AssociatedResourceID = extractParentResourceID(p.IPConfigurationID)
+		} else if pip.Properties.NatGateway != nil {
+			p.AssociatedResourceID = safeString(pip.Properties.NatGateway.ID)
+		}
+	}
+
+	return p
+}
+
+func (c *AzureClient) extractBastionHost(bastion *armnetwork.BastionHost) models.BastionHost {
+	b := models.BastionHost{
+		ID:             safeString(bastion.ID),
+		Name:           safeString(bastion.Name),
+		ResourceGroup:  extractResourceGroup(safeString(bastion.ID)),
+		SubscriptionID: extractSubscriptionID(safeString(bastion.ID)),
+		Location:       safeString(bastion.Location),
+		Tags:           safeTags(bastion.Tags),
+	}
+
+	if bastion.SKU != nil && bastion.SKU.Name != nil {
+		b.SKU = string(*bastion.SKU.Name)
+	}
+
+	if bastion.Properties != nil {
+		b.DNSName = safeString(bastion.Properties.DNSName)
+		b.IPConnect = safeBool(bastion.Properties.EnableIPConnect)
+		b.Tunneling = safeBool(bastion.Properties.EnableTunneling)
+		b.ShareableLink = safeBool(bastion.Properties.EnableShareableLink)
+		b.FileCopy = safeBool(bastion.Properties.EnableFileCopy)
+		if bastion.Properties.ScaleUnits != nil {
+			b.ScaleUnits = *bastion.Properties.ScaleUnits
+		}
+		if bastion.Properties.ProvisioningState != nil {
+			b.ProvisioningState = string(*bastion.Properties.ProvisioningState)
+		}
+
+		// Bastion has a single IP configuration in AzureBastionSubnet
+		for _, ipConfig := range bastion.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.Properties == nil {
+				continue
+			}
+			if ipConfig.Properties.Subnet != nil {
+				b.SubnetID = safeString(ipConfig.Properties.Subnet.ID)
+				b.VNetID = extractVNetIDFromSubnet(b.SubnetID)
+			}
+			if ipConfig.Properties.PublicIPAddress != nil {
+				b.PublicIPAddressID = safeString(ipConfig.Properties.PublicIPAddress.ID)
+			}
+			break
+		}
+	}
+
+	return b
+}
+
+func (c *AzureClient) extractVirtualWAN(wan *armnetwork.VirtualWAN) models.VirtualWAN {
+	w := models.VirtualWAN{
+		ID:             safeString(wan.ID),
+		Name:           safeString(wan.Name),
+		ResourceGroup:  extractResourceGroup(safeString(wan.ID)),
+		SubscriptionID: extractSubscriptionID(safeString(wan.ID)),
+		Location:       safeString(wan.Location),
+		Tags:           safeTags(wan.Tags),
+		VirtualHubs:    []string{},
+	}
+
+	if wan.Properties != nil {
+		w.Type = safeString(wan.Properties.Type)
+		w.AllowBranchToBranchTraffic = safeBool(wan.Properties.AllowBranchToBranchTraffic)
+		w.AllowVNetToVNetTraffic = safeBool(wan.Properties.AllowVnetToVnetTraffic)
+		for _, hub := range wan.Properties.VirtualHubs {
+			if hub != nil && hub.ID != nil {
+				w.VirtualHubs = append(w.VirtualHubs, *hub.ID)
+			}
+		}
+	}
+
+	return w
+}
+
+func (c *AzureClient) extractVirtualHub(hub *armnetwork.VirtualHub) models.VirtualHub {
+	h := models.VirtualHub{
+		ID:               safeString(hub.ID),
+		Name:             safeString(hub.Name),
+		ResourceGroup:    extractResourceGroup(safeString(hub.ID)),
+		SubscriptionID:   extractSubscriptionID(safeString(hub.ID)),
+		Location:         safeString(hub.Location),
+		Tags:             safeTags(hub.Tags),
+		VirtualRouterIPs: []string{},
+		VNetConnections:  []models.HubVNetConnection{},
+		RouteTables:      []models.HubRouteTable{},
+		Gateways:         []models.HubGateway{},
+	}
+
+	if hub.Properties == nil {
+		return h
+	}
+
+	subResourceID := func(r *armnetwork.SubResource) string {
+		if r == nil {
+			return ""
+		}
+		return safeString(r.ID)
+	}
+
+	h.VirtualWANID = subResourceID(hub.Properties.VirtualWan)
+	h.AddressPrefix = safeString(hub.Properties.AddressPrefix)
+	h.SKU = safeString(hub.Properties.SKU)
+	h.VirtualRouterIPs = append(h.VirtualRouterIPs, safeStrings(hub.Properties.VirtualRouterIPs)...)
+	h.AzureFirewallID = subResourceID(hub.Properties.AzureFirewall)
+	h.VPNGatewayID = subResourceID(hub.Properties.VPNGateway)
+	h.ExpressRouteGatewayID = subResourceID(hub.Properties.ExpressRouteGateway)
+	h.P2SVPNGatewayID = subResourceID(hub.Properties.P2SVPNGateway)
+	if hub.Properties.HubRoutingPreference != nil {
+		h.RoutingPreference = string(*hub.Properties.HubRoutingPreference)
+	}
+	if hub.Properties.RoutingState != nil {
+		h.RoutingState = string(*hub.Properties.RoutingState)
+	}
+
+	return h
+}
+
+func (c *AzureClient) extractHubVNetConnection(conn *armnetwork.HubVirtualNetworkConnection) models.HubVNetConnection {
+	hc := models.HubVNetConnection{
+		ID:                    safeString(conn.ID),
+		Name:                  safeString(conn.Name),
+		PropagatedRouteTables: []string{},
+		PropagatedLabels:      []string{},
+	}
+
+	if conn.Properties == nil {
+		return hc
+	}
+
+	if conn.Properties.RemoteVirtualNetwork != nil {
+		hc.RemoteVNetID = safeString(conn.Properties.RemoteVirtualNetwork.ID)
+	}
+	hc.EnableInternetSecurity = safeBool(conn.Properties.EnableInternetSecurity)
+	if conn.Properties.ProvisioningState != nil {
+		hc.ProvisioningState = string(*conn.Properties.ProvisioningState)
+	}
+
+	if routing := conn.Properties.RoutingConfiguration; routing != nil {
+		if routing.AssociatedRouteTable != nil {
+			hc.AssociatedRouteTable = safeString(routing.AssociatedRouteTable.ID)
+		}
+		if routing.PropagatedRouteTables != nil {
+			for _, rt := range routing.PropagatedRouteTables.IDs {
+				if rt != nil && rt.ID != nil {
+					hc.PropagatedRouteTables = append(hc.PropagatedRouteTables, *rt.ID)
+				}
+			}
+			hc.PropagatedLabels = append(hc.PropagatedLabels, safeStrings(routing.PropagatedRouteTables.Labels)...)
+		}
+	}
+
+	return hc
+}
+
+func (c *AzureClient) extractHubRouteTable(table *armnetwork.HubRouteTable) models.HubRouteTable {
+	rt := models.HubRouteTable{
+		ID:                     safeString(table.ID),
+		Name:                   safeString(table.Name),
+		Labels:                 []string{},
+		Routes:                 []models.HubRoute{},
+		AssociatedConnections:  []string{},
+		PropagatingConnections: []string{},
+	}
+
+	if table.Properties == nil {
+		return rt
+	}
+
+	rt.Labels = append(rt.Labels, safeStrings(table.Properties.Labels)...)
+	rt.AssociatedConnections = append(rt.AssociatedConnections, safeStrings(table.Properties.AssociatedConnections)...)
+	rt.PropagatingConnections = append(rt.PropagatingConnections, safeStrings(table.Properties.PropagatingConnections)...)
+	for _, route := range table.Properties.Routes {
+		if route == nil {
+			continue
+		}
+		rt.Routes = append(rt.Routes, models.HubRoute{
+			Name:            safeString(route.Name),
+			DestinationType: safeString(route.DestinationType),
+			Destinations:    append([]string{}, safeStrings(route.Destinations)...),
+			NextHopType:     safeString(route.NextHopType),
+			NextHop:         safeString(route.NextHop),
+		})
+	}
+
+	return rt
+}
+
+func (c *AzureClient) extractRoutingIntent(intent *armnetwork.RoutingIntent) models.HubRoutingIntent {
+	ri := models.HubRoutingIntent{
+		ID:              safeString(intent.ID),
+		Name:            safeString(intent.Name),
+		RoutingPolicies: []models.HubRoutingPolicy{},
+	}
+
+	if intent.Properties == nil {
+		return ri
+	}
+
+	for _, policy := range intent.Properties.RoutingPolicies {
+		if policy == nil {
+			continue
+		}
+		ri.RoutingPolicies = append(ri.RoutingPolicies, models.HubRoutingPolicy{
+			Name:         safeString(policy.Name),
+			Destinations: append([]string{}, safeStrings(policy.Destinations)...),
+			NextHop:      safeString(policy.NextHop),
+		})
+	}
+
+	return ri
+}
+
+// extractHubVPNGateway extracts a site-to-site VPN gateway together with the ID of
+// the virtual hub it is deployed in
+func (c *AzureClient) extractHubVPNGateway(gw *armnetwork.VPNGateway) (models.HubGateway, string) {
+	g := models.HubGateway{
+		ID:          safeString(gw.ID),
+		Name:        safeString(gw.Name),
+		Type:        models.HubGatewayVPN,
+		Tags:        safeTags(gw.Tags),
+		Connections: []models.HubGatewayConnection{},
+	}
+
+	if gw.Properties == nil {
+		return g, ""
+	}
+
+	if gw.Properties.VPNGatewayScaleUnit != nil {
+		g.ScaleUnits = *gw.Properties.VPNGatewayScaleUnit
+	}
+	for _, conn := range gw.Properties.Connections {
+		if conn == nil {
+			continue
+		}
+		gc := models.HubGatewayConnection{
+			ID:   safeString(conn.ID),
+			Name: safeString(conn.Name),
+		}
+		if conn.Properties != nil {
+			gc.EnableInternetSecurity = safeBool(conn.Properties.EnableInternetSecurity)
+			if conn.Properties.RemoteVPNSite != nil {
+				gc.RemoteID = safeString(conn.Properties.RemoteVPNSite.ID)
+			}
+		}
+		g.Connections = append(g.Connections, gc)
+	}
+
+	hubID := ""
+	if gw.Properties.VirtualHub != nil {
+		hubID = safeString(gw.Properties.VirtualHub.ID)
+	}
+	return g, hubID
+}
+
+// extractExpressRouteGateway extracts an ExpressRoute gateway together with the ID
+// of the virtual hub it is deployed in. Scale units are the autoscale minimum.
+func (c *AzureClient) extractExpressRouteGateway(gw *armnetwork.ExpressRouteGateway) (models.HubGateway, string) {
+	g := models.HubGateway{
+		ID:          safeString(gw.ID),
+		Name:        safeString(gw.Name),
+		Type:        models.HubGatewayExpressRoute,
+		Tags:        safeTags(gw.Tags),
+		Connections: []models.HubGatewayConnection{},
+	}
+
+	if gw.Properties == nil {
+		return g, ""
+	}
+
+	if scale := gw.Properties.AutoScaleConfiguration; scale != nil && scale.Bounds != nil && scale.Bounds.Min != nil {
+		g.ScaleUnits = *scale.Bounds.Min
+	}
+	for _, conn := range gw.Properties.ExpressRouteConnections {
+		if conn == nil {
+			continue
+		}
+		gc := models.HubGatewayConnection{
+			ID:   safeString(conn.ID),
+			Name: safeString(conn.Name),
+		}
+		if conn.Properties != nil {
+			gc.EnableInternetSecurity = safeBool(conn.Properties.EnableInternetSecurity)
+			if conn.Properties.ExpressRouteCircuitPeering != nil {
+				gc.RemoteID = safeString(conn.Properties.ExpressRouteCircuitPeering.ID)
+			}
+		}
+		g.Connections = append(g.Connections, gc)
+	}
+
+	hubID := ""
+	if gw.Properties.VirtualHub != nil {
+		hubID = safeString(gw.Properties.VirtualHub.ID)
+	}
+	return g, hubID
+}
+
+func (c *AzureClient) extractFirewallPolicy(policy *armnetwork.FirewallPolicy) models.FirewallPolicy {
+	p := models.FirewallPolicy{
+		ID:                   safeString(policy.ID),
+		Name:                 safeString(policy.Name),
+		ResourceGroup:        extractResourceGroup(safeString(policy.ID)),
+		SubscriptionID:       extractSubscriptionID(safeString(policy.ID)),
+		Location:             safeString(policy.Location),
+		Tags:                 safeTags(policy.Tags),
+		ChildPolicies:        []string{},
+		Firewalls:            []string{},
+		RuleCollectionGroups: []models.FirewallRuleCollectionGroup{},
+	}
+
+	if policy.Properties == nil {
+		return p
+	}
+
+	if policy.Properties.SKU != nil && policy.Properties.SKU.Tier != nil {
+		p.SKU = string(*policy.Properties.SKU.Tier)
+	}
+	if policy.Properties.ThreatIntelMode != nil {
+		p.ThreatIntelMode = string(*policy.Properties.ThreatIntelMode)
+	}
+	if policy.Properties.DNSSettings != nil && policy.Properties.DNSSettings.EnableProxy != nil {
+		p.DNSProxyEnabled = *policy.Properties.DNSSettings.EnableProxy
+	}
+	if policy.Properties.BasePolicy != nil {
+		p.BasePolicyID = safeString(policy.Properties.BasePolicy.ID)
+	}
+	for _, child := range policy.Properties.ChildPolicies {
+		if child != nil && child.ID != nil {
+			p.ChildPolicies = append(p.ChildPolicies, *child.ID)
+		}
+	}
+	for _, fw := range policy.Properties.Firewalls {
+		if fw != nil && fw.ID != nil {
+			p.Firewalls = append(p.Firewalls, *fw.ID)
+		}
+	}
+
+	return p
+}
+
+func (c *AzureClient) extractRuleCollectionGroup(group *armnetwork.FirewallPolicyRuleCollectionGroup) models.FirewallRuleCollectionGroup {
+	g := models.FirewallRuleCollectionGroup{
+		ID:              safeString(group.ID),
+		Name:            safeString(group.Name),
+		RuleCollections: []models.FirewallRuleCollection{},
+	}
+
+	if group.Properties != nil {
+		if group.Properties.Priority != nil {
+			g.Priority = *group.Properties.Priority
+		}
+		for _, rc := range group.Properties.RuleCollections {
+			if rc != nil {
+				g.RuleCollections = append(g.RuleCollections, c.extractFirewallRuleCollection(rc))
+			}
+		}
+	}
+
+	// Collections are evaluated in priority order within the group
+	sort.SliceStable(g.RuleCollections, func(i, j int) bool {
+		return g.RuleCollections[i].Priority < g.RuleCollections[j].Priority
+	})
+
+	return g
+}
+
+func (c *AzureClient) extractFirewallRuleCollection(collection armnetwork.FirewallPolicyRuleCollectionClassification) models.FirewallRuleCollection {
+	rc := models.FirewallRuleCollection{
+		Rules: []models.FirewallRule{},
+	}
+
+	var rules []armnetwork.FirewallPolicyRuleClassification
+	switch col := collection.(type) {
+	case *armnetwork.FirewallPolicyFilterRuleCollection:
+		rc.CollectionType = "Filter"
+		rc.Name = safeString(col.Name)
+		if col.Priority != nil {
+			rc.Priority = *col.Priority
+		}
+		if col.Action != nil && col.Action.Type != nil {
+			rc.Action = string(*col.Action.Type)
+		}
+		rules = col.Rules
+	case *armnetwork.FirewallPolicyNatRuleCollection:
+		rc.CollectionType = "NAT"
+		rc.Name = safeString(col.Name)
+		if col.Priority != nil {
+			rc.Priority = *col.Priority
+		}
+		if col.Action != nil && col.Action.Type != nil {
+			rc.Action = string(*col.Action.Type)
+		}
+		rules = col.Rules
+	default:
+		base := collection.GetFirewallPolicyRuleCollection()
+		rc.Name = safeString(base.Name)
+		if base.Priority != nil {
+			rc.Priority = *base.Priority
+		}
+	}
+
+	for _, rule := range rules {
+		if rule != nil {
+			rc.Rules = append(rc.Rules, c.extractFirewallRule(rule))
+		}
+	}
+
+	return rc
+}
+
+func (c *AzureClient) extractFirewallRule(rule armnetwork.FirewallPolicyRuleClassification) models.FirewallRule {
+	r := models.FirewallRule{
+		SourceAddresses:      []string{},
+		DestinationAddresses: []string{},
+		Protocols:            []string{},
+	}
+
+	switch ru := rule.(type) {
+	case *armnetwork.Rule:
+		r.RuleType = string(armnetwork.FirewallPolicyRuleTypeNetworkRule)
+		r.Name = safeString(ru.Name)
+		r.Description = safeString(ru.Description)
+		r.SourceAddresses = safeStrings(ru.SourceAddresses)
+		r.SourceIPGroups = safeStrings(ru.SourceIPGroups)
+		r.DestinationAddresses = safeStrings(ru.DestinationAddresses)
+		r.DestinationIPGroups = safeStrings(ru.DestinationIPGroups)
+		r.DestinationFQDNs = safeStrings(ru.DestinationFqdns)
+		r.DestinationPorts = safeStrings(ru.DestinationPorts)
+		for _, p := range ru.IPProtocols {
+			if p != nil {
+				r.Protocols = append(r.Protocols, string(*p))
+			}
+		}
+	case *armnetwork.ApplicationRule:
+		r.RuleType = string(armnetwork.FirewallPolicyRuleTypeApplicationRule)
+		r.Name = safeString(ru.Name)
+		r.Description = safeString(ru.Description)
+		r.SourceAddresses = safeStrings(ru.SourceAddresses)
+		r.SourceIPGroups = safeStrings(ru.SourceIPGroups)
+		r.DestinationAddresses = safeStrings(ru.DestinationAddresses)
+		r.TargetFQDNs = safeStrings(ru.TargetFqdns)
+		r.TargetURLs = safeStrings(ru.TargetUrls)
+		r.FQDNTags = safeStrings(ru.FqdnTags)
+		r.WebCategories = safeStrings(ru.WebCategories)
+		for _, p := range ru.Protocols {
+			if p == nil || p.ProtocolType == nil {
+				continue
+			}
+			protocol := string(*p.ProtocolType)
+			if p.Port != nil {
+				protocol = fmt.Sprintf("%s:%d", protocol, *p.Port)
+			}
+			r.Protocols = append(r.Protocols, protocol)
+		}
+	case *armnetwork.NatRule:
+		r.RuleType = string(armnetwork.FirewallPolicyRuleTypeNatRule)
+		r.Name = safeString(ru.Name)
+		r.Description = safeString(ru.Description)
+		r.SourceAddresses = safeStrings(ru.SourceAddresses)
+		r.SourceIPGroups = safeStrings(ru.SourceIPGroups)
+		r.DestinationAddresses = safeStrings(ru.DestinationAddresses)
+		r.DestinationPorts = safeStrings(ru.DestinationPorts)
+		r.TranslatedAddress = safeString(ru.TranslatedAddress)
+		r.TranslatedFQDN = safeString(ru.TranslatedFqdn)
+		r.TranslatedPort = safeString(ru.TranslatedPort)
+		for _, p := range ru.IPProtocols {
+			if p != nil {
+				r.Protocols = append(r.Protocols, string(*p))
+			}
+		}
+	default:
+		base := rule.GetFirewallPolicyRule()
+		r.Name = safeString(base.Name)
+		r.Description = safeString(base.Description)
+		if base.RuleType != nil {
+			r.RuleType = string(*base.RuleType)
+		}
+	}
+
+	return r
+}
+
 func (c *AzureClient) extractERPeering(peering *armnetwork.ExpressRouteCircuitPeering) models.ERPeering {
 	p := models.ERPeering{
 		Name: safeString(peering.Name),
@@ -430,11 +2081,91 @@ func (c *AzureClient) extractERPeering(peering *armnetwork.ExpressRouteCircuitPe
 		if peering.Properties.VlanID != nil {
 			p.VlanID = *peering.Properties.VlanID
 		}
+		if peering.Properties.RouteFilter != nil {
+			p.RouteFilterID = safeString(peering.Properties.RouteFilter.ID)
+		}
+
+		// Global Reach connections made from this circuit, then those other
+		// circuits made to it
+		for _, conn := range peering.Properties.Connections {
+			if conn == nil || conn.Properties == nil {
+				continue
+			}
+			gr := models.ERGlobalReachConnection{
+				Name:          safeString(conn.Name),
+				AddressPrefix: safeString(conn.Properties.AddressPrefix),
+			}
+			if conn.Properties.PeerExpressRouteCircuitPeering != nil {
+				gr.PeerCircuitID = extractCircuitID(safeString(conn.Properties.PeerExpressRouteCircuitPeering.ID))
+			}
+			if conn.Properties.CircuitConnectionStatus != nil {
+				gr.Status = string(*conn.Properties.CircuitConnectionStatus)
+			}
+			p.GlobalReachConnections = append(p.GlobalReachConnections, gr)
+		}
+		for _, conn := range peering.Properties.PeeredConnections {
+			if conn == nil || conn.Properties == nil {
+				continue
+			}
+			gr := models.ERGlobalReachConnection{
+				Name:          safeString(conn.Name),
+				AddressPrefix: safeString(conn.Properties.AddressPrefix),
+			}
+			if conn.Properties.PeerExpressRouteCircuitPeering != nil {
+				gr.PeerCircuitID = extractCircuitID(safeString(conn.Properties.PeerExpressRouteCircuitPeering.ID))
+			}
+			if conn.Properties.CircuitConnectionStatus != nil {
+				gr.Status = string(*conn.Properties.CircuitConnectionStatus)
+			}
+			p.GlobalReachConnections = append(p.GlobalReachConnections, gr)
+		}
 	}
 
 	return p
 }
 
+// extractCircuitID returns the ExpressRoute circuit a circuit or circuit
+// peering ID belongs to
+func extractCircuitID(resourceID string) string {
+	lower := strings.ToLower(resourceID)
+	i := strings.Index(lower, "/expressroutecircuits/")
+	if i < 0 {
+		return ""
+	}
+	end := i + len("/expressroutecircuits/")
+	if j := strings.Index(resourceID[end:], "/"); j >= 0 {
+		end += j
+	} else {
+		end = len(resourceID)
+	}
+	return resourceID[:end]
+}
+
+func extractRouteFilter(filter *armnetwork.RouteFilter) *models.ERRouteFilter {
+	f := &models.ERRouteFilter{
+		ID:    safeString(filter.ID),
+		Name:  safeString(filter.Name),
+		Rules: []models.RouteFilterRule{},
+	}
+	if filter.Properties == nil {
+		return f
+	}
+	for _, rule := range filter.Properties.Rules {
+		if rule == nil {
+			continue
+		}
+		r := models.RouteFilterRule{Name: safeString(rule.Name), Communities: []string{}}
+		if rule.Properties != nil {
+			if rule.Properties.Access != nil {
+				r.Access = string(*rule.Properties.Access)
+			}
+			r.Communities = safeStrings(rule.Properties.Communities)
+		}
+		f.Rules = append(f.Rules, r)
+	}
+	return f
+}
+
 func (c *AzureClient) extractERAuthorization(auth *armnetwork.ExpressRouteCircuitAuthorization) models.ERAuthorization {
 	a := models.ERAuthorization{
 		Name: safeString(auth.Name),
@@ -472,14 +2203,22 @@ func (c *AzureClient) extractFrontendIPConfig(feConfig *armnetwork.FrontendIPCon
 
 func (c *AzureClient) extractBackendAddressPool(bePool *armnetwork.BackendAddressPool) models.BackendAddressPool {
 	be := models.BackendAddressPool{
-		Name:             safeString(bePool.Name),
-		BackendIPConfigs: []string{},
+		Name:              safeString(bePool.Name),
+		BackendIPConfigs:  []string{},
+		NetworkInterfaces: []string{},
 	}
 
 	if bePool.Properties != nil {
+		seen := make(map[string]bool)
 		for _, ipConfig := range bePool.Properties.BackendIPConfigurations {
 			if ipConfig.ID != nil {
 				be.BackendIPConfigs = append(be.BackendIPConfigs, *ipConfig.ID)
+
+				nicID := extractNICIDFromIPConfig(*ipConfig.ID)
+				if nicID != "" && !seen[strings.ToLower(nicID)] {
+					seen[strings.ToLower(nicID)] = true
+					be.NetworkInterfaces = append(be.NetworkInterfaces, nicID)
+				}
 			}
 		}
 	}
@@ -1,9 +1,13 @@
 package azure
 
 import (
+	"strings"
 	"testing"
 
+	"azure-network-analyzer/pkg/models"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 )
 
 func TestSafeString(t *testing.T) {
@@ -49,6 +53,23 @@ func TestSafeString(t *testing.T) {
 	}
 }
 
+func TestSafeTags(t *testing.T) {
+	if tags := safeTags(nil); tags != nil {
+		t.Errorf("safeTags(nil) = %v, want nil", tags)
+	}
+	if tags := safeTags(map[string]*string{}); tags != nil {
+		t.Errorf("safeTags(empty) = %v, want nil", tags)
+	}
+
+	tags := safeTags(map[string]*string{"environment": strPtr("prod"), "owner": nil})
+	if len(tags) != 2 || tags["environment"] != "prod" {
+		t.Errorf("safeTags() = %v, want environment=prod", tags)
+	}
+	if v, ok := tags["owner"]; !ok || v != "" {
+		t.Errorf("safeTags() should keep a tag with a nil value as empty, got %q (present %v)", v, ok)
+	}
+}
+
 func TestExtractResourceName(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -242,6 +263,127 @@ func TestExtractSubnet(t *testing.T) {
 			t.Errorf("Delegations count mismatch: got %d", len(result.Delegations))
 		}
 	})
+
+	t.Run("network interfaces from IP configurations", func(t *testing.T) {
+		nicID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkInterfaces/nic1"
+		subnet := &armnetwork.Subnet{
+			ID: strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/subnet1"),
+			Properties: &armnetwork.SubnetPropertiesFormat{
+				IPConfigurations: []*armnetwork.IPConfiguration{
+					{ID: strPtr(nicID + "/ipConfigurations/ipconfig1")},
+					{ID: strPtr(nicID + "/ipConfigurations/ipconfig2")},
+					// Gateways and firewalls also hold IP configurations in the subnet
+					{ID: strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/azureFirewalls/fw1/azureFirewallIpConfigurations/cfg")},
+					nil,
+				},
+			},
+		}
+
+		result := client.extractSubnet(subnet)
+
+		if len(result.NetworkInterfaces) != 1 || result.NetworkInterfaces[0] != nicID {
+			t.Errorf("NetworkInterfaces mismatch: got %v", result.NetworkInterfaces)
+		}
+	})
+}
+
+func TestExtractNICIDFromIPConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		ipConfigID string
+		expected   string
+	}{
+		{
+			name:       "NIC IP configuration",
+			ipConfigID: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkInterfaces/nic1/ipConfigurations/ipconfig1",
+			expected:   "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkInterfaces/nic1",
+		},
+		{
+			name:       "lowercase provider",
+			ipConfigID: "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.network/networkinterfaces/nic1/ipconfigurations/ipconfig1",
+			expected:   "/subscriptions/sub1/resourcegroups/rg1/providers/microsoft.network/networkinterfaces/nic1",
+		},
+		{
+			name:       "load balancer frontend",
+			ipConfigID: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/loadBalancers/lb1/frontendIPConfigurations/fe1",
+			expected:   "",
+		},
+		{
+			name:       "empty",
+			ipConfigID: "",
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractNICIDFromIPConfig(tt.ipConfigID)
+			if result != tt.expected {
+				t.Errorf("extractNICIDFromIPConfig(%q) = %q, want %q", tt.ipConfigID, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractNICIPConfiguration(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("full IP configuration", func(t *testing.T) {
+		method := armnetwork.IPAllocationMethodStatic
+		ipConfig := &armnetwork.InterfaceIPConfiguration{
+			ID:   strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkInterfaces/nic1/ipConfigurations/ipconfig1"),
+			Name: strPtr("ipconfig1"),
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				Primary:                   boolPtr(true),
+				PrivateIPAddress:          strPtr("10.0.1.4"),
+				PrivateIPAllocationMethod: &method,
+				Subnet:                    &armnetwork.Subnet{ID: strPtr("subnet1")},
+				PublicIPAddress:           &armnetwork.PublicIPAddress{ID: strPtr("pip1")},
+				LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{
+					{ID: strPtr("lb-pool1")},
+					nil,
+				},
+				ApplicationGatewayBackendAddressPools: []*armnetwork.ApplicationGatewayBackendAddressPool{
+					{ID: strPtr("agw-pool1")},
+				},
+				ApplicationSecurityGroups: []*armnetwork.ApplicationSecurityGroup{
+					{ID: strPtr("asg-web")},
+				},
+			},
+		}
+
+		result := client.extractNICIPConfiguration(ipConfig)
+
+		if result.Name != "ipconfig1" || !result.Primary {
+			t.Errorf("Name/Primary mismatch: got %s / %v", result.Name, result.Primary)
+		}
+		if result.PrivateIPAddress != "10.0.1.4" || result.PrivateIPAllocationMethod != "Static" {
+			t.Errorf("private IP mismatch: got %s (%s)", result.PrivateIPAddress, result.PrivateIPAllocationMethod)
+		}
+		if result.SubnetID != "subnet1" || result.PublicIPAddressID != "pip1" {
+			t.Errorf("SubnetID/PublicIPAddressID mismatch: got %s / %s", result.SubnetID, result.PublicIPAddressID)
+		}
+		if len(result.LoadBalancerBackendPools) != 1 || result.LoadBalancerBackendPools[0] != "lb-pool1" {
+			t.Errorf("LoadBalancerBackendPools mismatch: got %v", result.LoadBalancerBackendPools)
+		}
+		if len(result.AppGatewayBackendPools) != 1 || result.AppGatewayBackendPools[0] != "agw-pool1" {
+			t.Errorf("AppGatewayBackendPools mismatch: got %v", result.AppGatewayBackendPools)
+		}
+		if len(result.ApplicationSecurityGroups) != 1 || result.ApplicationSecurityGroups[0] != "asg-web" {
+			t.Errorf("ApplicationSecurityGroups mismatch: got %v", result.ApplicationSecurityGroups)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractNICIPConfiguration(&armnetwork.InterfaceIPConfiguration{Name: strPtr("ipconfig1")})
+
+		if result.Primary || result.SubnetID != "" {
+			t.Errorf("Expected empty IP configuration, got %+v", result)
+		}
+		if result.LoadBalancerBackendPools == nil || result.AppGatewayBackendPools == nil {
+			t.Error("Backend pool slices should be initialized")
+		}
+	})
 }
 
 func TestExtractVNetPeering(t *testing.T) {
@@ -361,6 +503,45 @@ func TestExtractSecurityRule(t *testing.T) {
 			t.Errorf("DestinationPortRange mismatch: got %s", result.DestinationPortRange)
 		}
 	})
+
+	t.Run("rule with prefix lists, port lists and ASGs", func(t *testing.T) {
+		rule := &armnetwork.SecurityRule{
+			Name: strPtr("AllowAppPorts"),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				SourceAddressPrefixes: []*string{strPtr("10.1.0.0/16"), strPtr("10.2.0.0/16")},
+				SourcePortRanges:      []*string{strPtr("1024-65535")},
+				DestinationApplicationSecurityGroups: []*armnetwork.ApplicationSecurityGroup{
+					{ID: strPtr("asg-web")},
+					nil,
+				},
+				DestinationPortRanges: []*string{strPtr("443"), strPtr("8443")},
+			},
+		}
+
+		result := extractSecurityRule(rule)
+
+		if result.SourceAddressPrefix != "" || result.DestinationPortRange != "" {
+			t.Errorf("Singular fields should be empty, got %q / %q", result.SourceAddressPrefix, result.DestinationPortRange)
+		}
+		if strings.Join(result.SourcePrefixes(), ",") != "10.1.0.0/16,10.2.0.0/16" {
+			t.Errorf("SourcePrefixes mismatch: got %v", result.SourcePrefixes())
+		}
+		if strings.Join(result.SourcePorts(), ",") != "1024-65535" {
+			t.Errorf("SourcePorts mismatch: got %v", result.SourcePorts())
+		}
+		if strings.Join(result.DestinationPorts(), ",") != "443,8443" {
+			t.Errorf("DestinationPorts mismatch: got %v", result.DestinationPorts())
+		}
+		if len(result.DestinationASGs) != 1 || result.DestinationASGs[0] != "asg-web" {
+			t.Errorf("DestinationASGs mismatch: got %v", result.DestinationASGs)
+		}
+		if len(result.SourceASGs) != 0 || len(result.DestinationPrefixes()) != 0 {
+			t.Errorf("Expected no source ASGs or destination prefixes, got %v / %v", result.SourceASGs, result.DestinationPrefixes())
+		}
+		if result.IsDefault {
+			t.Error("extractSecurityRule should not mark rules as default")
+		}
+	})
 }
 
 // Helper functions for tests
@@ -379,3 +560,986 @@ func int32Ptr(i int32) *int32 {
 func int64Ptr(i int64) *int64 {
 	return &i
 }
+
+func TestExtractResourceGroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		resourceID string
+		expected   string
+	}{
+		{"empty string", "", ""},
+		{"vnet ID", "/subscriptions/sub1/resourceGroups/rg-hub/providers/Microsoft.Network/virtualNetworks/vnet1", "rg-hub"},
+		{"lowercase segment", "/subscriptions/sub1/resourcegroups/rg-spoke/providers/Microsoft.Network/virtualNetworks/vnet1", "rg-spoke"},
+		{"no resource group", "/subscriptions/sub1", ""},
+		{"trailing resourceGroups", "/subscriptions/sub1/resourceGroups", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := extractResourceGroup(tt.resourceID); result != tt.expected {
+				t.Errorf("extractResourceGroup(%q) = %q, want %q", tt.resourceID, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubnetIPUsage(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name          string
+		addressPrefix string
+		usage         armnetwork.VirtualNetworkUsage
+		expected      models.SubnetIPUsage
+	}{
+		{"sized from prefix", "10.0.1.0/24", armnetwork.VirtualNetworkUsage{CurrentValue: float(10), Limit: float(251)},
+			models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 10, AvailableIPs: 241}},
+		{"full subnet", "10.0.2.0/29", armnetwork.VirtualNetworkUsage{CurrentValue: float(3), Limit: float(3)},
+			models.SubnetIPUsage{TotalIPs: 8, ReservedIPs: 5, UsedIPs: 3, AvailableIPs: 0}},
+		{"no prefix falls back to limit", "", armnetwork.VirtualNetworkUsage{CurrentValue: float(4), Limit: float(59)},
+			models.SubnetIPUsage{TotalIPs: 64, ReservedIPs: 5, UsedIPs: 4, AvailableIPs: 55}},
+		{"more used than usable", "10.0.2.0/29", armnetwork.VirtualNetworkUsage{CurrentValue: float(4), Limit: float(3)},
+			models.SubnetIPUsage{TotalIPs: 8, ReservedIPs: 5, UsedIPs: 4, AvailableIPs: 0}},
+		{"malformed prefix falls back to limit", "10.0.3.0/33", armnetwork.VirtualNetworkUsage{CurrentValue: float(1), Limit: float(11)},
+			models.SubnetIPUsage{TotalIPs: 16, ReservedIPs: 5, UsedIPs: 1, AvailableIPs: 10}},
+		{"IPv6 prefix falls back to limit", "fd00::/64", armnetwork.VirtualNetworkUsage{Limit: float(27)},
+			models.SubnetIPUsage{TotalIPs: 32, ReservedIPs: 5, AvailableIPs: 27}},
+		{"nil values", "", armnetwork.VirtualNetworkUsage{},
+			models.SubnetIPUsage{TotalIPs: 5, ReservedIPs: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := subnetIPUsage(tt.addressPrefix, &tt.usage); *result != tt.expected {
+				t.Errorf("subnetIPUsage(%q) = %+v, want %+v", tt.addressPrefix, *result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractSubscriptionID(t *testing.T) {
+	tests := []struct {
+		name       string
+		resourceID string
+		expected   string
+	}{
+		{"empty string", "", ""},
+		{"vnet ID", "/subscriptions/sub1/resourceGroups/rg-hub/providers/Microsoft.Network/virtualNetworks/vnet1", "sub1"},
+		{"subscription ID", "/subscriptions/sub2", "sub2"},
+		{"lowercase segment", "/SUBSCRIPTIONS/sub3/resourceGroups/rg", "sub3"},
+		{"management group", "/providers/Microsoft.Management/managementGroups/mg1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := extractSubscriptionID(tt.resourceID); result != tt.expected {
+				t.Errorf("extractSubscriptionID(%q) = %q, want %q", tt.resourceID, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractVNetLink(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("linked VNet with auto-registration", func(t *testing.T) {
+		linkState := armprivatedns.VirtualNetworkLinkStateCompleted
+		link := &armprivatedns.VirtualNetworkLink{
+			ID:   strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net/virtualNetworkLinks/link1"),
+			Name: strPtr("link1"),
+			Properties: &armprivatedns.VirtualNetworkLinkProperties{
+				VirtualNetwork: &armprivatedns.SubResource{
+					ID: strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet-hub"),
+				},
+				RegistrationEnabled:     boolPtr(true),
+				VirtualNetworkLinkState: &linkState,
+			},
+		}
+
+		result := client.extractVNetLink(link)
+
+		if result.Name != "link1" {
+			t.Errorf("Name mismatch: got %s", result.Name)
+		}
+		if result.VNetName != "vnet-hub" {
+			t.Errorf("VNetName mismatch: got %s", result.VNetName)
+		}
+		if !result.RegistrationEnabled {
+			t.Error("RegistrationEnabled should be true")
+		}
+		if result.LinkState != "Completed" {
+			t.Errorf("LinkState mismatch: got %s", result.LinkState)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractVNetLink(&armprivatedns.VirtualNetworkLink{Name: strPtr("link2")})
+
+		if result.VNetID != "" || result.RegistrationEnabled {
+			t.Errorf("Expected empty link, got %+v", result)
+		}
+	})
+}
+
+func TestExtractARecord(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("private endpoint record", func(t *testing.T) {
+		recordSet := &armprivatedns.RecordSet{
+			Name: strPtr("stprod"),
+			Properties: &armprivatedns.RecordSetProperties{
+				Fqdn: strPtr("stprod.privatelink.blob.core.windows.net."),
+				TTL:  int64Ptr(10),
+				ARecords: []*armprivatedns.ARecord{
+					{IPv4Address: strPtr("10.0.2.11")},
+					nil,
+					{IPv4Address: strPtr("10.0.2.12")},
+				},
+			},
+		}
+
+		result := client.extractARecord(recordSet)
+
+		if result.FQDN != "stprod.privatelink.blob.core.windows.net" {
+			t.Errorf("FQDN should not have a trailing dot: got %s", result.FQDN)
+		}
+		if result.TTL != 10 {
+			t.Errorf("TTL mismatch: got %d", result.TTL)
+		}
+		if len(result.IPAddresses) != 2 || result.IPAddresses[0] != "10.0.2.11" {
+			t.Errorf("IPAddresses mismatch: got %v", result.IPAddresses)
+		}
+		if result.AutoRegistered {
+			t.Error("AutoRegistered should be false")
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractARecord(&armprivatedns.RecordSet{Name: strPtr("empty")})
+
+		if result.IPAddresses == nil || len(result.IPAddresses) != 0 {
+			t.Errorf("Expected empty IP list, got %v", result.IPAddresses)
+		}
+	})
+}
+
+func TestExtractFlowLog(t *testing.T) {
+	client := &AzureClient{}
+	nsgID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkSecurityGroups/nsg-web"
+
+	t.Run("NSG flow log with retention and traffic analytics", func(t *testing.T) {
+		flowLog := &armnetwork.FlowLog{
+			ID:       strPtr("/subscriptions/sub1/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_eastus/flowLogs/fl-nsg-web"),
+			Name:     strPtr("fl-nsg-web"),
+			Location: strPtr("eastus"),
+			Properties: &armnetwork.FlowLogPropertiesFormat{
+				TargetResourceID: strPtr(nsgID),
+				StorageID:        strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Storage/storageAccounts/stlogs"),
+				Enabled:          boolPtr(true),
+				RetentionPolicy: &armnetwork.RetentionPolicyParameters{
+					Enabled: boolPtr(true),
+					Days:    int32Ptr(30),
+				},
+				FlowAnalyticsConfiguration: &armnetwork.TrafficAnalyticsProperties{
+					NetworkWatcherFlowAnalyticsConfiguration: &armnetwork.TrafficAnalyticsConfigurationProperties{
+						Enabled: boolPtr(true),
+					},
+				},
+			},
+		}
+
+		result := client.extractFlowLog(flowLog)
+
+		if result.NSGId != nsgID || result.TargetResourceID != nsgID {
+			t.Errorf("Target mismatch: nsgId=%s target=%s", result.NSGId, result.TargetResourceID)
+		}
+		if !result.Enabled || !result.TrafficAnalytics {
+			t.Error("Expected enabled flow log with traffic analytics")
+		}
+		if result.RetentionDays != 30 {
+			t.Errorf("RetentionDays mismatch: got %d", result.RetentionDays)
+		}
+		if result.Name != "fl-nsg-web" || result.Location != "eastus" {
+			t.Errorf("Name/Location mismatch: got %s/%s", result.Name, result.Location)
+		}
+	})
+
+	t.Run("VNet flow log with retention disabled", func(t *testing.T) {
+		vnetID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet-hub"
+		flowLog := &armnetwork.FlowLog{
+			Name: strPtr("fl-vnet-hub"),
+			Properties: &armnetwork.FlowLogPropertiesFormat{
+				TargetResourceID: strPtr(vnetID),
+				Enabled:          boolPtr(false),
+				RetentionPolicy: &armnetwork.RetentionPolicyParameters{
+					Enabled: boolPtr(false),
+					Days:    int32Ptr(90),
+				},
+			},
+		}
+
+		result := client.extractFlowLog(flowLog)
+
+		if result.NSGId != "" {
+			t.Errorf("NSGId should be empty for a VNet target, got %s", result.NSGId)
+		}
+		if result.TargetResourceID != vnetID {
+			t.Errorf("TargetResourceID mismatch: got %s", result.TargetResourceID)
+		}
+		if result.RetentionDays != 0 {
+			t.Errorf("RetentionDays should be 0 when retention is disabled, got %d", result.RetentionDays)
+		}
+	})
+}
+
+func TestExtractConnectionMonitor(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("classic source and destination", func(t *testing.T) {
+		monitor := &armnetwork.ConnectionMonitorResult{
+			Name: strPtr("monitor-web-to-db"),
+			Properties: &armnetwork.ConnectionMonitorResultProperties{
+				Source: &armnetwork.ConnectionMonitorSource{
+					ResourceID: strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm-web-01"),
+				},
+				Destination: &armnetwork.ConnectionMonitorDestination{
+					Address: strPtr("10.0.2.10"),
+				},
+				MonitoringStatus: strPtr("Running"),
+			},
+		}
+
+		result := client.extractConnectionMonitor(monitor)
+
+		if result.Source != "vm-web-01" || result.Destination != "10.0.2.10" {
+			t.Errorf("Endpoints mismatch: got %s -> %s", result.Source, result.Destination)
+		}
+		if result.MonitoringStatus != "Running" {
+			t.Errorf("MonitoringStatus mismatch: got %s", result.MonitoringStatus)
+		}
+	})
+
+	t.Run("test group endpoints", func(t *testing.T) {
+		monitor := &armnetwork.ConnectionMonitorResult{
+			Name: strPtr("monitor-hub"),
+			Properties: &armnetwork.ConnectionMonitorResultProperties{
+				TestGroups: []*armnetwork.ConnectionMonitorTestGroup{
+					{
+						Sources:      []*string{strPtr("vm-web-01"), strPtr("vm-web-02")},
+						Destinations: []*string{strPtr("sql-server-prod")},
+					},
+					nil,
+					{
+						Sources:      []*string{strPtr("vm-web-01")},
+						Destinations: []*string{strPtr("stprod")},
+					},
+				},
+			},
+		}
+
+		result := client.extractConnectionMonitor(monitor)
+
+		if result.Source != "vm-web-01, vm-web-02" {
+			t.Errorf("Source mismatch: got %s", result.Source)
+		}
+		if result.Destination != "sql-server-prod, stprod" {
+			t.Errorf("Destination mismatch: got %s", result.Destination)
+		}
+	})
+}
+
+func TestExtractPacketCapture(t *testing.T) {
+	client := &AzureClient{}
+	state := armnetwork.ProvisioningStateSucceeded
+	capture := &armnetwork.PacketCaptureResult{
+		Name: strPtr("capture-web"),
+		Properties: &armnetwork.PacketCaptureResultProperties{
+			Target:            strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Compute/virtualMachines/vm-web-01"),
+			ProvisioningState: &state,
+		},
+	}
+
+	result := client.extractPacketCapture(capture)
+
+	if result.Target != "vm-web-01" {
+		t.Errorf("Target mismatch: got %s", result.Target)
+	}
+	if result.Status != "Succeeded" {
+		t.Errorf("Status mismatch: got %s", result.Status)
+	}
+}
+
+func TestExtractCustomDNSConfig(t *testing.T) {
+	client := &AzureClient{}
+	config := &armnetwork.CustomDNSConfigPropertiesFormat{
+		Fqdn:        strPtr("sql-server-prod.database.windows.net"),
+		IPAddresses: []*string{strPtr("10.0.2.10"), nil},
+	}
+
+	result := client.extractCustomDNSConfig(config)
+
+	if result.FQDN != "sql-server-prod.database.windows.net" {
+		t.Errorf("FQDN mismatch: got %s", result.FQDN)
+	}
+	if len(result.IPAddresses) != 1 || result.IPAddresses[0] != "10.0.2.10" {
+		t.Errorf("IPAddresses mismatch: got %v", result.IPAddresses)
+	}
+}
+
+func TestExtractNICDNSConfigs(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("one IP configuration per member", func(t *testing.T) {
+		nic := &armnetwork.Interface{
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+					{
+						Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							PrivateIPAddress: strPtr("10.0.2.20"),
+							PrivateLinkConnectionProperties: &armnetwork.InterfaceIPConfigurationPrivateLinkConnectionProperties{
+								Fqdns: []*string{strPtr("cosmos-prod.documents.azure.com")},
+							},
+						},
+					},
+					{
+						Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							PrivateIPAddress: strPtr("10.0.2.21"),
+							PrivateLinkConnectionProperties: &armnetwork.InterfaceIPConfigurationPrivateLinkConnectionProperties{
+								Fqdns: []*string{strPtr("cosmos-prod-eastus.documents.azure.com")},
+							},
+						},
+					},
+					nil,
+					{Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{}},
+				},
+			},
+		}
+
+		ips, configs := client.extractNICDNSConfigs(nic)
+
+		if len(ips) != 2 || ips[0] != "10.0.2.20" || ips[1] != "10.0.2.21" {
+			t.Errorf("IPs mismatch: got %v", ips)
+		}
+		if len(configs) != 2 || configs[1].FQDN != "cosmos-prod-eastus.documents.azure.com" || configs[1].IPAddresses[0] != "10.0.2.21" {
+			t.Errorf("DNS configs mismatch: got %+v", configs)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		ips, configs := client.extractNICDNSConfigs(&armnetwork.Interface{})
+
+		if ips == nil || configs == nil || len(ips) != 0 || len(configs) != 0 {
+			t.Errorf("Expected empty results, got %v / %v", ips, configs)
+		}
+	})
+}
+
+func TestDNSConfigIPs(t *testing.T) {
+	configs := []models.PrivateEndpointDNSConfig{
+		{FQDN: "a.documents.azure.com", IPAddresses: []string{"10.0.2.20"}},
+		{FQDN: "a-eastus.documents.azure.com", IPAddresses: []string{"10.0.2.21", "10.0.2.20"}},
+	}
+
+	ips := dnsConfigIPs(configs)
+	if len(ips) != 2 || ips[0] != "10.0.2.20" || ips[1] != "10.0.2.21" {
+		t.Errorf("Expected distinct IPs in order, got %v", ips)
+	}
+}
+
+func TestExtractLocalNetworkGateway(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("gateway with BGP", func(t *testing.T) {
+		lng := &armnetwork.LocalNetworkGateway{
+			ID:       strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/localNetworkGateways/lng1"),
+			Name:     strPtr("lng1"),
+			Location: strPtr("eastus"),
+			Tags:     map[string]*string{"environment": strPtr("prod")},
+			Properties: &armnetwork.LocalNetworkGatewayPropertiesFormat{
+				GatewayIPAddress: strPtr("203.0.113.10"),
+				LocalNetworkAddressSpace: &armnetwork.AddressSpace{
+					AddressPrefixes: []*string{strPtr("192.168.0.0/16"), strPtr("172.16.0.0/20")},
+				},
+				BgpSettings: &armnetwork.BgpSettings{
+					Asn:               int64Ptr(65010),
+					BgpPeeringAddress: strPtr("192.168.255.1"),
+				},
+			},
+		}
+
+		result := client.extractLocalNetworkGateway(lng)
+
+		if result.Name != "lng1" || result.ResourceGroup != "rg1" || result.GatewayIPAddress != "203.0.113.10" {
+			t.Errorf("Name/ResourceGroup/GatewayIPAddress mismatch: got %s / %s / %s", result.Name, result.ResourceGroup, result.GatewayIPAddress)
+		}
+		if len(result.AddressPrefixes) != 2 || result.AddressPrefixes[1] != "172.16.0.0/20" {
+			t.Errorf("AddressPrefixes mismatch: got %v", result.AddressPrefixes)
+		}
+		if result.BGPSettings == nil || result.BGPSettings.ASN != 65010 || result.BGPSettings.BGPPeeringAddress != "192.168.255.1" {
+			t.Errorf("BGPSettings mismatch: got %+v", result.BGPSettings)
+		}
+		if result.Tags["environment"] != "prod" {
+			t.Errorf("Tags mismatch: got %v", result.Tags)
+		}
+	})
+
+	t.Run("FQDN without BGP", func(t *testing.T) {
+		lng := &armnetwork.LocalNetworkGateway{
+			Name: strPtr("lng2"),
+			Properties: &armnetwork.LocalNetworkGatewayPropertiesFormat{
+				Fqdn: strPtr("vpn.branch.contoso.com"),
+			},
+		}
+
+		result := client.extractLocalNetworkGateway(lng)
+
+		if result.FQDN != "vpn.branch.contoso.com" || result.GatewayIPAddress != "" {
+			t.Errorf("FQDN/GatewayIPAddress mismatch: got %s / %s", result.FQDN, result.GatewayIPAddress)
+		}
+		if result.BGPSettings != nil || len(result.AddressPrefixes) != 0 || result.Connections == nil {
+			t.Errorf("Expected no BGP, no prefixes and an empty connection list, got %+v", result)
+		}
+	})
+}
+
+func TestExtractPrivateLinkService(t *testing.T) {
+	client := &AzureClient{}
+	prefix := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/"
+
+	t.Run("service with connections", func(t *testing.T) {
+		static := armnetwork.IPAllocationMethodStatic
+		pls := &armnetwork.PrivateLinkService{
+			ID:       strPtr(prefix + "privateLinkServices/pls1"),
+			Name:     strPtr("pls1"),
+			Location: strPtr("eastus"),
+			Properties: &armnetwork.PrivateLinkServiceProperties{
+				Alias: strPtr("pls1.guid.eastus.azure.privatelinkservice"),
+				LoadBalancerFrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+					{ID: strPtr(prefix + "loadBalancers/lb1/frontendIPConfigurations/fe1")},
+				},
+				IPConfigurations: []*armnetwork.PrivateLinkServiceIPConfiguration{
+					{
+						Name: strPtr("nat1"),
+						Properties: &armnetwork.PrivateLinkServiceIPConfigurationProperties{
+							Primary:                   boolPtr(true),
+							PrivateIPAddress:          strPtr("10.0.1.20"),
+							PrivateIPAllocationMethod: &static,
+							Subnet:                    &armnetwork.Subnet{ID: strPtr(prefix + "virtualNetworks/vnet1/subnets/app")},
+						},
+					},
+				},
+				Visibility:          &armnetwork.PrivateLinkServicePropertiesVisibility{Subscriptions: []*string{strPtr("*")}},
+				AutoApproval:        &armnetwork.PrivateLinkServicePropertiesAutoApproval{Subscriptions: []*string{strPtr("sub2")}},
+				EnableProxyProtocol: boolPtr(true),
+				Fqdns:               []*string{strPtr("app.contoso.com")},
+				PrivateEndpointConnections: []*armnetwork.PrivateEndpointConnection{
+					{
+						ID:   strPtr(prefix + "privateLinkServices/pls1/privateEndpointConnections/pe1.guid"),
+						Name: strPtr("pe1.guid"),
+						Properties: &armnetwork.PrivateEndpointConnectionProperties{
+							PrivateEndpoint: &armnetwork.PrivateEndpoint{ID: strPtr("/subscriptions/sub2/resourceGroups/rg2/providers/Microsoft.Network/privateEndpoints/pe1")},
+							PrivateLinkServiceConnectionState: &armnetwork.PrivateLinkServiceConnectionState{
+								Status:      strPtr("Pending"),
+								Description: strPtr("please approve"),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		result := client.extractPrivateLinkService(pls)
+
+		if result.Name != "pls1" || result.ResourceGroup != "rg1" || result.Alias != "pls1.guid.eastus.azure.privatelinkservice" {
+			t.Errorf("Name/ResourceGroup/Alias mismatch: got %s / %s / %s", result.Name, result.ResourceGroup, result.Alias)
+		}
+		if result.LoadBalancerID != prefix+"loadBalancers/lb1" || len(result.LoadBalancerFrontendIDs) != 1 {
+			t.Errorf("Load balancer mismatch: got %s / %v", result.LoadBalancerID, result.LoadBalancerFrontendIDs)
+		}
+		if len(result.IPConfigurations) != 1 {
+			t.Fatalf("Expected 1 NAT IP configuration, got %d", len(result.IPConfigurations))
+		}
+		if ic := result.IPConfigurations[0]; ic.PrivateIPAddress != "10.0.1.20" || ic.AllocationMethod != "Static" || !ic.Primary || ic.SubnetID != prefix+"virtualNetworks/vnet1/subnets/app" {
+			t.Errorf("NAT IP configuration mismatch: got %+v", ic)
+		}
+		if len(result.Visibility) != 1 || result.Visibility[0] != "*" || len(result.AutoApproval) != 1 || result.AutoApproval[0] != "sub2" {
+			t.Errorf("Visibility/AutoApproval mismatch: got %v / %v", result.Visibility, result.AutoApproval)
+		}
+		if !result.EnableProxyProtocol || len(result.FQDNs) != 1 {
+			t.Errorf("Proxy protocol/FQDNs mismatch: got %v / %v", result.EnableProxyProtocol, result.FQDNs)
+		}
+		if len(result.Connections) != 1 {
+			t.Fatalf("Expected 1 connection, got %d", len(result.Connections))
+		}
+		if conn := result.Connections[0]; conn.Status != "Pending" || conn.Description != "please approve" || extractResourceName(conn.PrivateEndpointID) != "pe1" {
+			t.Errorf("Connection mismatch: got %+v", conn)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractPrivateLinkService(&armnetwork.PrivateLinkService{Name: strPtr("pls1")})
+
+		if result.LoadBalancerID != "" || result.Visibility == nil || result.Connections == nil {
+			t.Errorf("Expected empty private link service with initialized slices, got %+v", result)
+		}
+	})
+}
+
+func TestExtractPublicIPAddress(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("load balancer frontend", func(t *testing.T) {
+		skuName := armnetwork.PublicIPAddressSKUNameStandard
+		skuTier := armnetwork.PublicIPAddressSKUTierRegional
+		method := armnetwork.IPAllocationMethodStatic
+		version := armnetwork.IPVersionIPv4
+		coverage := armnetwork.DdosSettingsProtectionCoverageStandard
+
+		pip := &armnetwork.PublicIPAddress{
+			ID:       strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/publicIPAddresses/pip-lb"),
+			Name:     strPtr("pip-lb"),
+			Location: strPtr("eastus"),
+			SKU:      &armnetwork.PublicIPAddressSKU{Name: &skuName, Tier: &skuTier},
+			Zones:    []*string{strPtr("1"), strPtr("2"), nil},
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				IPAddress:                strPtr("20.0.0.1"),
+				PublicIPAllocationMethod: &method,
+				PublicIPAddressVersion:   &version,
+				DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+					DomainNameLabel: strPtr("web-prod"),
+					Fqdn:            strPtr("web-prod.eastus.cloudapp.azure.com"),
+				},
+				DdosSettings: &armnetwork.DdosSettings{ProtectionCoverage: &coverage},
+				IPConfiguration: &armnetwork.IPConfiguration{
+					ID: strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/loadBalancers/lb1/frontendIPConfigurations/fe1"),
+				},
+			},
+		}
+
+		result := client.extractPublicIPAddress(pip)
+
+		if result.ResourceGroup != "rg1" || result.SubscriptionID != "sub1" {
+			t.Errorf("ResourceGroup/SubscriptionID mismatch: got %s / %s", result.ResourceGroup, result.SubscriptionID)
+		}
+		if result.IPAddress != "20.0.0.1" || result.SKU != "Standard" || result.Tier != "Regional" {
+			t.Errorf("IP/SKU mismatch: got %s %s %s", result.IPAddress, result.SKU, result.Tier)
+		}
+		if result.AllocationMethod != "Static" || result.IPVersion != "IPv4" {
+			t.Errorf("AllocationMethod/IPVersion mismatch: got %s / %s", result.AllocationMethod, result.IPVersion)
+		}
+		if len(result.Zones) != 2 {
+			t.Errorf("Zones mismatch: got %v", result.Zones)
+		}
+		if result.DNSLabel != "web-prod" || result.FQDN != "web-prod.eastus.cloudapp.azure.com" {
+			t.Errorf("DNS mismatch: got %s / %s", result.DNSLabel, result.FQDN)
+		}
+		if result.DDoSProtection != "Standard" {
+			t.Errorf("DDoSProtection mismatch: got %s", result.DDoSProtection)
+		}
+		if result.AssociatedResourceID != "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/loadBalancers/lb1" {
+			t.Errorf("AssociatedResourceID mismatch: got %s", result.AssociatedResourceID)
+		}
+	})
+
+	t.Run("NAT gateway", func(t *testing.T) {
+		natID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/natGateways/nat1"
+		pip := &armnetwork.PublicIPAddress{
+			Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+				NatGateway: &armnetwork.NatGateway{ID: &natID},
+			},
+		}
+
+		result := client.extractPublicIPAddress(pip)
+
+		if result.AssociatedResourceID != natID || result.IPConfigurationID != "" {
+			t.Errorf("Expected association with %s, got %+v", natID, result)
+		}
+	})
+
+	t.Run("unassociated", func(t *testing.T) {
+		result := client.extractPublicIPAddress(&armnetwork.PublicIPAddress{Name: strPtr("pip-unused")})
+
+		if result.AssociatedResourceID != "" || result.Zones == nil {
+			t.Errorf("Expected no association and empty zones, got %+v", result)
+		}
+	})
+}
+
+func TestExtractParentResourceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		childID  string
+		expected string
+	}{
+		{
+			name:     "child resource",
+			childID:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/applicationGateways/agw1/frontendIPConfigurations/fe1",
+			expected: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/applicationGateways/agw1",
+		},
+		{
+			name:     "top-level resource",
+			childID:  "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/natGateways/nat1",
+			expected: "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/natGateways/nat1",
+		},
+		{
+			name:     "not a resource ID",
+			childID:  "pip1",
+			expected: "pip1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractParentResourceID(tt.childID)
+			if result != tt.expected {
+				t.Errorf("extractParentResourceID(%q) = %q, want %q", tt.childID, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractBastionHost(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("standard SKU with features", func(t *testing.T) {
+		sku := armnetwork.BastionHostSKUNameStandard
+		subnetID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/AzureBastionSubnet"
+		bastion := &armnetwork.BastionHost{
+			ID:       strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/bastionHosts/bas1"),
+			Name:     strPtr("bas1"),
+			Location: strPtr("eastus"),
+			SKU:      &armnetwork.SKU{Name: &sku},
+			Properties: &armnetwork.BastionHostPropertiesFormat{
+				DNSName:             strPtr("bst-1.bastion.azure.com"),
+				EnableTunneling:     boolPtr(true),
+				EnableShareableLink: boolPtr(false),
+				ScaleUnits:          int32Ptr(4),
+				IPConfigurations: []*armnetwork.BastionHostIPConfiguration{
+					{
+						Properties: &armnetwork.BastionHostIPConfigurationPropertiesFormat{
+							Subnet:          &armnetwork.SubResource{ID: strPtr(subnetID)},
+							PublicIPAddress: &armnetwork.SubResource{ID: strPtr("pip-bastion")},
+						},
+					},
+				},
+			},
+		}
+
+		result := client.extractBastionHost(bastion)
+
+		if result.Name != "bas1" || result.ResourceGroup != "rg1" || result.SKU != "Standard" {
+			t.Errorf("Name/ResourceGroup/SKU mismatch: got %s / %s / %s", result.Name, result.ResourceGroup, result.SKU)
+		}
+		if result.SubnetID != subnetID || result.VNetID != "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet-hub" {
+			t.Errorf("SubnetID/VNetID mismatch: got %s / %s", result.SubnetID, result.VNetID)
+		}
+		if result.PublicIPAddressID != "pip-bastion" || result.ScaleUnits != 4 {
+			t.Errorf("PublicIPAddressID/ScaleUnits mismatch: got %s / %d", result.PublicIPAddressID, result.ScaleUnits)
+		}
+		if !result.Tunneling || result.ShareableLink || result.IPConnect {
+			t.Errorf("Feature flags mismatch: got %+v", result)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractBastionHost(&armnetwork.BastionHost{Name: strPtr("bas1")})
+
+		if result.SubnetID != "" || result.SKU != "" || result.Tunneling {
+			t.Errorf("Expected empty Bastion host, got %+v", result)
+		}
+	})
+}
+
+func TestExtractVirtualHub(t *testing.T) {
+	client := &AzureClient{}
+	prefix := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/"
+
+	t.Run("secured hub", func(t *testing.T) {
+		preference := armnetwork.HubRoutingPreferenceASPath
+		state := armnetwork.RoutingStateProvisioned
+		hub := &armnetwork.VirtualHub{
+			ID:       strPtr(prefix + "virtualHubs/vhub1"),
+			Name:     strPtr("vhub1"),
+			Location: strPtr("eastus"),
+			Properties: &armnetwork.VirtualHubProperties{
+				AddressPrefix:        strPtr("10.100.0.0/23"),
+				SKU:                  strPtr("Standard"),
+				VirtualWan:           &armnetwork.SubResource{ID: strPtr(prefix + "virtualWans/vwan1")},
+				AzureFirewall:        &armnetwork.SubResource{ID: strPtr(prefix + "azureFirewalls/fw1")},
+				VPNGateway:           &armnetwork.SubResource{ID: strPtr(prefix + "vpnGateways/vpngw1")},
+				HubRoutingPreference: &preference,
+				RoutingState:         &state,
+				VirtualRouterIPs:     []*string{strPtr("10.100.0.68"), strPtr("10.100.0.69")},
+			},
+		}
+
+		result := client.extractVirtualHub(hub)
+
+		if result.Name != "vhub1" || result.ResourceGroup != "rg1" || result.AddressPrefix != "10.100.0.0/23" {
+			t.Errorf("Name/ResourceGroup/AddressPrefix mismatch: got %s / %s / %s", result.Name, result.ResourceGroup, result.AddressPrefix)
+		}
+		if result.VirtualWANID != prefix+"virtualWans/vwan1" || result.AzureFirewallID != prefix+"azureFirewalls/fw1" {
+			t.Errorf("VirtualWANID/AzureFirewallID mismatch: got %s / %s", result.VirtualWANID, result.AzureFirewallID)
+		}
+		if result.VPNGatewayID != prefix+"vpnGateways/vpngw1" || result.ExpressRouteGatewayID != "" {
+			t.Errorf("Gateway IDs mismatch: got %s / %s", result.VPNGatewayID, result.ExpressRouteGatewayID)
+		}
+		if result.RoutingPreference != "ASPath" || result.RoutingState != "Provisioned" || len(result.VirtualRouterIPs) != 2 {
+			t.Errorf("Routing settings mismatch: got %+v", result)
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractVirtualHub(&armnetwork.VirtualHub{Name: strPtr("vhub1")})
+
+		if result.VirtualWANID != "" || result.VNetConnections == nil || result.RouteTables == nil || result.Gateways == nil {
+			t.Errorf("Expected empty hub with initialized slices, got %+v", result)
+		}
+	})
+}
+
+func TestExtractHubRouting(t *testing.T) {
+	client := &AzureClient{}
+	hubID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualHubs/vhub1"
+	fwID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/azureFirewalls/fw1"
+
+	t.Run("VNet connection", func(t *testing.T) {
+		conn := &armnetwork.HubVirtualNetworkConnection{
+			ID:   strPtr(hubID + "/hubVirtualNetworkConnections/conn1"),
+			Name: strPtr("conn1"),
+			Properties: &armnetwork.HubVirtualNetworkConnectionProperties{
+				RemoteVirtualNetwork:   &armnetwork.SubResource{ID: strPtr("vnet-spoke")},
+				EnableInternetSecurity: boolPtr(true),
+				RoutingConfiguration: &armnetwork.RoutingConfiguration{
+					AssociatedRouteTable: &armnetwork.SubResource{ID: strPtr(hubID + "/hubRouteTables/defaultRouteTable")},
+					PropagatedRouteTables: &armnetwork.PropagatedRouteTable{
+						IDs:    []*armnetwork.SubResource{{ID: strPtr(hubID + "/hubRouteTables/defaultRouteTable")}},
+						Labels: []*string{strPtr("default")},
+					},
+				},
+			},
+		}
+
+		result := client.extractHubVNetConnection(conn)
+
+		if result.RemoteVNetID != "vnet-spoke" || !result.EnableInternetSecurity {
+			t.Errorf("RemoteVNetID/EnableInternetSecurity mismatch: got %+v", result)
+		}
+		if result.AssociatedRouteTable != hubID+"/hubRouteTables/defaultRouteTable" || len(result.PropagatedRouteTables) != 1 || len(result.PropagatedLabels) != 1 {
+			t.Errorf("Routing configuration mismatch: got %+v", result)
+		}
+	})
+
+	t.Run("route table", func(t *testing.T) {
+		table := &armnetwork.HubRouteTable{
+			ID:   strPtr(hubID + "/hubRouteTables/defaultRouteTable"),
+			Name: strPtr("defaultRouteTable"),
+			Properties: &armnetwork.HubRouteTableProperties{
+				Labels: []*string{strPtr("default")},
+				Routes: []*armnetwork.HubRoute{
+					{
+						Name:            strPtr("to-firewall"),
+						DestinationType: strPtr("CIDR"),
+						Destinations:    []*string{strPtr("0.0.0.0/0")},
+						NextHopType:     strPtr("ResourceId"),
+						NextHop:         strPtr(fwID),
+					},
+					nil,
+				},
+				AssociatedConnections: []*string{strPtr("conn1")},
+			},
+		}
+
+		result := client.extractHubRouteTable(table)
+
+		if len(result.Routes) != 1 || result.Routes[0].NextHop != fwID || result.Routes[0].Destinations[0] != "0.0.0.0/0" {
+			t.Errorf("Routes mismatch: got %+v", result.Routes)
+		}
+		if len(result.Labels) != 1 || len(result.AssociatedConnections) != 1 || result.PropagatingConnections == nil {
+			t.Errorf("Labels/connections mismatch: got %+v", result)
+		}
+	})
+
+	t.Run("routing intent", func(t *testing.T) {
+		intent := &armnetwork.RoutingIntent{
+			Name: strPtr("intent1"),
+			Properties: &armnetwork.RoutingIntentProperties{
+				RoutingPolicies: []*armnetwork.RoutingPolicy{
+					{Name: strPtr("InternetTraffic"), Destinations: []*string{strPtr("Internet")}, NextHop: strPtr(fwID)},
+					{Name: strPtr("PrivateTrafficPolicy"), Destinations: []*string{strPtr("PrivateTraffic")}, NextHop: strPtr(fwID)},
+				},
+			},
+		}
+
+		result := client.extractRoutingIntent(intent)
+
+		if len(result.RoutingPolicies) != 2 || result.NextHop("PrivateTraffic") != fwID {
+			t.Errorf("Routing policies mismatch: got %+v", result.RoutingPolicies)
+		}
+	})
+
+	t.Run("hub gateways", func(t *testing.T) {
+		vpn := &armnetwork.VPNGateway{
+			ID:   strPtr("vpngw1"),
+			Name: strPtr("vpngw1"),
+			Properties: &armnetwork.VPNGatewayProperties{
+				VirtualHub:          &armnetwork.SubResource{ID: strPtr(hubID)},
+				VPNGatewayScaleUnit: int32Ptr(2),
+				Connections: []*armnetwork.VPNConnection{
+					{Name: strPtr("conn-site1"), Properties: &armnetwork.VPNConnectionProperties{RemoteVPNSite: &armnetwork.SubResource{ID: strPtr("site1")}}},
+				},
+			},
+		}
+		er := &armnetwork.ExpressRouteGateway{
+			ID:   strPtr("ergw1"),
+			Name: strPtr("ergw1"),
+			Properties: &armnetwork.ExpressRouteGatewayProperties{
+				VirtualHub: &armnetwork.VirtualHubID{ID: strPtr(hubID)},
+				AutoScaleConfiguration: &armnetwork.ExpressRouteGatewayPropertiesAutoScaleConfiguration{
+					Bounds: &armnetwork.ExpressRouteGatewayPropertiesAutoScaleConfigurationBounds{Min: int32Ptr(1)},
+				},
+				ExpressRouteConnections: []*armnetwork.ExpressRouteConnection{
+					{Name: strPtr("conn-er1"), Properties: &armnetwork.ExpressRouteConnectionProperties{EnableInternetSecurity: boolPtr(true)}},
+				},
+			},
+		}
+
+		vpnGateway, vpnHub := client.extractHubVPNGateway(vpn)
+		if vpnHub != hubID || vpnGateway.Type != "VPN" || vpnGateway.ScaleUnits != 2 || vpnGateway.Connections[0].RemoteID != "site1" {
+			t.Errorf("VPN gateway mismatch: got %+v in %s", vpnGateway, vpnHub)
+		}
+		erGateway, erHub := client.extractExpressRouteGateway(er)
+		if erHub != hubID || erGateway.Type != "ExpressRoute" || erGateway.ScaleUnits != 1 || !erGateway.Connections[0].EnableInternetSecurity {
+			t.Errorf("ExpressRoute gateway mismatch: got %+v in %s", erGateway, erHub)
+		}
+	})
+}
+
+func TestExtractFirewallPolicy(t *testing.T) {
+	client := &AzureClient{}
+
+	t.Run("child policy", func(t *testing.T) {
+		tier := armnetwork.FirewallPolicySKUTierPremium
+		mode := armnetwork.AzureFirewallThreatIntelModeDeny
+		policy := &armnetwork.FirewallPolicy{
+			ID:       strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/firewallPolicies/fwp-child"),
+			Name:     strPtr("fwp-child"),
+			Location: strPtr("eastus"),
+			Properties: &armnetwork.FirewallPolicyPropertiesFormat{
+				SKU:             &armnetwork.FirewallPolicySKU{Tier: &tier},
+				ThreatIntelMode: &mode,
+				DNSSettings:     &armnetwork.DNSSettings{EnableProxy: boolPtr(true)},
+				BasePolicy:      &armnetwork.SubResource{ID: strPtr("fwp-base")},
+				Firewalls:       []*armnetwork.SubResource{{ID: strPtr("fw1")}, nil},
+			},
+		}
+
+		result := client.extractFirewallPolicy(policy)
+
+		if result.ResourceGroup != "rg1" || result.SubscriptionID != "sub1" {
+			t.Errorf("ResourceGroup/SubscriptionID mismatch: got %s / %s", result.ResourceGroup, result.SubscriptionID)
+		}
+		if result.SKU != "Premium" || result.ThreatIntelMode != "Deny" || !result.DNSProxyEnabled {
+			t.Errorf("Settings mismatch: got %+v", result)
+		}
+		if result.BasePolicyID != "fwp-base" {
+			t.Errorf("BasePolicyID mismatch: got %s", result.BasePolicyID)
+		}
+		if len(result.Firewalls) != 1 || result.Firewalls[0] != "fw1" {
+			t.Errorf("Firewalls mismatch: got %v", result.Firewalls)
+		}
+		if result.ChildPolicies == nil || result.RuleCollectionGroups == nil {
+			t.Error("Slices should be initialized")
+		}
+	})
+
+	t.Run("nil properties", func(t *testing.T) {
+		result := client.extractFirewallPolicy(&armnetwork.FirewallPolicy{Name: strPtr("fwp")})
+
+		if result.Name != "fwp" || result.BasePolicyID != "" || result.Firewalls == nil {
+			t.Errorf("Expected empty policy, got %+v", result)
+		}
+	})
+}
+
+func TestExtractRuleCollectionGroup(t *testing.T) {
+	client := &AzureClient{}
+
+	allow := armnetwork.FirewallPolicyFilterRuleCollectionActionTypeAllow
+	dnat := armnetwork.FirewallPolicyNatRuleCollectionActionTypeDNAT
+	tcp := armnetwork.FirewallPolicyRuleNetworkProtocolTCP
+	https := armnetwork.FirewallPolicyRuleApplicationProtocolTypeHTTPS
+
+	group := &armnetwork.FirewallPolicyRuleCollectionGroup{
+		ID:   strPtr("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/firewallPolicies/fwp/ruleCollectionGroups/rcg1"),
+		Name: strPtr("rcg1"),
+		Properties: &armnetwork.FirewallPolicyRuleCollectionGroupProperties{
+			Priority: int32Ptr(200),
+			RuleCollections: []armnetwork.FirewallPolicyRuleCollectionClassification{
+				&armnetwork.FirewallPolicyFilterRuleCollection{
+					Name:     strPtr("allow-web"),
+					Priority: int32Ptr(300),
+					Action:   &armnetwork.FirewallPolicyFilterRuleCollectionAction{Type: &allow},
+					Rules: []armnetwork.FirewallPolicyRuleClassification{
+						&armnetwork.Rule{
+							Name:                 strPtr("net"),
+							SourceAddresses:      []*string{strPtr("10.0.0.0/24")},
+							DestinationAddresses: []*string{strPtr("*")},
+							DestinationPorts:     []*string{strPtr("443")},
+							IPProtocols:          []*armnetwork.FirewallPolicyRuleNetworkProtocol{&tcp},
+						},
+						&armnetwork.ApplicationRule{
+							Name:        strPtr("app"),
+							TargetFqdns: []*string{strPtr("*.contoso.com")},
+							Protocols: []*armnetwork.FirewallPolicyRuleApplicationProtocol{
+								{ProtocolType: &https, Port: int32Ptr(443)},
+							},
+						},
+					},
+				},
+				&armnetwork.FirewallPolicyNatRuleCollection{
+					Name:     strPtr("inbound"),
+					Priority: int32Ptr(100),
+					Action:   &armnetwork.FirewallPolicyNatRuleCollectionAction{Type: &dnat},
+					Rules: []armnetwork.FirewallPolicyRuleClassification{
+						&armnetwork.NatRule{
+							Name:              strPtr("ssh"),
+							DestinationPorts:  []*string{strPtr("2222")},
+							TranslatedAddress: strPtr("10.0.0.4"),
+							TranslatedPort:    strPtr("22"),
+						},
+					},
+				},
+				nil,
+			},
+		},
+	}
+
+	result := client.extractRuleCollectionGroup(group)
+
+	if result.Name != "rcg1" || result.Priority != 200 {
+		t.Errorf("Group mismatch: got %s (%d)", result.Name, result.Priority)
+	}
+	if len(result.RuleCollections) != 2 {
+		t.Fatalf("Expected 2 rule collections, got %d", len(result.RuleCollections))
+	}
+
+	// Collections are sorted by priority
+	nat, filter := result.RuleCollections[0], result.RuleCollections[1]
+	if nat.Name != "inbound" || nat.CollectionType != "NAT" || nat.Action != "DNAT" {
+		t.Errorf("NAT collection mismatch: got %+v", nat)
+	}
+	if len(nat.Rules) != 1 || nat.Rules[0].RuleType != "NatRule" || nat.Rules[0].TranslatedPort != "22" {
+		t.Errorf("NAT rule mismatch: got %+v", nat.Rules)
+	}
+	if filter.CollectionType != "Filter" || filter.Action != "Allow" || len(filter.Rules) != 2 {
+		t.Fatalf("Filter collection mismatch: got %+v", filter)
+	}
+
+	network, app := filter.Rules[0], filter.Rules[1]
+	if network.RuleType != "NetworkRule" || strings.Join(network.Protocols, ",") != "TCP" || network.DestinationAddresses[0] != "*" {
+		t.Errorf("Network rule mismatch: got %+v", network)
+	}
+	if app.RuleType != "ApplicationRule" || strings.Join(app.Protocols, ",") != "Https:443" || app.TargetFQDNs[0] != "*.contoso.com" {
+		t.Errorf("Application rule mismatch: got %+v", app)
+	}
+}
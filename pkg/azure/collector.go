@@ -0,0 +1,556 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// Collector is implemented by any source of Azure network resources.
+// AzureClient collects from the live ARM APIs and MockAzureClient returns
+// canned data; both flow through CollectTopology.
+type Collector interface {
+	GetVirtualNetworks(ctx context.Context, resourceGroup string) ([]models.VirtualNetwork, error)
+	GetNetworkSecurityGroups(ctx context.Context, resourceGroup string) ([]models.NetworkSecurityGroup, error)
+	GetApplicationSecurityGroups(ctx context.Context, resourceGroup string) ([]models.ApplicationSecurityGroup, error)
+	GetPrivateEndpoints(ctx context.Context, resourceGroup string) ([]models.PrivateEndpoint, error)
+	GetPrivateLinkServices(ctx context.Context, resourceGroup string) ([]models.PrivateLinkService, error)
+	GetNetworkInterfaces(ctx context.Context, resourceGroup string) ([]models.NetworkInterface, error)
+	GetPublicIPAddresses(ctx context.Context, resourceGroup string) ([]models.PublicIPAddress, error)
+	GetPrivateDNSZones(ctx context.Context, resourceGroup string) ([]models.PrivateDNSZone, error)
+	GetRouteTables(ctx context.Context, resourceGroup string) ([]models.RouteTable, error)
+	GetNATGateways(ctx context.Context, resourceGroup string) ([]models.NATGateway, error)
+	GetVPNGateways(ctx context.Context, resourceGroup string) ([]models.VPNGateway, error)
+	GetLocalNetworkGateways(ctx context.Context, resourceGroup string) ([]models.LocalNetworkGateway, error)
+	GetExpressRouteCircuits(ctx context.Context, resourceGroup string) ([]models.ExpressRouteCircuit, error)
+	GetLoadBalancers(ctx context.Context, resourceGroup string) ([]models.LoadBalancer, error)
+	GetApplicationGateways(ctx context.Context, resourceGroup string) ([]models.ApplicationGateway, error)
+	GetAzureFirewalls(ctx context.Context, resourceGroup string) ([]models.AzureFirewall, error)
+	GetFirewallPolicies(ctx context.Context, resourceGroup string) ([]models.FirewallPolicy, error)
+	GetBastionHosts(ctx context.Context, resourceGroup string) ([]models.BastionHost, error)
+	GetVirtualWANs(ctx context.Context, resourceGroup string) ([]models.VirtualWAN, error)
+	GetVirtualHubs(ctx context.Context, resourceGroup string) ([]models.VirtualHub, error)
+	// GetNetworkWatcherInsights is called once per subscription after the other
+	// resources, with the locations they were found in
+	GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error)
+}
+
+// SubscriptionCollector is a Collector that can also query other subscriptions.
+// CollectTopology uses it to build one topology across several subscriptions;
+// wrappers around a SubscriptionCollector should implement ForSubscription
+// themselves so that the wrapping is preserved.
+type SubscriptionCollector interface {
+	Collector
+	ForSubscription(subscriptionID string) (Collector, error)
+}
+
+// Compile-time checks that both clients satisfy SubscriptionCollector
+var (
+	_ SubscriptionCollector = (*AzureClient)(nil)
+	_ SubscriptionCollector = (*MockAzureClient)(nil)
+)
+
+// CollectionScope describes which part of Azure to collect
+type CollectionScope struct {
+	// SubscriptionIDs lists the subscriptions to collect from. Resource groups
+	// are looked up in every subscription.
+	SubscriptionIDs []string
+
+	// ResourceGroups lists the resource groups to collect from
+	ResourceGroups []string
+
+	// AllResourceGroups collects every resource in the subscription using the
+	// subscription-level list operations; ResourceGroups is ignored
+	AllResourceGroups bool
+}
+
+// Description returns a human-readable summary of the resource groups in scope
+func (s CollectionScope) Description() string {
+	if s.AllResourceGroups {
+		return "(all resource groups)"
+	}
+	return strings.Join(s.groups(), ", ")
+}
+
+// subscriptions returns the subscription IDs in scope, de-duplicated case-insensitively
+func (s CollectionScope) subscriptions() []string {
+	return uniqueFold(s.SubscriptionIDs)
+}
+
+// groups returns the resource groups to pass to the collector, de-duplicated
+// case-insensitively. An empty group name asks the collector for the whole
+// subscription.
+func (s CollectionScope) groups() []string {
+	if s.AllResourceGroups {
+		return []string{""}
+	}
+
+	return uniqueFold(s.ResourceGroups)
+}
+
+// uniqueFold trims values and drops blanks and case-insensitive duplicates,
+// keeping the first spelling of each
+func uniqueFold(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// CollectOptions controls how CollectTopology fans out requests
+type CollectOptions struct {
+	// Concurrency is the maximum number of resource types collected at once
+	Concurrency int
+
+	// Strict aborts collection on the first resource type that fails. By default
+	// the failure is recorded in the topology's CollectionErrors and the other
+	// resource types are still collected.
+	Strict bool
+}
+
+// DefaultCollectOptions returns the options used by CollectTopology
+func DefaultCollectOptions() CollectOptions {
+	return CollectOptions{
+		Concurrency: DefaultConcurrency,
+	}
+}
+
+// CollectTopology runs every collector against the scope and assembles the results
+// into a single NetworkTopology using the default options.
+func CollectTopology(ctx context.Context, collector Collector, scope CollectionScope) (*models.NetworkTopology, error) {
+	return CollectTopologyWithOptions(ctx, collector, scope, DefaultCollectOptions())
+}
+
+// CollectTopologyWithOptions collects every resource type in every subscription and
+// resource group of the scope in parallel, bounded by opts.Concurrency. A resource
+// type that fails is recorded in CollectionErrors and left empty, unless opts.Strict
+// is set, in which case the first failure cancels the remaining requests. Network
+// Watcher insights are then collected for the locations of the collected resources;
+// they are best-effort even in strict mode. Resource slices are
+// sorted by ID so that reports stay stable regardless of completion order.
+func CollectTopologyWithOptions(ctx context.Context, collector Collector, scope CollectionScope, opts CollectOptions) (*models.NetworkTopology, error) {
+	subscriptions := scope.subscriptions()
+	if len(subscriptions) == 0 {
+		return nil, fmt.Errorf("no subscriptions to collect: specify at least one subscription")
+	}
+	groups := scope.groups()
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no resource groups to collect: specify at least one resource group or collect all resource groups")
+	}
+
+	// A single subscription is collected with the collector as given; several
+	// subscriptions need a collector per subscription
+	collectors := []Collector{collector}
+	if len(subscriptions) > 1 {
+		sc, ok := collector.(SubscriptionCollector)
+		if !ok {
+			return nil, fmt.Errorf("collector does not support multiple subscriptions")
+		}
+
+		collectors = make([]Collector, len(subscriptions))
+		for i, subscriptionID := range subscriptions {
+			c, err := sc.ForSubscription(subscriptionID)
+			if err != nil {
+				return nil, err
+			}
+			collectors[i] = c
+		}
+	}
+
+	topology := &models.NetworkTopology{
+		SubscriptionID: strings.Join(subscriptions, ", "),
+		ResourceGroup:  scope.Description(),
+		Timestamp:      time.Now(),
+	}
+
+	// Results from different subscriptions and resource groups land in the same slices
+	var mu sync.Mutex
+
+	var tasks []func(context.Context) error
+	for i, collector := range collectors {
+		for _, rg := range groups {
+			target := collectionTarget{resourceGroup: rg}
+			if len(subscriptions) > 1 {
+				target.subscriptionID = subscriptions[i]
+			}
+			tasks = append(tasks, collectionTasks(collector, target, topology, &mu)...)
+		}
+	}
+	if !opts.Strict {
+		for i, task := range tasks {
+			tasks[i] = tolerateFailure(task, topology, &mu)
+		}
+	}
+
+	if err := runBounded(ctx, opts.Concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	// Public IPs can be referenced from another resource group or subscription,
+	// so they are only resolved once everything has been collected
+	resolvePublicIPs(topology)
+
+	// Likewise, a VNet can be connected to a hub in another resource group
+	linkHubPeerings(topology)
+	linkLocalNetworkGateways(topology)
+	linkExpressRouteCircuits(topology)
+
+	// Network Watcher is regional, so it can only be looked up once the
+	// locations in use are known
+	tasks = tasks[:0]
+	for i, collector := range collectors {
+		subscriptionID := ""
+		if len(subscriptions) > 1 {
+			subscriptionID = subscriptions[i]
+		}
+		locations := topologyLocations(topology, subscriptionID)
+		if len(locations) == 0 {
+			continue
+		}
+		tasks = append(tasks, networkWatcherTask(collector, collectionTarget{subscriptionID: subscriptionID}, locations, topology, &mu))
+	}
+
+	if err := runBounded(ctx, opts.Concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	sortTopology(topology)
+
+	// Subnet associations are checked from both ends once everything is in
+	// place, since either end can be in another resource group
+	validateSubnetAssociations(topology)
+
+	return topology, nil
+}
+
+// collectionTarget is one resource group (or, when empty, a whole subscription)
+// to collect from. subscriptionID is only set when it is needed to tell
+// several subscriptions apart in error messages.
+type collectionTarget struct {
+	subscriptionID string
+	resourceGroup  string
+}
+
+// String describes the target for error messages, e.g. " in resource group rg1"
+func (t collectionTarget) String() string {
+	var where string
+	if t.resourceGroup != "" {
+		where += " in resource group " + t.resourceGroup
+	}
+	if t.subscriptionID != "" {
+		where += " in subscription " + t.subscriptionID
+	}
+	return where
+}
+
+// scope describes the target for collection errors, e.g. "resource group rg1"
+func (t collectionTarget) scope() string {
+	if where := strings.TrimPrefix(t.String(), " in "); where != "" {
+		return where
+	}
+	return "subscription"
+}
+
+// collectionFailure is returned by a collection task when one resource type could
+// not be collected from its target
+type collectionFailure struct {
+	resourceType string
+	target       collectionTarget
+	err          error
+}
+
+func (f *collectionFailure) Error() string {
+	return fmt.Sprintf("failed to get %s%s: %v", f.resourceType, f.target, f.err)
+}
+
+func (f *collectionFailure) Unwrap() error {
+	return f.err
+}
+
+// partialError is returned alongside a collector's results when optional details
+// of some collected resources, such as subnet IP usage, could not be read. The
+// results are kept and each failure is recorded in CollectionErrors, even in
+// strict mode.
+type partialError struct {
+	resourceType string
+	errs         []error
+}
+
+func (e *partialError) Error() string {
+	return fmt.Sprintf("failed to get some %s: %v", e.resourceType, errors.Join(e.errs...))
+}
+
+func (e *partialError) Unwrap() []error {
+	return e.errs
+}
+
+// tolerateFailure wraps a collection task so that a failure to collect its
+// resource type is recorded in the topology rather than returned. Cancellation
+// is still returned so that an interrupted collection stops.
+func tolerateFailure(task func(context.Context) error, topology *models.NetworkTopology, mu *sync.Mutex) func(context.Context) error {
+	return func(ctx context.Context) error {
+		err := task(ctx)
+		var failure *collectionFailure
+		if err == nil || ctx.Err() != nil || !errors.As(err, &failure) {
+			return err
+		}
+		recordCollectionError(topology, mu, failure.resourceType, failure.target, failure.err)
+		return nil
+	}
+}
+
+// recordCollectionError adds a resource type that could not be collected to the topology
+func recordCollectionError(topology *models.NetworkTopology, mu *sync.Mutex, resourceType string, target collectionTarget, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	topology.CollectionErrors = append(topology.CollectionErrors, models.CollectionError{
+		ResourceType: resourceType,
+		Scope:        target.scope(),
+		Class:        classifyError(err),
+		Message:      describeError(err),
+	})
+}
+
+// collectionTasks returns one task per resource type for a single target
+func collectionTasks(collector Collector, target collectionTarget, topology *models.NetworkTopology, mu *sync.Mutex) []func(context.Context) error {
+	return []func(context.Context) error{
+		gather(topology, mu, &topology.VirtualNetworks, "virtual networks", target, collector.GetVirtualNetworks),
+		gather(topology, mu, &topology.NSGs, "NSGs", target, collector.GetNetworkSecurityGroups),
+		gather(topology, mu, &topology.ASGs, "application security groups", target, collector.GetApplicationSecurityGroups),
+		gather(topology, mu, &topology.PrivateEndpoints, "private endpoints", target, collector.GetPrivateEndpoints),
+		gather(topology, mu, &topology.PrivateLinkServices, "private link services", target, collector.GetPrivateLinkServices),
+		gather(topology, mu, &topology.NetworkInterfaces, "network interfaces", target, collector.GetNetworkInterfaces),
+		gather(topology, mu, &topology.PublicIPAddresses, "public IP addresses", target, collector.GetPublicIPAddresses),
+		gather(topology, mu, &topology.PrivateDNSZones, "private DNS zones", target, collector.GetPrivateDNSZones),
+		gather(topology, mu, &topology.RouteTables, "route tables", target, collector.GetRouteTables),
+		gather(topology, mu, &topology.NATGateways, "NAT gateways", target, collector.GetNATGateways),
+		gather(topology, mu, &topology.VPNGateways, "VPN gateways", target, collector.GetVPNGateways),
+		gather(topology, mu, &topology.LocalNetworkGateways, "local network gateways", target, collector.GetLocalNetworkGateways),
+		gather(topology, mu, &topology.ERCircuits, "ExpressRoute circuits", target, collector.GetExpressRouteCircuits),
+		gather(topology, mu, &topology.LoadBalancers, "load balancers", target, collector.GetLoadBalancers),
+		gather(topology, mu, &topology.AppGateways, "application gateways", target, collector.GetApplicationGateways),
+		gather(topology, mu, &topology.AzureFirewalls, "azure firewalls", target, collector.GetAzureFirewalls),
+		gather(topology, mu, &topology.FirewallPolicies, "firewall policies", target, collector.GetFirewallPolicies),
+		gather(topology, mu, &topology.BastionHosts, "bastion hosts", target, collector.GetBastionHosts),
+		gather(topology, mu, &topology.VirtualWANs, "virtual WANs", target, collector.GetVirtualWANs),
+		gather(topology, mu, &topology.VirtualHubs, "virtual hubs", target, collector.GetVirtualHubs),
+	}
+}
+
+// networkWatcherTask returns a task that collects Network Watcher insights for the
+// given locations. Network Watcher usually lives outside the analyzed resource
+// groups, so a failure here is recorded but never aborts the collection.
+func networkWatcherTask(collector Collector, target collectionTarget, locations []string, topology *models.NetworkTopology, mu *sync.Mutex) func(context.Context) error {
+	return func(ctx context.Context) error {
+		nwInsights, err := collector.GetNetworkWatcherInsights(ctx, locations)
+		if err != nil {
+			if ctx.Err() == nil {
+				recordCollectionError(topology, mu, "Network Watcher", target, err)
+			}
+			return nil
+		}
+		if nwInsights == nil {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		topology.NetworkWatcher = mergeNetworkWatcherInsights(topology.NetworkWatcher, nwInsights)
+		return nil
+	}
+}
+
+// topologyLocations returns the distinct locations of the collected resources, so
+// that a region holding any of them has its Network Watcher queried. Private DNS
+// zones are global and have none. When subscriptionID is set only resources from
+// that subscription are considered.
+func topologyLocations(topology *models.NetworkTopology, subscriptionID string) []string {
+	var locations []string
+	add := func(subscription, location string) {
+		if subscriptionID == "" || strings.EqualFold(subscription, subscriptionID) {
+			locations = append(locations, location)
+		}
+	}
+
+	for _, v := range topology.VirtualNetworks {
+		add(v.SubscriptionID, v.Location)
+	}
+	for _, n := range topology.NSGs {
+		add(n.SubscriptionID, n.Location)
+	}
+	for _, a := range topology.ASGs {
+		add(a.SubscriptionID, a.Location)
+	}
+	for _, p := range topology.PrivateEndpoints {
+		add(p.SubscriptionID, p.Location)
+	}
+	for _, p := range topology.PrivateLinkServices {
+		add(p.SubscriptionID, p.Location)
+	}
+	for _, n := range topology.NetworkInterfaces {
+		add(n.SubscriptionID, n.Location)
+	}
+	for _, p := range topology.PublicIPAddresses {
+		add(p.SubscriptionID, p.Location)
+	}
+	for _, r := range topology.RouteTables {
+		add(r.SubscriptionID, r.Location)
+	}
+	for _, n := range topology.NATGateways {
+		add(n.SubscriptionID, n.Location)
+	}
+	for _, g := range topology.VPNGateways {
+		add(g.SubscriptionID, g.Location)
+	}
+	for _, g := range topology.LocalNetworkGateways {
+		add(g.SubscriptionID, g.Location)
+	}
+	for _, e := range topology.ERCircuits {
+		add(e.SubscriptionID, e.Location)
+	}
+	for _, l := range topology.LoadBalancers {
+		add(l.SubscriptionID, l.Location)
+	}
+	for _, a := range topology.AppGateways {
+		add(a.SubscriptionID, a.Location)
+	}
+	for _, f := range topology.AzureFirewalls {
+		add(f.SubscriptionID, f.Location)
+	}
+	for _, p := range topology.FirewallPolicies {
+		add(p.SubscriptionID, p.Location)
+	}
+	for _, b := range topology.BastionHosts {
+		add(b.SubscriptionID, b.Location)
+	}
+	for _, w := range topology.VirtualWANs {
+		add(w.SubscriptionID, w.Location)
+	}
+	for _, h := range topology.VirtualHubs {
+		add(h.SubscriptionID, h.Location)
+	}
+
+	// Locations come back as "eastus" from some APIs and "East US" from others
+	seen := make(map[string]bool)
+	var unique []string
+	for _, location := range locations {
+		key := normalizeLocation(location)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, location)
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// gather returns a task that fetches one resource type from one target and
+// appends the results to dst. Details the collector could only partly read are
+// recorded in the topology's CollectionErrors.
+func gather[T any](topology *models.NetworkTopology, mu *sync.Mutex, dst *[]T, what string, target collectionTarget, fetch func(context.Context, string) ([]T, error)) func(context.Context) error {
+	return func(ctx context.Context) error {
+		items, err := fetch(ctx, target.resourceGroup)
+		var partial *partialError
+		if errors.As(err, &partial) && ctx.Err() == nil {
+			for _, e := range partial.errs {
+				recordCollectionError(topology, mu, partial.resourceType, target, e)
+			}
+			err = nil
+		}
+		if err != nil {
+			return &collectionFailure{resourceType: what, target: target, err: err}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		*dst = append(*dst, items...)
+		return nil
+	}
+}
+
+// mergeNetworkWatcherInsights combines insights collected from several subscriptions
+func mergeNetworkWatcherInsights(dst, src *models.NetworkWatcherInsights) *models.NetworkWatcherInsights {
+	if dst == nil {
+		return src
+	}
+
+	dst.FlowLogsEnabled = dst.FlowLogsEnabled || src.FlowLogsEnabled
+	dst.Watchers = append(dst.Watchers, src.Watchers...)
+	dst.FlowLogs = append(dst.FlowLogs, src.FlowLogs...)
+	dst.ConnectionMonitors = append(dst.ConnectionMonitors, src.ConnectionMonitors...)
+	dst.PacketCaptures = append(dst.PacketCaptures, src.PacketCaptures...)
+	dst.MissingRegions = append(dst.MissingRegions, src.MissingRegions...)
+	return dst
+}
+
+// sortTopology orders every resource slice by resource ID so output does not
+// depend on the order in which concurrent requests completed
+func sortTopology(topology *models.NetworkTopology) {
+	sortByID(topology.VirtualNetworks, func(v models.VirtualNetwork) string { return v.ID })
+	sortByID(topology.NSGs, func(n models.NetworkSecurityGroup) string { return n.ID })
+	sortByID(topology.ASGs, func(a models.ApplicationSecurityGroup) string { return a.ID })
+	sortByID(topology.PrivateEndpoints, func(p models.PrivateEndpoint) string { return p.ID })
+	sortByID(topology.PrivateLinkServices, func(p models.PrivateLinkService) string { return p.ID })
+	sortByID(topology.NetworkInterfaces, func(n models.NetworkInterface) string { return n.ID })
+	sortByID(topology.PublicIPAddresses, func(p models.PublicIPAddress) string { return p.ID })
+	sortByID(topology.PrivateDNSZones, func(z models.PrivateDNSZone) string { return z.ID })
+	sortByID(topology.RouteTables, func(r models.RouteTable) string { return r.ID })
+	sortByID(topology.NATGateways, func(n models.NATGateway) string { return n.ID })
+	sortByID(topology.VPNGateways, func(g models.VPNGateway) string { return g.ID })
+	sortByID(topology.LocalNetworkGateways, func(g models.LocalNetworkGateway) string { return g.ID })
+	sortByID(topology.ERCircuits, func(e models.ExpressRouteCircuit) string { return e.ID })
+	sortByID(topology.LoadBalancers, func(l models.LoadBalancer) string { return l.ID })
+	sortByID(topology.AppGateways, func(a models.ApplicationGateway) string { return a.ID })
+	sortByID(topology.AzureFirewalls, func(f models.AzureFirewall) string { return f.ID })
+	sortByID(topology.BastionHosts, func(b models.BastionHost) string { return b.ID })
+	sortByID(topology.FirewallPolicies, func(p models.FirewallPolicy) string { return p.ID })
+	sortByID(topology.VirtualWANs, func(w models.VirtualWAN) string { return w.ID })
+	sortByID(topology.VirtualHubs, func(h models.VirtualHub) string { return h.ID })
+	// A partial failure records one error per item under the same type and scope,
+	// in whatever order the parallel fetches finished
+	sortByID(topology.CollectionErrors, func(e models.CollectionError) string {
+		return e.ResourceType + "\x00" + e.Scope + "\x00" + e.Message
+	})
+
+	if nw := topology.NetworkWatcher; nw != nil {
+		sortByID(nw.Watchers, func(w models.NetworkWatcher) string { return w.ID })
+		sortByID(nw.FlowLogs, func(f models.FlowLog) string { return f.ID })
+		sortByID(nw.ConnectionMonitors, func(m models.ConnectionMonitor) string { return m.ID })
+		sortByID(nw.PacketCaptures, func(p models.PacketCapture) string { return p.ID })
+	}
+
+	for i := range topology.VPNGateways {
+		sortByID(topology.VPNGateways[i].Connections, func(c models.VPNConnection) string { return c.ID })
+	}
+	for i := range topology.LocalNetworkGateways {
+		sortByID(topology.LocalNetworkGateways[i].Connections, func(id string) string { return id })
+	}
+	for i := range topology.ERCircuits {
+		sortByID(topology.ERCircuits[i].Connections, func(id string) string { return id })
+	}
+	for i := range topology.VirtualHubs {
+		sortByID(topology.VirtualHubs[i].VNetConnections, func(c models.HubVNetConnection) string { return c.ID })
+		sortByID(topology.VirtualHubs[i].Gateways, func(g models.HubGateway) string { return g.ID })
+	}
+}
+
+// sortByID sorts a slice by a case-insensitive resource ID
+func sortByID[T any](items []T, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.ToLower(id(items[i])) < strings.ToLower(id(items[j]))
+	})
+}
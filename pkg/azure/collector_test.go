@@ -0,0 +1,770 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// failingCollector wraps the mock client and fails selected resource types
+type failingCollector struct {
+	*MockAzureClient
+	failNSGs           bool
+	failNetworkWatcher bool
+}
+
+func (c *failingCollector) GetNetworkSecurityGroups(ctx context.Context, resourceGroup string) ([]models.NetworkSecurityGroup, error) {
+	if c.failNSGs {
+		return nil, errors.New("403 Forbidden")
+	}
+	return c.MockAzureClient.GetNetworkSecurityGroups(ctx, resourceGroup)
+}
+
+func (c *failingCollector) GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error) {
+	if c.failNetworkWatcher {
+		return nil, errors.New("network watcher not found")
+	}
+	return c.MockAzureClient.GetNetworkWatcherInsights(ctx, locations)
+}
+
+func TestCollectTopology(t *testing.T) {
+	ctx := context.Background()
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"test-rg"}}
+
+	t.Run("Mock collector populates every resource type", func(t *testing.T) {
+		topology, err := CollectTopology(ctx, NewMockAzureClient("test-sub"), scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+
+		if topology.SubscriptionID != "test-sub" || topology.ResourceGroup != "test-rg" {
+			t.Errorf("Scope not recorded: got %s/%s", topology.SubscriptionID, topology.ResourceGroup)
+		}
+		if topology.Timestamp.IsZero() {
+			t.Error("Timestamp should be set")
+		}
+		if len(topology.VirtualNetworks) == 0 || len(topology.NSGs) == 0 || len(topology.AzureFirewalls) == 0 {
+			t.Error("Expected VNets, NSGs and firewalls from the mock collector")
+		}
+		if topology.NetworkWatcher == nil {
+			t.Error("Expected Network Watcher insights from the mock collector")
+		}
+	})
+
+	t.Run("Collector error is recorded and collection continues", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNSGs: true}
+
+		topology, err := CollectTopology(ctx, collector, scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+		if len(topology.NSGs) != 0 {
+			t.Errorf("Expected no NSGs, got %d", len(topology.NSGs))
+		}
+		if len(topology.VirtualNetworks) == 0 {
+			t.Error("Other resources should still be collected")
+		}
+		if len(topology.CollectionErrors) != 1 {
+			t.Fatalf("Expected 1 collection error, got %+v", topology.CollectionErrors)
+		}
+		got := topology.CollectionErrors[0]
+		if got.ResourceType != "NSGs" || got.Scope != "resource group test-rg" || got.Class != models.ErrorClassOther || got.Message != "403 Forbidden" {
+			t.Errorf("Unexpected collection error: %+v", got)
+		}
+	})
+
+	t.Run("Strict mode aborts collection", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNSGs: true}
+
+		_, err := CollectTopologyWithOptions(ctx, collector, scope, CollectOptions{Concurrency: 4, Strict: true})
+		if err == nil {
+			t.Fatal("Expected an error when NSG collection fails")
+		}
+		if !strings.Contains(err.Error(), "NSGs") {
+			t.Errorf("Error should name the failing resource type: %v", err)
+		}
+	})
+
+	t.Run("Network Watcher failure is not fatal", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNetworkWatcher: true}
+
+		topology, err := CollectTopology(ctx, collector, scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+		if topology.NetworkWatcher != nil {
+			t.Error("Network Watcher insights should be nil when collection fails")
+		}
+		if len(topology.VirtualNetworks) == 0 {
+			t.Error("Other resources should still be collected")
+		}
+		if len(topology.CollectionErrors) != 1 || topology.CollectionErrors[0].ResourceType != "Network Watcher" {
+			t.Errorf("Expected the Network Watcher failure to be recorded, got %+v", topology.CollectionErrors)
+		}
+	})
+
+	t.Run("Network Watcher failure is not fatal in strict mode", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNetworkWatcher: true}
+
+		if _, err := CollectTopologyWithOptions(ctx, collector, scope, CollectOptions{Concurrency: 4, Strict: true}); err != nil {
+			t.Fatalf("CollectTopologyWithOptions failed: %v", err)
+		}
+	})
+}
+
+// reversedCollector returns the mock VNets in reverse order
+type reversedCollector struct {
+	*MockAzureClient
+}
+
+func (c *reversedCollector) GetVirtualNetworks(ctx context.Context, resourceGroup string) ([]models.VirtualNetwork, error) {
+	vnets, err := c.MockAzureClient.GetVirtualNetworks(ctx, resourceGroup)
+	for i, j := 0, len(vnets)-1; i < j; i, j = i+1, j-1 {
+		vnets[i], vnets[j] = vnets[j], vnets[i]
+	}
+	return vnets, err
+}
+
+func TestCollectTopologyWithOptions(t *testing.T) {
+	ctx := context.Background()
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"test-rg"}}
+
+	t.Run("Results are ordered by ID regardless of source order", func(t *testing.T) {
+		for _, concurrency := range []int{1, 4, 16} {
+			collector := &reversedCollector{MockAzureClient: NewMockAzureClient("test-sub")}
+			topology, err := CollectTopologyWithOptions(ctx, collector, scope, CollectOptions{Concurrency: concurrency})
+			if err != nil {
+				t.Fatalf("CollectTopologyWithOptions failed: %v", err)
+			}
+
+			for i := 1; i < len(topology.VirtualNetworks); i++ {
+				if strings.ToLower(topology.VirtualNetworks[i-1].ID) > strings.ToLower(topology.VirtualNetworks[i].ID) {
+					t.Errorf("concurrency=%d: VNets not sorted: %s before %s", concurrency,
+						topology.VirtualNetworks[i-1].Name, topology.VirtualNetworks[i].Name)
+				}
+			}
+		}
+	})
+
+	t.Run("Cancelled context aborts collection", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := CollectTopologyWithOptions(cancelled, NewMockAzureClient("test-sub"), scope, DefaultCollectOptions())
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestCollectTopologyScopes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Multiple resource groups are merged", func(t *testing.T) {
+		scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-hub", "rg-spoke", "RG-HUB"}}
+
+		topology, err := CollectTopology(ctx, NewMockAzureClient("test-sub"), scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+
+		single, _ := CollectTopology(ctx, NewMockAzureClient("test-sub"), CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-hub"}})
+		if len(topology.VirtualNetworks) != 2*len(single.VirtualNetworks) {
+			t.Errorf("Expected %d VNets from two groups, got %d", 2*len(single.VirtualNetworks), len(topology.VirtualNetworks))
+		}
+
+		groups := make(map[string]bool)
+		for _, vnet := range topology.VirtualNetworks {
+			groups[vnet.ResourceGroup] = true
+		}
+		if !groups["rg-hub"] || !groups["rg-spoke"] {
+			t.Errorf("Expected VNets from both groups, got %v", groups)
+		}
+		if topology.ResourceGroup != "rg-hub, rg-spoke" {
+			t.Errorf("Unexpected scope description: %q", topology.ResourceGroup)
+		}
+	})
+
+	t.Run("All resource groups collects subscription-wide", func(t *testing.T) {
+		scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, AllResourceGroups: true, ResourceGroups: []string{"ignored"}}
+
+		topology, err := CollectTopology(ctx, NewMockAzureClient("test-sub"), scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+		if len(topology.VirtualNetworks) == 0 {
+			t.Fatal("Expected VNets from subscription-wide collection")
+		}
+		for _, vnet := range topology.VirtualNetworks {
+			if vnet.ResourceGroup == "" || vnet.ResourceGroup == "ignored" {
+				t.Errorf("Unexpected resource group %q on %s", vnet.ResourceGroup, vnet.Name)
+			}
+		}
+	})
+
+	t.Run("Empty scope is rejected", func(t *testing.T) {
+		_, err := CollectTopology(ctx, NewMockAzureClient("test-sub"), CollectionScope{SubscriptionIDs: []string{"test-sub"}})
+		if err == nil {
+			t.Error("Expected an error when no resource groups are given")
+		}
+	})
+
+	t.Run("Errors name the failing resource group", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNSGs: true}
+		scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-hub"}}
+
+		_, err := CollectTopologyWithOptions(ctx, collector, scope, CollectOptions{Concurrency: 4, Strict: true})
+		if err == nil || !strings.Contains(err.Error(), "rg-hub") {
+			t.Errorf("Error should name the resource group: %v", err)
+		}
+	})
+
+	t.Run("Collection errors record the failing scope", func(t *testing.T) {
+		collector := &failingCollector{MockAzureClient: NewMockAzureClient("test-sub"), failNSGs: true}
+		scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-spoke", "rg-hub"}}
+
+		topology, err := CollectTopology(ctx, collector, scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+		if len(topology.CollectionErrors) != 2 {
+			t.Fatalf("Expected one collection error per resource group, got %+v", topology.CollectionErrors)
+		}
+		if topology.CollectionErrors[0].Scope != "resource group rg-hub" || topology.CollectionErrors[1].Scope != "resource group rg-spoke" {
+			t.Errorf("Collection errors should be sorted by scope, got %+v", topology.CollectionErrors)
+		}
+	})
+}
+
+func TestCollectionScopeDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    CollectionScope
+		expected string
+	}{
+		{"single group", CollectionScope{ResourceGroups: []string{"rg1"}}, "rg1"},
+		{"multiple groups", CollectionScope{ResourceGroups: []string{"rg1", "rg2"}}, "rg1, rg2"},
+		{"duplicates and blanks", CollectionScope{ResourceGroups: []string{"rg1", " ", "RG1", "rg2"}}, "rg1, rg2"},
+		{"all groups", CollectionScope{AllResourceGroups: true}, "(all resource groups)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.scope.Description(); result != tt.expected {
+				t.Errorf("Description() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// singleSubscriptionCollector hides ForSubscription from the mock client
+type singleSubscriptionCollector struct {
+	Collector
+}
+
+func TestCollectTopologySubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Multiple subscriptions are merged", func(t *testing.T) {
+		scope := CollectionScope{SubscriptionIDs: []string{"sub-conn", "sub-app"}, ResourceGroups: []string{"rg-network"}}
+
+		topology, err := CollectTopology(ctx, NewMockAzureClient("sub-conn"), scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+
+		subscriptions := make(map[string]int)
+		for _, vnet := range topology.VirtualNetworks {
+			subscriptions[vnet.SubscriptionID]++
+			if !strings.Contains(vnet.ID, "/subscriptions/"+vnet.SubscriptionID+"/") {
+				t.Errorf("VNet %s has subscription %s that does not match its ID", vnet.ID, vnet.SubscriptionID)
+			}
+		}
+		if subscriptions["sub-conn"] == 0 || subscriptions["sub-app"] == 0 {
+			t.Errorf("Expected VNets from both subscriptions, got %v", subscriptions)
+		}
+		if topology.SubscriptionID != "sub-conn, sub-app" {
+			t.Errorf("Unexpected subscription description: %q", topology.SubscriptionID)
+		}
+	})
+
+	t.Run("Collector without subscription support is rejected", func(t *testing.T) {
+		collector := singleSubscriptionCollector{Collector: NewMockAzureClient("sub-conn")}
+		scope := CollectionScope{SubscriptionIDs: []string{"sub-conn", "sub-app"}, ResourceGroups: []string{"rg-network"}}
+
+		if _, err := CollectTopology(ctx, collector, scope); err == nil {
+			t.Error("Expected an error when the collector cannot switch subscriptions")
+		}
+	})
+
+}
+
+// locationRecorder records the locations passed to GetNetworkWatcherInsights
+type locationRecorder struct {
+	*MockAzureClient
+	locations []string
+}
+
+func (c *locationRecorder) GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error) {
+	c.locations = locations
+	return c.MockAzureClient.GetNetworkWatcherInsights(ctx, locations)
+}
+
+func TestCollectTopologyNetworkWatcher(t *testing.T) {
+	ctx := context.Background()
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-network"}}
+
+	t.Run("Watchers are looked up for topology locations", func(t *testing.T) {
+		collector := &locationRecorder{MockAzureClient: NewMockAzureClient("test-sub")}
+
+		topology, err := CollectTopology(ctx, collector, scope)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+
+		if len(collector.locations) != 1 || collector.locations[0] != "eastus" {
+			t.Errorf("Expected Network Watcher lookup for [eastus], got %v", collector.locations)
+		}
+		nw := topology.NetworkWatcher
+		if nw == nil || len(nw.Watchers) != 1 || nw.Watchers[0].Name != "NetworkWatcher_eastus" {
+			t.Fatalf("Expected the eastus watcher, got %+v", nw)
+		}
+		nsgWebID := "/subscriptions/test-sub/resourceGroups/rg-network/providers/Microsoft.Network/networkSecurityGroups/nsg-web"
+		if len(nw.FlowLogs) != 1 || !strings.EqualFold(nw.FlowLogs[0].NSGId, nsgWebID) {
+			t.Errorf("Expected the flow log to target %s, got %+v", nsgWebID, nw.FlowLogs)
+		}
+	})
+
+	t.Run("Insights are merged across subscriptions", func(t *testing.T) {
+		multi := CollectionScope{SubscriptionIDs: []string{"sub-conn", "sub-app"}, ResourceGroups: []string{"rg-network"}}
+
+		topology, err := CollectTopology(ctx, NewMockAzureClient("sub-conn"), multi)
+		if err != nil {
+			t.Fatalf("CollectTopology failed: %v", err)
+		}
+
+		if topology.NetworkWatcher == nil || len(topology.NetworkWatcher.Watchers) != 2 {
+			t.Fatalf("Expected one watcher per subscription, got %+v", topology.NetworkWatcher)
+		}
+		if len(topology.NetworkWatcher.FlowLogs) != 2 {
+			t.Errorf("Expected one flow log per subscription, got %d", len(topology.NetworkWatcher.FlowLogs))
+		}
+	})
+}
+
+func TestTopologyLocations(t *testing.T) {
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{SubscriptionID: "sub1", Location: "eastus"},
+			{SubscriptionID: "sub2", Location: "westeurope"},
+		},
+		NSGs: []models.NetworkSecurityGroup{
+			{SubscriptionID: "SUB1", Location: "East US"},
+			{SubscriptionID: "sub1", Location: ""},
+		},
+		AzureFirewalls: []models.AzureFirewall{
+			{SubscriptionID: "sub1", Location: "centralus"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		subscriptionID string
+		expected       []string
+	}{
+		{"all subscriptions", "", []string{"centralus", "eastus", "westeurope"}},
+		{"one subscription", "sub1", []string{"centralus", "eastus"}},
+		{"unknown subscription", "sub3", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := topologyLocations(topology, tt.subscriptionID)
+			if strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("topologyLocations(%q) = %v, want %v", tt.subscriptionID, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTopologyLocationsResourceTypes(t *testing.T) {
+	// Each topology holds one resource in a region nothing else uses
+	tests := []struct {
+		name     string
+		topology models.NetworkTopology
+	}{
+		{"application security group", models.NetworkTopology{ASGs: []models.ApplicationSecurityGroup{{Location: "norwayeast"}}}},
+		{"private endpoint", models.NetworkTopology{PrivateEndpoints: []models.PrivateEndpoint{{Location: "norwayeast"}}}},
+		{"private link service", models.NetworkTopology{PrivateLinkServices: []models.PrivateLinkService{{Location: "norwayeast"}}}},
+		{"network interface", models.NetworkTopology{NetworkInterfaces: []models.NetworkInterface{{Location: "norwayeast"}}}},
+		{"public IP", models.NetworkTopology{PublicIPAddresses: []models.PublicIPAddress{{Location: "norwayeast"}}}},
+		{"route table", models.NetworkTopology{RouteTables: []models.RouteTable{{Location: "norwayeast"}}}},
+		{"NAT gateway", models.NetworkTopology{NATGateways: []models.NATGateway{{Location: "norwayeast"}}}},
+		{"VPN gateway", models.NetworkTopology{VPNGateways: []models.VPNGateway{{Location: "norwayeast"}}}},
+		{"local network gateway", models.NetworkTopology{LocalNetworkGateways: []models.LocalNetworkGateway{{Location: "norwayeast"}}}},
+		{"ExpressRoute circuit", models.NetworkTopology{ERCircuits: []models.ExpressRouteCircuit{{Location: "norwayeast"}}}},
+		{"load balancer", models.NetworkTopology{LoadBalancers: []models.LoadBalancer{{Location: "norwayeast"}}}},
+		{"application gateway", models.NetworkTopology{AppGateways: []models.ApplicationGateway{{Location: "norwayeast"}}}},
+		{"firewall policy", models.NetworkTopology{FirewallPolicies: []models.FirewallPolicy{{Location: "norwayeast"}}}},
+		{"Bastion host", models.NetworkTopology{BastionHosts: []models.BastionHost{{Location: "norwayeast"}}}},
+		{"virtual WAN", models.NetworkTopology{VirtualWANs: []models.VirtualWAN{{Location: "norwayeast"}}}},
+		{"virtual hub", models.NetworkTopology{VirtualHubs: []models.VirtualHub{{Location: "norwayeast"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.topology.VirtualNetworks = []models.VirtualNetwork{{Location: "eastus"}}
+			result := topologyLocations(&tt.topology, "")
+			if strings.Join(result, ",") != "eastus,norwayeast" {
+				t.Errorf("topologyLocations() = %v, want the %s's region included", result, tt.name)
+			}
+		})
+	}
+}
+
+func TestMissingRegions(t *testing.T) {
+	watchers := []models.NetworkWatcher{{Name: "NetworkWatcher_eastus", Location: "eastus"}}
+
+	missing := missingRegions([]string{"East US", "westeurope"}, watchers)
+	if len(missing) != 1 || missing[0] != "westeurope" {
+		t.Errorf("Expected [westeurope], got %v", missing)
+	}
+}
+
+func TestCollectionTargetString(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   collectionTarget
+		expected string
+		scope    string
+	}{
+		{"whole subscription", collectionTarget{}, "", "subscription"},
+		{"resource group", collectionTarget{resourceGroup: "rg1"}, " in resource group rg1", "resource group rg1"},
+		{"subscription", collectionTarget{subscriptionID: "sub1"}, " in subscription sub1", "subscription sub1"},
+		{"both", collectionTarget{subscriptionID: "sub1", resourceGroup: "rg1"}, " in resource group rg1 in subscription sub1", "resource group rg1 in subscription sub1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.target.String(); result != tt.expected {
+				t.Errorf("String() = %q, want %q", result, tt.expected)
+			}
+			if result := tt.target.scope(); result != tt.scope {
+				t.Errorf("scope() = %q, want %q", result, tt.scope)
+			}
+		})
+	}
+}
+
+func TestAzureClientSubscriptions(t *testing.T) {
+	client := &AzureClient{clients: make(map[string]*armClients), arm: &armClients{}}
+	client.addSubscription("sub-conn")
+	client.addSubscription("SUB-CONN")
+	client.addSubscription(" ")
+	client.addSubscription("sub-app")
+
+	if ids := client.SubscriptionIDs(); len(ids) != 2 || ids[0] != "sub-conn" || ids[1] != "sub-app" {
+		t.Fatalf("Unexpected subscriptions: %v", ids)
+	}
+	if client.subscriptionID != "sub-conn" {
+		t.Errorf("First subscription should be current, got %s", client.subscriptionID)
+	}
+
+	view, err := client.ForSubscription("Sub-App")
+	if err != nil {
+		t.Fatalf("ForSubscription failed: %v", err)
+	}
+	app := view.(*AzureClient)
+	if app.arm != client.clients["sub-app"] {
+		t.Error("View should share the subscription's cached clients")
+	}
+	if app.arm == client.arm {
+		t.Error("View should not share the current subscription's clients")
+	}
+
+	if _, err := client.ForSubscription("sub-unknown"); err == nil {
+		t.Error("Expected an error for an unconfigured subscription")
+	}
+}
+
+func TestResolvePublicIPs(t *testing.T) {
+	pipID := func(name string) string {
+		return "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/publicIPAddresses/" + name
+	}
+
+	topology := &models.NetworkTopology{
+		PublicIPAddresses: []models.PublicIPAddress{
+			{ID: pipID("pip-lb"), IPAddress: "20.0.0.1"},
+			{ID: pipID("pip-fw-1"), IPAddress: "20.0.0.2"},
+			{ID: pipID("pip-fw-2"), IPAddress: "20.0.0.3"},
+			{ID: pipID("pip-dynamic")},
+		},
+		LoadBalancers: []models.LoadBalancer{
+			{FrontendIPConfigs: []models.FrontendIPConfig{
+				{Name: "public", PublicIPAddressID: strings.ToUpper(pipID("pip-lb"))},
+				{Name: "private", PrivateIPAddress: "10.0.0.4"},
+			}},
+		},
+		AppGateways: []models.ApplicationGateway{
+			{FrontendIPConfigs: []models.AppGWFrontendIPConfig{
+				{Name: "public", PublicIPAddressID: pipID("pip-dynamic")},
+			}},
+		},
+		AzureFirewalls: []models.AzureFirewall{
+			{PublicIPAddresses: []string{pipID("pip-fw-1"), pipID("pip-other-scope"), pipID("pip-fw-2")}},
+			// Hub firewalls report addresses without public IP resources
+			{PublicIPs: []string{"20.84.1.10"}},
+		},
+	}
+
+	resolvePublicIPs(topology)
+
+	if ip := topology.LoadBalancers[0].FrontendIPConfigs[0].PublicIPAddress; ip != "20.0.0.1" {
+		t.Errorf("Expected the LB frontend to resolve to 20.0.0.1, got %q", ip)
+	}
+	if ip := topology.LoadBalancers[0].FrontendIPConfigs[1].PublicIPAddress; ip != "" {
+		t.Errorf("Private frontend should have no public IP, got %q", ip)
+	}
+	if ip := topology.AppGateways[0].FrontendIPConfigs[0].PublicIPAddress; ip != "" {
+		t.Errorf("Unallocated dynamic IP should stay unresolved, got %q", ip)
+	}
+	if ips := topology.AzureFirewalls[0].PublicIPs; strings.Join(ips, ",") != "20.0.0.2,20.0.0.3" {
+		t.Errorf("Expected firewall IPs [20.0.0.2 20.0.0.3], got %v", ips)
+	}
+	if ips := topology.AzureFirewalls[1].PublicIPs; len(ips) != 1 || ips[0] != "20.84.1.10" {
+		t.Errorf("Hub firewall addresses should be kept, got %v", ips)
+	}
+}
+
+func TestLinkHubPeerings(t *testing.T) {
+	prefix := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/"
+	managedVNetID := "/subscriptions/msft/resourceGroups/RG_vhub-eastus_1a2b/providers/Microsoft.Network/virtualNetworks/HV_vhub-eastus_1a2b"
+
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID: prefix + "virtualNetworks/vnet-spoke",
+				Peerings: []models.VNetPeering{
+					{Name: "RemoteVnetToHubPeering_1a2b", RemoteVNetID: managedVNetID, RemoteVNetName: "HV_vhub-eastus_1a2b"},
+					{Name: "peer-to-hub", RemoteVNetID: prefix + "virtualNetworks/vnet-hub", RemoteVNetName: "vnet-hub"},
+				},
+			},
+			{
+				// Peered with the hub's managed VNet but not connected to it
+				ID:       prefix + "virtualNetworks/vnet-other",
+				Peerings: []models.VNetPeering{{RemoteVNetID: managedVNetID}},
+			},
+		},
+		VirtualHubs: []models.VirtualHub{
+			{
+				ID:   prefix + "virtualHubs/vhub-eastus",
+				Name: "vhub-eastus",
+				VNetConnections: []models.HubVNetConnection{
+					{Name: "conn-spoke", RemoteVNetID: strings.ToUpper(prefix + "virtualNetworks/vnet-spoke")},
+				},
+			},
+		},
+	}
+
+	linkHubPeerings(topology)
+
+	spoke := topology.VirtualNetworks[0]
+	if spoke.Peerings[0].RemoteVirtualHubID != prefix+"virtualHubs/vhub-eastus" {
+		t.Errorf("Expected the managed VNet peering to link to vhub-eastus, got %q", spoke.Peerings[0].RemoteVirtualHubID)
+	}
+	if spoke.Peerings[1].RemoteVirtualHubID != "" {
+		t.Errorf("Regular peering should not link to a hub, got %q", spoke.Peerings[1].RemoteVirtualHubID)
+	}
+	if id := topology.VirtualNetworks[1].Peerings[0].RemoteVirtualHubID; id != "" {
+		t.Errorf("VNet without a hub connection should not be linked, got %q", id)
+	}
+}
+
+func TestLinkLocalNetworkGateways(t *testing.T) {
+	prefix := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/"
+
+	topology := &models.NetworkTopology{
+		VPNGateways: []models.VPNGateway{
+			{
+				Connections: []models.VPNConnection{
+					{ID: prefix + "connections/s2s-branch", RemoteEntityID: strings.ToUpper(prefix + "localNetworkGateways/lng-branch")},
+					{ID: prefix + "connections/v2v", RemoteEntityID: prefix + "virtualNetworkGateways/vpngw-other"},
+				},
+			},
+		},
+		LocalNetworkGateways: []models.LocalNetworkGateway{
+			{ID: prefix + "localNetworkGateways/lng-branch", Connections: []string{}},
+			{ID: prefix + "localNetworkGateways/lng-unused", Connections: []string{}},
+		},
+	}
+
+	linkLocalNetworkGateways(topology)
+
+	if conns := topology.LocalNetworkGateways[0].Connections; len(conns) != 1 || conns[0] != prefix+"connections/s2s-branch" {
+		t.Errorf("Expected lng-branch to be linked to s2s-branch, got %v", conns)
+	}
+	if conns := topology.LocalNetworkGateways[1].Connections; len(conns) != 0 {
+		t.Errorf("Expected lng-unused to have no connections, got %v", conns)
+	}
+}
+
+func TestCollectTopologyVirtualHubs(t *testing.T) {
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-network"}}
+
+	topology, err := CollectTopology(context.Background(), NewMockAzureClient("test-sub"), scope)
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+
+	if len(topology.VirtualWANs) != 1 || len(topology.VirtualHubs) != 1 {
+		t.Fatalf("Expected 1 virtual WAN and 1 hub, got %d and %d", len(topology.VirtualWANs), len(topology.VirtualHubs))
+	}
+	hub := topology.VirtualHubs[0]
+	if !strings.EqualFold(hub.VirtualWANID, topology.VirtualWANs[0].ID) {
+		t.Errorf("Hub should belong to %s, got %s", topology.VirtualWANs[0].ID, hub.VirtualWANID)
+	}
+
+	// Connections and gateways are sorted by ID
+	if len(hub.VNetConnections) != 2 || hub.VNetConnections[0].Name != "conn-vnet-partner" {
+		t.Errorf("Expected conn-vnet-partner first of 2 connections, got %+v", hub.VNetConnections)
+	}
+	if len(hub.Gateways) != 2 || hub.Gateways[0].Type != models.HubGatewayExpressRoute || hub.Gateways[1].Type != models.HubGatewayVPN {
+		t.Errorf("Expected an ExpressRoute and a VPN gateway, got %+v", hub.Gateways)
+	}
+	if next := hub.RoutingIntent.NextHop(models.RoutingIntentInternet); !strings.EqualFold(next, hub.AzureFirewallID) {
+		t.Errorf("Expected internet routing intent via %s, got %q", hub.AzureFirewallID, next)
+	}
+}
+
+func TestCollectTopologyPrivateLinkServices(t *testing.T) {
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-network"}}
+
+	topology, err := CollectTopology(context.Background(), NewMockAzureClient("test-sub"), scope)
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+
+	if len(topology.PrivateLinkServices) != 1 {
+		t.Fatalf("Expected 1 private link service, got %d", len(topology.PrivateLinkServices))
+	}
+	pls := topology.PrivateLinkServices[0]
+
+	// The service's frontend must belong to an internal load balancer in the topology
+	var lb *models.LoadBalancer
+	for i := range topology.LoadBalancers {
+		if strings.EqualFold(topology.LoadBalancers[i].ID, pls.LoadBalancerID) {
+			lb = &topology.LoadBalancers[i]
+		}
+	}
+	if lb == nil {
+		t.Fatalf("Load balancer %s of %s was not collected", pls.LoadBalancerID, pls.Name)
+	}
+	if lb.Type != "Internal" {
+		t.Errorf("Expected %s behind an internal load balancer, got %s", pls.Name, lb.Type)
+	}
+	for _, feID := range pls.LoadBalancerFrontendIDs {
+		found := false
+		for _, fe := range lb.FrontendIPConfigs {
+			found = found || strings.EqualFold(fe.Name, extractResourceName(feID))
+		}
+		if !found {
+			t.Errorf("Frontend %s not found on %s", feID, lb.Name)
+		}
+	}
+}
+
+func TestCollectTopologyPublicIPs(t *testing.T) {
+	scope := CollectionScope{SubscriptionIDs: []string{"test-sub"}, ResourceGroups: []string{"rg-network"}}
+
+	topology, err := CollectTopology(context.Background(), NewMockAzureClient("test-sub"), scope)
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+
+	if len(topology.PublicIPAddresses) != 6 {
+		t.Errorf("Expected 6 public IPs, got %d", len(topology.PublicIPAddresses))
+	}
+	for _, lb := range topology.LoadBalancers {
+		if lb.Name != "lb-web" {
+			continue
+		}
+		if ip := lb.FrontendIPConfigs[0].PublicIPAddress; ip != "20.62.10.4" {
+			t.Errorf("Expected lb-web frontend 20.62.10.4, got %q", ip)
+		}
+	}
+	if ips := topology.NATGateways[0].PublicIPs; len(ips) != 1 || ips[0] != "20.62.10.6" {
+		t.Errorf("Expected NAT gateway IP 20.62.10.6, got %v", ips)
+	}
+	if len(topology.BastionHosts) != 1 || topology.BastionHosts[0].PublicIPAddress != "20.62.10.8" {
+		t.Errorf("Expected bas-hub to resolve to 20.62.10.8, got %+v", topology.BastionHosts)
+	}
+}
+
+func TestValidateSubnetAssociations(t *testing.T) {
+	prefix := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/"
+	subnetID := func(name string) string { return prefix + "virtualNetworks/vnet1/subnets/" + name }
+	routeTable := func(name string) *string { id := prefix + "routeTables/" + name; return &id }
+	natGateway := func(name string) *string { id := prefix + "natGateways/" + name; return &id }
+	outside := "/subscriptions/sub1/resourceGroups/rg-shared/providers/Microsoft.Network/routeTables/rt-shared"
+
+	newTopology := func() *models.NetworkTopology {
+		return &models.NetworkTopology{
+			VirtualNetworks: []models.VirtualNetwork{{
+				Name: "vnet1",
+				Subnets: []models.Subnet{
+					// Consistent from both ends, with a differently cased reference
+					{ID: subnetID("snet-ok"), Name: "snet-ok", RouteTable: routeTable("RT-APP"), NATGateway: natGateway("ng-app")},
+					// Points at rt-app, which does not list it
+					{ID: subnetID("snet-unlisted"), Name: "snet-unlisted", RouteTable: routeTable("rt-app")},
+					// Listed by rt-app, but has no route table
+					{ID: subnetID("snet-stale"), Name: "snet-stale"},
+					// Points at a route table that was not collected
+					{ID: subnetID("snet-shared"), Name: "snet-shared", RouteTable: &outside},
+				},
+			}},
+			RouteTables: []models.RouteTable{{
+				ID:                *routeTable("rt-app"),
+				AssociatedSubnets: []string{subnetID("snet-ok"), subnetID("snet-stale"), "/subscriptions/sub1/resourceGroups/rg2/providers/Microsoft.Network/virtualNetworks/vnet2/subnets/other"},
+			}},
+			NATGateways: []models.NATGateway{{
+				ID:                *natGateway("ng-app"),
+				AssociatedSubnets: []string{subnetID("snet-ok")},
+			}},
+		}
+	}
+
+	t.Run("Mismatches and references outside the scope are reported", func(t *testing.T) {
+		topology := newTopology()
+		validateSubnetAssociations(topology)
+
+		want := []models.IntegrityWarning{
+			{ResourceType: "subnet", ResourceID: subnetID("snet-unlisted"),
+				Message: "Subnet vnet1/snet-unlisted uses route table rt-app, but the route table does not list the subnet"},
+			{ResourceType: "subnet", ResourceID: subnetID("snet-shared"),
+				Message: "Subnet vnet1/snet-shared uses route table " + outside + ", which is outside the collected scope"},
+			{ResourceType: "route table", ResourceID: *routeTable("rt-app"),
+				Message: "Route table rt-app lists subnet vnet1/snet-stale, but the subnet uses no route table"},
+		}
+		if len(topology.IntegrityWarnings) != len(want) {
+			t.Fatalf("Expected %d warnings, got %+v", len(want), topology.IntegrityWarnings)
+		}
+		for i, w := range want {
+			if topology.IntegrityWarnings[i] != w {
+				t.Errorf("Warning %d:\n got %+v\nwant %+v", i, topology.IntegrityWarnings[i], w)
+			}
+		}
+	})
+
+	t.Run("Resource types that failed to collect are not checked", func(t *testing.T) {
+		topology := newTopology()
+		topology.CollectionErrors = []models.CollectionError{{ResourceType: "route tables"}}
+		validateSubnetAssociations(topology)
+
+		if len(topology.IntegrityWarnings) != 0 {
+			t.Errorf("Expected no warnings without route tables, got %+v", topology.IntegrityWarnings)
+		}
+	})
+}
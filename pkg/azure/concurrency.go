@@ -0,0 +1,63 @@
+package azure
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of ARM requests allowed in flight at once
+// when no explicit limit is configured
+const DefaultConcurrency = 4
+
+// runBounded runs tasks with at most limit of them executing at the same time.
+// The first task to fail cancels the context handed to the remaining tasks, and
+// its error is returned once every started task has finished.
+func runBounded(ctx context.Context, limit int, tasks []func(context.Context) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, limit)
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+schedule:
+	for _, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+
+		wg.Add(1)
+		go func(task func(context.Context) error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := task(ctx); err != nil {
+				fail(err)
+			}
+		}(task)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	// Parent context cancelled before all tasks were scheduled
+	return ctx.Err()
+}
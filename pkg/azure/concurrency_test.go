@@ -0,0 +1,91 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBounded(t *testing.T) {
+	t.Run("Respects concurrency limit", func(t *testing.T) {
+		var running, peak int32
+		tasks := make([]func(context.Context) error, 20)
+		for i := range tasks {
+			tasks[i] = func(ctx context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			}
+		}
+
+		if err := runBounded(context.Background(), 3, tasks); err != nil {
+			t.Fatalf("runBounded failed: %v", err)
+		}
+		if peak > 3 {
+			t.Errorf("Peak concurrency %d exceeded limit 3", peak)
+		}
+	})
+
+	t.Run("First error cancels remaining tasks", func(t *testing.T) {
+		wantErr := errors.New("boom")
+		var cancelled int32
+
+		tasks := []func(context.Context) error{
+			func(ctx context.Context) error { return wantErr },
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					atomic.AddInt32(&cancelled, 1)
+				case <-time.After(time.Second):
+				}
+				return nil
+			},
+		}
+
+		err := runBounded(context.Background(), 2, tasks)
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Expected %v, got %v", wantErr, err)
+		}
+		if cancelled != 1 {
+			t.Error("Second task should have observed cancellation")
+		}
+	})
+
+	t.Run("Cancelled parent context stops scheduling", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tasks := []func(context.Context) error{
+			func(ctx context.Context) error { return nil },
+		}
+
+		err := runBounded(ctx, 1, tasks)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Zero limit is treated as one", func(t *testing.T) {
+		var ran int32
+		tasks := []func(context.Context) error{
+			func(ctx context.Context) error { atomic.AddInt32(&ran, 1); return nil },
+			func(ctx context.Context) error { atomic.AddInt32(&ran, 1); return nil },
+		}
+
+		if err := runBounded(context.Background(), 0, tasks); err != nil {
+			t.Fatalf("runBounded failed: %v", err)
+		}
+		if ran != 2 {
+			t.Errorf("Expected 2 tasks to run, got %d", ran)
+		}
+	})
+}
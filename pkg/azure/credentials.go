@@ -0,0 +1,185 @@
+package azure
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// Credential modes select how AzureClient authenticates
+const (
+	CredentialDefault          = "default"           // DefaultAzureCredential chain
+	CredentialCLI              = "cli"               // az login
+	CredentialManagedIdentity  = "managed-identity"  // system or user-assigned managed identity
+	CredentialServicePrincipal = "service-principal" // client secret or certificate
+	CredentialWorkloadIdentity = "workload-identity" // federated token, e.g. from a CI OIDC provider
+	CredentialEnvironment      = "environment"       // AZURE_* environment variables only
+)
+
+// CredentialModes lists the supported credential modes
+var CredentialModes = []string{
+	CredentialDefault,
+	CredentialCLI,
+	CredentialManagedIdentity,
+	CredentialServicePrincipal,
+	CredentialWorkloadIdentity,
+	CredentialEnvironment,
+}
+
+// CredentialOptions selects and configures the credential. Empty fields fall back
+// to the environment variables the Azure SDK uses: AZURE_TENANT_ID,
+// AZURE_CLIENT_ID, AZURE_CLIENT_SECRET, AZURE_CLIENT_CERTIFICATE_PATH,
+// AZURE_CLIENT_CERTIFICATE_PASSWORD and AZURE_FEDERATED_TOKEN_FILE.
+type CredentialOptions struct {
+	// Mode is one of the Credential* constants; empty means CredentialDefault
+	Mode string
+
+	TenantID string
+	// ClientID is the application ID of a service principal or workload identity,
+	// or the client ID of a user-assigned managed identity
+	ClientID string
+
+	ClientSecret        string
+	CertificatePath     string // PEM or PKCS#12 file with the certificate and private key
+	CertificatePassword string
+
+	FederatedTokenFile string
+}
+
+// withEnvironment fills empty fields from the AZURE_* environment variables
+func (o CredentialOptions) withEnvironment() CredentialOptions {
+	fill := func(field *string, env string) {
+		if *field == "" {
+			*field = os.Getenv(env)
+		}
+	}
+	fill(&o.TenantID, "AZURE_TENANT_ID")
+	fill(&o.ClientID, "AZURE_CLIENT_ID")
+	fill(&o.ClientSecret, "AZURE_CLIENT_SECRET")
+	fill(&o.CertificatePath, "AZURE_CLIENT_CERTIFICATE_PATH")
+	fill(&o.CertificatePassword, "AZURE_CLIENT_CERTIFICATE_PASSWORD")
+	fill(&o.FederatedTokenFile, "AZURE_FEDERATED_TOKEN_FILE")
+	return o
+}
+
+// NewCredential creates the credential selected by opts. clientOpts carries the
+// cloud and transport used to reach Microsoft Entra ID.
+func NewCredential(opts CredentialOptions, clientOpts policy.ClientOptions) (azcore.TokenCredential, error) {
+	mode := strings.ToLower(strings.TrimSpace(opts.Mode))
+	if mode == "" {
+		mode = CredentialDefault
+	}
+	// The default and environment chains read the environment themselves
+	if mode != CredentialDefault && mode != CredentialEnvironment {
+		opts = opts.withEnvironment()
+	}
+
+	switch mode {
+	case CredentialDefault:
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: clientOpts,
+			TenantID:      opts.TenantID,
+		})
+
+	case CredentialCLI:
+		// The Azure CLI picks its cloud from "az cloud set", not from clientOpts
+		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{
+			TenantID: opts.TenantID,
+		})
+
+	case CredentialManagedIdentity:
+		miOpts := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOpts}
+		if opts.ClientID != "" {
+			miOpts.ID = azidentity.ClientID(opts.ClientID)
+		}
+		return azidentity.NewManagedIdentityCredential(miOpts)
+
+	case CredentialServicePrincipal:
+		if opts.TenantID == "" || opts.ClientID == "" {
+			return nil, fmt.Errorf("%s credential requires a tenant ID and client ID", mode)
+		}
+		if opts.CertificatePath != "" {
+			return newCertificateCredential(opts, clientOpts)
+		}
+		if opts.ClientSecret == "" {
+			return nil, fmt.Errorf("%s credential requires a client secret (AZURE_CLIENT_SECRET) or a certificate", mode)
+		}
+		return azidentity.NewClientSecretCredential(opts.TenantID, opts.ClientID, opts.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: clientOpts})
+
+	case CredentialWorkloadIdentity:
+		if opts.TenantID == "" || opts.ClientID == "" || opts.FederatedTokenFile == "" {
+			return nil, fmt.Errorf("%s credential requires a tenant ID, client ID and federated token file", mode)
+		}
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOpts,
+			TenantID:      opts.TenantID,
+			ClientID:      opts.ClientID,
+			TokenFilePath: opts.FederatedTokenFile,
+		})
+
+	case CredentialEnvironment:
+		return azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{
+			ClientOptions: clientOpts,
+		})
+	}
+
+	return nil, fmt.Errorf("unknown credential mode %q (expected one of: %s)", opts.Mode, strings.Join(CredentialModes, ", "))
+}
+
+// newCertificateCredential authenticates a service principal with the
+// certificate and private key in opts.CertificatePath
+func newCertificateCredential(opts CredentialOptions, clientOpts policy.ClientOptions) (azcore.TokenCredential, error) {
+	data, err := os.ReadFile(opts.CertificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+	var password []byte
+	if opts.CertificatePassword != "" {
+		password = []byte(opts.CertificatePassword)
+	}
+	certs, key, err := azidentity.ParseCertificates(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate %s: %w", opts.CertificatePath, err)
+	}
+	return azidentity.NewClientCertificateCredential(opts.TenantID, opts.ClientID, certs, key,
+		&azidentity.ClientCertificateCredentialOptions{ClientOptions: clientOpts})
+}
+
+// ParseCloud returns the cloud configuration for a cloud name: "public",
+// "usgovernment" or "china" (the Azure CLI names such as "AzureUSGovernment" are
+// accepted too), or the https URL of a custom ARM endpoint. A custom endpoint
+// authenticates against the authority host in AZURE_AUTHORITY_HOST, or public
+// cloud Entra ID when that is not set.
+func ParseCloud(name string) (cloud.Configuration, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "public", "azurecloud", "azurepubliccloud":
+		return cloud.AzurePublic, nil
+	case "usgovernment", "usgov", "azureusgovernment":
+		return cloud.AzureGovernment, nil
+	case "china", "azurechina", "azurechinacloud":
+		return cloud.AzureChina, nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(name))
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return cloud.Configuration{}, fmt.Errorf("unknown cloud %q (expected public, usgovernment, china or an https ARM endpoint)", name)
+	}
+	endpoint := strings.TrimSuffix(u.String(), "/")
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = cloud.AzurePublic.ActiveDirectoryAuthorityHost
+	}
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: authorityHost,
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {Audience: endpoint, Endpoint: endpoint},
+		},
+	}, nil
+}
@@ -0,0 +1,114 @@
+package azure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+func TestParseCloud(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected cloud.Configuration
+	}{
+		{"", cloud.AzurePublic},
+		{"public", cloud.AzurePublic},
+		{"AzureCloud", cloud.AzurePublic},
+		{"usgovernment", cloud.AzureGovernment},
+		{"AzureUSGovernment", cloud.AzureGovernment},
+		{"china", cloud.AzureChina},
+		{"AzureChinaCloud", cloud.AzureChina},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseCloud(tt.name)
+			if err != nil {
+				t.Fatalf("ParseCloud(%q) failed: %v", tt.name, err)
+			}
+			if result.ActiveDirectoryAuthorityHost != tt.expected.ActiveDirectoryAuthorityHost ||
+				result.Services[cloud.ResourceManager] != tt.expected.Services[cloud.ResourceManager] {
+				t.Errorf("ParseCloud(%q) = %+v, want %+v", tt.name, result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Custom ARM endpoint", func(t *testing.T) {
+		t.Setenv("AZURE_AUTHORITY_HOST", "https://login.example.test/")
+		result, err := ParseCloud("https://management.example.test/")
+		if err != nil {
+			t.Fatalf("ParseCloud failed: %v", err)
+		}
+		arm := result.Services[cloud.ResourceManager]
+		if arm.Endpoint != "https://management.example.test" || arm.Audience != "https://management.example.test" {
+			t.Errorf("Unexpected ARM configuration: %+v", arm)
+		}
+		if result.ActiveDirectoryAuthorityHost != "https://login.example.test/" {
+			t.Errorf("Expected authority host from AZURE_AUTHORITY_HOST, got %q", result.ActiveDirectoryAuthorityHost)
+		}
+	})
+
+	for _, name := range []string{"mars", "http://management.example.test", "https://"} {
+		if _, err := ParseCloud(name); err == nil {
+			t.Errorf("ParseCloud(%q) should fail", name)
+		}
+	}
+}
+
+// clearCredentialEnvironment hides any AZURE_* credentials of the machine running the tests
+func clearCredentialEnvironment(t *testing.T) {
+	for _, env := range []string{"AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET",
+		"AZURE_CLIENT_CERTIFICATE_PATH", "AZURE_CLIENT_CERTIFICATE_PASSWORD", "AZURE_FEDERATED_TOKEN_FILE"} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestNewCredential(t *testing.T) {
+	clearCredentialEnvironment(t)
+
+	created := []CredentialOptions{
+		{},
+		{Mode: CredentialCLI},
+		{Mode: CredentialManagedIdentity},
+		{Mode: CredentialManagedIdentity, ClientID: "00000000-0000-0000-0000-000000000001"},
+		{Mode: CredentialServicePrincipal, TenantID: "tenant", ClientID: "client", ClientSecret: "secret"},
+		{Mode: "Workload-Identity", TenantID: "tenant", ClientID: "client", FederatedTokenFile: "/var/run/token"},
+	}
+	for _, opts := range created {
+		t.Run("mode "+opts.Mode, func(t *testing.T) {
+			if cred, err := NewCredential(opts, policy.ClientOptions{}); err != nil || cred == nil {
+				t.Errorf("NewCredential(%+v) failed: %v", opts, err)
+			}
+		})
+	}
+
+	failures := []struct {
+		name string
+		opts CredentialOptions
+		want string
+	}{
+		{"Unknown mode", CredentialOptions{Mode: "password"}, "unknown credential mode"},
+		{"Service principal without IDs", CredentialOptions{Mode: CredentialServicePrincipal, ClientSecret: "secret"}, "tenant ID and client ID"},
+		{"Service principal without secret", CredentialOptions{Mode: CredentialServicePrincipal, TenantID: "tenant", ClientID: "client"}, "client secret"},
+		{"Missing certificate", CredentialOptions{Mode: CredentialServicePrincipal, TenantID: "tenant", ClientID: "client", CertificatePath: "/nonexistent.pem"}, "failed to read client certificate"},
+		{"Workload identity without token file", CredentialOptions{Mode: CredentialWorkloadIdentity, TenantID: "tenant", ClientID: "client"}, "federated token file"},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCredential(tt.opts, policy.ClientOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("Environment fills empty fields", func(t *testing.T) {
+		t.Setenv("AZURE_TENANT_ID", "tenant")
+		t.Setenv("AZURE_CLIENT_ID", "client")
+		t.Setenv("AZURE_CLIENT_SECRET", "secret")
+		if _, err := NewCredential(CredentialOptions{Mode: CredentialServicePrincipal}, policy.ClientOptions{}); err != nil {
+			t.Errorf("Expected the service principal to come from the environment, got %v", err)
+		}
+	})
+}
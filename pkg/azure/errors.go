@@ -0,0 +1,62 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// classifyError sorts a collection error into one of the models.ErrorClass*
+// classes from the ARM response status, or from the error type when the
+// request never got a response
+func classifyError(err error) string {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		switch code := respErr.StatusCode; {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return models.ErrorClassAuthorization
+		case code == http.StatusNotFound:
+			return models.ErrorClassNotFound
+		case code == http.StatusTooManyRequests:
+			return models.ErrorClassThrottled
+		case code == http.StatusRequestTimeout || code >= http.StatusInternalServerError:
+			return models.ErrorClassTransient
+		}
+		return models.ErrorClassOther
+	}
+
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) {
+		return models.ErrorClassAuthorization
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return models.ErrorClassTransient
+	}
+	return models.ErrorClassOther
+}
+
+// describeError returns a one-line error message. ARM response errors carry the
+// full request and response body, which is replaced by the status and error code.
+func describeError(err error) string {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return err.Error()
+	}
+
+	status := fmt.Sprintf("%d %s", respErr.StatusCode, http.StatusText(respErr.StatusCode))
+	if respErr.ErrorCode != "" {
+		status += " (" + respErr.ErrorCode + ")"
+	}
+	// Keep the context the collector wrapped around the response error
+	return strings.TrimSuffix(err.Error(), respErr.Error()) + status
+}
@@ -0,0 +1,60 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+func TestClassifyError(t *testing.T) {
+	responseError := func(status int) error {
+		return fmt.Errorf("failed to get next page of NSGs: %w", &azcore.ResponseError{StatusCode: status})
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"forbidden", responseError(http.StatusForbidden), models.ErrorClassAuthorization},
+		{"unauthorized", responseError(http.StatusUnauthorized), models.ErrorClassAuthorization},
+		{"not found", responseError(http.StatusNotFound), models.ErrorClassNotFound},
+		{"throttled", responseError(http.StatusTooManyRequests), models.ErrorClassThrottled},
+		{"server error", responseError(http.StatusServiceUnavailable), models.ErrorClassTransient},
+		{"bad request", responseError(http.StatusBadRequest), models.ErrorClassOther},
+		{"deadline exceeded", fmt.Errorf("list: %w", context.DeadlineExceeded), models.ErrorClassTransient},
+		{"plain error", errors.New("boom"), models.ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifyError(tt.err); result != tt.expected {
+				t.Errorf("classifyError() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	err := fmt.Errorf("failed to get next page of ExpressRoute circuits: %w",
+		&azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"})
+
+	result := describeError(err)
+	if result != "failed to get next page of ExpressRoute circuits: 403 Forbidden (AuthorizationFailed)" {
+		t.Errorf("describeError() = %q", result)
+	}
+	if strings.Contains(result, "\n") {
+		t.Error("describeError() should return a single line")
+	}
+
+	if result := describeError(errors.New("boom")); result != "boom" {
+		t.Errorf("describeError() = %q, want %q", result, "boom")
+	}
+}
@@ -0,0 +1,358 @@
+// Package fakearm is an in-process stand-in for Azure Resource Manager. It
+// serves resources registered by tests, so AzureClient's collectors, including
+// their paging, can be exercised without a live subscription.
+package fakearm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server answers ARM GET requests for the resources added to it:
+//   - a resource ID returns that resource, or 404 when it was never added
+//   - a collection returns the resources directly beneath it, e.g.
+//     ".../virtualNetworks/vnet1/subnets" lists the subnets of vnet1
+//   - a subscription-level collection such as
+//     "/subscriptions/s/providers/Microsoft.Network/virtualNetworks" lists
+//     that type across every resource group
+//
+// Collections are paged PageSize items at a time through nextLink.
+//
+// Azure Resource Graph queries are answered too, as far as the collectors use
+// them: every resource of a type quoted in the query, in the requested
+// subscriptions, is returned as a row, paged through $skipToken.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the number of items per page; zero returns a single page
+	PageSize int
+
+	mu        sync.Mutex
+	resources map[string]json.RawMessage // by lower-cased resource ID
+	lists     map[string][]json.RawMessage
+	failures  map[string]int
+	requests  []string
+}
+
+// NewServer starts a fake ARM server over TLS. Clients must use the server's
+// Client() as their transport. Close the server when done.
+func NewServer() *Server {
+	s := &Server{
+		resources: make(map[string]json.RawMessage),
+		lists:     make(map[string][]json.RawMessage),
+		failures:  make(map[string]int),
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Add registers resources. Each is a JSON string or a value marshalled to JSON,
+// and must carry its ARM "id"; "name" is filled in from the ID when missing.
+func (s *Server) Add(resources ...any) error {
+	for _, r := range resources {
+		var fields map[string]any
+		if err := json.Unmarshal(toJSON(r), &fields); err != nil {
+			return fmt.Errorf("fakearm: resource is not a JSON object: %w", err)
+		}
+		id, _ := fields["id"].(string)
+		if id == "" {
+			return fmt.Errorf("fakearm: resource has no id: %s", toJSON(r))
+		}
+		if _, ok := fields["name"]; !ok {
+			fields["name"] = id[strings.LastIndex(id, "/")+1:]
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.resources[normalize(id)] = data
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// SetList serves items for a collection whose members do not live beneath it in
+// the ID hierarchy, such as the connections of a virtual network gateway
+func (s *Server) SetList(path string, items ...any) {
+	raw := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		raw = append(raw, toJSON(item))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[normalize(path)] = raw
+}
+
+// Fail answers every request for path with status and an ARM error body
+func (s *Server) Fail(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[normalize(path)] = status
+}
+
+// Requests returns the paths requested so far, in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	path := normalize(r.URL.Path)
+	if r.Method == http.MethodPost && path == resourceGraphPath {
+		s.serveResourceGraph(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	status, failing := s.failures[path]
+	resource, found := s.resources[path]
+	var items []json.RawMessage
+	if !failing && !found {
+		items, found = s.collection(path)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case failing:
+		writeError(w, status, http.StatusText(status))
+	case r.Method != http.MethodGet:
+		writeError(w, http.StatusMethodNotAllowed, "fakearm only serves GET requests")
+	case resource != nil:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resource)
+	case found:
+		s.writePage(w, r, items)
+	default:
+		writeError(w, http.StatusNotFound, "ResourceNotFound")
+	}
+}
+
+// collection returns the items listed by path, or false when path names a
+// single resource rather than a collection. Callers hold s.mu.
+func (s *Server) collection(path string) ([]json.RawMessage, bool) {
+	if items, ok := s.lists[path]; ok {
+		return items, true
+	}
+	if !isCollection(path) {
+		return nil, false
+	}
+
+	matches := func(id string) bool {
+		return strings.HasPrefix(id, path+"/") && !strings.Contains(id[len(path)+1:], "/")
+	}
+	// "/subscriptions/s/providers/ns/type" spans every resource group
+	if segments := strings.Split(strings.Trim(path, "/"), "/"); len(segments) == 5 && segments[0] == "subscriptions" && segments[2] == "providers" {
+		prefix := "/subscriptions/" + segments[1] + "/resourcegroups/"
+		suffix := "/providers/" + segments[3] + "/" + segments[4]
+		matches = func(id string) bool {
+			if !strings.HasPrefix(id, prefix) {
+				return false
+			}
+			rest := strings.SplitN(id[len(prefix):], "/", 2) // resource group, remainder
+			if len(rest) != 2 {
+				return false
+			}
+			remainder := "/" + rest[1]
+			return strings.HasPrefix(remainder, suffix+"/") && !strings.Contains(remainder[len(suffix)+1:], "/")
+		}
+	}
+
+	ids := make([]string, 0)
+	for id := range s.resources {
+		if matches(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	items := make([]json.RawMessage, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, s.resources[id])
+	}
+	return items, true
+}
+
+// writePage writes one page of items, with a nextLink when more remain
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []json.RawMessage) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("$skiptoken"))
+	if start < 0 || start > len(items) {
+		start = len(items)
+	}
+	end := len(items)
+	if s.PageSize > 0 && start+s.PageSize < end {
+		end = start + s.PageSize
+	}
+
+	page := struct {
+		Value    []json.RawMessage `json:"value"`
+		NextLink string            `json:"nextLink,omitempty"`
+	}{Value: items[start:end]}
+	if end < len(items) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("$skiptoken", strconv.Itoa(end))
+		next.RawQuery = query.Encode()
+		page.NextLink = s.URL + next.RequestURI()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// resourceGraphPath is the Resource Graph query endpoint, normalized
+const resourceGraphPath = "/providers/microsoft.resourcegraph/resources"
+
+// quotedPattern finds the resource types quoted in a Resource Graph query
+var quotedPattern = regexp.MustCompile(`'([^']+)'`)
+
+// serveResourceGraph answers a Resource Graph query with the matching resources
+func (s *Server) serveResourceGraph(w http.ResponseWriter, r *http.Request) {
+	var query struct {
+		Subscriptions []string `json:"subscriptions"`
+		Query         string   `json:"query"`
+		Options       struct {
+			SkipToken string `json:"$skipToken"`
+		} `json:"options"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidQuery")
+		return
+	}
+
+	types := make(map[string]bool)
+	for _, m := range quotedPattern.FindAllStringSubmatch(query.Query, -1) {
+		types[strings.ToLower(m[1])] = true
+	}
+	subscriptions := make(map[string]bool)
+	for _, sub := range query.Subscriptions {
+		subscriptions[strings.ToLower(sub)] = true
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	status, failing := s.failures[resourceGraphPath]
+	ids := make([]string, 0)
+	for id := range s.resources {
+		if types[resourceType(id)] && subscriptions[segmentAfter(id, "subscriptions")] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	rows := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		var row map[string]any
+		json.Unmarshal(s.resources[id], &row)
+		row["type"] = resourceType(id)
+		row["subscriptionId"] = segmentAfter(row["id"].(string), "subscriptions")
+		row["resourceGroup"] = segmentAfter(id, "resourcegroups") // Resource Graph lower-cases it
+		rows = append(rows, row)
+	}
+	s.mu.Unlock()
+
+	if failing {
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+
+	start, _ := strconv.Atoi(query.Options.SkipToken)
+	if start < 0 || start > len(rows) {
+		start = len(rows)
+	}
+	end := len(rows)
+	if s.PageSize > 0 && start+s.PageSize < end {
+		end = start + s.PageSize
+	}
+
+	response := map[string]any{
+		"totalRecords":    len(rows),
+		"count":           end - start,
+		"data":            rows[start:end],
+		"resultTruncated": "false",
+	}
+	if end < len(rows) {
+		response["$skipToken"] = strconv.Itoa(end)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// resourceType returns the lower-cased type of a resource ID, e.g.
+// "microsoft.network/virtualnetworks/subnets"
+func resourceType(id string) string {
+	segments := strings.Split(strings.Trim(strings.ToLower(id), "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "providers" {
+			types := []string{segments[i+1]}
+			for j := i + 2; j < len(segments); j += 2 {
+				types = append(types, segments[j])
+			}
+			return strings.Join(types, "/")
+		}
+	}
+	return ""
+}
+
+// segmentAfter returns the path segment following name in a resource ID
+func segmentAfter(id, name string) string {
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], name) {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// writeError writes an ARM error response
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error": {"code": %q, "message": "fakearm: %s"}}`, code, code)
+}
+
+// isCollection reports whether path ends in a resource type rather than a
+// resource name. After the last provider namespace, types and names alternate.
+func isCollection(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "providers" {
+			// namespace at i+1, then type/name pairs
+			return (len(segments)-(i+2))%2 == 1
+		}
+	}
+	// e.g. /subscriptions, /subscriptions/s/resourcegroups
+	return len(segments)%2 == 1
+}
+
+func normalize(path string) string {
+	return strings.ToLower(strings.TrimSuffix(path, "/"))
+}
+
+func toJSON(v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	case json.RawMessage:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("fakearm: cannot marshal %T: %v", v, err))
+	}
+	return data
+}
@@ -0,0 +1,99 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// GetFirewallPolicies retrieves all firewall policies in the specified resource group,
+// or across the whole subscription when resourceGroup is empty, together with their
+// rule collection groups
+func (c *AzureClient) GetFirewallPolicies(ctx context.Context, resourceGroup string) ([]models.FirewallPolicy, error) {
+	client, err := c.getFirewallPoliciesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []models.FirewallPolicy
+	var pager itemPager[armnetwork.FirewallPolicy]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.FirewallPolicy](c, graphFirewallPolicies, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.FirewallPoliciesClientListAllResponse) []*armnetwork.FirewallPolicy {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.FirewallPoliciesClientListResponse) []*armnetwork.FirewallPolicy {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Firewall Policies: %w", err)
+		}
+
+		for _, policy := range page {
+			if policy != nil {
+				policies = append(policies, c.extractFirewallPolicy(policy))
+			}
+		}
+	}
+
+	// Fetch each policy's rule collection groups in parallel, bounded by the client concurrency
+	tasks := make([]func(context.Context) error, 0, len(policies))
+	for i := range policies {
+		policy := &policies[i]
+		tasks = append(tasks, func(ctx context.Context) error {
+			groups, err := c.GetFirewallRuleCollectionGroups(ctx, policy.ResourceGroup, policy.Name)
+			if err != nil {
+				return err
+			}
+			policy.RuleCollectionGroups = groups
+			return nil
+		})
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// GetFirewallRuleCollectionGroups retrieves the rule collection groups of a firewall
+// policy, in priority order. Groups inherited from a base policy are not included.
+func (c *AzureClient) GetFirewallRuleCollectionGroups(ctx context.Context, resourceGroup, policyName string) ([]models.FirewallRuleCollectionGroup, error) {
+	client, err := c.getFirewallRuleCollectionGroupsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []models.FirewallRuleCollectionGroup{}
+	pager := newItemPager(client.NewListPager(resourceGroup, policyName, nil), func(r armnetwork.FirewallPolicyRuleCollectionGroupsClientListResponse) []*armnetwork.FirewallPolicyRuleCollectionGroup {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rule collection groups for firewall policy %s: %w", policyName, err)
+		}
+
+		for _, group := range page {
+			if group != nil {
+				groups = append(groups, c.extractRuleCollectionGroup(group))
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Priority < groups[j].Priority
+	})
+
+	return groups, nil
+}
@@ -2,20 +2,74 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 )
 
-// GetVPNGateways retrieves all VPN gateways in the specified resource group
+// GetVPNGateways retrieves all VPN gateways in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetVPNGateways(ctx context.Context, resourceGroup string) ([]models.VPNGateway, error) {
+	// Virtual network gateways can only be listed per resource group, except
+	// from Resource Graph
+	groups := []string{resourceGroup}
+	if resourceGroup == "" && c.graph == nil {
+		var err error
+		if groups, err = c.listResourceGroups(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var vpnGateways []models.VPNGateway
+	for _, rg := range groups {
+		gateways, err := c.listVPNGateways(ctx, rg)
+		if err != nil {
+			return nil, err
+		}
+		vpnGateways = append(vpnGateways, gateways...)
+	}
+
+	// Fetch each gateway's connections in parallel, bounded by the client concurrency
+	tasks := make([]func(context.Context) error, len(vpnGateways))
+	for i := range vpnGateways {
+		gw := &vpnGateways[i]
+		tasks[i] = func(ctx context.Context) error {
+			connections, err := c.GetVPNConnections(ctx, gw.ResourceGroup, gw.Name)
+			if err != nil {
+				return err
+			}
+			gw.Connections = connections
+			return nil
+		}
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	return vpnGateways, nil
+}
+
+// listVPNGateways lists the virtual network gateways in a single resource group
+func (c *AzureClient) listVPNGateways(ctx context.Context, resourceGroup string) ([]models.VPNGateway, error) {
 	client, err := c.getVPNGatewaysClient()
 	if err != nil {
 		return nil, err
 	}
 
 	var vpnGateways []models.VPNGateway
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.VirtualNetworkGateway]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.VirtualNetworkGateway](c, graphVirtualNetworkGateways, resourceGroup)
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.VirtualNetworkGatewaysClientListResponse) []*armnetwork.VirtualNetworkGateway {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -23,13 +77,15 @@ func (c *AzureClient) GetVPNGateways(ctx context.Context, resourceGroup string)
 			return nil, fmt.Errorf("failed to get next page of VPN Gateways: %w", err)
 		}
 
-		for _, gw := range page.Value {
+		for _, gw := range page {
 			gateway := models.VPNGateway{
-				ID:            safeString(gw.ID),
-				Name:          safeString(gw.Name),
-				ResourceGroup: resourceGroup,
-				Location:      safeString(gw.Location),
-				Connections:   []models.VPNConnection{},
+				ID:             safeString(gw.ID),
+				Name:           safeString(gw.Name),
+				ResourceGroup:  extractResourceGroup(safeString(gw.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(gw.ID)),
+				Location:       safeString(gw.Location),
+				Tags:           safeTags(gw.Tags),
+				Connections:    []models.VPNConnection{},
 			}
 
 			if gw.Properties != nil {
@@ -54,18 +110,7 @@ func (c *AzureClient) GetVPNGateways(ctx context.Context, resourceGroup string)
 				}
 
 				// Extract BGP settings
-				if gw.Properties.BgpSettings != nil {
-					gateway.BGPSettings = &models.BGPSettings{}
-					if gw.Properties.BgpSettings.Asn != nil {
-						gateway.BGPSettings.ASN = *gw.Properties.BgpSettings.Asn
-					}
-					if gw.Properties.BgpSettings.BgpPeeringAddress != nil {
-						gateway.BGPSettings.BGPPeeringAddress = *gw.Properties.BgpSettings.BgpPeeringAddress
-					}
-					if gw.Properties.BgpSettings.PeerWeight != nil {
-						gateway.BGPSettings.PeerWeight = *gw.Properties.BgpSettings.PeerWeight
-					}
-				}
+				gateway.BGPSettings = c.extractBGPSettings(gw.Properties.BgpSettings)
 			}
 
 			vpnGateways = append(vpnGateways, gateway)
@@ -77,63 +122,97 @@ func (c *AzureClient) GetVPNGateways(ctx context.Context, resourceGroup string)
 
 // GetVPNConnections retrieves all connections for a specific VPN gateway
 func (c *AzureClient) GetVPNConnections(ctx context.Context, resourceGroup, gatewayName string) ([]models.VPNConnection, error) {
-	client, err := c.getConnectionsClient()
+	client, err := c.getVPNGatewaysClient()
 	if err != nil {
 		return nil, err
 	}
 
-	var connections []models.VPNConnection
-	pager := client.NewListPager(resourceGroup, nil)
+	connections := []models.VPNConnection{}
+	pager := client.NewListConnectionsPager(resourceGroup, gatewayName, nil)
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get next page of VPN connections: %w", err)
+			return nil, fmt.Errorf("failed to get next page of VPN connections for %s: %w", gatewayName, err)
 		}
 
 		for _, conn := range page.Value {
-			// Filter connections for this gateway
-			if conn.Properties != nil && conn.Properties.VirtualNetworkGateway1 != nil {
-				gwID := safeString(conn.Properties.VirtualNetworkGateway1.ID)
-				if extractResourceName(gwID) == gatewayName {
-					c := models.VPNConnection{
-						ID:   safeString(conn.ID),
-						Name: safeString(conn.Name),
-					}
-
-					if conn.Properties.ConnectionType != nil {
-						c.ConnectionType = string(*conn.Properties.ConnectionType)
-					}
+			connections = append(connections, c.extractVPNConnection(conn))
+		}
+	}
 
-					if conn.Properties.ConnectionStatus != nil {
-						c.ConnectionStatus = string(*conn.Properties.ConnectionStatus)
-					}
+	return connections, nil
+}
 
-					c.SharedKey = conn.Properties.SharedKey != nil && *conn.Properties.SharedKey != ""
+// GetLocalNetworkGateways retrieves the local network gateways, which describe
+// on-premises VPN devices and the address space behind them, in the specified
+// resource group or in every resource group when resourceGroup is empty
+func (c *AzureClient) GetLocalNetworkGateways(ctx context.Context, resourceGroup string) ([]models.LocalNetworkGateway, error) {
+	// Local network gateways can only be listed per resource group, except
+	// from Resource Graph
+	groups := []string{resourceGroup}
+	if resourceGroup == "" && c.graph == nil {
+		var err error
+		if groups, err = c.listResourceGroups(ctx); err != nil {
+			return nil, err
+		}
+	}
 
-					if conn.Properties.EnableBgp != nil {
-						c.EnableBGP = *conn.Properties.EnableBgp
-					}
+	client, err := c.getLocalNetworkGatewaysClient()
+	if err != nil {
+		return nil, err
+	}
 
-					// Get remote entity ID
-					if conn.Properties.VirtualNetworkGateway2 != nil && conn.Properties.VirtualNetworkGateway2.ID != nil {
-						c.RemoteEntityID = *conn.Properties.VirtualNetworkGateway2.ID
-					} else if conn.Properties.LocalNetworkGateway2 != nil && conn.Properties.LocalNetworkGateway2.ID != nil {
-						c.RemoteEntityID = *conn.Properties.LocalNetworkGateway2.ID
-					} else if conn.Properties.Peer != nil && conn.Properties.Peer.ID != nil {
-						c.RemoteEntityID = *conn.Properties.Peer.ID
-					}
+	var gateways []models.LocalNetworkGateway
+	for _, rg := range groups {
+		var pager itemPager[armnetwork.LocalNetworkGateway]
+		if c.graph != nil {
+			pager = newGraphPager[armnetwork.LocalNetworkGateway](c, graphLocalNetworkGateways, rg)
+		} else {
+			pager = newItemPager(client.NewListPager(rg, nil), func(r armnetwork.LocalNetworkGatewaysClientListResponse) []*armnetwork.LocalNetworkGateway {
+				return r.Value
+			})
+		}
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get next page of Local Network Gateways: %w", err)
+			}
 
-					connections = append(connections, c)
+			for _, lng := range page {
+				if lng != nil {
+					gateways = append(gateways, c.extractLocalNetworkGateway(lng))
 				}
 			}
 		}
 	}
 
-	return connections, nil
+	return gateways, nil
+}
+
+// linkLocalNetworkGateways records on each local network gateway the VPN connections
+// that use it. A connection may live in another resource group than the gateway.
+func linkLocalNetworkGateways(topology *models.NetworkTopology) {
+	if len(topology.LocalNetworkGateways) == 0 {
+		return
+	}
+
+	gateways := make(map[string]*models.LocalNetworkGateway, len(topology.LocalNetworkGateways))
+	for i := range topology.LocalNetworkGateways {
+		gateways[strings.ToLower(topology.LocalNetworkGateways[i].ID)] = &topology.LocalNetworkGateways[i]
+	}
+
+	for _, gw := range topology.VPNGateways {
+		for _, conn := range gw.Connections {
+			if lng, ok := gateways[strings.ToLower(conn.RemoteEntityID)]; ok {
+				lng.Connections = append(lng.Connections, conn.ID)
+			}
+		}
+	}
 }
 
-// GetExpressRouteCircuits retrieves all ExpressRoute circuits in the specified resource group
+// GetExpressRouteCircuits retrieves all ExpressRoute circuits in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup string) ([]models.ExpressRouteCircuit, error) {
 	client, err := c.getERCircuitsClient()
 	if err != nil {
@@ -141,7 +220,18 @@ func (c *AzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup
 	}
 
 	var circuits []models.ExpressRouteCircuit
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.ExpressRouteCircuit]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.ExpressRouteCircuit](c, graphExpressRouteCircuits, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.ExpressRouteCircuitsClientListAllResponse) []*armnetwork.ExpressRouteCircuit {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.ExpressRouteCircuitsClientListResponse) []*armnetwork.ExpressRouteCircuit {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -149,14 +239,17 @@ func (c *AzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup
 			return nil, fmt.Errorf("failed to get next page of ExpressRoute Circuits: %w", err)
 		}
 
-		for _, circuit := range page.Value {
+		for _, circuit := range page {
 			er := models.ExpressRouteCircuit{
 				ID:             safeString(circuit.ID),
 				Name:           safeString(circuit.Name),
-				ResourceGroup:  resourceGroup,
+				ResourceGroup:  extractResourceGroup(safeString(circuit.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(circuit.ID)),
 				Location:       safeString(circuit.Location),
+				Tags:           safeTags(circuit.Tags),
 				Peerings:       []models.ERPeering{},
 				Authorizations: []models.ERAuthorization{},
+				Connections:    []string{},
 			}
 
 			if circuit.SKU != nil {
@@ -184,6 +277,9 @@ func (c *AzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup
 				if circuit.Properties.CircuitProvisioningState != nil {
 					er.CircuitProvisioningState = *circuit.Properties.CircuitProvisioningState
 				}
+				if circuit.Properties.GlobalReachEnabled != nil {
+					er.GlobalReachEnabled = *circuit.Properties.GlobalReachEnabled
+				}
 
 				// Extract peerings
 				for _, peering := range circuit.Properties.Peerings {
@@ -202,9 +298,115 @@ func (c *AzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup
 		}
 	}
 
+	// Fetch each circuit's route filters and traffic counters in parallel,
+	// bounded by the client concurrency. A circuit whose route filter cannot be
+	// read is kept with the filter left as its ID.
+	var mu sync.Mutex
+	var filterErrs []error
+	tasks := make([]func(context.Context) error, len(circuits))
+	for i := range circuits {
+		circuit := &circuits[i]
+		tasks[i] = func(ctx context.Context) error {
+			if err := c.resolveRouteFilters(ctx, circuit); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				mu.Lock()
+				filterErrs = append(filterErrs, err)
+				mu.Unlock()
+			}
+			circuit.Stats = c.getERCircuitStats(ctx, circuit)
+			return nil
+		}
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	if len(filterErrs) > 0 {
+		return circuits, &partialError{resourceType: "ExpressRoute route filters", errs: filterErrs}
+	}
 	return circuits, nil
 }
 
+// resolveRouteFilters fetches the route filters attached to a circuit's peerings.
+// Filters in another subscription, and filters that cannot be read, are left as
+// IDs; the failures are returned together.
+func (c *AzureClient) resolveRouteFilters(ctx context.Context, circuit *models.ExpressRouteCircuit) error {
+	client, err := c.getRouteFiltersClient()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	filters := make(map[string]*models.ERRouteFilter)
+	for i := range circuit.Peerings {
+		peering := &circuit.Peerings[i]
+		id := peering.RouteFilterID
+		if id == "" || !strings.EqualFold(extractSubscriptionID(id), c.subscriptionID) {
+			continue
+		}
+
+		filter, ok := filters[strings.ToLower(id)]
+		if !ok {
+			resp, err := client.Get(ctx, extractResourceGroup(id), extractResourceName(id), nil)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get route filter %s for circuit %s: %w", extractResourceName(id), circuit.Name, err))
+			} else {
+				filter = extractRouteFilter(&resp.RouteFilter)
+			}
+			filters[strings.ToLower(id)] = filter // nil after a failure, so it is not retried
+		}
+		peering.RouteFilter = filter
+	}
+	return errors.Join(errs...)
+}
+
+// getERCircuitStats returns a circuit's traffic counters, or nil when they are
+// unavailable. Circuits the provider has not provisioned yet report no stats,
+// so a failure here does not fail the collection.
+func (c *AzureClient) getERCircuitStats(ctx context.Context, circuit *models.ExpressRouteCircuit) *models.ERCircuitStats {
+	if !strings.EqualFold(circuit.SubscriptionID, c.subscriptionID) {
+		return nil
+	}
+	client, err := c.getERCircuitsClient()
+	if err != nil {
+		return nil
+	}
+	resp, err := client.GetStats(ctx, circuit.ResourceGroup, circuit.Name, nil)
+	if err != nil {
+		return nil
+	}
+	return &models.ERCircuitStats{
+		PrimaryBytesIn:    safeInt64(resp.PrimarybytesIn),
+		PrimaryBytesOut:   safeInt64(resp.PrimarybytesOut),
+		SecondaryBytesIn:  safeInt64(resp.SecondarybytesIn),
+		SecondaryBytesOut: safeInt64(resp.SecondarybytesOut),
+	}
+}
+
+// linkExpressRouteCircuits records on each circuit the gateway connections that
+// use it. A connection may live in another resource group or subscription than
+// the circuit.
+func linkExpressRouteCircuits(topology *models.NetworkTopology) {
+	if len(topology.ERCircuits) == 0 {
+		return
+	}
+
+	circuits := make(map[string]*models.ExpressRouteCircuit, len(topology.ERCircuits))
+	for i := range topology.ERCircuits {
+		circuits[strings.ToLower(topology.ERCircuits[i].ID)] = &topology.ERCircuits[i]
+	}
+
+	for _, gw := range topology.VPNGateways {
+		for _, conn := range gw.Connections {
+			if circuit, ok := circuits[strings.ToLower(conn.CircuitID)]; ok && conn.CircuitID != "" {
+				circuit.Connections = append(circuit.Connections, conn.ID)
+			}
+		}
+	}
+}
+
 // GetERPeerings retrieves all peerings for a specific ExpressRoute circuit
 func (c *AzureClient) GetERPeerings(ctx context.Context, resourceGroup, circuitName string) ([]models.ERPeering, error) {
 	client, err := c.getERPeeringsClient()
@@ -255,7 +457,8 @@ func (c *AzureClient) GetERAuthorizations(ctx context.Context, resourceGroup, ci
 	return authorizations, nil
 }
 
-// GetAzureFirewalls retrieves all Azure Firewalls in the specified resource group
+// GetAzureFirewalls retrieves all Azure Firewalls in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetAzureFirewalls(ctx context.Context, resourceGroup string) ([]models.AzureFirewall, error) {
 	client, err := c.getAzureFirewallsClient()
 	if err != nil {
@@ -263,7 +466,18 @@ func (c *AzureClient) GetAzureFirewalls(ctx context.Context, resourceGroup strin
 	}
 
 	var firewalls []models.AzureFirewall
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.AzureFirewall]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.AzureFirewall](c, graphAzureFirewalls, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.AzureFirewallsClientListAllResponse) []*armnetwork.AzureFirewall {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.AzureFirewallsClientListResponse) []*armnetwork.AzureFirewall {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -271,12 +485,14 @@ func (c *AzureClient) GetAzureFirewalls(ctx context.Context, resourceGroup strin
 			return nil, fmt.Errorf("failed to get next page of Azure Firewalls: %w", err)
 		}
 
-		for _, fw := range page.Value {
+		for _, fw := range page {
 			firewall := models.AzureFirewall{
 				ID:                safeString(fw.ID),
 				Name:              safeString(fw.Name),
-				ResourceGroup:     resourceGroup,
+				ResourceGroup:     extractResourceGroup(safeString(fw.ID)),
+				SubscriptionID:    extractSubscriptionID(safeString(fw.ID)),
 				Location:          safeString(fw.Location),
+				Tags:              safeTags(fw.Tags),
 				PublicIPAddresses: []string{},
 			}
 
@@ -311,6 +527,24 @@ func (c *AzureClient) GetAzureFirewalls(ctx context.Context, resourceGroup strin
 					}
 				}
 
+				// Firewalls in a secured Virtual WAN hub have no IP configurations;
+				// their addresses are allocated by the hub
+				if fw.Properties.VirtualHub != nil {
+					firewall.VirtualHubID = safeString(fw.Properties.VirtualHub.ID)
+				}
+				if hubIPs := fw.Properties.HubIPAddresses; hubIPs != nil {
+					if firewall.PrivateIPAddress == "" {
+						firewall.PrivateIPAddress = safeString(hubIPs.PrivateIPAddress)
+					}
+					if hubIPs.PublicIPs != nil {
+						for _, address := range hubIPs.PublicIPs.Addresses {
+							if address != nil && address.Address != nil {
+								firewall.PublicIPs = append(firewall.PublicIPs, *address.Address)
+							}
+						}
+					}
+				}
+
 				// Extract firewall policy
 				if fw.Properties.FirewallPolicy != nil && fw.Properties.FirewallPolicy.ID != nil {
 					firewall.FirewallPolicyID = *fw.Properties.FirewallPolicy.ID
@@ -0,0 +1,218 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+)
+
+// Collector backends for live collection; CollectorGraph is enabled with
+// UseResourceGraph
+const (
+	CollectorARM   = "arm"   // per-type ARM list operations
+	CollectorGraph = "graph" // bulk Azure Resource Graph queries
+)
+
+// Resource types fetched from Azure Resource Graph, as Resource Graph names them
+const (
+	graphVirtualNetworks         = "microsoft.network/virtualnetworks"
+	graphNSGs                    = "microsoft.network/networksecuritygroups"
+	graphASGs                    = "microsoft.network/applicationsecuritygroups"
+	graphPrivateEndpoints        = "microsoft.network/privateendpoints"
+	graphPrivateLinkServices     = "microsoft.network/privatelinkservices"
+	graphNetworkInterfaces       = "microsoft.network/networkinterfaces"
+	graphPublicIPAddresses       = "microsoft.network/publicipaddresses"
+	graphPrivateDNSZones         = "microsoft.network/privatednszones"
+	graphRouteTables             = "microsoft.network/routetables"
+	graphNATGateways             = "microsoft.network/natgateways"
+	graphVirtualNetworkGateways  = "microsoft.network/virtualnetworkgateways"
+	graphLocalNetworkGateways    = "microsoft.network/localnetworkgateways"
+	graphExpressRouteCircuits    = "microsoft.network/expressroutecircuits"
+	graphLoadBalancers           = "microsoft.network/loadbalancers"
+	graphApplicationGateways     = "microsoft.network/applicationgateways"
+	graphAzureFirewalls          = "microsoft.network/azurefirewalls"
+	graphFirewallPolicies        = "microsoft.network/firewallpolicies"
+	graphBastionHosts            = "microsoft.network/bastionhosts"
+	graphVirtualWANs             = "microsoft.network/virtualwans"
+	graphVirtualHubs             = "microsoft.network/virtualhubs"
+	graphHubVPNGateways          = "microsoft.network/vpngateways"
+	graphHubExpressRouteGateways = "microsoft.network/expressroutegateways"
+	graphNetworkWatchers         = "microsoft.network/networkwatchers"
+)
+
+var graphResourceTypes = []string{
+	graphVirtualNetworks, graphNSGs, graphASGs, graphPrivateEndpoints, graphPrivateLinkServices,
+	graphNetworkInterfaces, graphPublicIPAddresses, graphPrivateDNSZones, graphRouteTables,
+	graphNATGateways, graphVirtualNetworkGateways, graphLocalNetworkGateways, graphExpressRouteCircuits,
+	graphLoadBalancers, graphApplicationGateways, graphAzureFirewalls, graphFirewallPolicies,
+	graphBastionHosts, graphVirtualWANs, graphVirtualHubs, graphHubVPNGateways,
+	graphHubExpressRouteGateways, graphNetworkWatchers,
+}
+
+// graphPageSize is the largest page Resource Graph returns
+const graphPageSize = 1000
+
+// graphQuery returns every network resource the collectors list, in the shape of
+// the ARM list operations
+func graphQuery() string {
+	types := make([]string, len(graphResourceTypes))
+	for i, t := range graphResourceTypes {
+		types[i] = "'" + t + "'"
+	}
+	return "resources\n" +
+		"| where type in~ (" + strings.Join(types, ", ") + ")\n" +
+		"| project id, name, type, location, tags, sku, zones, etag, identity, properties, subscriptionId, resourceGroup\n" +
+		"| order by id asc"
+}
+
+// graphResource is one Resource Graph row, kept as raw JSON for the armnetwork
+// types to unmarshal
+type graphResource struct {
+	resourceType   string
+	subscriptionID string
+	resourceGroup  string
+	raw            json.RawMessage
+}
+
+// graphSnapshot holds the network resources of every subscription on the client,
+// fetched by a single paged Resource Graph query the first time any collector
+// needs them. It is shared by every subscription view of an AzureClient.
+type graphSnapshot struct {
+	mu        sync.Mutex
+	loaded    bool
+	err       error
+	resources map[string][]graphResource // by resource type
+}
+
+// UseResourceGraph switches the top-level resource listings to bulk Azure
+// Resource Graph queries. Sub-resources Resource Graph does not index, such as
+// gateway connections or DNS record sets, are still read from ARM.
+func (c *AzureClient) UseResourceGraph() {
+	c.graph = &graphSnapshot{}
+}
+
+// load runs the Resource Graph query once; later calls return the same result
+func (s *graphSnapshot) load(ctx context.Context, c *AzureClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loaded {
+		return s.err
+	}
+
+	resources, err := queryResourceGraph(ctx, c)
+	if ctx.Err() != nil {
+		// Leave the snapshot unloaded so a later collection can retry
+		return err
+	}
+	s.loaded, s.resources, s.err = true, resources, err
+	return err
+}
+
+func queryResourceGraph(ctx context.Context, c *AzureClient) (map[string][]graphResource, error) {
+	client, err := armresourcegraph.NewClient(c.cred, c.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Graph client: %w", err)
+	}
+
+	request := armresourcegraph.QueryRequest{
+		Query:         to.Ptr(graphQuery()),
+		Subscriptions: to.SliceOfPtrs(c.subscriptionIDs...),
+		Options: &armresourcegraph.QueryRequestOptions{
+			ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+			Top:          to.Ptr[int32](graphPageSize),
+		},
+	}
+
+	resources := make(map[string][]graphResource)
+	for {
+		resp, err := client.Resources(ctx, request, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Resource Graph: %w", err)
+		}
+
+		rows, ok := resp.Data.([]any)
+		if !ok && resp.Data != nil {
+			return nil, fmt.Errorf("unexpected Resource Graph result format %T", resp.Data)
+		}
+		for _, row := range rows {
+			fields, ok := row.(map[string]any)
+			if !ok {
+				continue
+			}
+			raw, err := json.Marshal(fields)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read Resource Graph row: %w", err)
+			}
+			r := graphResource{
+				resourceType:   strings.ToLower(stringField(fields, "type")),
+				subscriptionID: stringField(fields, "subscriptionId"),
+				resourceGroup:  stringField(fields, "resourceGroup"),
+				raw:            raw,
+			}
+			resources[r.resourceType] = append(resources[r.resourceType], r)
+		}
+
+		if resp.SkipToken == nil || *resp.SkipToken == "" {
+			break
+		}
+		request.Options.SkipToken = resp.SkipToken
+	}
+	return resources, nil
+}
+
+func stringField(fields map[string]any, key string) string {
+	s, _ := fields[key].(string)
+	return s
+}
+
+// graphList returns the resources of one type in the client's current
+// subscription, in the given resource group or all of them when it is empty,
+// decoded into the same armnetwork type the ARM list operation returns
+func graphList[T any](ctx context.Context, c *AzureClient, resourceType, resourceGroup string) ([]*T, error) {
+	if err := c.graph.load(ctx, c); err != nil {
+		return nil, err
+	}
+
+	var items []*T
+	for _, r := range c.graph.resources[resourceType] {
+		if !strings.EqualFold(r.subscriptionID, c.subscriptionID) {
+			continue
+		}
+		if resourceGroup != "" && !strings.EqualFold(r.resourceGroup, resourceGroup) {
+			continue
+		}
+		item := new(T)
+		if err := json.Unmarshal(r.raw, item); err != nil {
+			return nil, fmt.Errorf("failed to decode %s from Resource Graph: %w", resourceType, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// graphPager serves graphList as a single page, so collectors keep their
+// itemPager loop
+type graphPager[T any] struct {
+	c             *AzureClient
+	resourceType  string
+	resourceGroup string
+	done          bool
+}
+
+func newGraphPager[T any](c *AzureClient, resourceType, resourceGroup string) itemPager[T] {
+	return &graphPager[T]{c: c, resourceType: resourceType, resourceGroup: resourceGroup}
+}
+
+func (p *graphPager[T]) More() bool {
+	return !p.done
+}
+
+func (p *graphPager[T]) NextPage(ctx context.Context) ([]*T, error) {
+	p.done = true
+	return graphList[T](ctx, p.c, p.resourceType, p.resourceGroup)
+}
@@ -0,0 +1,143 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"azure-network-analyzer/pkg/azure/fakearm"
+	"azure-network-analyzer/pkg/models"
+)
+
+// countRequests counts the requests srv received whose path ends with suffix
+func countRequests(srv *fakearm.Server, suffix string) int {
+	n := 0
+	for _, path := range srv.Requests() {
+		if strings.HasSuffix(strings.ToLower(path), strings.ToLower(suffix)) {
+			n++
+		}
+	}
+	return n
+}
+
+func topologyJSON(t *testing.T, topology *models.NetworkTopology) string {
+	t.Helper()
+	topology.Timestamp = time.Time{}
+	data, err := json.MarshalIndent(topology, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestResourceGraphCollectorMatchesARM(t *testing.T) {
+	srv := newFakeARM(t)
+
+	armTopology := collectFakeTopology(t, newFakeARMClient(t, srv, testRetryOptions()))
+
+	graphClient := newFakeARMClient(t, srv, testRetryOptions())
+	graphClient.UseResourceGraph()
+	before := len(srv.Requests())
+	graphTopology := collectFakeTopology(t, graphClient)
+
+	if want, got := topologyJSON(t, armTopology), topologyJSON(t, graphTopology); want != got {
+		t.Errorf("Resource Graph topology differs from ARM:\nwant %s\ngot  %s", want, got)
+	}
+	if len(graphTopology.VirtualNetworks) != 2 || len(graphTopology.LocalNetworkGateways) != 1 {
+		t.Errorf("Expected the fake network to be collected, got %d VNets and %d local gateways",
+			len(graphTopology.VirtualNetworks), len(graphTopology.LocalNetworkGateways))
+	}
+
+	// Top-level resources came from Resource Graph, one page per resource
+	// (PageSize is 1); only sub-resources were read from ARM
+	graphRequests := srv.Requests()[before:]
+	for _, path := range graphRequests {
+		if strings.HasSuffix(strings.ToLower(path), "/virtualnetworks") || strings.HasSuffix(strings.ToLower(path), "/networkwatchers") {
+			t.Errorf("Unexpected ARM list request in graph mode: %s", path)
+		}
+	}
+	if n := countRequests(srv, "/providers/Microsoft.ResourceGraph/resources"); n == 0 {
+		t.Error("Expected Resource Graph to be queried")
+	}
+}
+
+func TestResourceGraphAllResourceGroups(t *testing.T) {
+	srv := newFakeARM(t)
+	srv.PageSize = 0 // a single Resource Graph page
+	client := newFakeARMClient(t, srv, testRetryOptions())
+	client.UseResourceGraph()
+
+	opts := DefaultCollectOptions()
+	opts.Strict = true
+	topology, err := CollectTopologyWithOptions(context.Background(), client, CollectionScope{
+		SubscriptionIDs:   []string{fakeSubscription},
+		AllResourceGroups: true,
+	}, opts)
+	if err != nil {
+		t.Fatalf("CollectTopologyWithOptions failed: %v", err)
+	}
+
+	if len(topology.VPNGateways) != 2 || len(topology.LocalNetworkGateways) != 1 {
+		t.Errorf("Expected gateways from every resource group, got %d VPN and %d local gateways",
+			len(topology.VPNGateways), len(topology.LocalNetworkGateways))
+	}
+	if n := countRequests(srv, "/providers/Microsoft.ResourceGraph/resources"); n != 1 {
+		t.Errorf("Expected a single Resource Graph query, got %d", n)
+	}
+	// Resource Graph needs no per-resource-group listing for gateways
+	if n := countRequests(srv, "/resourcegroups"); n != 0 {
+		t.Errorf("Expected resource groups not to be listed, got %d requests", n)
+	}
+}
+
+func TestResourceGraphFailure(t *testing.T) {
+	srv := newFakeARM(t)
+	srv.Fail("/providers/Microsoft.ResourceGraph/resources", 403)
+	client := newFakeARMClient(t, srv, testRetryOptions())
+	client.UseResourceGraph()
+
+	topology, err := CollectTopology(context.Background(), client, CollectionScope{
+		SubscriptionIDs: []string{fakeSubscription},
+		ResourceGroups:  []string{"rg-network"},
+	})
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+	if len(topology.CollectionErrors) == 0 {
+		t.Fatal("Expected collection errors when Resource Graph is forbidden")
+	}
+	for _, ce := range topology.CollectionErrors {
+		if ce.Class != models.ErrorClassAuthorization {
+			t.Errorf("Expected an authorization error, got %+v", ce)
+		}
+	}
+	// The failed query is not repeated for every resource type
+	if n := countRequests(srv, "/providers/Microsoft.ResourceGraph/resources"); n != 1 {
+		t.Errorf("Expected one Resource Graph query, got %d", n)
+	}
+}
+
+func TestResourceGraphRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	srv := newFakeARM(t)
+	opts := testRetryOptions()
+	opts.RecordDir = dir
+	client := newFakeARMClient(t, srv, opts)
+	client.UseResourceGraph()
+	recorded := collectFakeTopology(t, client)
+	srv.Close()
+
+	replay, err := NewAzureClientWithOptions(ClientOptions{ReplayDir: dir}, fakeSubscription)
+	if err != nil {
+		t.Fatalf("NewAzureClientWithOptions failed: %v", err)
+	}
+	replay.UseResourceGraph()
+	replayed := collectFakeTopology(t, replay)
+
+	if want, got := topologyJSON(t, recorded), topologyJSON(t, replayed); want != got {
+		t.Errorf("Replayed topology differs from the recorded one:\nwant %s\ngot  %s", want, got)
+	}
+}
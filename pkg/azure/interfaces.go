@@ -0,0 +1,93 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// GetNetworkInterfaces retrieves all network interfaces in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
+func (c *AzureClient) GetNetworkInterfaces(ctx context.Context, resourceGroup string) ([]models.NetworkInterface, error) {
+	client, err := c.getInterfacesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var nics []models.NetworkInterface
+	var pager itemPager[armnetwork.Interface]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.Interface](c, graphNetworkInterfaces, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.InterfacesClientListAllResponse) []*armnetwork.Interface {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.InterfacesClientListResponse) []*armnetwork.Interface {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Network Interfaces: %w", err)
+		}
+
+		for _, nic := range page {
+			n := models.NetworkInterface{
+				ID:                 safeString(nic.ID),
+				Name:               safeString(nic.Name),
+				ResourceGroup:      extractResourceGroup(safeString(nic.ID)),
+				SubscriptionID:     extractSubscriptionID(safeString(nic.ID)),
+				Location:           safeString(nic.Location),
+				Tags:               safeTags(nic.Tags),
+				PrivateIPAddresses: []string{},
+				IPConfigurations:   []models.NICIPConfiguration{},
+			}
+
+			if nic.Properties != nil {
+				n.MACAddress = safeString(nic.Properties.MacAddress)
+
+				if nic.Properties.NetworkSecurityGroup != nil {
+					n.NetworkSecurityGroupID = safeString(nic.Properties.NetworkSecurityGroup.ID)
+				}
+				if nic.Properties.VirtualMachine != nil {
+					n.VirtualMachineID = safeString(nic.Properties.VirtualMachine.ID)
+				}
+				if nic.Properties.PrivateEndpoint != nil {
+					n.PrivateEndpointID = safeString(nic.Properties.PrivateEndpoint.ID)
+				}
+				if nic.Properties.EnableIPForwarding != nil {
+					n.EnableIPForwarding = *nic.Properties.EnableIPForwarding
+				}
+				if nic.Properties.EnableAcceleratedNetworking != nil {
+					n.EnableAcceleratedNetworking = *nic.Properties.EnableAcceleratedNetworking
+				}
+
+				for _, ipConfig := range nic.Properties.IPConfigurations {
+					if ipConfig == nil {
+						continue
+					}
+					ic := c.extractNICIPConfiguration(ipConfig)
+					n.IPConfigurations = append(n.IPConfigurations, ic)
+
+					if ic.PrivateIPAddress != "" {
+						n.PrivateIPAddresses = append(n.PrivateIPAddresses, ic.PrivateIPAddress)
+					}
+					// The NIC's subnet is that of its primary IP configuration
+					if ic.Primary || n.SubnetID == "" {
+						n.SubnetID = ic.SubnetID
+					}
+				}
+			}
+
+			nics = append(nics, n)
+		}
+	}
+
+	return nics, nil
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 
 	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 )
 
-// GetLoadBalancers retrieves all load balancers in the specified resource group
+// GetLoadBalancers retrieves all load balancers in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetLoadBalancers(ctx context.Context, resourceGroup string) ([]models.LoadBalancer, error) {
 	client, err := c.getLoadBalancersClient()
 	if err != nil {
@@ -15,7 +18,18 @@ func (c *AzureClient) GetLoadBalancers(ctx context.Context, resourceGroup string
 	}
 
 	var loadBalancers []models.LoadBalancer
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.LoadBalancer]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.LoadBalancer](c, graphLoadBalancers, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.LoadBalancersClientListAllResponse) []*armnetwork.LoadBalancer {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.LoadBalancersClientListResponse) []*armnetwork.LoadBalancer {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -23,12 +37,14 @@ func (c *AzureClient) GetLoadBalancers(ctx context.Context, resourceGroup string
 			return nil, fmt.Errorf("failed to get next page of Load Balancers: %w", err)
 		}
 
-		for _, lb := range page.Value {
+		for _, lb := range page {
 			balancer := models.LoadBalancer{
 				ID:                  safeString(lb.ID),
 				Name:                safeString(lb.Name),
-				ResourceGroup:       resourceGroup,
+				ResourceGroup:       extractResourceGroup(safeString(lb.ID)),
+				SubscriptionID:      extractSubscriptionID(safeString(lb.ID)),
 				Location:            safeString(lb.Location),
+				Tags:                safeTags(lb.Tags),
 				FrontendIPConfigs:   []models.FrontendIPConfig{},
 				BackendAddressPools: []models.BackendAddressPool{},
 				LoadBalancingRules:  []models.LoadBalancingRule{},
@@ -88,7 +104,8 @@ func (c *AzureClient) GetLoadBalancers(ctx context.Context, resourceGroup string
 	return loadBalancers, nil
 }
 
-// GetApplicationGateways retrieves all application gateways in the specified resource group
+// GetApplicationGateways retrieves all application gateways in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetApplicationGateways(ctx context.Context, resourceGroup string) ([]models.ApplicationGateway, error) {
 	client, err := c.getAppGatewaysClient()
 	if err != nil {
@@ -96,7 +113,18 @@ func (c *AzureClient) GetApplicationGateways(ctx context.Context, resourceGroup
 	}
 
 	var appGateways []models.ApplicationGateway
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.ApplicationGateway]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.ApplicationGateway](c, graphApplicationGateways, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.ApplicationGatewaysClientListAllResponse) []*armnetwork.ApplicationGateway {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.ApplicationGatewaysClientListResponse) []*armnetwork.ApplicationGateway {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -104,12 +132,14 @@ func (c *AzureClient) GetApplicationGateways(ctx context.Context, resourceGroup
 			return nil, fmt.Errorf("failed to get next page of Application Gateways: %w", err)
 		}
 
-		for _, ag := range page.Value {
+		for _, ag := range page {
 			gateway := models.ApplicationGateway{
 				ID:                  safeString(ag.ID),
 				Name:                safeString(ag.Name),
-				ResourceGroup:       resourceGroup,
+				ResourceGroup:       extractResourceGroup(safeString(ag.ID)),
+				SubscriptionID:      extractSubscriptionID(safeString(ag.ID)),
 				Location:            safeString(ag.Location),
+				Tags:                safeTags(ag.Tags),
 				FrontendIPConfigs:   []models.AppGWFrontendIPConfig{},
 				FrontendPorts:       []models.AppGWFrontendPort{},
 				BackendAddressPools: []models.AppGWBackendAddressPool{},
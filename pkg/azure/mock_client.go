@@ -2,7 +2,6 @@ package azure
 
 import (
 	"context"
-	"time"
 
 	"azure-network-analyzer/pkg/models"
 )
@@ -19,21 +18,44 @@ func NewMockAzureClient(subscriptionID string) *MockAzureClient {
 	}
 }
 
+// ForSubscription returns a mock client for another subscription
+func (c *MockAzureClient) ForSubscription(subscriptionID string) (Collector, error) {
+	return NewMockAzureClient(subscriptionID), nil
+}
+
+// mockResourceGroupName is used for mock resources when collecting subscription-wide
+const mockResourceGroupName = "rg-network"
+
+// mockResourceGroup substitutes a fixed group name for subscription-wide
+// requests so mock resource IDs stay well formed
+func mockResourceGroup(resourceGroup string) string {
+	if resourceGroup == "" {
+		return mockResourceGroupName
+	}
+	return resourceGroup
+}
+
 // GetVirtualNetworks returns mock VNet data
 func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup string) ([]models.VirtualNetwork, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	nsgID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-web"
+	bastionNSGID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-bastion"
 	routeTableID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/routeTables/rt-main"
 	natGatewayID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/natGateways/nat-outbound"
+	nicPrefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkInterfaces/"
 
 	return []models.VirtualNetwork{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
-			Name:          "vnet-hub",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
-			AddressSpace:  []string{"10.0.0.0/16"},
-			DNSServers:    []string{"10.0.0.4", "10.0.0.5"},
-			EnableDDoS:    true,
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
+			Name:           "vnet-hub",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"},
+			AddressSpace:   []string{"10.0.0.0/16"},
+			DNSServers:     []string{"10.0.0.4", "10.0.0.5"},
+			EnableDDoS:     true,
 			Subnets: []models.Subnet{
 				{
 					ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/AzureFirewallSubnet",
@@ -43,8 +65,10 @@ func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup
 					RouteTable:           nil,
 					NATGateway:           nil,
 					PrivateEndpoints:     []string{},
+					NetworkInterfaces:    []string{},
 					ServiceEndpoints:     []string{},
 					Delegations:          []string{},
+					IPUsage:              &models.SubnetIPUsage{TotalIPs: 64, ReservedIPs: 5, UsedIPs: 4, AvailableIPs: 55},
 				},
 				{
 					ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-web",
@@ -54,8 +78,10 @@ func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup
 					RouteTable:           &routeTableID,
 					NATGateway:           &natGatewayID,
 					PrivateEndpoints:     []string{},
+					NetworkInterfaces:    []string{nicPrefix + "nic-web-1", nicPrefix + "nic-web-2", nicPrefix + "nic-web-3"},
 					ServiceEndpoints:     []string{"Microsoft.Storage", "Microsoft.KeyVault"},
 					Delegations:          []string{},
+					IPUsage:              &models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 3, AvailableIPs: 248},
 				},
 				{
 					ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
@@ -65,16 +91,31 @@ func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup
 					RouteTable:           nil,
 					NATGateway:           nil,
 					PrivateEndpoints:     []string{"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe-sql"},
+					NetworkInterfaces:    []string{nicPrefix + "pe-sql.nic", nicPrefix + "pe-storage.nic"},
 					ServiceEndpoints:     []string{"Microsoft.Sql"},
 					Delegations:          []string{},
+					IPUsage:              &models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 2, AvailableIPs: 249},
+				},
+				{
+					ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/AzureBastionSubnet",
+					Name:                 "AzureBastionSubnet",
+					AddressPrefix:        "10.0.254.0/26",
+					NetworkSecurityGroup: &bastionNSGID,
+					PrivateEndpoints:     []string{},
+					NetworkInterfaces:    []string{},
+					ServiceEndpoints:     []string{},
+					Delegations:          []string{},
+					IPUsage:              &models.SubnetIPUsage{TotalIPs: 64, ReservedIPs: 5, UsedIPs: 2, AvailableIPs: 57},
 				},
 				{
-					ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/GatewaySubnet",
-					Name:          "GatewaySubnet",
-					AddressPrefix: "10.0.255.0/27",
-					PrivateEndpoints: []string{},
-					ServiceEndpoints: []string{},
-					Delegations:      []string{},
+					ID:                "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/GatewaySubnet",
+					Name:              "GatewaySubnet",
+					AddressPrefix:     "10.0.255.0/27",
+					PrivateEndpoints:  []string{},
+					NetworkInterfaces: []string{},
+					ServiceEndpoints:  []string{},
+					Delegations:       []string{},
+					IPUsage:           &models.SubnetIPUsage{TotalIPs: 32, ReservedIPs: 5, UsedIPs: 22, AvailableIPs: 5},
 				},
 			},
 			Peerings: []models.VNetPeering{
@@ -92,21 +133,25 @@ func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup
 			},
 		},
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-spoke",
-			Name:          "vnet-spoke",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
-			AddressSpace:  []string{"10.1.0.0/16"},
-			DNSServers:    []string{},
-			EnableDDoS:    false,
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-spoke",
+			Name:           "vnet-spoke",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "app-team", "cost-center": "cc-200"},
+			AddressSpace:   []string{"10.1.0.0/16"},
+			DNSServers:     []string{},
+			EnableDDoS:     false,
 			Subnets: []models.Subnet{
 				{
-					ID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-spoke/subnets/subnet-app",
-					Name:             "subnet-app",
-					AddressPrefix:    "10.1.1.0/24",
-					PrivateEndpoints: []string{},
-					ServiceEndpoints: []string{},
-					Delegations:      []string{"Microsoft.Web/serverFarms"},
+					ID:                "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-spoke/subnets/subnet-app",
+					Name:              "subnet-app",
+					AddressPrefix:     "10.1.1.0/24",
+					PrivateEndpoints:  []string{},
+					NetworkInterfaces: []string{},
+					ServiceEndpoints:  []string{},
+					Delegations:       []string{"Microsoft.Web/serverFarms"},
+					IPUsage:           &models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 240, AvailableIPs: 11},
 				},
 			},
 			Peerings: []models.VNetPeering{
@@ -123,17 +168,46 @@ func (c *MockAzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup
 				},
 			},
 		},
+		{
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-vwan-spoke",
+			Name:           "vnet-vwan-spoke",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "dev", "owner": "app-team"},
+			AddressSpace:   []string{"10.3.0.0/16"},
+			DNSServers:     []string{},
+			EnableDDoS:     false,
+			Subnets: []models.Subnet{
+				{
+					ID:                "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-vwan-spoke/subnets/subnet-workload",
+					Name:              "subnet-workload",
+					AddressPrefix:     "10.3.1.0/24",
+					PrivateEndpoints:  []string{},
+					NetworkInterfaces: []string{},
+					ServiceEndpoints:  []string{},
+					Delegations:       []string{},
+					IPUsage:           &models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 12, AvailableIPs: 239},
+				},
+			},
+			// Connected to vhub-eastus rather than peered
+			Peerings: []models.VNetPeering{},
+		},
 	}, nil
 }
 
 // GetNetworkSecurityGroups returns mock NSG data
 func (c *MockAzureClient) GetNetworkSecurityGroups(ctx context.Context, resourceGroup string) ([]models.NetworkSecurityGroup, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.NetworkSecurityGroup{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-web",
-			Name:          "nsg-web",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-web",
+			Name:           "nsg-web",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team"},
 			SecurityRules: []models.SecurityRule{
 				{
 					Name:                     "AllowHTTP",
@@ -171,6 +245,20 @@ func (c *MockAzureClient) GetNetworkSecurityGroups(ctx context.Context, resource
 					DestinationPortRange:     "22",
 					Description:              "", // Missing description - security finding
 				},
+				{
+					Name:                  "AllowAppPortsFromSpokes",
+					Priority:              130,
+					Direction:             "Inbound",
+					Access:                "Allow",
+					Protocol:              "TCP",
+					SourceAddressPrefixes: []string{"10.1.0.0/16", "10.2.0.0/16"},
+					SourcePortRange:       "*",
+					DestinationASGs: []string{
+						"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/applicationSecurityGroups/asg-web",
+					},
+					DestinationPortRanges: []string{"443", "8443"},
+					Description:           "Allow the spoke networks to reach the web tier",
+				},
 				{
 					Name:                     "DenyAll",
 					Priority:                 4096,
@@ -189,33 +277,119 @@ func (c *MockAzureClient) GetNetworkSecurityGroups(ctx context.Context, resource
 					"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-web",
 					"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
 				},
+				NetworkInterfaces: []string{
+					"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkInterfaces/nic-web-1",
+				},
+			},
+		},
+		{
+			// nsg-bastion lacks the GatewayManager and AzureLoadBalancer rules Bastion needs
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-bastion",
+			Name:           "nsg-bastion",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team"},
+			SecurityRules: []models.SecurityRule{
+				{
+					Name:                     "AllowHttpsInbound",
+					Priority:                 120,
+					Direction:                "Inbound",
+					Access:                   "Allow",
+					Protocol:                 "TCP",
+					SourceAddressPrefix:      "Internet",
+					SourcePortRange:          "*",
+					DestinationAddressPrefix: "*",
+					DestinationPortRange:     "443",
+					Description:              "Allow users to reach Bastion over HTTPS",
+				},
+				{
+					Name:                     "AllowBastionHostCommunication",
+					Priority:                 150,
+					Direction:                "Inbound",
+					Access:                   "Allow",
+					Protocol:                 "*",
+					SourceAddressPrefix:      "VirtualNetwork",
+					SourcePortRange:          "*",
+					DestinationAddressPrefix: "VirtualNetwork",
+					DestinationPortRanges:    []string{"8080", "5701"},
+					Description:              "Bastion data plane",
+				},
+				{
+					Name:                     "DenyAllInbound",
+					Priority:                 4096,
+					Direction:                "Inbound",
+					Access:                   "Deny",
+					Protocol:                 "*",
+					SourceAddressPrefix:      "*",
+					SourcePortRange:          "*",
+					DestinationAddressPrefix: "*",
+					DestinationPortRange:     "*",
+					Description:              "Deny all other inbound traffic",
+				},
+			},
+			Associations: models.NSGAssociations{
+				Subnets: []string{
+					"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/AzureBastionSubnet",
+				},
 				NetworkInterfaces: []string{},
 			},
 		},
 	}, nil
 }
 
+// GetApplicationSecurityGroups returns mock ASG data: asg-web groups the web VMs
+func (c *MockAzureClient) GetApplicationSecurityGroups(ctx context.Context, resourceGroup string) ([]models.ApplicationSecurityGroup, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	return []models.ApplicationSecurityGroup{
+		{
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/applicationSecurityGroups/asg-web",
+			Name:           "asg-web",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+		},
+	}, nil
+}
+
 // GetPrivateEndpoints returns mock private endpoint data
 func (c *MockAzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup string) ([]models.PrivateEndpoint, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.PrivateEndpoint{
 		{
-			ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe-sql",
-			Name:                 "pe-sql",
-			ResourceGroup:        resourceGroup,
-			Location:             "eastus",
-			SubnetID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
-			PrivateIPAddress:     "10.0.2.10",
+			ID:                 "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe-sql",
+			Name:               "pe-sql",
+			ResourceGroup:      resourceGroup,
+			SubscriptionID:     c.subscriptionID,
+			Location:           "eastus",
+			Tags:               map[string]string{"environment": "prod", "owner": "data-team"},
+			SubnetID:           "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
+			NetworkInterfaceID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkInterfaces/pe-sql.nic",
+			PrivateIPAddress:   "10.0.2.10",
+			PrivateIPAddresses: []string{"10.0.2.10"},
+			DNSConfigs: []models.PrivateEndpointDNSConfig{
+				{FQDN: "sql-server-prod.database.windows.net", IPAddresses: []string{"10.0.2.10"}},
+			},
 			PrivateLinkServiceID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Sql/servers/sql-server-prod",
 			ConnectionState:      "Approved",
 			GroupIDs:             []string{"sqlServer"},
 		},
 		{
-			ID:                   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe-storage",
-			Name:                 "pe-storage",
-			ResourceGroup:        resourceGroup,
-			Location:             "eastus",
-			SubnetID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
-			PrivateIPAddress:     "10.0.2.11",
+			ID:                 "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateEndpoints/pe-storage",
+			Name:               "pe-storage",
+			ResourceGroup:      resourceGroup,
+			SubscriptionID:     c.subscriptionID,
+			Location:           "eastus",
+			Tags:               map[string]string{"environment": "prod", "owner": "data-team"},
+			SubnetID:           "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-db",
+			NetworkInterfaceID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkInterfaces/pe-storage.nic",
+			PrivateIPAddress:   "10.0.2.11",
+			PrivateIPAddresses: []string{"10.0.2.11"},
+			DNSConfigs: []models.PrivateEndpointDNSConfig{
+				{FQDN: "stprod.blob.core.windows.net", IPAddresses: []string{"10.0.2.11"}},
+			},
 			PrivateLinkServiceID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Storage/storageAccounts/stprod",
 			ConnectionState:      "Approved",
 			GroupIDs:             []string{"blob"},
@@ -223,20 +397,222 @@ func (c *MockAzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup
 	}, nil
 }
 
+// GetPrivateLinkServices returns a mock private link service published behind the
+// internal load balancer, with one approved and one pending consumer connection
+func (c *MockAzureClient) GetPrivateLinkServices(ctx context.Context, resourceGroup string) ([]models.PrivateLinkService, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+	plsID := prefix + "privateLinkServices/pls-app"
+	consumerPrefix := "/subscriptions/22222222-2222-2222-2222-222222222222/resourceGroups/rg-consumer/providers/Microsoft.Network/privateEndpoints/"
+
+	return []models.PrivateLinkService{
+		{
+			ID:                      plsID,
+			Name:                    "pls-app",
+			ResourceGroup:           resourceGroup,
+			SubscriptionID:          c.subscriptionID,
+			Location:                "eastus",
+			Tags:                    map[string]string{"environment": "prod", "owner": "app-team", "cost-center": "cc-200"},
+			Alias:                   "pls-app.6f0e1c2a-3b4d-4e5f-8a9b-0c1d2e3f4a5b.eastus.azure.privatelinkservice",
+			LoadBalancerID:          prefix + "loadBalancers/lb-app-internal",
+			LoadBalancerFrontendIDs: []string{prefix + "loadBalancers/lb-app-internal/frontendIPConfigurations/frontend-app"},
+			IPConfigurations: []models.PrivateLinkServiceIPConfig{
+				{
+					Name:             "nat-ipconfig-1",
+					PrivateIPAddress: "10.1.1.20",
+					AllocationMethod: "Static",
+					SubnetID:         prefix + "virtualNetworks/vnet-spoke/subnets/subnet-app",
+					Primary:          true,
+				},
+			},
+			Visibility:   []string{"*"},
+			AutoApproval: []string{c.subscriptionID},
+			FQDNs:        []string{"app.contoso.com"},
+			Connections: []models.PrivateLinkServiceConnection{
+				{
+					ID:                plsID + "/privateEndpointConnections/pe-consumer-app.1a2b",
+					Name:              "pe-consumer-app.1a2b",
+					PrivateEndpointID: consumerPrefix + "pe-consumer-app",
+					Status:            "Approved",
+					Description:       "Approved by contoso-ops",
+				},
+				{
+					ID:                plsID + "/privateEndpointConnections/pe-unknown.3c4d",
+					Name:              "pe-unknown.3c4d",
+					PrivateEndpointID: consumerPrefix + "pe-unknown",
+					Status:            "Pending",
+					Description:       "Please approve",
+				},
+			},
+			ProvisioningState: "Succeeded",
+		},
+	}, nil
+}
+
+// GetNetworkInterfaces returns mock network interface data: three web VMs behind
+// lb-web and the NICs of the two private endpoints
+func (c *MockAzureClient) GetNetworkInterfaces(ctx context.Context, resourceGroup string) ([]models.NetworkInterface, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+	webSubnetID := prefix + "virtualNetworks/vnet-hub/subnets/subnet-web"
+	dbSubnetID := prefix + "virtualNetworks/vnet-hub/subnets/subnet-db"
+	poolID := prefix + "loadBalancers/lb-web/backendAddressPools/backend-web-servers"
+
+	nic := func(name, ip, subnetID string) models.NetworkInterface {
+		id := prefix + "networkInterfaces/" + name
+		return models.NetworkInterface{
+			ID:                 id,
+			Name:               name,
+			ResourceGroup:      resourceGroup,
+			SubscriptionID:     c.subscriptionID,
+			Location:           "eastus",
+			PrivateIPAddresses: []string{ip},
+			SubnetID:           subnetID,
+			IPConfigurations: []models.NICIPConfiguration{
+				{
+					ID:                        id + "/ipConfigurations/ipconfig1",
+					Name:                      "ipconfig1",
+					Primary:                   true,
+					PrivateIPAddress:          ip,
+					PrivateIPAllocationMethod: "Dynamic",
+					SubnetID:                  subnetID,
+					LoadBalancerBackendPools:  []string{},
+					AppGatewayBackendPools:    []string{},
+					ApplicationSecurityGroups: []string{},
+				},
+			},
+		}
+	}
+
+	var nics []models.NetworkInterface
+	for _, web := range []struct{ name, ip, mac, vm string }{
+		{"nic-web-1", "10.0.1.4", "00-0D-3A-00-00-01", "vm-web-01"},
+		{"nic-web-2", "10.0.1.5", "00-0D-3A-00-00-02", "vm-web-02"},
+		{"nic-web-3", "10.0.1.6", "00-0D-3A-00-00-03", "vm-web-03"},
+	} {
+		n := nic(web.name, web.ip, webSubnetID)
+		n.MACAddress = web.mac
+		n.VirtualMachineID = "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Compute/virtualMachines/" + web.vm
+		n.EnableAcceleratedNetworking = true
+		n.IPConfigurations[0].LoadBalancerBackendPools = []string{poolID}
+		n.IPConfigurations[0].ApplicationSecurityGroups = []string{prefix + "applicationSecurityGroups/asg-web"}
+		nics = append(nics, n)
+	}
+	// nic-web-1 also has a NIC-level NSG
+	nics[0].NetworkSecurityGroupID = prefix + "networkSecurityGroups/nsg-web"
+
+	for _, pe := range []struct{ name, ip string }{{"pe-sql", "10.0.2.10"}, {"pe-storage", "10.0.2.11"}} {
+		n := nic(pe.name+".nic", pe.ip, dbSubnetID)
+		n.PrivateEndpointID = prefix + "privateEndpoints/" + pe.name
+		n.IPConfigurations[0].Name = "privateEndpointIpConfig"
+		n.IPConfigurations[0].ID = n.ID + "/ipConfigurations/privateEndpointIpConfig"
+		nics = append(nics, n)
+	}
+
+	return nics, nil
+}
+
+// GetPublicIPAddresses returns mock public IP data: the frontends of lb-web and
+// appgw-web, the NAT gateway, firewall and Bastion addresses, and one unassociated address
+func (c *MockAzureClient) GetPublicIPAddresses(ctx context.Context, resourceGroup string) ([]models.PublicIPAddress, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+
+	pip := func(name, ip, associatedID, ipConfigID string) models.PublicIPAddress {
+		return models.PublicIPAddress{
+			ID:                   prefix + "publicIPAddresses/" + name,
+			Name:                 name,
+			ResourceGroup:        resourceGroup,
+			SubscriptionID:       c.subscriptionID,
+			Location:             "eastus",
+			IPAddress:            ip,
+			SKU:                  "Standard",
+			Tier:                 "Regional",
+			AllocationMethod:     "Static",
+			IPVersion:            "IPv4",
+			Zones:                []string{"1", "2", "3"},
+			IPConfigurationID:    ipConfigID,
+			AssociatedResourceID: associatedID,
+		}
+	}
+
+	lb := pip("pip-lb", "20.62.10.4", prefix+"loadBalancers/lb-web", prefix+"loadBalancers/lb-web/frontendIPConfigurations/frontend-public")
+	lb.DNSLabel = "web-prod"
+	lb.FQDN = "web-prod.eastus.cloudapp.azure.com"
+
+	// pip-legacy is left over from a deleted VM
+	legacy := pip("pip-legacy", "", "", "")
+	legacy.SKU = "Basic"
+	legacy.AllocationMethod = "Dynamic"
+	legacy.Zones = []string{}
+
+	return []models.PublicIPAddress{
+		lb,
+		pip("pip-appgw", "20.62.10.5", prefix+"applicationGateways/appgw-web", prefix+"applicationGateways/appgw-web/frontendIPConfigurations/appGwPublicFrontendIp"),
+		pip("pip-nat", "20.62.10.6", prefix+"natGateways/nat-outbound", ""),
+		pip("pip-firewall", "20.62.10.7", prefix+"azureFirewalls/fw-hub", prefix+"azureFirewalls/fw-hub/azureFirewallIpConfigurations/fw-ipconfig"),
+		pip("pip-bastion", "20.62.10.8", prefix+"bastionHosts/bas-hub", prefix+"bastionHosts/bas-hub/bastionHostIpConfigurations/IpConf"),
+		legacy,
+	}, nil
+}
+
 // GetPrivateDNSZones returns mock private DNS zone data
 func (c *MockAzureClient) GetPrivateDNSZones(ctx context.Context, resourceGroup string) ([]models.PrivateDNSZone, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.PrivateDNSZone{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateDnsZones/privatelink.database.windows.net",
-			Name:          "privatelink.database.windows.net",
-			ResourceGroup: resourceGroup,
-			RecordSets:    5,
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateDnsZones/privatelink.database.windows.net",
+			Name:           "privatelink.database.windows.net",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Tags:           map[string]string{"owner": "network-team"},
+			RecordSets:     5,
 			VNetLinks: []models.VNetLink{
 				{
 					ID:                  "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateDnsZones/privatelink.database.windows.net/virtualNetworkLinks/link-to-hub",
+					Name:                "link-to-hub",
 					VNetID:              "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
 					VNetName:            "vnet-hub",
 					RegistrationEnabled: false,
+					LinkState:           "Completed",
+				},
+			},
+			ARecords: []models.DNSARecord{
+				{
+					Name:        "sql-server-prod",
+					FQDN:        "sql-server-prod.privatelink.database.windows.net",
+					IPAddresses: []string{"10.0.2.10"},
+					TTL:         10,
+				},
+			},
+		},
+		{
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net",
+			Name:           "privatelink.blob.core.windows.net",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Tags:           map[string]string{"owner": "network-team"},
+			RecordSets:     2,
+			VNetLinks: []models.VNetLink{
+				{
+					ID:                  "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/privateDnsZones/privatelink.blob.core.windows.net/virtualNetworkLinks/link-to-hub",
+					Name:                "link-to-hub",
+					VNetID:              "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
+					VNetName:            "vnet-hub",
+					RegistrationEnabled: false,
+					LinkState:           "Completed",
+				},
+			},
+			ARecords: []models.DNSARecord{
+				{
+					Name:        "stprod",
+					FQDN:        "stprod.privatelink.blob.core.windows.net",
+					IPAddresses: []string{"10.0.2.11"},
+					TTL:         10,
 				},
 			},
 		},
@@ -245,12 +621,16 @@ func (c *MockAzureClient) GetPrivateDNSZones(ctx context.Context, resourceGroup
 
 // GetRouteTables returns mock route table data
 func (c *MockAzureClient) GetRouteTables(ctx context.Context, resourceGroup string) ([]models.RouteTable, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.RouteTable{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/routeTables/rt-main",
-			Name:          "rt-main",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/routeTables/rt-main",
+			Name:           "rt-main",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team"},
 			Routes: []models.Route{
 				{
 					Name:             "route-to-internet",
@@ -275,15 +655,24 @@ func (c *MockAzureClient) GetRouteTables(ctx context.Context, resourceGroup stri
 
 // GetNATGateways returns mock NAT gateway data
 func (c *MockAzureClient) GetNATGateways(ctx context.Context, resourceGroup string) ([]models.NATGateway, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.NATGateway{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/natGateways/nat-outbound",
-			Name:          "nat-outbound",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/natGateways/nat-outbound",
+			Name:           "nat-outbound",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team"},
+			SKU:            "Standard",
+			Zones:          []string{"1"},
 			PublicIPAddresses: []string{
 				"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/publicIPAddresses/pip-nat",
 			},
+			PublicIPPrefixes: []string{
+				"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/publicIPPrefixes/ippre-nat",
+			},
 			IdleTimeoutMinutes: 10,
 			AssociatedSubnets: []string{
 				"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-web",
@@ -294,17 +683,21 @@ func (c *MockAzureClient) GetNATGateways(ctx context.Context, resourceGroup stri
 
 // GetVPNGateways returns mock VPN gateway data
 func (c *MockAzureClient) GetVPNGateways(ctx context.Context, resourceGroup string) ([]models.VPNGateway, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.VPNGateway{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworkGateways/vpn-gateway",
-			Name:          "vpn-gateway",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
-			VNetID:        "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
-			GatewayType:   "Vpn",
-			VpnType:       "RouteBased",
-			SKU:           "VpnGw2",
-			ActiveActive:  false,
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworkGateways/vpn-gateway",
+			Name:           "vpn-gateway",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"},
+			VNetID:         "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
+			GatewayType:    "Vpn",
+			VpnType:        "RouteBased",
+			SKU:            "VpnGw2",
+			ActiveActive:   false,
 			BGPSettings: &models.BGPSettings{
 				ASN:               65515,
 				BGPPeeringAddress: "10.0.255.30",
@@ -318,28 +711,150 @@ func (c *MockAzureClient) GetVPNGateways(ctx context.Context, resourceGroup stri
 					ConnectionStatus: "Connected",
 					SharedKey:        true,
 					EnableBGP:        true,
-					RemoteEntityID:   "52.168.1.100",
+					RemoteEntityID:   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/localNetworkGateways/lng-onprem",
+				},
+			},
+		},
+		{
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworkGateways/ergw-hub",
+			Name:           "ergw-hub",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"},
+			VNetID:         "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub",
+			GatewayType:    "ExpressRoute",
+			VpnType:        "RouteBased",
+			SKU:            "UltraPerformance",
+			Connections: []models.VPNConnection{
+				{
+					ID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/connections/er-to-dc",
+					Name:             "er-to-dc",
+					ConnectionType:   "ExpressRoute",
+					ConnectionStatus: "Connected",
+					RemoteEntityID:   "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/expressRouteCircuits/er-circuit-dc",
+					CircuitID:        "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/expressRouteCircuits/er-circuit-dc",
+					FastPath:         true,
 				},
 			},
 		},
 	}, nil
 }
 
-// GetExpressRouteCircuits returns mock ExpressRoute circuit data
+// GetLocalNetworkGateways returns the mock on-premises site behind vpn-to-onprem. One
+// of its prefixes overlaps vnet-spoke.
+func (c *MockAzureClient) GetLocalNetworkGateways(ctx context.Context, resourceGroup string) ([]models.LocalNetworkGateway, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	return []models.LocalNetworkGateway{
+		{
+			ID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/localNetworkGateways/lng-onprem",
+			Name:             "lng-onprem",
+			ResourceGroup:    resourceGroup,
+			SubscriptionID:   c.subscriptionID,
+			Location:         "eastus",
+			Tags:             map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"},
+			GatewayIPAddress: "52.168.1.100",
+			AddressPrefixes:  []string{"192.168.0.0/16", "10.1.128.0/20"},
+			BGPSettings: &models.BGPSettings{
+				ASN:               65010,
+				BGPPeeringAddress: "192.168.255.1",
+			},
+			Connections:       []string{},
+			ProvisioningState: "Succeeded",
+		},
+	}, nil
+}
+
+// GetExpressRouteCircuits returns the mock datacenter circuit used by ergw-hub. Its
+// private peering is linked by Global Reach to a circuit outside the mock scope.
 func (c *MockAzureClient) GetExpressRouteCircuits(ctx context.Context, resourceGroup string) ([]models.ExpressRouteCircuit, error) {
-	return []models.ExpressRouteCircuit{}, nil // No ER circuits in mock
+	resourceGroup = mockResourceGroup(resourceGroup)
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+
+	return []models.ExpressRouteCircuit{
+		{
+			ID:                       prefix + "expressRouteCircuits/er-circuit-dc",
+			Name:                     "er-circuit-dc",
+			ResourceGroup:            resourceGroup,
+			SubscriptionID:           c.subscriptionID,
+			Location:                 "eastus",
+			Tags:                     map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"},
+			ServiceProviderName:      "Equinix",
+			PeeringLocation:          "Washington DC",
+			BandwidthInMbps:          1000,
+			SKUTier:                  "Premium",
+			SKUFamily:                "MeteredData",
+			CircuitProvisioningState: "Enabled",
+			GlobalReachEnabled:       true,
+			Peerings: []models.ERPeering{
+				{
+					Name:                       "AzurePrivatePeering",
+					PeeringType:                "AzurePrivatePeering",
+					State:                      "Enabled",
+					AzureASN:                   12076,
+					PeerASN:                    65020,
+					PrimaryPeerAddressPrefix:   "172.16.0.0/30",
+					SecondaryPeerAddressPrefix: "172.16.0.4/30",
+					VlanID:                     100,
+					GlobalReachConnections: []models.ERGlobalReachConnection{
+						{
+							Name:          "gr-to-london",
+							PeerCircuitID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/rg-dr/providers/Microsoft.Network/expressRouteCircuits/er-circuit-london",
+							AddressPrefix: "172.16.1.0/29",
+							Status:        "Connected",
+						},
+					},
+				},
+				{
+					Name:                       "MicrosoftPeering",
+					PeeringType:                "MicrosoftPeering",
+					State:                      "Enabled",
+					AzureASN:                   12076,
+					PeerASN:                    65020,
+					PrimaryPeerAddressPrefix:   "203.0.113.0/30",
+					SecondaryPeerAddressPrefix: "203.0.113.4/30",
+					VlanID:                     200,
+					RouteFilterID:              prefix + "routeFilters/rf-m365",
+					RouteFilter: &models.ERRouteFilter{
+						ID:   prefix + "routeFilters/rf-m365",
+						Name: "rf-m365",
+						Rules: []models.RouteFilterRule{
+							{Name: "allow-exchange", Access: "Allow", Communities: []string{"12076:5010", "12076:5040"}},
+						},
+					},
+				},
+			},
+			Authorizations: []models.ERAuthorization{
+				{Name: "auth-ergw-hub", AuthorizationKey: true, AuthorizationUseStatus: "InUse"},
+			},
+			Connections: []string{},
+			Stats: &models.ERCircuitStats{
+				PrimaryBytesIn:    52428800000,
+				PrimaryBytesOut:   31457280000,
+				SecondaryBytesIn:  1048576000,
+				SecondaryBytesOut: 524288000,
+			},
+		},
+	}, nil
 }
 
 // GetLoadBalancers returns mock load balancer data
 func (c *MockAzureClient) GetLoadBalancers(ctx context.Context, resourceGroup string) ([]models.LoadBalancer, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	nicPrefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkInterfaces/"
+
 	return []models.LoadBalancer{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/loadBalancers/lb-web",
-			Name:          "lb-web",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
-			SKU:           "Standard",
-			Type:          "Public",
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/loadBalancers/lb-web",
+			Name:           "lb-web",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "app-team"},
+			SKU:            "Standard",
+			Type:           "Public",
 			FrontendIPConfigs: []models.FrontendIPConfig{
 				{
 					Name:              "frontend-public",
@@ -350,8 +865,13 @@ func (c *MockAzureClient) GetLoadBalancers(ctx context.Context, resourceGroup st
 			},
 			BackendAddressPools: []models.BackendAddressPool{
 				{
-					Name:             "backend-web-servers",
-					BackendIPConfigs: []string{"nic-web-1", "nic-web-2", "nic-web-3"},
+					Name: "backend-web-servers",
+					BackendIPConfigs: []string{
+						nicPrefix + "nic-web-1/ipConfigurations/ipconfig1",
+						nicPrefix + "nic-web-2/ipConfigurations/ipconfig1",
+						nicPrefix + "nic-web-3/ipConfigurations/ipconfig1",
+					},
+					NetworkInterfaces: []string{nicPrefix + "nic-web-1", nicPrefix + "nic-web-2", nicPrefix + "nic-web-3"},
 				},
 			},
 			LoadBalancingRules: []models.LoadBalancingRule{
@@ -386,44 +906,285 @@ func (c *MockAzureClient) GetLoadBalancers(ctx context.Context, resourceGroup st
 			},
 			InboundNATRules: []models.InboundNATRule{},
 		},
+		{
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/loadBalancers/lb-app-internal",
+			Name:           "lb-app-internal",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "prod", "owner": "app-team", "cost-center": "cc-200"},
+			SKU:            "Standard",
+			Type:           "Internal",
+			FrontendIPConfigs: []models.FrontendIPConfig{
+				{
+					Name:             "frontend-app",
+					PrivateIPAddress: "10.1.1.10",
+					SubnetID:         "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-spoke/subnets/subnet-app",
+				},
+			},
+			BackendAddressPools: []models.BackendAddressPool{
+				{
+					Name:              "backend-app",
+					BackendIPConfigs:  []string{},
+					NetworkInterfaces: []string{},
+				},
+			},
+			LoadBalancingRules: []models.LoadBalancingRule{
+				{
+					Name:               "rule-app-https",
+					Protocol:           "TCP",
+					FrontendPort:       443,
+					BackendPort:        8443,
+					IdleTimeoutMinutes: 4,
+					LoadDistribution:   "Default",
+				},
+			},
+			Probes: []models.Probe{
+				{
+					Name:              "probe-app",
+					Protocol:          "TCP",
+					Port:              8443,
+					IntervalInSeconds: 5,
+					NumberOfProbes:    2,
+				},
+			},
+			InboundNATRules: []models.InboundNATRule{},
+		},
 	}, nil
 }
 
 // GetAzureFirewalls returns mock Azure Firewall data
 func (c *MockAzureClient) GetAzureFirewalls(ctx context.Context, resourceGroup string) ([]models.AzureFirewall, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.AzureFirewall{
 		{
 			ID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/azureFirewalls/fw-hub",
 			Name:             "fw-hub",
 			ResourceGroup:    resourceGroup,
+			SubscriptionID:   c.subscriptionID,
 			Location:         "eastus",
+			Tags:             map[string]string{"environment": "prod", "owner": "security-team", "cost-center": "cc-100"},
 			SKU:              "Premium",
 			SubnetID:         "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/AzureFirewallSubnet",
 			PrivateIPAddress: "10.0.0.4",
 			PublicIPAddresses: []string{
 				"/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/publicIPAddresses/pip-firewall",
 			},
+			FirewallPolicyID:  "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/firewallPolicies/fwpolicy-hub",
 			ThreatIntelMode:   "Alert",
 			DNSProxyEnabled:   true,
 			ProvisioningState: "Succeeded",
 		},
+		{
+			ID:                "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/azureFirewalls/fw-vhub",
+			Name:              "fw-vhub",
+			ResourceGroup:     resourceGroup,
+			SubscriptionID:    c.subscriptionID,
+			Location:          "eastus",
+			Tags:              map[string]string{"environment": "prod", "owner": "security-team"},
+			SKU:               "Standard",
+			PrivateIPAddress:  "10.100.64.4",
+			PublicIPAddresses: []string{},
+			PublicIPs:         []string{"20.84.1.10"},
+			FirewallPolicyID:  "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/firewallPolicies/fwpolicy-hub",
+			VirtualHubID:      "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualHubs/vhub-eastus",
+			ThreatIntelMode:   "Alert",
+			ProvisioningState: "Succeeded",
+		},
+	}, nil
+}
+
+// GetVirtualWANs returns mock Virtual WAN data: vwan-core with a single hub
+func (c *MockAzureClient) GetVirtualWANs(ctx context.Context, resourceGroup string) ([]models.VirtualWAN, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+
+	return []models.VirtualWAN{
+		{
+			ID:                         prefix + "virtualWans/vwan-core",
+			Name:                       "vwan-core",
+			ResourceGroup:              resourceGroup,
+			SubscriptionID:             c.subscriptionID,
+			Location:                   "eastus",
+			Tags:                       map[string]string{"environment": "prod", "owner": "network-team"},
+			Type:                       "Standard",
+			AllowBranchToBranchTraffic: true,
+			AllowVNetToVNetTraffic:     true,
+			VirtualHubs:                []string{prefix + "virtualHubs/vhub-eastus"},
+		},
+	}, nil
+}
+
+// GetVirtualHubs returns mock virtual hub data: vhub-eastus is secured by fw-vhub
+// with routing intent for internet and private traffic. vnet-vwan-spoke is connected
+// with internet security; vnet-partner, outside the mock resource group, without.
+func (c *MockAzureClient) GetVirtualHubs(ctx context.Context, resourceGroup string) ([]models.VirtualHub, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+	hubID := prefix + "virtualHubs/vhub-eastus"
+	firewallID := prefix + "azureFirewalls/fw-vhub"
+	defaultRouteTableID := hubID + "/hubRouteTables/defaultRouteTable"
+
+	return []models.VirtualHub{
+		{
+			ID:                    hubID,
+			Name:                  "vhub-eastus",
+			ResourceGroup:         resourceGroup,
+			SubscriptionID:        c.subscriptionID,
+			Location:              "eastus",
+			Tags:                  map[string]string{"environment": "prod", "owner": "network-team"},
+			VirtualWANID:          prefix + "virtualWans/vwan-core",
+			AddressPrefix:         "10.100.0.0/23",
+			SKU:                   "Standard",
+			RoutingPreference:     "ExpressRoute",
+			RoutingState:          "Provisioned",
+			VirtualRouterIPs:      []string{"10.100.0.68", "10.100.0.69"},
+			AzureFirewallID:       firewallID,
+			VPNGatewayID:          prefix + "vpnGateways/vpngw-vhub-eastus",
+			ExpressRouteGatewayID: prefix + "expressRouteGateways/ergw-vhub-eastus",
+			VNetConnections: []models.HubVNetConnection{
+				{
+					ID:                     hubID + "/hubVirtualNetworkConnections/conn-vnet-partner",
+					Name:                   "conn-vnet-partner",
+					RemoteVNetID:           "/subscriptions/" + c.subscriptionID + "/resourceGroups/rg-partner/providers/Microsoft.Network/virtualNetworks/vnet-partner",
+					EnableInternetSecurity: false,
+					AssociatedRouteTable:   defaultRouteTableID,
+					PropagatedRouteTables:  []string{defaultRouteTableID},
+					PropagatedLabels:       []string{"default"},
+					ProvisioningState:      "Succeeded",
+				},
+				{
+					ID:                     hubID + "/hubVirtualNetworkConnections/conn-vnet-vwan-spoke",
+					Name:                   "conn-vnet-vwan-spoke",
+					RemoteVNetID:           prefix + "virtualNetworks/vnet-vwan-spoke",
+					EnableInternetSecurity: true,
+					AssociatedRouteTable:   defaultRouteTableID,
+					PropagatedRouteTables:  []string{defaultRouteTableID},
+					PropagatedLabels:       []string{"default"},
+					ProvisioningState:      "Succeeded",
+				},
+			},
+			RouteTables: []models.HubRouteTable{
+				{
+					ID:     defaultRouteTableID,
+					Name:   "defaultRouteTable",
+					Labels: []string{"default"},
+					Routes: []models.HubRoute{
+						{
+							Name:            "_policy_PublicTraffic",
+							DestinationType: "CIDR",
+							Destinations:    []string{"0.0.0.0/0"},
+							NextHopType:     "ResourceId",
+							NextHop:         firewallID,
+						},
+						{
+							Name:            "_policy_PrivateTraffic",
+							DestinationType: "CIDR",
+							Destinations:    []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
+							NextHopType:     "ResourceId",
+							NextHop:         firewallID,
+						},
+					},
+					AssociatedConnections: []string{
+						hubID + "/hubVirtualNetworkConnections/conn-vnet-partner",
+						hubID + "/hubVirtualNetworkConnections/conn-vnet-vwan-spoke",
+					},
+					PropagatingConnections: []string{
+						hubID + "/hubVirtualNetworkConnections/conn-vnet-partner",
+						hubID + "/hubVirtualNetworkConnections/conn-vnet-vwan-spoke",
+					},
+				},
+			},
+			RoutingIntent: &models.HubRoutingIntent{
+				ID:   hubID + "/routingIntent/hub-routing-intent",
+				Name: "hub-routing-intent",
+				RoutingPolicies: []models.HubRoutingPolicy{
+					{Name: "InternetTraffic", Destinations: []string{models.RoutingIntentInternet}, NextHop: firewallID},
+					{Name: "PrivateTrafficPolicy", Destinations: []string{models.RoutingIntentPrivateTraffic}, NextHop: firewallID},
+				},
+			},
+			Gateways: []models.HubGateway{
+				{
+					ID:         prefix + "expressRouteGateways/ergw-vhub-eastus",
+					Name:       "ergw-vhub-eastus",
+					Type:       models.HubGatewayExpressRoute,
+					ScaleUnits: 1,
+					Connections: []models.HubGatewayConnection{
+						{
+							ID:                     prefix + "expressRouteGateways/ergw-vhub-eastus/expressRouteConnections/conn-er-dc1",
+							Name:                   "conn-er-dc1",
+							RemoteID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/rg-connectivity/providers/Microsoft.Network/expressRouteCircuits/er-dc1/peerings/AzurePrivatePeering",
+							EnableInternetSecurity: true,
+						},
+					},
+				},
+				{
+					ID:         prefix + "vpnGateways/vpngw-vhub-eastus",
+					Name:       "vpngw-vhub-eastus",
+					Type:       models.HubGatewayVPN,
+					ScaleUnits: 1,
+					Connections: []models.HubGatewayConnection{
+						{
+							ID:                     prefix + "vpnGateways/vpngw-vhub-eastus/vpnConnections/conn-branch-office",
+							Name:                   "conn-branch-office",
+							RemoteID:               prefix + "vpnSites/site-branch-office",
+							EnableInternetSecurity: false,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// GetBastionHosts returns mock Bastion data: bas-hub in vnet-hub, which also reaches vnet-spoke
+func (c *MockAzureClient) GetBastionHosts(ctx context.Context, resourceGroup string) ([]models.BastionHost, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+
+	return []models.BastionHost{
+		{
+			ID:                prefix + "bastionHosts/bas-hub",
+			Name:              "bas-hub",
+			ResourceGroup:     resourceGroup,
+			SubscriptionID:    c.subscriptionID,
+			Location:          "eastus",
+			Tags:              map[string]string{"environment": "prod", "owner": "network-team"},
+			SKU:               "Standard",
+			ScaleUnits:        2,
+			DNSName:           "bst-7f3c2a1e.bastion.azure.com",
+			SubnetID:          prefix + "virtualNetworks/vnet-hub/subnets/AzureBastionSubnet",
+			VNetID:            prefix + "virtualNetworks/vnet-hub",
+			PublicIPAddressID: prefix + "publicIPAddresses/pip-bastion",
+			Tunneling:         true,
+			ShareableLink:     true,
+			ProvisioningState: "Succeeded",
+		},
 	}, nil
 }
 
 // GetApplicationGateways returns mock application gateway data
 func (c *MockAzureClient) GetApplicationGateways(ctx context.Context, resourceGroup string) ([]models.ApplicationGateway, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
 	return []models.ApplicationGateway{
 		{
-			ID:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/applicationGateways/appgw-web",
-			Name:          "appgw-web",
-			ResourceGroup: resourceGroup,
-			Location:      "eastus",
-			SKU:           "WAF_v2",
-			Tier:          "WAF_v2",
-			Capacity:      2,
-			SubnetID:      "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-appgw",
-			WAFEnabled:    true,
-			WAFMode:       "Prevention",
+			ID:             "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/applicationGateways/appgw-web",
+			Name:           "appgw-web",
+			ResourceGroup:  resourceGroup,
+			SubscriptionID: c.subscriptionID,
+			Location:       "eastus",
+			Tags:           map[string]string{"environment": "dev", "owner": "app-team"},
+			SKU:            "WAF_v2",
+			Tier:           "WAF_v2",
+			Capacity:       2,
+			SubnetID:       "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/virtualNetworks/vnet-hub/subnets/subnet-appgw",
+			WAFEnabled:     true,
+			WAFMode:        "Prevention",
 			FrontendIPConfigs: []models.AppGWFrontendIPConfig{
 				{
 					Name:              "appGwPublicFrontendIp",
@@ -485,65 +1246,207 @@ func (c *MockAzureClient) GetApplicationGateways(ctx context.Context, resourceGr
 	}, nil
 }
 
-// GetNetworkWatcherInsights returns mock Network Watcher insights
-func (c *MockAzureClient) GetNetworkWatcherInsights(ctx context.Context, resourceGroup string) (*models.NetworkWatcherInsights, error) {
-	return &models.NetworkWatcherInsights{
-		FlowLogsEnabled: true,
-		FlowLogs: []models.FlowLog{
-			{
-				ID:               "/subscriptions/" + c.subscriptionID + "/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_eastus/flowLogs/fl-nsg-web",
-				NSGId:            "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/networkSecurityGroups/nsg-web",
-				StorageAccountID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Storage/storageAccounts/stflowlogs",
-				Enabled:          true,
-				RetentionDays:    30,
-				TrafficAnalytics: true,
+// GetFirewallPolicies returns mock firewall policy data: fwpolicy-hub, used by fw-hub,
+// inherits a platform rule collection group from fwpolicy-base and adds a DNAT to
+// RDP, a temporary any-any network rule and an application rule allowing any FQDN
+func (c *MockAzureClient) GetFirewallPolicies(ctx context.Context, resourceGroup string) ([]models.FirewallPolicy, error) {
+	resourceGroup = mockResourceGroup(resourceGroup)
+
+	prefix := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.Network/"
+	baseID := prefix + "firewallPolicies/fwpolicy-base"
+	hubID := prefix + "firewallPolicies/fwpolicy-hub"
+
+	return []models.FirewallPolicy{
+		{
+			ID:              baseID,
+			Name:            "fwpolicy-base",
+			ResourceGroup:   resourceGroup,
+			SubscriptionID:  c.subscriptionID,
+			Location:        "eastus",
+			Tags:            map[string]string{"owner": "security-team"},
+			SKU:             "Premium",
+			ThreatIntelMode: "Deny",
+			ChildPolicies:   []string{hubID},
+			Firewalls:       []string{},
+			RuleCollectionGroups: []models.FirewallRuleCollectionGroup{
+				{
+					ID:       baseID + "/ruleCollectionGroups/platform",
+					Name:     "platform",
+					Priority: 100,
+					RuleCollections: []models.FirewallRuleCollection{
+						{
+							Name:           "allow-platform-dns",
+							Priority:       100,
+							CollectionType: "Filter",
+							Action:         "Allow",
+							Rules: []models.FirewallRule{
+								{
+									Name:                 "azure-dns",
+									Description:          "Azure-provided DNS",
+									RuleType:             "NetworkRule",
+									SourceAddresses:      []string{"10.0.0.0/8"},
+									DestinationAddresses: []string{"168.63.129.16"},
+									DestinationPorts:     []string{"53"},
+									Protocols:            []string{"UDP", "TCP"},
+								},
+							},
+						},
+					},
+				},
 			},
 		},
-		ConnectionMonitors: []models.ConnectionMonitor{
-			{
-				Name:             "monitor-web-to-db",
-				Source:           "vm-web-01",
-				Destination:      "10.0.2.10",
-				MonitoringStatus: "Running",
+		{
+			ID:              hubID,
+			Name:            "fwpolicy-hub",
+			ResourceGroup:   resourceGroup,
+			SubscriptionID:  c.subscriptionID,
+			Location:        "eastus",
+			Tags:            map[string]string{"environment": "prod", "owner": "security-team"},
+			SKU:             "Premium",
+			ThreatIntelMode: "Alert",
+			DNSProxyEnabled: true,
+			BasePolicyID:    baseID,
+			ChildPolicies:   []string{},
+			Firewalls:       []string{prefix + "azureFirewalls/fw-hub", prefix + "azureFirewalls/fw-vhub"},
+			RuleCollectionGroups: []models.FirewallRuleCollectionGroup{
+				{
+					ID:       hubID + "/ruleCollectionGroups/dnat",
+					Name:     "dnat",
+					Priority: 100,
+					RuleCollections: []models.FirewallRuleCollection{
+						{
+							Name:           "inbound-dnat",
+							Priority:       100,
+							CollectionType: "NAT",
+							Action:         "DNAT",
+							Rules: []models.FirewallRule{
+								{
+									Name:                 "rdp-jumpbox",
+									RuleType:             "NatRule",
+									SourceAddresses:      []string{"*"},
+									DestinationAddresses: []string{"20.62.10.7"},
+									DestinationPorts:     []string{"3389"},
+									Protocols:            []string{"TCP"},
+									TranslatedAddress:    "10.0.1.4",
+									TranslatedPort:       "3389",
+								},
+							},
+						},
+					},
+				},
+				{
+					ID:       hubID + "/ruleCollectionGroups/workloads",
+					Name:     "workloads",
+					Priority: 200,
+					RuleCollections: []models.FirewallRuleCollection{
+						{
+							Name:           "allow-outbound",
+							Priority:       100,
+							CollectionType: "Filter",
+							Action:         "Allow",
+							Rules: []models.FirewallRule{
+								{
+									Name:                 "allow-all-temp",
+									RuleType:             "NetworkRule",
+									SourceAddresses:      []string{"*"},
+									DestinationAddresses: []string{"*"},
+									DestinationPorts:     []string{"*"},
+									Protocols:            []string{"Any"},
+								},
+							},
+						},
+						{
+							Name:           "allow-web",
+							Priority:       200,
+							CollectionType: "Filter",
+							Action:         "Allow",
+							Rules: []models.FirewallRule{
+								{
+									Name:                 "windows-update",
+									RuleType:             "ApplicationRule",
+									SourceAddresses:      []string{"10.0.1.0/24"},
+									DestinationAddresses: []string{},
+									Protocols:            []string{"Http:80", "Https:443"},
+									FQDNTags:             []string{"WindowsUpdate"},
+								},
+								{
+									Name:                 "any-https",
+									RuleType:             "ApplicationRule",
+									SourceAddresses:      []string{"10.0.1.0/24"},
+									DestinationAddresses: []string{},
+									Protocols:            []string{"Https:443"},
+									TargetFQDNs:          []string{"*"},
+								},
+							},
+						},
+					},
+				},
 			},
 		},
-		PacketCaptures: []models.PacketCapture{},
 	}, nil
 }
 
+// GetNetworkWatcherInsights returns mock Network Watcher insights. Every location
+// gets a watcher in NetworkWatcherRG; the eastus watcher has a flow log on nsg-web
+// and a connection monitor.
+func (c *MockAzureClient) GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error) {
+	insights := &models.NetworkWatcherInsights{
+		Watchers:           []models.NetworkWatcher{},
+		FlowLogs:           []models.FlowLog{},
+		ConnectionMonitors: []models.ConnectionMonitor{},
+		PacketCaptures:     []models.PacketCapture{},
+	}
+
+	for _, location := range locations {
+		location = normalizeLocation(location)
+		watcherID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_" + location
+		insights.Watchers = append(insights.Watchers, models.NetworkWatcher{
+			ID:             watcherID,
+			Name:           "NetworkWatcher_" + location,
+			ResourceGroup:  "NetworkWatcherRG",
+			SubscriptionID: c.subscriptionID,
+			Location:       location,
+		})
+
+		if location != "eastus" {
+			continue
+		}
+
+		nsgID := "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + mockResourceGroupName + "/providers/Microsoft.Network/networkSecurityGroups/nsg-web"
+		insights.FlowLogsEnabled = true
+		insights.FlowLogs = append(insights.FlowLogs, models.FlowLog{
+			ID:               watcherID + "/flowLogs/fl-nsg-web",
+			Name:             "fl-nsg-web",
+			Location:         location,
+			NSGId:            nsgID,
+			TargetResourceID: nsgID,
+			StorageAccountID: "/subscriptions/" + c.subscriptionID + "/resourceGroups/" + mockResourceGroupName + "/providers/Microsoft.Storage/storageAccounts/stflowlogs",
+			Enabled:          true,
+			RetentionDays:    30,
+			TrafficAnalytics: true,
+		})
+		insights.ConnectionMonitors = append(insights.ConnectionMonitors, models.ConnectionMonitor{
+			ID:               watcherID + "/connectionMonitors/monitor-web-to-db",
+			Location:         location,
+			Name:             "monitor-web-to-db",
+			Source:           "vm-web-01",
+			Destination:      "10.0.2.10",
+			MonitoringStatus: "Running",
+		})
+	}
+
+	return insights, nil
+}
+
 // GenerateMockTopology generates a complete mock topology for testing
 func GenerateMockTopology(subscriptionID, resourceGroup string) *models.NetworkTopology {
 	client := NewMockAzureClient(subscriptionID)
-	ctx := context.Background()
-
-	vnets, _ := client.GetVirtualNetworks(ctx, resourceGroup)
-	nsgs, _ := client.GetNetworkSecurityGroups(ctx, resourceGroup)
-	privateEndpoints, _ := client.GetPrivateEndpoints(ctx, resourceGroup)
-	dnsZones, _ := client.GetPrivateDNSZones(ctx, resourceGroup)
-	routeTables, _ := client.GetRouteTables(ctx, resourceGroup)
-	natGateways, _ := client.GetNATGateways(ctx, resourceGroup)
-	vpnGateways, _ := client.GetVPNGateways(ctx, resourceGroup)
-	erCircuits, _ := client.GetExpressRouteCircuits(ctx, resourceGroup)
-	loadBalancers, _ := client.GetLoadBalancers(ctx, resourceGroup)
-	appGateways, _ := client.GetApplicationGateways(ctx, resourceGroup)
-	azureFirewalls, _ := client.GetAzureFirewalls(ctx, resourceGroup)
-	nwInsights, _ := client.GetNetworkWatcherInsights(ctx, resourceGroup)
-
-	return &models.NetworkTopology{
-		SubscriptionID:   subscriptionID,
-		ResourceGroup:    resourceGroup,
-		VirtualNetworks:  vnets,
-		NSGs:             nsgs,
-		PrivateEndpoints: privateEndpoints,
-		PrivateDNSZones:  dnsZones,
-		RouteTables:      routeTables,
-		NATGateways:      natGateways,
-		VPNGateways:      vpnGateways,
-		ERCircuits:       erCircuits,
-		LoadBalancers:    loadBalancers,
-		AppGateways:      appGateways,
-		AzureFirewalls:   azureFirewalls,
-		NetworkWatcher:   nwInsights,
-		Timestamp:        time.Now(),
+	scope := CollectionScope{
+		SubscriptionIDs: []string{subscriptionID},
+		ResourceGroups:  []string{resourceGroup},
 	}
+
+	// The mock collectors never fail, so the error can be safely ignored
+	topology, _ := CollectTopology(context.Background(), client, scope)
+	return topology
 }
@@ -2,68 +2,236 @@ package azure
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 
 	"azure-network-analyzer/pkg/models"
 )
 
-// GetNetworkWatcherInsights retrieves Network Watcher insights for the specified resource group
-// Note: Network Watcher is typically deployed at the region level, not resource group level
-func (c *AzureClient) GetNetworkWatcherInsights(ctx context.Context, resourceGroup string) (*models.NetworkWatcherInsights, error) {
+// GetNetworkWatcherInsights retrieves flow logs, connection monitors and packet captures
+// from the Network Watcher of each of the given locations.
+// Note: Network Watcher is deployed once per region, usually in NetworkWatcherRG, so it
+// is looked up by location rather than by resource group. Locations without a Network
+// Watcher are reported in MissingRegions.
+func (c *AzureClient) GetNetworkWatcherInsights(ctx context.Context, locations []string) (*models.NetworkWatcherInsights, error) {
 	insights := &models.NetworkWatcherInsights{
 		FlowLogsEnabled:    false,
+		Watchers:           []models.NetworkWatcher{},
 		FlowLogs:           []models.FlowLog{},
 		ConnectionMonitors: []models.ConnectionMonitor{},
 		PacketCaptures:     []models.PacketCapture{},
 	}
 
-	// Try to get flow logs
-	flowLogs, err := c.GetFlowLogs(ctx, resourceGroup)
-	if err == nil && len(flowLogs) > 0 {
-		insights.FlowLogs = flowLogs
-		insights.FlowLogsEnabled = true
+	watchers, err := c.GetNetworkWatchers(ctx, locations)
+	if err != nil {
+		return nil, err
+	}
+	insights.Watchers = watchers
+	insights.MissingRegions = missingRegions(locations, watchers)
+
+	// Each watcher's results go in their own slot so the output order
+	// follows the watcher order regardless of completion order
+	flowLogs := make([][]models.FlowLog, len(watchers))
+	monitors := make([][]models.ConnectionMonitor, len(watchers))
+	captures := make([][]models.PacketCapture, len(watchers))
+
+	var tasks []func(context.Context) error
+	for i, watcher := range watchers {
+		tasks = append(tasks,
+			// Flow logs drive the per-NSG coverage report, so a failure here is
+			// returned rather than reported as missing coverage
+			func(ctx context.Context) error {
+				logs, err := c.GetFlowLogs(ctx, watcher)
+				if err != nil {
+					return err
+				}
+				flowLogs[i] = logs
+				return nil
+			},
+			// Connection monitors and packet captures are informational; skip them
+			// if they cannot be read
+			func(ctx context.Context) error {
+				if m, err := c.GetConnectionMonitors(ctx, watcher); err == nil {
+					monitors[i] = m
+				}
+				return nil
+			},
+			func(ctx context.Context) error {
+				if pc, err := c.GetPacketCaptures(ctx, watcher); err == nil {
+					captures[i] = pc
+				}
+				return nil
+			},
+		)
+	}
+
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
 	}
 
-	// Try to get connection monitors
-	monitors, err := c.GetConnectionMonitors(ctx, resourceGroup)
-	if err == nil {
-		insights.ConnectionMonitors = monitors
+	for i := range watchers {
+		insights.FlowLogs = append(insights.FlowLogs, flowLogs[i]...)
+		insights.ConnectionMonitors = append(insights.ConnectionMonitors, monitors[i]...)
+		insights.PacketCaptures = append(insights.PacketCaptures, captures[i]...)
 	}
 
-	// Try to get packet captures
-	captures, err := c.GetPacketCaptures(ctx, resourceGroup)
-	if err == nil {
-		insights.PacketCaptures = captures
+	for _, fl := range insights.FlowLogs {
+		if fl.Enabled {
+			insights.FlowLogsEnabled = true
+			break
+		}
 	}
 
 	return insights, nil
 }
 
-// GetNetworkWatcher finds the Network Watcher instance for the region
-func (c *AzureClient) GetNetworkWatcher(ctx context.Context, resourceGroup string) (string, error) {
-	// Network Watcher is typically in a resource group named "NetworkWatcherRG"
-	// and follows the pattern "NetworkWatcher_<region>"
-	// For now, return the standard resource group name
-	return "NetworkWatcherRG", nil
+// GetNetworkWatchers returns the Network Watcher instances of the subscription that are
+// deployed in one of the given locations
+func (c *AzureClient) GetNetworkWatchers(ctx context.Context, locations []string) ([]models.NetworkWatcher, error) {
+	client, err := c.getWatchersClient()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, location := range locations {
+		wanted[normalizeLocation(location)] = true
+	}
+
+	var watchers []models.NetworkWatcher
+	var pager itemPager[armnetwork.Watcher]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.Watcher](c, graphNetworkWatchers, "")
+	} else {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.WatchersClientListAllResponse) []*armnetwork.Watcher {
+			return r.Value
+		})
+	}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list network watchers: %w", err)
+		}
+
+		for _, w := range page {
+			if w == nil || !wanted[normalizeLocation(safeString(w.Location))] {
+				continue
+			}
+
+			watchers = append(watchers, models.NetworkWatcher{
+				ID:             safeString(w.ID),
+				Name:           safeString(w.Name),
+				ResourceGroup:  extractResourceGroup(safeString(w.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(w.ID)),
+				Location:       safeString(w.Location),
+				Tags:           safeTags(w.Tags),
+			})
+		}
+	}
+
+	return watchers, nil
 }
 
-// GetFlowLogs retrieves NSG flow logs from Network Watcher
-func (c *AzureClient) GetFlowLogs(ctx context.Context, resourceGroup string) ([]models.FlowLog, error) {
-	// TODO: Implement using Network Watcher Flow Logs API
-	// This requires finding the Network Watcher instance first
-	// and then listing flow logs for that watcher
-	return []models.FlowLog{}, nil
+// GetFlowLogs retrieves the flow logs configured on a Network Watcher
+func (c *AzureClient) GetFlowLogs(ctx context.Context, watcher models.NetworkWatcher) ([]models.FlowLog, error) {
+	client, err := c.getFlowLogsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var flowLogs []models.FlowLog
+	pager := newItemPager(client.NewListPager(watcher.ResourceGroup, watcher.Name, nil), func(r armnetwork.FlowLogsClientListResponse) []*armnetwork.FlowLog {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list flow logs for %s: %w", watcher.Name, err)
+		}
+
+		for _, fl := range page {
+			if fl != nil {
+				flowLogs = append(flowLogs, c.extractFlowLog(fl))
+			}
+		}
+	}
+
+	return flowLogs, nil
+}
+
+// GetConnectionMonitors retrieves the connection monitors of a Network Watcher
+func (c *AzureClient) GetConnectionMonitors(ctx context.Context, watcher models.NetworkWatcher) ([]models.ConnectionMonitor, error) {
+	client, err := c.getConnectionMonitorsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var monitors []models.ConnectionMonitor
+	pager := newItemPager(client.NewListPager(watcher.ResourceGroup, watcher.Name, nil), func(r armnetwork.ConnectionMonitorsClientListResponse) []*armnetwork.ConnectionMonitorResult {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list connection monitors for %s: %w", watcher.Name, err)
+		}
+
+		for _, m := range page {
+			if m != nil {
+				monitors = append(monitors, c.extractConnectionMonitor(m))
+			}
+		}
+	}
+
+	return monitors, nil
+}
+
+// GetPacketCaptures retrieves the packet capture sessions of a Network Watcher
+func (c *AzureClient) GetPacketCaptures(ctx context.Context, watcher models.NetworkWatcher) ([]models.PacketCapture, error) {
+	client, err := c.getPacketCapturesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var captures []models.PacketCapture
+	pager := newItemPager(client.NewListPager(watcher.ResourceGroup, watcher.Name, nil), func(r armnetwork.PacketCapturesClientListResponse) []*armnetwork.PacketCaptureResult {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packet captures for %s: %w", watcher.Name, err)
+		}
+
+		for _, pc := range page {
+			if pc != nil {
+				captures = append(captures, c.extractPacketCapture(pc))
+			}
+		}
+	}
+
+	return captures, nil
 }
 
-// GetConnectionMonitors retrieves connection monitors from Network Watcher
-func (c *AzureClient) GetConnectionMonitors(ctx context.Context, resourceGroup string) ([]models.ConnectionMonitor, error) {
-	// TODO: Implement using Network Watcher Connection Monitor API
-	// Requires NetworkWatchersClient and listing connection monitors
-	return []models.ConnectionMonitor{}, nil
+// missingRegions returns the locations that have no Network Watcher
+func missingRegions(locations []string, watchers []models.NetworkWatcher) []string {
+	covered := make(map[string]bool)
+	for _, w := range watchers {
+		covered[normalizeLocation(w.Location)] = true
+	}
+
+	var missing []string
+	for _, location := range locations {
+		if !covered[normalizeLocation(location)] {
+			missing = append(missing, location)
+		}
+	}
+	return missing
 }
 
-// GetPacketCaptures retrieves packet captures from Network Watcher
-func (c *AzureClient) GetPacketCaptures(ctx context.Context, resourceGroup string) ([]models.PacketCapture, error) {
-	// TODO: Implement using Network Watcher Packet Capture API
-	// Requires NetworkWatchersClient and listing packet captures
-	return []models.PacketCapture{}, nil
+// normalizeLocation maps display names such as "East US" to the ARM form "eastus"
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
 }
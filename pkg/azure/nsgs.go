@@ -9,7 +9,8 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 )
 
-// GetNetworkSecurityGroups retrieves all NSGs in the specified resource group
+// GetNetworkSecurityGroups retrieves all NSGs in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetNetworkSecurityGroups(ctx context.Context, resourceGroup string) ([]models.NetworkSecurityGroup, error) {
 	client, err := c.getNSGsClient()
 	if err != nil {
@@ -17,7 +18,18 @@ func (c *AzureClient) GetNetworkSecurityGroups(ctx context.Context, resourceGrou
 	}
 
 	var nsgs []models.NetworkSecurityGroup
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.SecurityGroup]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.SecurityGroup](c, graphNSGs, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.SecurityGroupsClientListAllResponse) []*armnetwork.SecurityGroup {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.SecurityGroupsClientListResponse) []*armnetwork.SecurityGroup {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -25,13 +37,15 @@ func (c *AzureClient) GetNetworkSecurityGroups(ctx context.Context, resourceGrou
 			return nil, fmt.Errorf("failed to get next page of NSGs: %w", err)
 		}
 
-		for _, nsg := range page.Value {
+		for _, nsg := range page {
 			n := models.NetworkSecurityGroup{
-				ID:            safeString(nsg.ID),
-				Name:          safeString(nsg.Name),
-				ResourceGroup: resourceGroup,
-				Location:      safeString(nsg.Location),
-				SecurityRules: []models.SecurityRule{},
+				ID:             safeString(nsg.ID),
+				Name:           safeString(nsg.Name),
+				ResourceGroup:  extractResourceGroup(safeString(nsg.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(nsg.ID)),
+				Location:       safeString(nsg.Location),
+				Tags:           safeTags(nsg.Tags),
+				SecurityRules:  []models.SecurityRule{},
 				Associations: models.NSGAssociations{
 					Subnets:           []string{},
 					NetworkInterfaces: []string{},
@@ -48,6 +62,7 @@ func (c *AzureClient) GetNetworkSecurityGroups(ctx context.Context, resourceGrou
 				// Also include default security rules
 				for _, rule := range nsg.Properties.DefaultSecurityRules {
 					r := extractSecurityRule(rule)
+					r.IsDefault = true
 					n.SecurityRules = append(n.SecurityRules, r)
 				}
 
@@ -97,6 +112,7 @@ func (c *AzureClient) GetNSGSecurityRules(ctx context.Context, resourceGroup, ns
 		// Default rules
 		for _, rule := range nsg.Properties.DefaultSecurityRules {
 			r := extractSecurityRule(rule)
+			r.IsDefault = true
 			rules = append(rules, r)
 		}
 	}
@@ -182,6 +198,15 @@ func extractSecurityRule(rule *armnetwork.SecurityRule) models.SecurityRule {
 			r.DestinationPortRange = *rule.Properties.DestinationPortRange
 		}
 
+		// Rules with several prefixes, port ranges or ASGs use the plural
+		// properties and leave the singular ones empty
+		r.SourceAddressPrefixes = safeStrings(rule.Properties.SourceAddressPrefixes)
+		r.SourcePortRanges = safeStrings(rule.Properties.SourcePortRanges)
+		r.DestinationAddressPrefixes = safeStrings(rule.Properties.DestinationAddressPrefixes)
+		r.DestinationPortRanges = safeStrings(rule.Properties.DestinationPortRanges)
+		r.SourceASGs = asgIDs(rule.Properties.SourceApplicationSecurityGroups)
+		r.DestinationASGs = asgIDs(rule.Properties.DestinationApplicationSecurityGroups)
+
 		if rule.Properties.Description != nil {
 			r.Description = *rule.Properties.Description
 		}
@@ -189,3 +214,60 @@ func extractSecurityRule(rule *armnetwork.SecurityRule) models.SecurityRule {
 
 	return r
 }
+
+// asgIDs returns the IDs of the referenced application security groups
+func asgIDs(asgs []*armnetwork.ApplicationSecurityGroup) []string {
+	ids := make([]string, 0, len(asgs))
+	for _, asg := range asgs {
+		if asg != nil && asg.ID != nil {
+			ids = append(ids, *asg.ID)
+		}
+	}
+	return ids
+}
+
+// GetApplicationSecurityGroups retrieves all application security groups in the specified
+// resource group, or across the whole subscription when resourceGroup is empty
+func (c *AzureClient) GetApplicationSecurityGroups(ctx context.Context, resourceGroup string) ([]models.ApplicationSecurityGroup, error) {
+	client, err := c.getASGsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var asgs []models.ApplicationSecurityGroup
+	var pager itemPager[armnetwork.ApplicationSecurityGroup]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.ApplicationSecurityGroup](c, graphASGs, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.ApplicationSecurityGroupsClientListAllResponse) []*armnetwork.ApplicationSecurityGroup {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.ApplicationSecurityGroupsClientListResponse) []*armnetwork.ApplicationSecurityGroup {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Application Security Groups: %w", err)
+		}
+
+		for _, asg := range page {
+			if asg == nil {
+				continue
+			}
+			asgs = append(asgs, models.ApplicationSecurityGroup{
+				ID:             safeString(asg.ID),
+				Name:           safeString(asg.Name),
+				ResourceGroup:  extractResourceGroup(safeString(asg.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(asg.ID)),
+				Location:       safeString(asg.Location),
+				Tags:           safeTags(asg.Tags),
+			})
+		}
+	}
+
+	return asgs, nil
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// itemPager iterates pages of ARM resources independently of the SDK response
+// type, so a collector can use the same loop for resource-group and
+// subscription-wide listings
+type itemPager[T any] interface {
+	More() bool
+	NextPage(ctx context.Context) ([]*T, error)
+}
+
+// responsePager adapts an SDK pager to itemPager
+type responsePager[R, T any] struct {
+	pager *runtime.Pager[R]
+	items func(R) []*T
+}
+
+func (p *responsePager[R, T]) More() bool {
+	return p.pager.More()
+}
+
+func (p *responsePager[R, T]) NextPage(ctx context.Context) ([]*T, error) {
+	page, err := p.pager.NextPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.items(page), nil
+}
+
+// newItemPager wraps an SDK pager, using items to pull the resources out of each page
+func newItemPager[R, T any](pager *runtime.Pager[R], items func(R) []*T) itemPager[T] {
+	return &responsePager[R, T]{pager: pager, items: items}
+}
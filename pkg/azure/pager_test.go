@@ -0,0 +1,72 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+type testPage struct {
+	Value    []*string
+	NextLink *string
+}
+
+func TestItemPager(t *testing.T) {
+	pages := [][]*string{
+		{strPtr("a"), strPtr("b")},
+		{strPtr("c")},
+	}
+
+	newPager := func(failOn int) *runtime.Pager[testPage] {
+		index := 0
+		return runtime.NewPager(runtime.PagingHandler[testPage]{
+			More: func(page testPage) bool {
+				return page.NextLink != nil
+			},
+			Fetcher: func(ctx context.Context, _ *testPage) (testPage, error) {
+				if index == failOn {
+					return testPage{}, errors.New("fetch failed")
+				}
+				page := testPage{Value: pages[index]}
+				index++
+				if index < len(pages) {
+					page.NextLink = strPtr("next")
+				}
+				return page, nil
+			},
+		})
+	}
+	values := func(p testPage) []*string { return p.Value }
+
+	t.Run("Iterates every page", func(t *testing.T) {
+		pager := newItemPager(newPager(-1), values)
+
+		var got []string
+		for pager.More() {
+			page, err := pager.NextPage(context.Background())
+			if err != nil {
+				t.Fatalf("NextPage failed: %v", err)
+			}
+			for _, item := range page {
+				got = append(got, *item)
+			}
+		}
+
+		if len(got) != 3 || got[0] != "a" || got[2] != "c" {
+			t.Errorf("Unexpected items: %v", got)
+		}
+	})
+
+	t.Run("Propagates fetch errors", func(t *testing.T) {
+		pager := newItemPager(newPager(1), values)
+
+		if _, err := pager.NextPage(context.Background()); err != nil {
+			t.Fatalf("First page should succeed: %v", err)
+		}
+		if _, err := pager.NextPage(context.Background()); err == nil {
+			t.Error("Expected error from second page")
+		}
+	})
+}
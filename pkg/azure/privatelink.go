@@ -5,9 +5,13 @@ import (
 	"fmt"
 
 	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 )
 
-// GetPrivateEndpoints retrieves all private endpoints in the specified resource group
+// GetPrivateEndpoints retrieves all private endpoints in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup string) ([]models.PrivateEndpoint, error) {
 	client, err := c.getPrivateEndpointsClient()
 	if err != nil {
@@ -15,7 +19,18 @@ func (c *AzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup str
 	}
 
 	var endpoints []models.PrivateEndpoint
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.PrivateEndpoint]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.PrivateEndpoint](c, graphPrivateEndpoints, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListBySubscriptionPager(nil), func(r armnetwork.PrivateEndpointsClientListBySubscriptionResponse) []*armnetwork.PrivateEndpoint {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.PrivateEndpointsClientListResponse) []*armnetwork.PrivateEndpoint {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -23,14 +38,19 @@ func (c *AzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup str
 			return nil, fmt.Errorf("failed to get next page of Private Endpoints: %w", err)
 		}
 
-		for _, pe := range page.Value {
+		for _, pe := range page {
 			endpoint := models.PrivateEndpoint{
-				ID:            safeString(pe.ID),
-				Name:          safeString(pe.Name),
-				ResourceGroup: resourceGroup,
-				Location:      safeString(pe.Location),
-				SubnetID:      "",
-				GroupIDs:      []string{},
+				ID:             safeString(pe.ID),
+				Name:           safeString(pe.Name),
+				ResourceGroup:  extractResourceGroup(safeString(pe.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(pe.ID)),
+				Location:       safeString(pe.Location),
+				Tags:           safeTags(pe.Tags),
+				SubnetID:       "",
+				GroupIDs:       []string{},
+
+				PrivateIPAddresses: []string{},
+				DNSConfigs:         []models.PrivateEndpointDNSConfig{},
 			}
 
 			if pe.Properties != nil {
@@ -38,10 +58,19 @@ func (c *AzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup str
 					endpoint.SubnetID = *pe.Properties.Subnet.ID
 				}
 
-				// Get private IP address from network interfaces
 				if len(pe.Properties.NetworkInterfaces) > 0 && pe.Properties.NetworkInterfaces[0].ID != nil {
-					endpoint.PrivateIPAddress = "See NIC: " + extractResourceName(*pe.Properties.NetworkInterfaces[0].ID)
+					endpoint.NetworkInterfaceID = *pe.Properties.NetworkInterfaces[0].ID
+				}
+
+				// customDnsConfigs carries both the private IPs and the FQDNs. It is
+				// empty for endpoints integrated with a private DNS zone group; those
+				// are resolved through their network interface below.
+				for _, config := range pe.Properties.CustomDNSConfigs {
+					if config != nil {
+						endpoint.DNSConfigs = append(endpoint.DNSConfigs, c.extractCustomDNSConfig(config))
+					}
 				}
+				setPrivateEndpointIPs(&endpoint, dnsConfigIPs(endpoint.DNSConfigs))
 
 				// Extract private link service connections
 				for _, conn := range pe.Properties.PrivateLinkServiceConnections {
@@ -89,20 +118,231 @@ func (c *AzureClient) GetPrivateEndpoints(ctx context.Context, resourceGroup str
 		}
 	}
 
+	// Look up the network interface of endpoints without customDnsConfigs in
+	// parallel, bounded by the client concurrency
+	var tasks []func(context.Context) error
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if len(endpoint.PrivateIPAddresses) > 0 || endpoint.NetworkInterfaceID == "" {
+			continue
+		}
+		tasks = append(tasks, func(ctx context.Context) error {
+			return c.resolvePrivateEndpointNIC(ctx, endpoint)
+		})
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
 	return endpoints, nil
 }
 
-// GetPrivateDNSZones retrieves all private DNS zones in the specified resource group
-// Note: This requires the privatedns SDK package
+// resolvePrivateEndpointNIC fills in a private endpoint's IPs and FQDNs from its network interface
+func (c *AzureClient) resolvePrivateEndpointNIC(ctx context.Context, endpoint *models.PrivateEndpoint) error {
+	client, err := c.getInterfacesClient()
+	if err != nil {
+		return err
+	}
+
+	nicID := endpoint.NetworkInterfaceID
+	resp, err := client.Get(ctx, extractResourceGroup(nicID), extractResourceName(nicID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get network interface of private endpoint %s: %w", endpoint.Name, err)
+	}
+
+	ips, configs := c.extractNICDNSConfigs(&resp.Interface)
+	endpoint.DNSConfigs = configs
+	setPrivateEndpointIPs(endpoint, ips)
+	return nil
+}
+
+// setPrivateEndpointIPs records the endpoint's private IPs, keeping the first as PrivateIPAddress
+func setPrivateEndpointIPs(endpoint *models.PrivateEndpoint, ips []string) {
+	endpoint.PrivateIPAddresses = ips
+	endpoint.PrivateIPAddress = ""
+	if len(ips) > 0 {
+		endpoint.PrivateIPAddress = ips[0]
+	}
+}
+
+// dnsConfigIPs returns the distinct IPs of a set of DNS configs in order of appearance
+func dnsConfigIPs(configs []models.PrivateEndpointDNSConfig) []string {
+	seen := make(map[string]bool)
+	ips := []string{}
+	for _, config := range configs {
+		for _, ip := range config.IPAddresses {
+			if !seen[ip] {
+				seen[ip] = true
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips
+}
+
+// GetPrivateLinkServices retrieves all private link services in the specified resource
+// group, or across the whole subscription when resourceGroup is empty, together with
+// the private endpoint connections consumers have made to them
+func (c *AzureClient) GetPrivateLinkServices(ctx context.Context, resourceGroup string) ([]models.PrivateLinkService, error) {
+	client, err := c.getPrivateLinkServicesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var services []models.PrivateLinkService
+	var pager itemPager[armnetwork.PrivateLinkService]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.PrivateLinkService](c, graphPrivateLinkServices, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListBySubscriptionPager(nil), func(r armnetwork.PrivateLinkServicesClientListBySubscriptionResponse) []*armnetwork.PrivateLinkService {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.PrivateLinkServicesClientListResponse) []*armnetwork.PrivateLinkService {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Private Link Services: %w", err)
+		}
+
+		for _, pls := range page {
+			if pls != nil {
+				services = append(services, c.extractPrivateLinkService(pls))
+			}
+		}
+	}
+
+	return services, nil
+}
+
+// GetPrivateDNSZones retrieves all private DNS zones in the specified resource group,
+// or across the whole subscription when resourceGroup is empty, together with each
+// zone's VNet links and A records
 func (c *AzureClient) GetPrivateDNSZones(ctx context.Context, resourceGroup string) ([]models.PrivateDNSZone, error) {
-	// TODO: Implement using github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns
-	// For now, return empty slice as this requires an additional SDK package
-	return []models.PrivateDNSZone{}, nil
+	client, err := c.getPrivateZonesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []models.PrivateDNSZone
+	var pager itemPager[armprivatedns.PrivateZone]
+	if c.graph != nil {
+		pager = newGraphPager[armprivatedns.PrivateZone](c, graphPrivateDNSZones, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListPager(nil), func(r armprivatedns.PrivateZonesClientListResponse) []*armprivatedns.PrivateZone {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListByResourceGroupPager(resourceGroup, nil), func(r armprivatedns.PrivateZonesClientListByResourceGroupResponse) []*armprivatedns.PrivateZone {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Private DNS Zones: %w", err)
+		}
+
+		for _, z := range page {
+			zone := models.PrivateDNSZone{
+				ID:             safeString(z.ID),
+				Name:           safeString(z.Name),
+				ResourceGroup:  extractResourceGroup(safeString(z.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(z.ID)),
+				Tags:           safeTags(z.Tags),
+				VNetLinks:      []models.VNetLink{},
+				ARecords:       []models.DNSARecord{},
+			}
+
+			if z.Properties != nil && z.Properties.NumberOfRecordSets != nil {
+				zone.RecordSets = int(*z.Properties.NumberOfRecordSets)
+			}
+
+			zones = append(zones, zone)
+		}
+	}
+
+	// Fetch each zone's VNet links and A records in parallel, bounded by the client concurrency
+	tasks := make([]func(context.Context) error, 0, 2*len(zones))
+	for i := range zones {
+		zone := &zones[i]
+		tasks = append(tasks,
+			func(ctx context.Context) error {
+				links, err := c.GetPrivateDNSZoneVNetLinks(ctx, zone.ResourceGroup, zone.Name)
+				if err != nil {
+					return err
+				}
+				zone.VNetLinks = links
+				return nil
+			},
+			func(ctx context.Context) error {
+				records, err := c.GetPrivateDNSARecords(ctx, zone.ResourceGroup, zone.Name)
+				if err != nil {
+					return err
+				}
+				zone.ARecords = records
+				return nil
+			},
+		)
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
 }
 
 // GetPrivateDNSZoneVNetLinks retrieves VNet links for a specific private DNS zone
 func (c *AzureClient) GetPrivateDNSZoneVNetLinks(ctx context.Context, resourceGroup, zoneName string) ([]models.VNetLink, error) {
-	// TODO: Implement using github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns
-	// For now, return empty slice
-	return []models.VNetLink{}, nil
+	client, err := c.getDNSVNetLinksClient()
+	if err != nil {
+		return nil, err
+	}
+
+	links := []models.VNetLink{}
+	pager := client.NewListPager(resourceGroup, zoneName, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of VNet links for %s: %w", zoneName, err)
+		}
+
+		for _, link := range page.Value {
+			links = append(links, c.extractVNetLink(link))
+		}
+	}
+
+	return links, nil
+}
+
+// GetPrivateDNSARecords retrieves the A record sets in a specific private DNS zone.
+// For privatelink zones these are the records that resolve service FQDNs to
+// private endpoint IP addresses.
+func (c *AzureClient) GetPrivateDNSARecords(ctx context.Context, resourceGroup, zoneName string) ([]models.DNSARecord, error) {
+	client, err := c.getDNSRecordSetsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []models.DNSARecord{}
+	pager := client.NewListByTypePager(resourceGroup, zoneName, armprivatedns.RecordTypeA, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of A records for %s: %w", zoneName, err)
+		}
+
+		for _, recordSet := range page.Value {
+			records = append(records, c.extractARecord(recordSet))
+		}
+	}
+
+	return records, nil
 }
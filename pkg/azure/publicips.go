@@ -0,0 +1,108 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// GetPublicIPAddresses retrieves all public IP addresses in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
+func (c *AzureClient) GetPublicIPAddresses(ctx context.Context, resourceGroup string) ([]models.PublicIPAddress, error) {
+	client, err := c.getPublicIPAddressesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var publicIPs []models.PublicIPAddress
+	var pager itemPager[armnetwork.PublicIPAddress]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.PublicIPAddress](c, graphPublicIPAddresses, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.PublicIPAddressesClientListAllResponse) []*armnetwork.PublicIPAddress {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.PublicIPAddressesClientListResponse) []*armnetwork.PublicIPAddress {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Public IP Addresses: %w", err)
+		}
+
+		for _, pip := range page {
+			if pip != nil {
+				publicIPs = append(publicIPs, c.extractPublicIPAddress(pip))
+			}
+		}
+	}
+
+	return publicIPs, nil
+}
+
+// resolvePublicIPs fills in the addresses behind the public IP resource IDs held by
+// frontends, NAT gateways, firewalls, Bastion hosts and NICs. References to public IPs that were
+// not collected (e.g. in a resource group outside the scope) are left unresolved.
+func resolvePublicIPs(topology *models.NetworkTopology) {
+	addresses := make(map[string]string, len(topology.PublicIPAddresses))
+	for _, pip := range topology.PublicIPAddresses {
+		if pip.IPAddress != "" {
+			addresses[strings.ToLower(pip.ID)] = pip.IPAddress
+		}
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	lookup := func(id string) string {
+		return addresses[strings.ToLower(id)]
+	}
+	lookupAll := func(ids []string) []string {
+		var ips []string
+		for _, id := range ids {
+			if ip := lookup(id); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+		return ips
+	}
+
+	for i := range topology.LoadBalancers {
+		for j := range topology.LoadBalancers[i].FrontendIPConfigs {
+			fe := &topology.LoadBalancers[i].FrontendIPConfigs[j]
+			fe.PublicIPAddress = lookup(fe.PublicIPAddressID)
+		}
+	}
+	for i := range topology.AppGateways {
+		for j := range topology.AppGateways[i].FrontendIPConfigs {
+			fe := &topology.AppGateways[i].FrontendIPConfigs[j]
+			fe.PublicIPAddress = lookup(fe.PublicIPAddressID)
+		}
+	}
+	for i := range topology.NATGateways {
+		topology.NATGateways[i].PublicIPs = lookupAll(topology.NATGateways[i].PublicIPAddresses)
+	}
+	for i := range topology.AzureFirewalls {
+		// Hub firewalls report their addresses directly rather than through public IP resources
+		if fw := &topology.AzureFirewalls[i]; len(fw.PublicIPAddresses) > 0 {
+			fw.PublicIPs = lookupAll(fw.PublicIPAddresses)
+		}
+	}
+	for i := range topology.BastionHosts {
+		topology.BastionHosts[i].PublicIPAddress = lookup(topology.BastionHosts[i].PublicIPAddressID)
+	}
+	for i := range topology.NetworkInterfaces {
+		for j := range topology.NetworkInterfaces[i].IPConfigurations {
+			ipConfig := &topology.NetworkInterfaces[i].IPConfigurations[j]
+			ipConfig.PublicIPAddress = lookup(ipConfig.PublicIPAddressID)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Recorded responses are scrubbed before they are written: subscription IDs are
+// replaced by a stable placeholder, the ARM host by the public endpoint, and
+// credentials and identities by redactedValue.
+const (
+	redactedValue    = "REDACTED"
+	recordedEndpoint = "https://management.azure.com"
+)
+
+// sensitiveFields are JSON properties whose values are never written to fixtures
+var sensitiveFields = map[string]bool{
+	"sharedkey":        true,
+	"authorizationkey": true,
+	"servicekey":       true,
+	"primarykey":       true,
+	"secondarykey":     true,
+	"password":         true,
+	"secret":           true,
+	"clientsecret":     true,
+	"principalid":      true,
+	"tenantid":         true,
+	"clientid":         true,
+}
+
+var subscriptionPattern = regexp.MustCompile(`(?i)/subscriptions/([^/?#"\s]+)`)
+
+// scrubSubscriptions replaces every subscription ID in s with a placeholder
+// derived from it, so recordings from different subscriptions stay apart
+// without revealing the real IDs
+func scrubSubscriptions(s string) string {
+	return subscriptionPattern.ReplaceAllStringFunc(s, func(match string) string {
+		id := match[len("/subscriptions/"):]
+		return match[:len("/subscriptions/")] + placeholderSubscription(id)
+	})
+}
+
+func placeholderSubscription(id string) string {
+	if strings.HasPrefix(id, "00000000-0000-0000-0000-") {
+		return id // already scrubbed
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(id)))
+	return "00000000-0000-0000-0000-" + hex.EncodeToString(sum[:6])
+}
+
+// fixture is one recorded ARM response
+type fixture struct {
+	Request    string          `json:"request"`
+	StatusCode int             `json:"statusCode"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// fixtureKey identifies a request independently of the ARM host, the
+// subscription placeholder, query parameter order, and the api-version, so
+// fixtures survive SDK upgrades. Requests with a body, such as Resource Graph
+// queries, are told apart by a digest of the body.
+func fixtureKey(req *http.Request) (string, error) {
+	query := req.URL.Query()
+	query.Del("api-version")
+	key := req.Method + " " + scrubSubscriptions(req.URL.Path)
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+
+	digest, err := bodyDigest(req)
+	if err != nil {
+		return "", err
+	}
+	if digest != "" {
+		key += " body=" + digest
+	}
+	return key, nil
+}
+
+// bodyDigest hashes a JSON request body, leaving out the subscriptions a
+// Resource Graph query is scoped to. The body is left ready to be sent.
+func bodyDigest(req *http.Request) (string, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return "", nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	// azcore's GetBody seeks the request's own body back to the start and
+	// returns it rather than a copy, so reading it above drained req.Body.
+	// Calling it again rewinds the stream for the transport.
+	if _, err := req.GetBody(); err != nil {
+		return "", err
+	}
+
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) == nil {
+		delete(fields, "subscriptions")
+		data, _ = json.Marshal(fields) // map keys are sorted
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6]), nil
+}
+
+// fixtureFile names the file holding the fixture for key, e.g.
+// "virtualNetworks-1a2b3c4d5e6f.json"
+func fixtureFile(dir, key string) string {
+	path := key[strings.Index(key, " ")+1:]
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	name := strings.ReplaceAll(apiResourceType(path), "/", "_")
+	sum := sha256.Sum256([]byte(strings.ToLower(key)))
+	return filepath.Join(dir, name+"-"+hex.EncodeToString(sum[:6])+".json")
+}
+
+// scrubBody removes subscription IDs, the ARM host and sensitive fields from a
+// JSON response body. Bodies that are not JSON are dropped.
+func scrubBody(body []byte, host string) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+	scrubbed, err := json.MarshalIndent(scrubValue(value, host), "", "  ")
+	if err != nil {
+		return nil
+	}
+	return scrubbed
+}
+
+func scrubValue(value any, host string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if s, ok := field.(string); ok {
+				switch {
+				case sensitiveFields[strings.ToLower(key)]:
+					v[key] = redactedValue
+					continue
+				case strings.EqualFold(key, "subscriptionId"):
+					// Resource Graph rows carry the bare subscription ID
+					v[key] = placeholderSubscription(s)
+					continue
+				}
+			}
+			v[key] = scrubValue(field, host)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = scrubValue(v[i], host)
+		}
+		return v
+	case string:
+		if host != "" {
+			v = strings.ReplaceAll(v, "https://"+host, recordedEndpoint)
+		}
+		return scrubSubscriptions(v)
+	}
+	return value
+}
+
+// recordingTransport sends requests through next and saves every ARM response
+// it receives as a scrubbed fixture in dir
+type recordingTransport struct {
+	next policy.Transporter
+	dir  string
+	mu   sync.Mutex
+}
+
+func newRecordingTransport(dir string, next policy.Transporter) (*recordingTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	if next == nil {
+		next = &http.Client{}
+	}
+	return &recordingTransport{next: next, dir: dir}, nil
+}
+
+func (t *recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	key, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := json.MarshalIndent(fixture{
+		Request:    key,
+		StatusCode: resp.StatusCode,
+		Body:       scrubBody(body, req.URL.Host),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.WriteFile(fixtureFile(t.dir, key), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record ARM response: %w", err)
+	}
+	return resp, nil
+}
+
+// replayTransport answers requests from the fixtures in a directory and never
+// reaches the network. Requests without a fixture fail.
+type replayTransport struct {
+	fixtures map[string]fixture // by lower-cased key
+}
+
+func newReplayTransport(dir string) (*replayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded ARM responses found in %s", dir)
+	}
+
+	t := &replayTransport{fixtures: make(map[string]fixture)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var f fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+		}
+		t.fixtures[strings.ToLower(f.Request)] = f
+	}
+	return t, nil
+}
+
+func (t *replayTransport) Do(req *http.Request) (*http.Response, error) {
+	key, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := t.fixtures[strings.ToLower(key)]
+	if !ok {
+		return nil, &missingFixtureError{key: key}
+	}
+
+	// nextLinks were recorded against the public endpoint; point them back at
+	// the endpoint the client is using, and restore the requested subscription ID
+	body := bytes.ReplaceAll(f.Body, []byte(recordedEndpoint), []byte(req.URL.Scheme+"://"+req.URL.Host))
+	for _, id := range requestSubscriptions(req) {
+		body = bytes.ReplaceAll(body, []byte(placeholderSubscription(id)), []byte(id))
+	}
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if f.StatusCode >= 400 {
+		var armErr struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(f.Body, &armErr) == nil && armErr.Error.Code != "" {
+			header.Set("x-ms-error-code", armErr.Error.Code)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// requestSubscriptions returns the subscriptions a request is for: the one in
+// its path, or those a Resource Graph query is scoped to
+func requestSubscriptions(req *http.Request) []string {
+	if m := subscriptionPattern.FindStringSubmatch(req.URL.Path); m != nil {
+		return []string{m[1]}
+	}
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	var query struct {
+		Subscriptions []string `json:"subscriptions"`
+	}
+	json.NewDecoder(body).Decode(&query)
+	return query.Subscriptions
+}
+
+// missingFixtureError reports a request that was not recorded. It is not
+// retried, since replaying the same request cannot succeed.
+type missingFixtureError struct {
+	key string
+}
+
+func (e *missingFixtureError) Error() string {
+	return "no recorded ARM response for " + e.key
+}
+
+func (e *missingFixtureError) NonRetriable() {}
+
+// replayCredential stands in for a real credential when replaying, so no
+// token is requested from Microsoft Entra ID
+type replayCredential struct{}
+
+func (replayCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "replay", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
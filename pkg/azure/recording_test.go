@@ -0,0 +1,443 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"azure-network-analyzer/pkg/azure/fakearm"
+	"azure-network-analyzer/pkg/models"
+)
+
+const (
+	fakeSubscription = "11111111-2222-3333-4444-555555555555"
+	fakeRG           = "/subscriptions/" + fakeSubscription + "/resourceGroups/rg-network"
+	fakeProviders    = fakeRG + "/providers/Microsoft.Network"
+)
+
+// newFakeARM starts a fake ARM server holding a small hub network. Payloads are
+// deliberately sparse: most optional properties are missing or null.
+func newFakeARM(t *testing.T) *fakearm.Server {
+	t.Helper()
+
+	srv := fakearm.NewServer()
+	t.Cleanup(srv.Close)
+	srv.PageSize = 1 // every list is paged
+
+	err := srv.Add(
+		`{"id": "`+fakeRG+`", "location": "eastus"}`,
+		`{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub", "location": "eastus", "tags": {"environment": "prod"},
+		  "properties": {
+			"addressSpace": {"addressPrefixes": ["10.0.0.0/16", null]},
+			"dhcpOptions": null,
+			"subnets": [
+				{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/GatewaySubnet", "name": "GatewaySubnet",
+				 "properties": {"addressPrefix": "10.0.0.0/27"}},
+				{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/snet-app", "name": "snet-app",
+				 "properties": {"addressPrefix": "10.0.1.0/24",
+				   "networkSecurityGroup": {"id": "`+fakeProviders+`/networkSecurityGroups/nsg-app"},
+				   "natGateway": {"id": "`+fakeProviders+`/natGateways/ng-app"},
+				   "ipConfigurations": null, "delegations": [{"name": "d", "properties": null}]}},
+				{"name": "unnamed-properties"}
+			],
+			"virtualNetworkPeerings": [{"name": "to-spoke", "properties": {"remoteVirtualNetwork": null}}]
+		  }}`,
+		`{"id": "`+fakeProviders+`/virtualNetworks/vnet-empty", "location": "eastus"}`,
+		`{"id": "`+fakeProviders+`/networkSecurityGroups/nsg-app", "location": "eastus",
+		  "properties": {"securityRules": [
+			{"name": "AllowSSH", "properties": {"priority": 100, "direction": "Inbound", "access": "Allow",
+			  "protocol": "Tcp", "sourceAddressPrefix": "*", "destinationPortRange": "22"}},
+			{"name": "empty-rule", "properties": null}
+		  ],
+		  "subnets": [{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/snet-app"}]}}`,
+		`{"id": "`+fakeProviders+`/virtualNetworkGateways/vgw-hub", "location": "eastus",
+		  "properties": {"gatewayType": "Vpn", "ipConfigurations": [{"properties": {"subnet":
+			{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/GatewaySubnet"}}}]}}`,
+		`{"id": "`+fakeProviders+`/routeTables/rt-app", "location": "eastus", "properties": {"routes": null}}`,
+		`{"id": "`+fakeProviders+`/azureFirewalls/fw-hub", "location": "eastus"}`,
+		`{"id": "`+fakeProviders+`/natGateways/ng-app", "location": "eastus", "sku": {"name": "Standard"}, "zones": ["1", null],
+		  "properties": {"publicIpPrefixes": [{"id": "`+fakeProviders+`/publicIPPrefixes/ippre-app"}, {"id": null}],
+			"publicIpAddresses": null, "subnets": [{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/snet-app"}]}}`,
+		`{"id": "`+fakeProviders+`/publicIPAddresses/pip-vgw", "location": "eastus", "sku": {"name": "Standard"},
+		  "properties": {"ipAddress": "20.1.2.3", "publicIPAllocationMethod": "Static"}}`,
+		`{"id": "`+fakeProviders+`/networkInterfaces/nic-app", "location": "eastus",
+		  "properties": {"ipConfigurations": [{"name": "ipconfig1", "properties": {"privateIPAddress": "10.0.1.4",
+			"subnet": {"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/snet-app"}}}]}}`,
+		`{"id": "`+fakeProviders+`/localNetworkGateways/lgw-onprem", "location": "eastus",
+		  "properties": {"gatewayIpAddress": "198.51.100.1", "localNetworkAddressSpace": {"addressPrefixes": ["192.168.0.0/16"]}}}`,
+		`{"id": "`+fakeProviders+`/networkWatchers/NetworkWatcher_eastus", "location": "eastus"}`,
+		`{"id": "`+fakeProviders+`/virtualNetworkGateways/ergw-hub", "location": "eastus",
+		  "properties": {"gatewayType": "ExpressRoute", "ipConfigurations": [{"properties": {"subnet":
+			{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/GatewaySubnet"}}}]}}`,
+		`{"id": "`+fakeProviders+`/expressRouteCircuits/er-dc", "location": "eastus",
+		  "properties": {"globalReachEnabled": true, "serviceKey": "s3cr3t-service-key", "serviceProviderProperties": {"serviceProviderName": "Equinix"},
+			"peerings": [
+				{"name": "MicrosoftPeering", "properties": {"peeringType": "MicrosoftPeering",
+				  "routeFilter": {"id": "`+fakeProviders+`/routeFilters/rf-m365"}}},
+				{"name": "AzurePrivatePeering", "properties": {"peeringType": "AzurePrivatePeering", "peerASN": 65020,
+				  "connections": [{"name": "gr-london", "properties": {"circuitConnectionStatus": "Connected",
+					"peerExpressRouteCircuitPeering": {"id": "`+fakeRG+`2/providers/Microsoft.Network/expressRouteCircuits/er-london/peerings/AzurePrivatePeering"}}},
+					null]}}
+			]}}`,
+		`{"id": "`+fakeProviders+`/expressRouteCircuits/er-dc/stats", "primarybytesIn": 2048, "primarybytesOut": 1024}`,
+		`{"id": "`+fakeProviders+`/routeFilters/rf-m365", "location": "eastus",
+		  "properties": {"rules": [{"name": "allow-exchange", "properties": {"access": "Allow", "communities": ["12076:5010", null]}}]}}`,
+	)
+	if err != nil {
+		t.Fatalf("Failed to add resources: %v", err)
+	}
+	srv.SetList(fakeProviders+"/virtualNetworkGateways/vgw-hub/connections",
+		`{"id": "`+fakeProviders+`/connections/to-onprem", "name": "to-onprem",
+		  "properties": {"connectionType": "IPsec", "connectionStatus": "Connected", "sharedKey": "s3cr3t-psk"}}`)
+	srv.SetList(fakeProviders+"/virtualNetworkGateways/ergw-hub/connections",
+		`{"id": "`+fakeProviders+`/connections/er-to-dc", "name": "er-to-dc",
+		  "properties": {"connectionType": "ExpressRoute", "connectionStatus": "Connected", "expressRouteGatewayBypass": true,
+			"peer": {"id": "`+fakeProviders+`/expressRouteCircuits/er-dc"}}}`)
+	srv.SetList(fakeProviders+"/virtualNetworks/vnet-hub/usages",
+		`{"id": "`+fakeProviders+`/virtualNetworks/vnet-hub/subnets/GatewaySubnet", "currentValue": 2, "limit": 27, "unit": "Count"}`,
+		`{"id": "`+strings.ToUpper(fakeProviders)+`/virtualNetworks/vnet-hub/subnets/snet-app", "currentValue": 200, "limit": 251, "unit": "Count"}`,
+		`{"id": null, "currentValue": 1}`)
+	return srv
+}
+
+// newFakeARMClient returns a client that talks to srv
+func newFakeARMClient(t *testing.T, srv *fakearm.Server, opts ClientOptions) *AzureClient {
+	t.Helper()
+
+	cloudConfig, err := ParseCloud(srv.URL)
+	if err != nil {
+		t.Fatalf("ParseCloud failed: %v", err)
+	}
+	opts.Credential = staticCredential{}
+	opts.Transport = srv.Client()
+	opts.Cloud = cloudConfig
+
+	client, err := NewAzureClientWithOptions(opts, fakeSubscription)
+	if err != nil {
+		t.Fatalf("NewAzureClientWithOptions failed: %v", err)
+	}
+	return client
+}
+
+func collectFakeTopology(t *testing.T, collector Collector) *models.NetworkTopology {
+	t.Helper()
+
+	opts := DefaultCollectOptions()
+	opts.Strict = true
+	topology, err := CollectTopologyWithOptions(context.Background(), collector, CollectionScope{
+		SubscriptionIDs: []string{fakeSubscription},
+		ResourceGroups:  []string{"rg-network"},
+	}, opts)
+	if err != nil {
+		t.Fatalf("CollectTopologyWithOptions failed: %v", err)
+	}
+	return topology
+}
+
+func TestCollectTopologyFromFakeARM(t *testing.T) {
+	srv := newFakeARM(t)
+	topology := collectFakeTopology(t, newFakeARMClient(t, srv, testRetryOptions()))
+
+	if len(topology.VirtualNetworks) != 2 {
+		t.Fatalf("Expected 2 VNets across two pages, got %d", len(topology.VirtualNetworks))
+	}
+	hub := topology.VirtualNetworks[1] // sorted by ID, after vnet-empty
+	if hub.Name != "vnet-hub" || hub.SubscriptionID != fakeSubscription || hub.Tags["environment"] != "prod" {
+		t.Errorf("Unexpected hub VNet: %+v", hub)
+	}
+	if len(hub.Subnets) != 3 || len(hub.Peerings) != 1 {
+		t.Fatalf("Expected 3 subnets and 1 peering, got %d and %d", len(hub.Subnets), len(hub.Peerings))
+	}
+	for _, subnet := range hub.Subnets {
+		var want *models.SubnetIPUsage
+		switch subnet.Name {
+		case "GatewaySubnet":
+			want = &models.SubnetIPUsage{TotalIPs: 32, ReservedIPs: 5, UsedIPs: 2, AvailableIPs: 25}
+		case "snet-app":
+			want = &models.SubnetIPUsage{TotalIPs: 256, ReservedIPs: 5, UsedIPs: 200, AvailableIPs: 51}
+		}
+		if (want == nil) != (subnet.IPUsage == nil) || (want != nil && *want != *subnet.IPUsage) {
+			t.Errorf("Unexpected IP usage for %s: %+v", subnet.Name, subnet.IPUsage)
+		}
+	}
+
+	if len(topology.NSGs) != 1 || len(topology.NSGs[0].SecurityRules) != 2 {
+		t.Fatalf("Expected nsg-app with 2 rules, got %+v", topology.NSGs)
+	}
+	if rule := topology.NSGs[0].SecurityRules[0]; rule.Name != "AllowSSH" || rule.Priority != 100 {
+		t.Errorf("Unexpected rule: %+v", rule)
+	}
+
+	if len(topology.VPNGateways) != 2 || len(topology.VPNGateways[1].Connections) != 1 {
+		t.Fatalf("Expected vgw-hub with one connection, got %+v", topology.VPNGateways)
+	}
+	if gw := topology.VPNGateways[1]; !strings.HasSuffix(gw.VNetID, "/virtualNetworks/vnet-hub") || !gw.Connections[0].SharedKey {
+		t.Errorf("Unexpected VPN gateway: %+v", gw)
+	}
+	if len(topology.RouteTables) != 1 || len(topology.AzureFirewalls) != 1 {
+		t.Errorf("Expected one route table and one firewall, got %d and %d", len(topology.RouteTables), len(topology.AzureFirewalls))
+	}
+
+	if len(topology.NATGateways) != 1 {
+		t.Fatalf("Expected ng-app, got %+v", topology.NATGateways)
+	}
+	nat := topology.NATGateways[0]
+	if nat.SKU != "Standard" || len(nat.Zones) != 1 || len(nat.PublicIPAddresses) != 0 || len(nat.AssociatedSubnets) != 1 {
+		t.Errorf("Unexpected NAT gateway: %+v", nat)
+	}
+	if len(nat.PublicIPPrefixes) != 1 || !strings.HasSuffix(nat.PublicIPPrefixes[0], "/publicIPPrefixes/ippre-app") {
+		t.Errorf("Expected the ippre-app prefix, got %v", nat.PublicIPPrefixes)
+	}
+	if len(topology.IntegrityWarnings) != 0 {
+		t.Errorf("Expected consistent subnet associations, got %+v", topology.IntegrityWarnings)
+	}
+
+	if len(topology.ERCircuits) != 1 {
+		t.Fatalf("Expected er-dc, got %+v", topology.ERCircuits)
+	}
+	circuit := topology.ERCircuits[0]
+	if conn := topology.VPNGateways[0].Connections[0]; conn.CircuitID != circuit.ID || !conn.FastPath {
+		t.Errorf("Expected er-to-dc to use er-dc with FastPath, got %+v", conn)
+	}
+	if len(circuit.Connections) != 1 || !strings.HasSuffix(circuit.Connections[0], "/connections/er-to-dc") {
+		t.Errorf("Expected er-dc to be linked to er-to-dc, got %v", circuit.Connections)
+	}
+	if !circuit.GlobalReachEnabled || circuit.Stats == nil || circuit.Stats.PrimaryBytesIn != 2048 {
+		t.Errorf("Expected Global Reach and stats on er-dc, got %+v", circuit)
+	}
+	if len(circuit.Peerings) != 2 {
+		t.Fatalf("Expected 2 peerings, got %+v", circuit.Peerings)
+	}
+	microsoft, private := circuit.Peerings[0], circuit.Peerings[1]
+	if f := microsoft.RouteFilter; f == nil || f.Name != "rf-m365" || len(f.Rules) != 1 || f.Rules[0].Access != "Allow" || len(f.Rules[0].Communities) != 1 {
+		t.Errorf("Expected the rf-m365 route filter on Microsoft peering, got %+v", f)
+	}
+	if gr := private.GlobalReachConnections; len(gr) != 1 || !strings.HasSuffix(gr[0].PeerCircuitID, "/expressRouteCircuits/er-london") || gr[0].Status != "Connected" {
+		t.Errorf("Expected Global Reach to er-london, got %+v", gr)
+	}
+
+	// One VNet per page: the second page was fetched through nextLink
+	pages := 0
+	for _, path := range srv.Requests() {
+		if strings.HasSuffix(path, "/virtualNetworks") {
+			pages++
+		}
+	}
+	if pages != 2 {
+		t.Errorf("Expected the VNet list to be fetched in 2 pages, got %d", pages)
+	}
+}
+
+func TestFakeARMErrors(t *testing.T) {
+	srv := newFakeARM(t)
+	srv.Fail(fakeProviders+"/azureFirewalls", 403)
+	client := newFakeARMClient(t, srv, testRetryOptions())
+
+	topology, err := CollectTopology(context.Background(), client, CollectionScope{
+		SubscriptionIDs: []string{fakeSubscription},
+		ResourceGroups:  []string{"rg-network"},
+	})
+	if err != nil {
+		t.Fatalf("CollectTopology failed: %v", err)
+	}
+	if len(topology.CollectionErrors) != 1 {
+		t.Fatalf("Expected one collection error, got %+v", topology.CollectionErrors)
+	}
+	if ce := topology.CollectionErrors[0]; ce.ResourceType != "azure firewalls" || ce.Class != models.ErrorClassAuthorization {
+		t.Errorf("Unexpected collection error: %+v", ce)
+	}
+	if len(topology.VirtualNetworks) != 2 {
+		t.Errorf("Other resource types should still be collected, got %d VNets", len(topology.VirtualNetworks))
+	}
+}
+
+func TestFakeARMSubnetUsageErrors(t *testing.T) {
+	srv := newFakeARM(t)
+	srv.Fail(fakeProviders+"/virtualNetworks/vnet-hub/usages", 403)
+	cache := NewCachingCollector(newFakeARMClient(t, srv, testRetryOptions()), fakeSubscription, CacheOptions{Dir: t.TempDir(), TTL: time.Hour})
+
+	// Strict mode still keeps the VNets, since usage is optional
+	topology := collectFakeTopology(t, cache)
+	if len(topology.VirtualNetworks) != 2 {
+		t.Fatalf("Expected the VNets to be collected without usage, got %d", len(topology.VirtualNetworks))
+	}
+	for _, subnet := range topology.VirtualNetworks[1].Subnets {
+		if subnet.IPUsage != nil {
+			t.Errorf("Expected no IP usage for %s, got %+v", subnet.Name, subnet.IPUsage)
+		}
+	}
+	if len(topology.CollectionErrors) != 1 {
+		t.Fatalf("Expected one collection error, got %+v", topology.CollectionErrors)
+	}
+	if ce := topology.CollectionErrors[0]; ce.ResourceType != "subnet IP usage" || ce.Class != models.ErrorClassAuthorization || !strings.Contains(ce.Message, "vnet-hub") {
+		t.Errorf("Unexpected collection error: %+v", ce)
+	}
+	if _, err := os.Stat(cache.path("rg-rg-network", "virtualNetworks")); !os.IsNotExist(err) {
+		t.Errorf("VNets without their usage should not be cached, got %v", err)
+	}
+}
+
+func TestFakeARMSubnetUsageErrorOrder(t *testing.T) {
+	srv := newFakeARM(t)
+	err := srv.Add(`{"id": "` + fakeProviders + `/virtualNetworks/vnet-spoke", "location": "eastus",
+		"properties": {"subnets": [{"id": "` + fakeProviders + `/virtualNetworks/vnet-spoke/subnets/snet-web", "name": "snet-web",
+		  "properties": {"addressPrefix": "10.1.0.0/24"}}]}}`)
+	if err != nil {
+		t.Fatalf("Failed to add resources: %v", err)
+	}
+	srv.Fail(fakeProviders+"/virtualNetworks/vnet-spoke/usages", 403)
+	srv.Fail(fakeProviders+"/virtualNetworks/vnet-hub/usages", 403)
+
+	// Both failures share a resource type and scope, and finish in any order
+	for i := 0; i < 5; i++ {
+		topology := collectFakeTopology(t, newFakeARMClient(t, srv, testRetryOptions()))
+		errs := topology.CollectionErrors
+		if len(errs) != 2 {
+			t.Fatalf("Expected two collection errors, got %+v", errs)
+		}
+		if !strings.Contains(errs[0].Message, "vnet-hub") || !strings.Contains(errs[1].Message, "vnet-spoke") {
+			t.Fatalf("Run %d: collection errors are not in a stable order: %+v", i, errs)
+		}
+	}
+}
+
+func TestFakeARMRouteFilterErrors(t *testing.T) {
+	srv := newFakeARM(t)
+	srv.Fail(fakeProviders+"/routeFilters/rf-m365", 403)
+
+	topology := collectFakeTopology(t, newFakeARMClient(t, srv, testRetryOptions()))
+	if len(topology.ERCircuits) != 1 {
+		t.Fatalf("Expected er-dc to be collected without its route filter, got %+v", topology.ERCircuits)
+	}
+	microsoft := topology.ERCircuits[0].Peerings[0]
+	if microsoft.RouteFilter != nil || !strings.HasSuffix(microsoft.RouteFilterID, "/routeFilters/rf-m365") {
+		t.Errorf("Expected the route filter to be left as its ID, got %+v", microsoft)
+	}
+	if topology.ERCircuits[0].Stats == nil {
+		t.Error("Expected the circuit's stats to be collected despite the route filter failure")
+	}
+	if len(topology.CollectionErrors) != 1 {
+		t.Fatalf("Expected one collection error, got %+v", topology.CollectionErrors)
+	}
+	if ce := topology.CollectionErrors[0]; ce.ResourceType != "ExpressRoute route filters" || ce.Class != models.ErrorClassAuthorization || !strings.Contains(ce.Message, "rf-m365") {
+		t.Errorf("Unexpected collection error: %+v", ce)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	srv := newFakeARM(t)
+	opts := testRetryOptions()
+	opts.RecordDir = dir
+	recorded := collectFakeTopology(t, newFakeARMClient(t, srv, opts))
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) == 0 {
+		t.Fatal("Expected fixture files to be recorded")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{fakeSubscription, "s3cr3t-psk", "s3cr3t-service-key", strings.TrimPrefix(srv.URL, "https://")} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s was not scrubbed from %s", secret, filepath.Base(file))
+			}
+		}
+	}
+
+	t.Run("Replay serves the same topology offline", func(t *testing.T) {
+		client, err := NewAzureClientWithOptions(ClientOptions{ReplayDir: dir}, fakeSubscription)
+		if err != nil {
+			t.Fatalf("NewAzureClientWithOptions failed: %v", err)
+		}
+		replayed := collectFakeTopology(t, client)
+
+		recorded.Timestamp, replayed.Timestamp = time.Time{}, time.Time{}
+		want, _ := json.Marshal(recorded)
+		got, _ := json.Marshal(replayed)
+		if string(want) != string(got) {
+			t.Errorf("Replayed topology differs from the recorded one:\nwant %s\ngot  %s", want, got)
+		}
+	})
+
+	t.Run("Unrecorded requests fail without retrying", func(t *testing.T) {
+		client, err := NewAzureClientWithOptions(ClientOptions{ReplayDir: dir, MaxRetries: 3, RetryDelay: time.Second}, fakeSubscription)
+		if err != nil {
+			t.Fatalf("NewAzureClientWithOptions failed: %v", err)
+		}
+
+		start := time.Now()
+		_, err = client.GetVirtualNetworks(context.Background(), "rg-other")
+		var missing *missingFixtureError
+		if !errors.As(err, &missing) {
+			t.Fatalf("Expected a missing fixture error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Missing fixture was retried, took %v", elapsed)
+		}
+	})
+
+	t.Run("Replay directory must contain fixtures", func(t *testing.T) {
+		if _, err := NewAzureClientWithOptions(ClientOptions{ReplayDir: t.TempDir()}, fakeSubscription); err == nil {
+			t.Error("Expected an error for an empty replay directory")
+		}
+	})
+}
+
+func TestScrubBody(t *testing.T) {
+	body := `{"id": "/subscriptions/` + fakeSubscription + `/resourceGroups/rg",
+		"nextLink": "https://127.0.0.1:8443/subscriptions/` + fakeSubscription + `/providers/x?$skiptoken=2",
+		"properties": {"sharedKey": "psk", "identity": {"principalId": "p", "tenantId": "t"}, "count": 3}}`
+
+	var scrubbed map[string]any
+	if err := json.Unmarshal(scrubBody([]byte(body), "127.0.0.1:8443"), &scrubbed); err != nil {
+		t.Fatalf("scrubBody returned invalid JSON: %v", err)
+	}
+
+	placeholder := placeholderSubscription(fakeSubscription)
+	if id := scrubbed["id"]; id != "/subscriptions/"+placeholder+"/resourceGroups/rg" {
+		t.Errorf("id = %v", id)
+	}
+	if next := scrubbed["nextLink"]; next != recordedEndpoint+"/subscriptions/"+placeholder+"/providers/x?$skiptoken=2" {
+		t.Errorf("nextLink = %v", next)
+	}
+	props := scrubbed["properties"].(map[string]any)
+	identity := props["identity"].(map[string]any)
+	if props["sharedKey"] != redactedValue || identity["principalId"] != redactedValue || identity["tenantId"] != redactedValue {
+		t.Errorf("Sensitive fields were not redacted: %v", props)
+	}
+	if props["count"] != float64(3) {
+		t.Errorf("Other fields should be kept, got %v", props["count"])
+	}
+
+	if placeholderSubscription(placeholder) != placeholder {
+		t.Error("Scrubbing should be idempotent")
+	}
+	if scrubBody([]byte("not json"), "") != nil {
+		t.Error("Non-JSON bodies should be dropped")
+	}
+
+	circuit := `{"name": "er-dc", "properties": {"serviceKey": "00000000-1111-2222-3333-444444444444",
+		"serviceProviderProvisioningState": "Provisioned",
+		"authorizations": [{"name": "to-spoke", "properties": {"authorizationKey": "auth-key"}}]}}`
+	if err := json.Unmarshal(scrubBody([]byte(circuit), ""), &scrubbed); err != nil {
+		t.Fatalf("scrubBody returned invalid JSON: %v", err)
+	}
+	props = scrubbed["properties"].(map[string]any)
+	authorization := props["authorizations"].([]any)[0].(map[string]any)["properties"].(map[string]any)
+	if props["serviceKey"] != redactedValue || authorization["authorizationKey"] != redactedValue {
+		t.Errorf("ExpressRoute circuit keys were not redacted: %v", props)
+	}
+	if props["serviceProviderProvisioningState"] != "Provisioned" {
+		t.Errorf("Other circuit fields should be kept, got %v", props)
+	}
+}
@@ -3,11 +3,15 @@ package azure
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 )
 
-// GetRouteTables retrieves all route tables in the specified resource group
+// GetRouteTables retrieves all route tables in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetRouteTables(ctx context.Context, resourceGroup string) ([]models.RouteTable, error) {
 	client, err := c.getRouteTablesClient()
 	if err != nil {
@@ -15,7 +19,18 @@ func (c *AzureClient) GetRouteTables(ctx context.Context, resourceGroup string)
 	}
 
 	var routeTables []models.RouteTable
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.RouteTable]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.RouteTable](c, graphRouteTables, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.RouteTablesClientListAllResponse) []*armnetwork.RouteTable {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.RouteTablesClientListResponse) []*armnetwork.RouteTable {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -23,12 +38,14 @@ func (c *AzureClient) GetRouteTables(ctx context.Context, resourceGroup string)
 			return nil, fmt.Errorf("failed to get next page of Route Tables: %w", err)
 		}
 
-		for _, rt := range page.Value {
+		for _, rt := range page {
 			table := models.RouteTable{
 				ID:                safeString(rt.ID),
 				Name:              safeString(rt.Name),
-				ResourceGroup:     resourceGroup,
+				ResourceGroup:     extractResourceGroup(safeString(rt.ID)),
+				SubscriptionID:    extractSubscriptionID(safeString(rt.ID)),
 				Location:          safeString(rt.Location),
+				Tags:              safeTags(rt.Tags),
 				Routes:            []models.Route{},
 				AssociatedSubnets: []string{},
 			}
@@ -84,7 +101,8 @@ func (c *AzureClient) GetRoutes(ctx context.Context, resourceGroup, routeTableNa
 	return routes, nil
 }
 
-// GetNATGateways retrieves all NAT gateways in the specified resource group
+// GetNATGateways retrieves all NAT gateways in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetNATGateways(ctx context.Context, resourceGroup string) ([]models.NATGateway, error) {
 	client, err := c.getNATGatewaysClient()
 	if err != nil {
@@ -92,7 +110,18 @@ func (c *AzureClient) GetNATGateways(ctx context.Context, resourceGroup string)
 	}
 
 	var natGateways []models.NATGateway
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.NatGateway]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.NatGateway](c, graphNATGateways, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.NatGatewaysClientListAllResponse) []*armnetwork.NatGateway {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.NatGatewaysClientListResponse) []*armnetwork.NatGateway {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -100,16 +129,29 @@ func (c *AzureClient) GetNATGateways(ctx context.Context, resourceGroup string)
 			return nil, fmt.Errorf("failed to get next page of NAT Gateways: %w", err)
 		}
 
-		for _, nat := range page.Value {
+		for _, nat := range page {
 			gw := models.NATGateway{
 				ID:                safeString(nat.ID),
 				Name:              safeString(nat.Name),
-				ResourceGroup:     resourceGroup,
+				ResourceGroup:     extractResourceGroup(safeString(nat.ID)),
+				SubscriptionID:    extractSubscriptionID(safeString(nat.ID)),
 				Location:          safeString(nat.Location),
+				Tags:              safeTags(nat.Tags),
+				Zones:             []string{},
 				PublicIPAddresses: []string{},
+				PublicIPPrefixes:  []string{},
 				AssociatedSubnets: []string{},
 			}
 
+			if nat.SKU != nil && nat.SKU.Name != nil {
+				gw.SKU = string(*nat.SKU.Name)
+			}
+			for _, zone := range nat.Zones {
+				if zone != nil {
+					gw.Zones = append(gw.Zones, *zone)
+				}
+			}
+
 			if nat.Properties != nil {
 				if nat.Properties.IdleTimeoutInMinutes != nil {
 					gw.IdleTimeoutMinutes = *nat.Properties.IdleTimeoutInMinutes
@@ -122,6 +164,13 @@ func (c *AzureClient) GetNATGateways(ctx context.Context, resourceGroup string)
 					}
 				}
 
+				// Extract public IP prefixes
+				for _, prefix := range nat.Properties.PublicIPPrefixes {
+					if prefix.ID != nil {
+						gw.PublicIPPrefixes = append(gw.PublicIPPrefixes, *prefix.ID)
+					}
+				}
+
 				// Extract associated subnets
 				for _, subnet := range nat.Properties.Subnets {
 					if subnet.ID != nil {
@@ -137,7 +186,9 @@ func (c *AzureClient) GetNATGateways(ctx context.Context, resourceGroup string)
 	return natGateways, nil
 }
 
-// GetNATGatewayPublicIPs retrieves the public IP addresses associated with a NAT gateway
+// GetNATGatewayPublicIPs retrieves the public IP addresses associated with a NAT
+// gateway. GetNATGateways already returns them, along with the public IP
+// prefixes; this is for looking up a single gateway.
 func (c *AzureClient) GetNATGatewayPublicIPs(ctx context.Context, resourceGroup, natGatewayName string) ([]string, error) {
 	client, err := c.getNATGatewaysClient()
 	if err != nil {
@@ -160,3 +211,111 @@ func (c *AzureClient) GetNATGatewayPublicIPs(ctx context.Context, resourceGroup,
 
 	return publicIPs, nil
 }
+
+// subnetAttachment is a resource that lists the subnets it is associated with
+type subnetAttachment struct {
+	id      string
+	subnets []string
+}
+
+// validateSubnetAssociations cross-checks the subnets route tables and NAT
+// gateways list against the route table and NAT gateway each subnet points at,
+// and records every disagreement as an integrity warning. A resource type that
+// could not be collected is not checked, since every reference to it would
+// look like it points outside the collected scope.
+func validateSubnetAssociations(topology *models.NetworkTopology) {
+	failed := make(map[string]bool)
+	for _, e := range topology.CollectionErrors {
+		failed[e.ResourceType] = true
+	}
+	if failed["virtual networks"] {
+		return
+	}
+
+	if !failed["route tables"] {
+		attachments := make([]subnetAttachment, len(topology.RouteTables))
+		for i, rt := range topology.RouteTables {
+			attachments[i] = subnetAttachment{id: rt.ID, subnets: rt.AssociatedSubnets}
+		}
+		topology.IntegrityWarnings = append(topology.IntegrityWarnings, checkSubnetAssociations(topology, "route table", "Route table", attachments,
+			func(s models.Subnet) *string { return s.RouteTable })...)
+	}
+	if !failed["NAT gateways"] {
+		attachments := make([]subnetAttachment, len(topology.NATGateways))
+		for i, nat := range topology.NATGateways {
+			attachments[i] = subnetAttachment{id: nat.ID, subnets: nat.AssociatedSubnets}
+		}
+		topology.IntegrityWarnings = append(topology.IntegrityWarnings, checkSubnetAssociations(topology, "NAT gateway", "NAT gateway", attachments,
+			func(s models.Subnet) *string { return s.NATGateway })...)
+	}
+}
+
+// checkSubnetAssociations compares the subnets each attachment lists with the
+// attachment each collected subnet references. Listed subnets that were not
+// collected are not reported; a subnet can live in any resource group.
+func checkSubnetAssociations(topology *models.NetworkTopology, kind, title string, attachments []subnetAttachment, reference func(models.Subnet) *string) []models.IntegrityWarning {
+	listed := make(map[string]map[string]bool, len(attachments))
+	for _, a := range attachments {
+		subnets := make(map[string]bool, len(a.subnets))
+		for _, id := range a.subnets {
+			subnets[strings.ToLower(id)] = true
+		}
+		listed[strings.ToLower(a.id)] = subnets
+	}
+
+	var warnings []models.IntegrityWarning
+	subnets := make(map[string]models.Subnet)
+	names := make(map[string]string)
+	for _, vnet := range topology.VirtualNetworks {
+		for _, subnet := range vnet.Subnets {
+			key := strings.ToLower(subnet.ID)
+			subnets[key] = subnet
+			names[key] = vnet.Name + "/" + subnet.Name
+
+			ref := reference(subnet)
+			if ref == nil || *ref == "" {
+				continue
+			}
+			switch listedSubnets, ok := listed[strings.ToLower(*ref)]; {
+			case !ok:
+				warnings = append(warnings, models.IntegrityWarning{
+					ResourceType: "subnet",
+					ResourceID:   subnet.ID,
+					Message: fmt.Sprintf("Subnet %s uses %s %s, which is outside the collected scope",
+						names[key], kind, *ref),
+				})
+			case !listedSubnets[key]:
+				warnings = append(warnings, models.IntegrityWarning{
+					ResourceType: "subnet",
+					ResourceID:   subnet.ID,
+					Message: fmt.Sprintf("Subnet %s uses %s %s, but the %s does not list the subnet",
+						names[key], kind, extractResourceName(*ref), kind),
+				})
+			}
+		}
+	}
+
+	for _, a := range attachments {
+		for _, id := range a.subnets {
+			subnet, ok := subnets[strings.ToLower(id)]
+			if !ok {
+				continue
+			}
+			ref := reference(subnet)
+			if ref != nil && strings.EqualFold(*ref, a.id) {
+				continue
+			}
+			uses := "no " + kind
+			if ref != nil && *ref != "" {
+				uses = kind + " " + extractResourceName(*ref)
+			}
+			warnings = append(warnings, models.IntegrityWarning{
+				ResourceType: kind,
+				ResourceID:   a.id,
+				Message: fmt.Sprintf("%s %s lists subnet %s, but the subnet uses %s",
+					title, extractResourceName(a.id), names[strings.ToLower(id)], uses),
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,242 @@
+package azure
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// ClientOptions configures how AzureClient talks to Azure Resource Manager
+type ClientOptions struct {
+	// MaxRetries is how many times a throttled (429) or failed (408, 5xx or
+	// connection error) request is retried. Zero disables retries.
+	MaxRetries int
+
+	// RetryDelay is the initial backoff between retries; it doubles on every
+	// retry up to MaxRetryDelay. A Retry-After header from ARM takes precedence,
+	// but a request is not retried when ARM asks to wait longer than MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+
+	// RequestTimeout bounds each attempt of a single ARM request. Zero means no limit.
+	RequestTimeout time.Duration
+
+	// Credential authenticates requests; nil creates one from Authentication
+	Credential azcore.TokenCredential
+
+	// Authentication selects the credential mode when Credential is nil
+	Authentication CredentialOptions
+
+	// Cloud selects the ARM endpoint. The zero value is Azure public cloud.
+	Cloud cloud.Configuration
+
+	// Transport sends the HTTP requests; nil uses the SDK's default HTTP client.
+	// Tests set it to talk to a local stand-in for ARM.
+	Transport policy.Transporter
+
+	// RecordDir, when set, saves every ARM response as a scrubbed fixture file in
+	// this directory. Token requests are never recorded.
+	RecordDir string
+
+	// ReplayDir, when set, answers ARM requests from the fixtures recorded in this
+	// directory instead of calling Azure; no credential is needed
+	ReplayDir string
+}
+
+// DefaultClientOptions returns the options used by NewAzureClient
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		MaxRetries:     3,
+		RetryDelay:     time.Second,
+		MaxRetryDelay:  time.Minute,
+		RequestTimeout: 2 * time.Minute,
+	}
+}
+
+// armOptions builds the ARM pipeline options shared by every client. Requests
+// and retries are counted in stats, and every attempt waits for a slot in limiter.
+func (o ClientOptions) armOptions(stats *callStats, limiter *requestLimiter) *arm.ClientOptions {
+	maxRetries := int32(o.MaxRetries)
+	if maxRetries <= 0 {
+		// The SDK treats zero as "use the default", and a negative value as no retries
+		maxRetries = -1
+	}
+
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud:     o.Cloud,
+			Transport: o.Transport,
+			Retry: policy.RetryOptions{
+				MaxRetries:    maxRetries,
+				RetryDelay:    o.RetryDelay,
+				MaxRetryDelay: o.MaxRetryDelay,
+				TryTimeout:    o.RequestTimeout,
+			},
+			PerCallPolicies:  []policy.Policy{callCounter{stats}},
+			PerRetryPolicies: []policy.Policy{attemptCounter{stats}, limiter},
+		},
+	}
+}
+
+// requestLimiter caps the ARM requests in flight across every client and
+// subscription view of an AzureClient, however deeply collection tasks fan out.
+// It runs once per attempt, so retry backoff does not hold a slot.
+type requestLimiter struct {
+	slots atomic.Pointer[chan struct{}]
+}
+
+func newRequestLimiter(limit int) *requestLimiter {
+	l := &requestLimiter{}
+	l.setLimit(limit)
+	return l
+}
+
+// setLimit replaces the slots; requests already in flight release their old slot
+func (l *requestLimiter) setLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+	l.slots.Store(&slots)
+}
+
+func (l *requestLimiter) Do(req *policy.Request) (*http.Response, error) {
+	slots := *l.slots.Load()
+	ctx := req.Raw().Context()
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-slots }()
+	return req.Next()
+}
+
+// APICallStats counts the ARM requests made for one resource type. Every page of
+// a list counts as a call.
+type APICallStats struct {
+	ResourceType string
+	Calls        int
+	Retries      int
+	Throttled    int // responses with status 429
+}
+
+// callStats accumulates APICallStats per resource type. It is shared by every
+// subscription view of an AzureClient.
+type callStats struct {
+	mu    sync.Mutex
+	stats map[string]*APICallStats
+}
+
+func newCallStats() *callStats {
+	return &callStats{stats: make(map[string]*APICallStats)}
+}
+
+// update applies fn to the stats of the resource type requested by req
+func (s *callStats) update(req *http.Request, fn func(*APICallStats)) {
+	resourceType := apiResourceType(req.URL.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[resourceType]
+	if !ok {
+		st = &APICallStats{ResourceType: resourceType}
+		s.stats[resourceType] = st
+	}
+	fn(st)
+}
+
+// snapshot returns the stats sorted by resource type
+func (s *callStats) snapshot() []APICallStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]APICallStats, 0, len(s.stats))
+	for _, st := range s.stats {
+		result = append(result, *st)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ResourceType < result[j].ResourceType
+	})
+	return result
+}
+
+// requestAttempts counts the attempts made for one request. It is attached to the
+// request as an operation value, which every retry of the request shares.
+type requestAttempts struct {
+	n int
+}
+
+// callCounter runs once per request, however many times it is retried
+type callCounter struct {
+	stats *callStats
+}
+
+func (p callCounter) Do(req *policy.Request) (*http.Response, error) {
+	p.stats.update(req.Raw(), func(st *APICallStats) { st.Calls++ })
+	req.SetOperationValue(&requestAttempts{})
+	return req.Next()
+}
+
+// attemptCounter runs once per attempt; every attempt after the first is a retry
+type attemptCounter struct {
+	stats *callStats
+}
+
+func (p attemptCounter) Do(req *policy.Request) (*http.Response, error) {
+	retry := false
+	var attempts *requestAttempts
+	if req.OperationValue(&attempts) {
+		attempts.n++
+		retry = attempts.n > 1
+	}
+
+	resp, err := req.Next()
+	p.stats.update(req.Raw(), func(st *APICallStats) {
+		if retry {
+			st.Retries++
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			st.Throttled++
+		}
+	})
+	return resp, err
+}
+
+// apiResourceType names the resource type an ARM request is for, from its path:
+// "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualHubs/hub/hubRouteTables"
+// is "virtualHubs/hubRouteTables". Requests outside a resource provider, such as
+// listing resource groups, are named after their collection.
+func apiResourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Resource types follow the last provider namespace and alternate with names
+	start := -1
+	for i := len(segments) - 2; i >= 0; i-- {
+		if strings.EqualFold(segments[i], "providers") {
+			start = i + 2
+			break
+		}
+	}
+	if start < 0 {
+		// e.g. /subscriptions/s/resourcegroups
+		start = 2
+	}
+
+	var types []string
+	for i := start; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	if len(types) == 0 {
+		return "other"
+	}
+	return strings.Join(types, "/")
+}
@@ -0,0 +1,273 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// staticCredential hands out a fixed token so tests never reach Entra ID
+type staticCredential struct{}
+
+func (staticCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+const vnetListResponse = `{"value": [{
+	"id": "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1",
+	"name": "vnet1",
+	"location": "eastus",
+	"properties": {"addressSpace": {"addressPrefixes": ["10.0.0.0/16"]}}
+}]}`
+
+// newTestARM starts a local stand-in for ARM and returns a client pointed at it.
+// The handler sees every attempt, including retries.
+func newTestARM(t *testing.T, opts ClientOptions, handler http.HandlerFunc) *AzureClient {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	cloudConfig, err := ParseCloud(srv.URL)
+	if err != nil {
+		t.Fatalf("ParseCloud failed: %v", err)
+	}
+	opts.Credential = staticCredential{}
+	opts.Transport = srv.Client()
+	opts.Cloud = cloudConfig
+
+	client, err := NewAzureClientWithOptions(opts, "sub1")
+	if err != nil {
+		t.Fatalf("NewAzureClientWithOptions failed: %v", err)
+	}
+	return client
+}
+
+// testRetryOptions retries quickly so tests do not wait on backoff
+func testRetryOptions() ClientOptions {
+	return ClientOptions{MaxRetries: 3, RetryDelay: time.Millisecond, MaxRetryDelay: 5 * time.Second}
+}
+
+func TestRetryOnThrottling(t *testing.T) {
+	t.Run("Throttled requests are retried and counted", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) <= 2 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, vnetListResponse)
+		})
+
+		vnets, err := client.GetVirtualNetworks(context.Background(), "rg1")
+		if err != nil {
+			t.Fatalf("GetVirtualNetworks failed: %v", err)
+		}
+		if len(vnets) != 1 || vnets[0].Name != "vnet1" {
+			t.Errorf("Expected vnet1, got %+v", vnets)
+		}
+
+		stats := client.APICallStats()
+		if len(stats) != 1 {
+			t.Fatalf("Expected stats for one resource type, got %+v", stats)
+		}
+		want := APICallStats{ResourceType: "virtualNetworks", Calls: 1, Retries: 2, Throttled: 2}
+		if stats[0] != want {
+			t.Errorf("APICallStats() = %+v, want %+v", stats[0], want)
+		}
+	})
+
+	t.Run("Retry-After is honoured", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, vnetListResponse)
+		})
+
+		start := time.Now()
+		if _, err := client.GetVirtualNetworks(context.Background(), "rg1"); err != nil {
+			t.Fatalf("GetVirtualNetworks failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("Expected the retry to wait for Retry-After, took %v", elapsed)
+		}
+	})
+
+	t.Run("Retry-After beyond MaxRetryDelay fails as throttled", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		_, err := client.GetVirtualNetworks(context.Background(), "rg1")
+		if err == nil {
+			t.Fatal("Expected an error when ARM keeps throttling")
+		}
+		if class := classifyError(err); class != models.ErrorClassThrottled {
+			t.Errorf("classifyError() = %q, want %q", class, models.ErrorClassThrottled)
+		}
+		if n := attempts.Load(); n != 1 {
+			t.Errorf("Expected no retry, got %d attempts", n)
+		}
+	})
+
+	t.Run("Retries can be disabled", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newTestARM(t, ClientOptions{MaxRetries: 0}, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		if _, err := client.GetVirtualNetworks(context.Background(), "rg1"); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if n := attempts.Load(); n != 1 {
+			t.Errorf("Expected a single attempt, got %d", n)
+		}
+	})
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	t.Run("Each attempt is bounded by RequestTimeout", func(t *testing.T) {
+		opts := testRetryOptions()
+		opts.MaxRetries = 1
+		opts.RequestTimeout = 50 * time.Millisecond
+		client := newTestARM(t, opts, slow)
+
+		start := time.Now()
+		if _, err := client.GetVirtualNetworks(context.Background(), "rg1"); err == nil {
+			t.Fatal("Expected a timeout error")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Request was not cut short by RequestTimeout, took %v", elapsed)
+		}
+		if stats := client.APICallStats(); len(stats) != 1 || stats[0].Retries != 1 {
+			t.Errorf("Expected one retry after the timeout, got %+v", stats)
+		}
+	})
+
+	t.Run("Context deadline stops retries", func(t *testing.T) {
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetVirtualNetworks(ctx, "rg1")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestRequestLimiter(t *testing.T) {
+	t.Run("Nested fan-out shares the client limit", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			if strings.HasSuffix(r.URL.Path, "/usages") {
+				fmt.Fprint(w, `{"value": []}`)
+				return
+			}
+			var vnets []string
+			for i := 0; i < 6; i++ {
+				vnets = append(vnets, fmt.Sprintf(`{"id": "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet%d", "name": "vnet%d"}`, i, i))
+			}
+			fmt.Fprintf(w, `{"value": [%s]}`, strings.Join(vnets, ","))
+		})
+		client.SetConcurrency(2)
+
+		// Every list fans out to a usage request per VNet, each bounded by the
+		// client concurrency, inside tasks that are themselves run in parallel
+		tasks := make([]func(context.Context) error, 4)
+		for i := range tasks {
+			tasks[i] = func(ctx context.Context) error {
+				_, err := client.GetVirtualNetworks(ctx, "rg1")
+				return err
+			}
+		}
+		if err := runBounded(context.Background(), 4, tasks); err != nil {
+			t.Fatalf("Collection failed: %v", err)
+		}
+		if p := peak.Load(); p > 2 {
+			t.Errorf("Peak in-flight requests %d exceeded limit 2", p)
+		}
+	})
+
+	t.Run("Waiting for a slot honours cancellation", func(t *testing.T) {
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		client := newTestARM(t, testRetryOptions(), func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-release
+			fmt.Fprint(w, vnetListResponse)
+		})
+		defer close(release)
+		client.SetConcurrency(1)
+
+		go client.GetVirtualNetworks(context.Background(), "rg1")
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := client.GetVirtualNetworks(ctx, "rg1"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if len(started) != 0 {
+			t.Error("Request reached ARM without a free slot")
+		}
+	})
+}
+
+func TestAPIResourceType(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks", "virtualNetworks"},
+		{"/subscriptions/s/providers/Microsoft.Network/virtualNetworks", "virtualNetworks"},
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualHubs/hub1/hubRouteTables", "virtualHubs/hubRouteTables"},
+		{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/azureFirewalls/fw1", "azureFirewalls"},
+		{"/subscriptions/s/resourcegroups", "resourcegroups"},
+		{"/providers/Microsoft.Management/managementGroups/mg1/descendants", "managementGroups/descendants"},
+		{"/", "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if result := apiResourceType(tt.path); result != tt.expected {
+				t.Errorf("apiResourceType(%q) = %q, want %q", tt.path, result, tt.expected)
+			}
+		})
+	}
+}
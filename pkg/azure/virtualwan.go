@@ -0,0 +1,326 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+)
+
+// GetVirtualWANs retrieves all Virtual WANs in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
+func (c *AzureClient) GetVirtualWANs(ctx context.Context, resourceGroup string) ([]models.VirtualWAN, error) {
+	client, err := c.getVirtualWANsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var wans []models.VirtualWAN
+	var pager itemPager[armnetwork.VirtualWAN]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.VirtualWAN](c, graphVirtualWANs, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListPager(nil), func(r armnetwork.VirtualWansClientListResponse) []*armnetwork.VirtualWAN {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListByResourceGroupPager(resourceGroup, nil), func(r armnetwork.VirtualWansClientListByResourceGroupResponse) []*armnetwork.VirtualWAN {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Virtual WANs: %w", err)
+		}
+
+		for _, wan := range page {
+			if wan != nil {
+				wans = append(wans, c.extractVirtualWAN(wan))
+			}
+		}
+	}
+
+	return wans, nil
+}
+
+// GetVirtualHubs retrieves all Virtual WAN hubs in the specified resource group,
+// or across the whole subscription when resourceGroup is empty, together with their
+// VNet connections, route tables, routing intent and VPN/ExpressRoute gateways
+func (c *AzureClient) GetVirtualHubs(ctx context.Context, resourceGroup string) ([]models.VirtualHub, error) {
+	client, err := c.getVirtualHubsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var hubs []models.VirtualHub
+	var pager itemPager[armnetwork.VirtualHub]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.VirtualHub](c, graphVirtualHubs, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListPager(nil), func(r armnetwork.VirtualHubsClientListResponse) []*armnetwork.VirtualHub {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListByResourceGroupPager(resourceGroup, nil), func(r armnetwork.VirtualHubsClientListByResourceGroupResponse) []*armnetwork.VirtualHub {
+			return r.Value
+		})
+	}
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of Virtual Hubs: %w", err)
+		}
+
+		for _, hub := range page {
+			if hub != nil {
+				hubs = append(hubs, c.extractVirtualHub(hub))
+			}
+		}
+	}
+
+	if len(hubs) == 0 {
+		return hubs, nil
+	}
+
+	// Fetch each hub's connections, route tables and routing intent in parallel,
+	// bounded by the client concurrency
+	tasks := make([]func(context.Context) error, 0, 3*len(hubs))
+	for i := range hubs {
+		hub := &hubs[i]
+		tasks = append(tasks,
+			func(ctx context.Context) error {
+				connections, err := c.GetHubVNetConnections(ctx, hub.ResourceGroup, hub.Name)
+				if err != nil {
+					return err
+				}
+				hub.VNetConnections = connections
+				return nil
+			},
+			func(ctx context.Context) error {
+				routeTables, err := c.GetHubRouteTables(ctx, hub.ResourceGroup, hub.Name)
+				if err != nil {
+					return err
+				}
+				hub.RouteTables = routeTables
+				return nil
+			},
+			func(ctx context.Context) error {
+				intent, err := c.GetHubRoutingIntent(ctx, hub.ResourceGroup, hub.Name)
+				if err != nil {
+					return err
+				}
+				hub.RoutingIntent = intent
+				return nil
+			},
+		)
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	// Hub gateways are listed in the same scope as the hubs and matched by hub ID
+	gateways, err := c.listHubGateways(ctx, resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hubs {
+		hubs[i].Gateways = append(hubs[i].Gateways, gateways[strings.ToLower(hubs[i].ID)]...)
+	}
+
+	return hubs, nil
+}
+
+// GetHubVNetConnections retrieves the virtual network connections of a virtual hub
+func (c *AzureClient) GetHubVNetConnections(ctx context.Context, resourceGroup, hubName string) ([]models.HubVNetConnection, error) {
+	client, err := c.getHubVNetConnectionsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	connections := []models.HubVNetConnection{}
+	pager := newItemPager(client.NewListPager(resourceGroup, hubName, nil), func(r armnetwork.HubVirtualNetworkConnectionsClientListResponse) []*armnetwork.HubVirtualNetworkConnection {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list VNet connections for virtual hub %s: %w", hubName, err)
+		}
+
+		for _, conn := range page {
+			if conn != nil {
+				connections = append(connections, c.extractHubVNetConnection(conn))
+			}
+		}
+	}
+
+	return connections, nil
+}
+
+// GetHubRouteTables retrieves the route tables of a virtual hub
+func (c *AzureClient) GetHubRouteTables(ctx context.Context, resourceGroup, hubName string) ([]models.HubRouteTable, error) {
+	client, err := c.getHubRouteTablesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	routeTables := []models.HubRouteTable{}
+	pager := newItemPager(client.NewListPager(resourceGroup, hubName, nil), func(r armnetwork.HubRouteTablesClientListResponse) []*armnetwork.HubRouteTable {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list route tables for virtual hub %s: %w", hubName, err)
+		}
+
+		for _, table := range page {
+			if table != nil {
+				routeTables = append(routeTables, c.extractHubRouteTable(table))
+			}
+		}
+	}
+
+	return routeTables, nil
+}
+
+// GetHubRoutingIntent retrieves the routing intent of a virtual hub, or nil when
+// none is configured. A hub has at most one routing intent.
+func (c *AzureClient) GetHubRoutingIntent(ctx context.Context, resourceGroup, hubName string) (*models.HubRoutingIntent, error) {
+	client, err := c.getRoutingIntentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	pager := newItemPager(client.NewListPager(resourceGroup, hubName, nil), func(r armnetwork.RoutingIntentClientListResponse) []*armnetwork.RoutingIntent {
+		return r.Value
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list routing intent for virtual hub %s: %w", hubName, err)
+		}
+
+		for _, intent := range page {
+			if intent != nil {
+				ri := c.extractRoutingIntent(intent)
+				return &ri, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// listHubGateways lists the site-to-site VPN and ExpressRoute gateways deployed in
+// virtual hubs, keyed by lower-cased hub ID
+func (c *AzureClient) listHubGateways(ctx context.Context, resourceGroup string) (map[string][]models.HubGateway, error) {
+	gateways := make(map[string][]models.HubGateway)
+
+	vpnClient, err := c.getHubVPNGatewaysClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var pager itemPager[armnetwork.VPNGateway]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.VPNGateway](c, graphHubVPNGateways, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(vpnClient.NewListPager(nil), func(r armnetwork.VPNGatewaysClientListResponse) []*armnetwork.VPNGateway {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(vpnClient.NewListByResourceGroupPager(resourceGroup, nil), func(r armnetwork.VPNGatewaysClientListByResourceGroupResponse) []*armnetwork.VPNGateway {
+			return r.Value
+		})
+	}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of hub VPN Gateways: %w", err)
+		}
+
+		for _, gw := range page {
+			if gw == nil {
+				continue
+			}
+			gateway, hubID := c.extractHubVPNGateway(gw)
+			gateways[strings.ToLower(hubID)] = append(gateways[strings.ToLower(hubID)], gateway)
+		}
+	}
+
+	erClient, err := c.getERGatewaysClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// ExpressRoute gateways are returned in a single, unpaged response
+	var erGateways []*armnetwork.ExpressRouteGateway
+	if c.graph != nil {
+		if erGateways, err = graphList[armnetwork.ExpressRouteGateway](ctx, c, graphHubExpressRouteGateways, resourceGroup); err != nil {
+			return nil, err
+		}
+	} else if resourceGroup == "" {
+		resp, err := erClient.ListBySubscription(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ExpressRoute Gateways: %w", err)
+		}
+		erGateways = resp.Value
+	} else {
+		resp, err := erClient.ListByResourceGroup(ctx, resourceGroup, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ExpressRoute Gateways: %w", err)
+		}
+		erGateways = resp.Value
+	}
+	for _, gw := range erGateways {
+		if gw == nil {
+			continue
+		}
+		gateway, hubID := c.extractExpressRouteGateway(gw)
+		gateways[strings.ToLower(hubID)] = append(gateways[strings.ToLower(hubID)], gateway)
+	}
+
+	return gateways, nil
+}
+
+// linkHubPeerings marks the VNet peerings created by hub virtual network connections.
+// Azure peers a connected VNet with the hub's managed virtual network, named
+// "HV_<hub name>_<guid>" in a Microsoft-owned subscription, so such peerings point
+// at a VNet that is never collected.
+func linkHubPeerings(topology *models.NetworkTopology) {
+	if len(topology.VirtualHubs) == 0 {
+		return
+	}
+
+	vnets := make(map[string]*models.VirtualNetwork, len(topology.VirtualNetworks))
+	for i := range topology.VirtualNetworks {
+		vnets[strings.ToLower(topology.VirtualNetworks[i].ID)] = &topology.VirtualNetworks[i]
+	}
+
+	for _, hub := range topology.VirtualHubs {
+		managedPrefix := strings.ToLower("HV_" + hub.Name + "_")
+		for _, conn := range hub.VNetConnections {
+			vnet, ok := vnets[strings.ToLower(conn.RemoteVNetID)]
+			if !ok {
+				continue
+			}
+			for i := range vnet.Peerings {
+				peering := &vnet.Peerings[i]
+				remoteName := peering.RemoteVNetName
+				if remoteName == "" {
+					remoteName = extractResourceName(peering.RemoteVNetID)
+				}
+				if strings.HasPrefix(strings.ToLower(remoteName), managedPrefix) {
+					peering.RemoteVirtualHubID = hub.ID
+				}
+			}
+		}
+	}
+}
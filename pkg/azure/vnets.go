@@ -3,11 +3,17 @@ package azure
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
 
 	"azure-network-analyzer/pkg/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 )
 
-// GetVirtualNetworks retrieves all virtual networks in the specified resource group
+// GetVirtualNetworks retrieves all virtual networks in the specified resource group,
+// or across the whole subscription when resourceGroup is empty
 func (c *AzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup string) ([]models.VirtualNetwork, error) {
 	client, err := c.getVNetsClient()
 	if err != nil {
@@ -15,7 +21,18 @@ func (c *AzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup stri
 	}
 
 	var vnets []models.VirtualNetwork
-	pager := client.NewListPager(resourceGroup, nil)
+	var pager itemPager[armnetwork.VirtualNetwork]
+	if c.graph != nil {
+		pager = newGraphPager[armnetwork.VirtualNetwork](c, graphVirtualNetworks, resourceGroup)
+	} else if resourceGroup == "" {
+		pager = newItemPager(client.NewListAllPager(nil), func(r armnetwork.VirtualNetworksClientListAllResponse) []*armnetwork.VirtualNetwork {
+			return r.Value
+		})
+	} else {
+		pager = newItemPager(client.NewListPager(resourceGroup, nil), func(r armnetwork.VirtualNetworksClientListResponse) []*armnetwork.VirtualNetwork {
+			return r.Value
+		})
+	}
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
@@ -23,17 +40,19 @@ func (c *AzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup stri
 			return nil, fmt.Errorf("failed to get next page of VNets: %w", err)
 		}
 
-		for _, vnet := range page.Value {
+		for _, vnet := range page {
 			v := models.VirtualNetwork{
-				ID:            safeString(vnet.ID),
-				Name:          safeString(vnet.Name),
-				ResourceGroup: resourceGroup,
-				Location:      safeString(vnet.Location),
-				AddressSpace:  []string{},
-				Subnets:       []models.Subnet{},
-				Peerings:      []models.VNetPeering{},
-				DNSServers:    []string{},
-				EnableDDoS:    false,
+				ID:             safeString(vnet.ID),
+				Name:           safeString(vnet.Name),
+				ResourceGroup:  extractResourceGroup(safeString(vnet.ID)),
+				SubscriptionID: extractSubscriptionID(safeString(vnet.ID)),
+				Location:       safeString(vnet.Location),
+				Tags:           safeTags(vnet.Tags),
+				AddressSpace:   []string{},
+				Subnets:        []models.Subnet{},
+				Peerings:       []models.VNetPeering{},
+				DNSServers:     []string{},
+				EnableDDoS:     false,
 			}
 
 			// Extract address space
@@ -79,9 +98,91 @@ func (c *AzureClient) GetVirtualNetworks(ctx context.Context, resourceGroup stri
 		}
 	}
 
+	// Fetch each VNet's subnet IP usage in parallel, bounded by the client
+	// concurrency. Usage is optional, so a VNet whose usage cannot be read is
+	// kept without it.
+	var mu sync.Mutex
+	var usageErrs []error
+	tasks := make([]func(context.Context) error, len(vnets))
+	for i := range vnets {
+		vnet := &vnets[i]
+		tasks[i] = func(ctx context.Context) error {
+			err := c.collectSubnetIPUsage(ctx, vnet)
+			if err == nil || ctx.Err() != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			usageErrs = append(usageErrs, err)
+			return nil
+		}
+	}
+	if err := runBounded(ctx, c.concurrency, tasks); err != nil {
+		return nil, err
+	}
+
+	if len(usageErrs) > 0 {
+		return vnets, &partialError{resourceType: "subnet IP usage", errs: usageErrs}
+	}
 	return vnets, nil
 }
 
+// azureReservedIPs is the number of addresses Azure reserves in every subnet: the
+// network address, three for the default gateway and DNS, and the broadcast address
+const azureReservedIPs = 5
+
+// collectSubnetIPUsage fills in the IP usage of a VNet's subnets from the virtual
+// network usage API, which reports one entry per subnet
+func (c *AzureClient) collectSubnetIPUsage(ctx context.Context, vnet *models.VirtualNetwork) error {
+	if len(vnet.Subnets) == 0 {
+		return nil
+	}
+	client, err := c.getVNetsClient()
+	if err != nil {
+		return err
+	}
+
+	usages := make(map[string]*armnetwork.VirtualNetworkUsage)
+	pager := client.NewListUsagePager(vnet.ResourceGroup, vnet.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get IP usage of VNet %s: %w", vnet.Name, err)
+		}
+		for _, usage := range page.Value {
+			if usage != nil && usage.ID != nil {
+				usages[strings.ToLower(*usage.ID)] = usage
+			}
+		}
+	}
+
+	for i := range vnet.Subnets {
+		subnet := &vnet.Subnets[i]
+		if usage, ok := usages[strings.ToLower(subnet.ID)]; ok {
+			subnet.IPUsage = subnetIPUsage(subnet.AddressPrefix, usage)
+		}
+	}
+	return nil
+}
+
+// subnetIPUsage converts a usage entry, sizing the subnet from its IPv4 prefix.
+// The usage API's limit already excludes the reserved addresses, so it is only
+// used for subnets whose size cannot be read from a single prefix.
+func subnetIPUsage(addressPrefix string, usage *armnetwork.VirtualNetworkUsage) *models.SubnetIPUsage {
+	total := int64(safeFloat64(usage.Limit)) + azureReservedIPs
+	if prefix, err := netip.ParsePrefix(addressPrefix); err == nil && prefix.Addr().Is4() {
+		total = int64(1) << (32 - prefix.Bits())
+	}
+
+	u := &models.SubnetIPUsage{
+		TotalIPs:    total,
+		ReservedIPs: min(azureReservedIPs, total),
+		UsedIPs:     int64(safeFloat64(usage.CurrentValue)),
+	}
+	u.AvailableIPs = max(u.TotalIPs-u.ReservedIPs-u.UsedIPs, 0)
+	return u
+}
+
 // GetSubnets retrieves all subnets for a specific VNet
 func (c *AzureClient) GetSubnets(ctx context.Context, resourceGroup, vnetName string) ([]models.Subnet, error) {
 	client, err := c.getSubnetsClient()
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagFilter selects resources by tag. A resource matches when it carries every
+// key/value pair of the filter. Tag names are compared case-insensitively, as
+// Azure treats them; values must match exactly.
+type TagFilter map[string]string
+
+// ParseTagFilter parses "key=value" expressions into a tag filter
+func ParseTagFilter(expressions []string) (TagFilter, error) {
+	filter := make(TagFilter, len(expressions))
+	for _, expr := range expressions {
+		key, value, ok := strings.Cut(expr, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag filter %q: expected key=value", expr)
+		}
+		filter[key] = strings.TrimSpace(value)
+	}
+	return filter, nil
+}
+
+// Matches reports whether tags contain every key/value pair of the filter
+func (f TagFilter) Matches(tags map[string]string) bool {
+	for key, want := range f {
+		value, ok := TagValue(tags, key)
+		if !ok || value != want {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the filter as sorted "key=value" pairs, e.g. "env=prod, owner=ops"
+func (f TagFilter) String() string {
+	pairs := make([]string, 0, len(f))
+	for key, value := range f {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// TagValue looks up a tag by name, ignoring case
+func TagValue(tags map[string]string, key string) (string, bool) {
+	if value, ok := tags[key]; ok {
+		return value, true
+	}
+	for k, value := range tags {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// FilterByTags returns a copy of the topology that holds only the resources
+// matching the filter. Subnets and other child resources stay with their parent.
+// Network Watcher insights are regional rather than workload resources and are
+// kept as collected. An empty filter returns the topology unchanged.
+func (t *NetworkTopology) FilterByTags(filter TagFilter) *NetworkTopology {
+	if len(filter) == 0 {
+		return t
+	}
+
+	filtered := *t
+	filtered.TagFilter = filter
+	filtered.VirtualNetworks = filterTagged(t.VirtualNetworks, filter, func(r VirtualNetwork) map[string]string { return r.Tags })
+	filtered.NSGs = filterTagged(t.NSGs, filter, func(r NetworkSecurityGroup) map[string]string { return r.Tags })
+	filtered.ASGs = filterTagged(t.ASGs, filter, func(r ApplicationSecurityGroup) map[string]string { return r.Tags })
+	filtered.PrivateEndpoints = filterTagged(t.PrivateEndpoints, filter, func(r PrivateEndpoint) map[string]string { return r.Tags })
+	filtered.PrivateLinkServices = filterTagged(t.PrivateLinkServices, filter, func(r PrivateLinkService) map[string]string { return r.Tags })
+	filtered.NetworkInterfaces = filterTagged(t.NetworkInterfaces, filter, func(r NetworkInterface) map[string]string { return r.Tags })
+	filtered.PublicIPAddresses = filterTagged(t.PublicIPAddresses, filter, func(r PublicIPAddress) map[string]string { return r.Tags })
+	filtered.PrivateDNSZones = filterTagged(t.PrivateDNSZones, filter, func(r PrivateDNSZone) map[string]string { return r.Tags })
+	filtered.RouteTables = filterTagged(t.RouteTables, filter, func(r RouteTable) map[string]string { return r.Tags })
+	filtered.NATGateways = filterTagged(t.NATGateways, filter, func(r NATGateway) map[string]string { return r.Tags })
+	filtered.VPNGateways = filterTagged(t.VPNGateways, filter, func(r VPNGateway) map[string]string { return r.Tags })
+	filtered.LocalNetworkGateways = filterTagged(t.LocalNetworkGateways, filter, func(r LocalNetworkGateway) map[string]string { return r.Tags })
+	filtered.ERCircuits = filterTagged(t.ERCircuits, filter, func(r ExpressRouteCircuit) map[string]string { return r.Tags })
+	filtered.LoadBalancers = filterTagged(t.LoadBalancers, filter, func(r LoadBalancer) map[string]string { return r.Tags })
+	filtered.AppGateways = filterTagged(t.AppGateways, filter, func(r ApplicationGateway) map[string]string { return r.Tags })
+	filtered.AzureFirewalls = filterTagged(t.AzureFirewalls, filter, func(r AzureFirewall) map[string]string { return r.Tags })
+	filtered.FirewallPolicies = filterTagged(t.FirewallPolicies, filter, func(r FirewallPolicy) map[string]string { return r.Tags })
+	filtered.BastionHosts = filterTagged(t.BastionHosts, filter, func(r BastionHost) map[string]string { return r.Tags })
+	filtered.VirtualWANs = filterTagged(t.VirtualWANs, filter, func(r VirtualWAN) map[string]string { return r.Tags })
+	filtered.VirtualHubs = filterTagged(t.VirtualHubs, filter, func(r VirtualHub) map[string]string { return r.Tags })
+	return &filtered
+}
+
+// filterTagged returns the items whose tags match the filter
+func filterTagged[T any](items []T, filter TagFilter, tags func(T) map[string]string) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if filter.Matches(tags(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package models
+
+import "testing"
+
+func TestParseTagFilter(t *testing.T) {
+	filter, err := ParseTagFilter([]string{"environment=prod", " owner = network-team ", "empty="})
+	if err != nil {
+		t.Fatalf("ParseTagFilter() error = %v", err)
+	}
+	if len(filter) != 3 || filter["environment"] != "prod" || filter["owner"] != "network-team" {
+		t.Errorf("ParseTagFilter() = %v", filter)
+	}
+	if v, ok := filter["empty"]; !ok || v != "" {
+		t.Errorf("expected empty value for 'empty', got %q (present %v)", v, ok)
+	}
+	if got := filter.String(); got != "empty=, environment=prod, owner=network-team" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, expr := range []string{"environment", "=prod", ""} {
+		if _, err := ParseTagFilter([]string{expr}); err == nil {
+			t.Errorf("ParseTagFilter(%q) should fail", expr)
+		}
+	}
+}
+
+func TestTagFilterMatches(t *testing.T) {
+	filter := TagFilter{"Environment": "prod", "owner": "network-team"}
+
+	tests := []struct {
+		name string
+		tags map[string]string
+		want bool
+	}{
+		{"all tags match", map[string]string{"environment": "prod", "owner": "network-team", "cost-center": "cc-100"}, true},
+		{"key case ignored", map[string]string{"ENVIRONMENT": "prod", "Owner": "network-team"}, true},
+		{"value case matters", map[string]string{"environment": "Prod", "owner": "network-team"}, false},
+		{"missing tag", map[string]string{"environment": "prod"}, false},
+		{"untagged", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Matches(tt.tags); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+
+	if !(TagFilter{}).Matches(nil) {
+		t.Error("an empty filter should match untagged resources")
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	prod := map[string]string{"environment": "prod"}
+	dev := map[string]string{"environment": "dev"}
+	topology := &NetworkTopology{
+		SubscriptionID: "sub1",
+		VirtualNetworks: []VirtualNetwork{
+			{Name: "vnet-prod", Tags: prod, Subnets: []Subnet{{Name: "subnet-a"}}},
+			{Name: "vnet-dev", Tags: dev},
+			{Name: "vnet-untagged"},
+		},
+		NSGs:           []NetworkSecurityGroup{{Name: "nsg-prod", Tags: prod}, {Name: "nsg-dev", Tags: dev}},
+		AzureFirewalls: []AzureFirewall{{Name: "fw-untagged"}},
+		VirtualHubs:    []VirtualHub{{Name: "vhub-prod", Tags: prod}},
+		NetworkWatcher: &NetworkWatcherInsights{Watchers: []NetworkWatcher{{Name: "NetworkWatcher_eastus"}}},
+	}
+
+	filtered := topology.FilterByTags(TagFilter{"environment": "prod"})
+
+	if len(filtered.VirtualNetworks) != 1 || filtered.VirtualNetworks[0].Name != "vnet-prod" {
+		t.Fatalf("expected only vnet-prod, got %+v", filtered.VirtualNetworks)
+	}
+	if len(filtered.VirtualNetworks[0].Subnets) != 1 {
+		t.Error("subnets should stay with their VNet")
+	}
+	if len(filtered.NSGs) != 1 || filtered.NSGs[0].Name != "nsg-prod" {
+		t.Errorf("expected only nsg-prod, got %+v", filtered.NSGs)
+	}
+	if len(filtered.AzureFirewalls) != 0 {
+		t.Errorf("untagged firewall should be filtered out, got %+v", filtered.AzureFirewalls)
+	}
+	if len(filtered.VirtualHubs) != 1 {
+		t.Errorf("expected vhub-prod to be kept, got %+v", filtered.VirtualHubs)
+	}
+	if filtered.NetworkWatcher != topology.NetworkWatcher {
+		t.Error("Network Watcher insights should be kept as collected")
+	}
+	if filtered.SubscriptionID != "sub1" || filtered.TagFilter["environment"] != "prod" {
+		t.Errorf("expected scope and tag filter to be recorded, got %q / %v", filtered.SubscriptionID, filtered.TagFilter)
+	}
+
+	// The original topology is left untouched
+	if len(topology.VirtualNetworks) != 3 || topology.TagFilter != nil {
+		t.Error("FilterByTags should not modify the original topology")
+	}
+
+	if topology.FilterByTags(nil) != topology {
+		t.Error("an empty filter should return the topology unchanged")
+	}
+}
@@ -1,37 +1,55 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // NetworkTopology represents the complete network topology for a resource group
 type NetworkTopology struct {
-	SubscriptionID   string                   `json:"subscriptionId"`
-	ResourceGroup    string                   `json:"resourceGroup"`
-	VirtualNetworks  []VirtualNetwork         `json:"virtualNetworks"`
-	NSGs             []NetworkSecurityGroup   `json:"networkSecurityGroups"`
-	PrivateEndpoints []PrivateEndpoint        `json:"privateEndpoints"`
-	PrivateDNSZones  []PrivateDNSZone         `json:"privateDnsZones"`
-	RouteTables      []RouteTable             `json:"routeTables"`
-	NATGateways      []NATGateway             `json:"natGateways"`
-	VPNGateways      []VPNGateway             `json:"vpnGateways"`
-	ERCircuits       []ExpressRouteCircuit    `json:"expressRouteCircuits"`
-	LoadBalancers    []LoadBalancer           `json:"loadBalancers"`
-	AppGateways      []ApplicationGateway     `json:"applicationGateways"`
-	AzureFirewalls   []AzureFirewall          `json:"azureFirewalls"`
-	NetworkWatcher   *NetworkWatcherInsights  `json:"networkWatcher,omitempty"`
-	Timestamp        time.Time                `json:"timestamp"`
+	SubscriptionID       string                     `json:"subscriptionId"`
+	ResourceGroup        string                     `json:"resourceGroup"`
+	VirtualNetworks      []VirtualNetwork           `json:"virtualNetworks"`
+	NSGs                 []NetworkSecurityGroup     `json:"networkSecurityGroups"`
+	ASGs                 []ApplicationSecurityGroup `json:"applicationSecurityGroups"`
+	PrivateEndpoints     []PrivateEndpoint          `json:"privateEndpoints"`
+	PrivateLinkServices  []PrivateLinkService       `json:"privateLinkServices"`
+	NetworkInterfaces    []NetworkInterface         `json:"networkInterfaces"`
+	PublicIPAddresses    []PublicIPAddress          `json:"publicIpAddresses"`
+	PrivateDNSZones      []PrivateDNSZone           `json:"privateDnsZones"`
+	RouteTables          []RouteTable               `json:"routeTables"`
+	NATGateways          []NATGateway               `json:"natGateways"`
+	VPNGateways          []VPNGateway               `json:"vpnGateways"`
+	LocalNetworkGateways []LocalNetworkGateway      `json:"localNetworkGateways"`
+	ERCircuits           []ExpressRouteCircuit      `json:"expressRouteCircuits"`
+	LoadBalancers        []LoadBalancer             `json:"loadBalancers"`
+	AppGateways          []ApplicationGateway       `json:"applicationGateways"`
+	AzureFirewalls       []AzureFirewall            `json:"azureFirewalls"`
+	FirewallPolicies     []FirewallPolicy           `json:"firewallPolicies"`
+	BastionHosts         []BastionHost              `json:"bastionHosts"`
+	VirtualWANs          []VirtualWAN               `json:"virtualWans"`
+	VirtualHubs          []VirtualHub               `json:"virtualHubs"`
+	NetworkWatcher       *NetworkWatcherInsights    `json:"networkWatcher,omitempty"`
+	CollectionErrors     []CollectionError          `json:"collectionErrors,omitempty"`  // resource types that could not be collected
+	IntegrityWarnings    []IntegrityWarning         `json:"integrityWarnings,omitempty"` // references that disagree after collection
+	TagFilter            map[string]string          `json:"tagFilter,omitempty"`         // tags the topology was filtered by
+	CachedAt             *time.Time                 `json:"cachedAt,omitempty"`          // collection time of the oldest cached response used
+	Timestamp            time.Time                  `json:"timestamp"`
 }
 
 // VirtualNetwork represents an Azure Virtual Network
 type VirtualNetwork struct {
-	ID            string        `json:"id"`
-	Name          string        `json:"name"`
-	ResourceGroup string        `json:"resourceGroup"`
-	Location      string        `json:"location"`
-	AddressSpace  []string      `json:"addressSpace"`
-	Subnets       []Subnet      `json:"subnets"`
-	Peerings      []VNetPeering `json:"peerings"`
-	DNSServers    []string      `json:"dnsServers"`
-	EnableDDoS    bool          `json:"enableDdosProtection"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Location       string            `json:"location"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	AddressSpace   []string          `json:"addressSpace"`
+	Subnets        []Subnet          `json:"subnets"`
+	Peerings       []VNetPeering     `json:"peerings"`
+	DNSServers     []string          `json:"dnsServers"`
+	EnableDDoS     bool              `json:"enableDdosProtection"`
 }
 
 // Subnet represents a subnet within a virtual network
@@ -43,32 +61,95 @@ type Subnet struct {
 	RouteTable           *string  `json:"routeTable,omitempty"`           // Route table ID if associated
 	NATGateway           *string  `json:"natGateway,omitempty"`           // NAT gateway ID if associated
 	PrivateEndpoints     []string `json:"privateEndpoints"`               // List of private endpoint IDs
+	NetworkInterfaces    []string `json:"networkInterfaces"`              // List of NIC IDs with an IP in the subnet
 	ServiceEndpoints     []string `json:"serviceEndpoints"`
 	Delegations          []string `json:"delegations"`
+
+	IPUsage *SubnetIPUsage `json:"ipUsage,omitempty"` // nil when usage was not collected
+}
+
+// SubnetIPUsage is how many of a subnet's IPv4 addresses are in use, from the
+// virtual network usage API
+type SubnetIPUsage struct {
+	TotalIPs     int64 `json:"totalIps"`     // Addresses in the subnet prefix
+	ReservedIPs  int64 `json:"reservedIps"`  // Addresses Azure reserves in every subnet
+	UsedIPs      int64 `json:"usedIps"`      // Addresses assigned to NICs, endpoints and services
+	AvailableIPs int64 `json:"availableIps"` // Addresses that can still be assigned
 }
 
 // NetworkSecurityGroup represents an Azure NSG
 type NetworkSecurityGroup struct {
-	ID            string          `json:"id"`
-	Name          string          `json:"name"`
-	ResourceGroup string          `json:"resourceGroup"`
-	Location      string          `json:"location"`
-	SecurityRules []SecurityRule  `json:"securityRules"`
-	Associations  NSGAssociations `json:"associations"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Location       string            `json:"location"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	SecurityRules  []SecurityRule    `json:"securityRules"`
+	Associations   NSGAssociations   `json:"associations"`
 }
 
 // SecurityRule represents a security rule within an NSG
 type SecurityRule struct {
-	Name                     string `json:"name"`
-	Priority                 int32  `json:"priority"`
-	Direction                string `json:"direction"` // Inbound/Outbound
-	Access                   string `json:"access"`    // Allow/Deny
-	Protocol                 string `json:"protocol"`
-	SourceAddressPrefix      string `json:"sourceAddressPrefix"`
-	SourcePortRange          string `json:"sourcePortRange"`
-	DestinationAddressPrefix string `json:"destinationAddressPrefix"`
-	DestinationPortRange     string `json:"destinationPortRange"`
-	Description              string `json:"description"`
+	Name                       string   `json:"name"`
+	Priority                   int32    `json:"priority"`
+	Direction                  string   `json:"direction"` // Inbound/Outbound
+	Access                     string   `json:"access"`    // Allow/Deny
+	Protocol                   string   `json:"protocol"`
+	SourceAddressPrefix        string   `json:"sourceAddressPrefix"`
+	SourceAddressPrefixes      []string `json:"sourceAddressPrefixes,omitempty"`
+	SourcePortRange            string   `json:"sourcePortRange"`
+	SourcePortRanges           []string `json:"sourcePortRanges,omitempty"`
+	SourceASGs                 []string `json:"sourceApplicationSecurityGroups,omitempty"` // ASG IDs
+	DestinationAddressPrefix   string   `json:"destinationAddressPrefix"`
+	DestinationAddressPrefixes []string `json:"destinationAddressPrefixes,omitempty"`
+	DestinationPortRange       string   `json:"destinationPortRange"`
+	DestinationPortRanges      []string `json:"destinationPortRanges,omitempty"`
+	DestinationASGs            []string `json:"destinationApplicationSecurityGroups,omitempty"` // ASG IDs
+	Description                string   `json:"description"`
+	IsDefault                  bool     `json:"isDefault"` // Built-in rule that every NSG has
+}
+
+// Azure sets either the singular or the plural form of a rule's prefixes and
+// port ranges. The accessors below return both combined.
+
+// SourcePrefixes returns every source address prefix of the rule
+func (r SecurityRule) SourcePrefixes() []string {
+	return singularAndPlural(r.SourceAddressPrefix, r.SourceAddressPrefixes)
+}
+
+// SourcePorts returns every source port range of the rule
+func (r SecurityRule) SourcePorts() []string {
+	return singularAndPlural(r.SourcePortRange, r.SourcePortRanges)
+}
+
+// DestinationPrefixes returns every destination address prefix of the rule
+func (r SecurityRule) DestinationPrefixes() []string {
+	return singularAndPlural(r.DestinationAddressPrefix, r.DestinationAddressPrefixes)
+}
+
+// DestinationPorts returns every destination port range of the rule
+func (r SecurityRule) DestinationPorts() []string {
+	return singularAndPlural(r.DestinationPortRange, r.DestinationPortRanges)
+}
+
+func singularAndPlural(single string, list []string) []string {
+	var values []string
+	if single != "" {
+		values = append(values, single)
+	}
+	return append(values, list...)
+}
+
+// ApplicationSecurityGroup represents an Azure application security group. Members
+// are NIC IP configurations, see NICIPConfiguration.ApplicationSecurityGroups.
+type ApplicationSecurityGroup struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Location       string            `json:"location"`
+	Tags           map[string]string `json:"tags,omitempty"`
 }
 
 // NSGAssociations tracks what resources are associated with an NSG
@@ -88,47 +169,173 @@ type VNetPeering struct {
 	AllowForwardedTraffic bool   `json:"allowForwardedTraffic"`
 	AllowGatewayTransit   bool   `json:"allowGatewayTransit"`
 	UseRemoteGateways     bool   `json:"useRemoteGateways"`
+	// RemoteVirtualHubID is set when the remote VNet is the managed VNet of a Virtual
+	// WAN hub, i.e. the peering was created by a hub virtual network connection
+	RemoteVirtualHubID string `json:"remoteVirtualHubId,omitempty"`
 }
 
 // PrivateEndpoint represents an Azure Private Endpoint
 type PrivateEndpoint struct {
-	ID                   string   `json:"id"`
-	Name                 string   `json:"name"`
-	ResourceGroup        string   `json:"resourceGroup"`
-	Location             string   `json:"location"`
-	SubnetID             string   `json:"subnetId"`
-	PrivateIPAddress     string   `json:"privateIpAddress"`
-	PrivateLinkServiceID string   `json:"privateLinkServiceId"`
-	ConnectionState      string   `json:"connectionState"`
-	GroupIDs             []string `json:"groupIds"`
+	ID                   string                     `json:"id"`
+	Name                 string                     `json:"name"`
+	ResourceGroup        string                     `json:"resourceGroup"`
+	SubscriptionID       string                     `json:"subscriptionId"`
+	Location             string                     `json:"location"`
+	Tags                 map[string]string          `json:"tags,omitempty"`
+	SubnetID             string                     `json:"subnetId"`
+	NetworkInterfaceID   string                     `json:"networkInterfaceId"`
+	PrivateIPAddress     string                     `json:"privateIpAddress"` // First of PrivateIPAddresses
+	PrivateIPAddresses   []string                   `json:"privateIpAddresses"`
+	DNSConfigs           []PrivateEndpointDNSConfig `json:"dnsConfigs"`
+	PrivateLinkServiceID string                     `json:"privateLinkServiceId"`
+	ConnectionState      string                     `json:"connectionState"`
+	GroupIDs             []string                   `json:"groupIds"`
+}
+
+// PrivateLinkService represents the provider side of Azure Private Link: a service
+// published behind the frontend of a Standard internal load balancer that consumers
+// in other VNets or tenants reach through their own private endpoints
+type PrivateLinkService struct {
+	ID                      string                         `json:"id"`
+	Name                    string                         `json:"name"`
+	ResourceGroup           string                         `json:"resourceGroup"`
+	SubscriptionID          string                         `json:"subscriptionId"`
+	Location                string                         `json:"location"`
+	Tags                    map[string]string              `json:"tags,omitempty"`
+	Alias                   string                         `json:"alias"` // Name consumers use to request a connection
+	LoadBalancerID          string                         `json:"loadBalancerId"`
+	LoadBalancerFrontendIDs []string                       `json:"loadBalancerFrontendIds"`
+	IPConfigurations        []PrivateLinkServiceIPConfig   `json:"ipConfigurations"` // NAT IPs that source consumer traffic
+	Visibility              []string                       `json:"visibility"`       // Subscriptions that can find the service by alias, "*" for all
+	AutoApproval            []string                       `json:"autoApproval"`     // Subscriptions whose connections are approved automatically
+	FQDNs                   []string                       `json:"fqdns"`
+	EnableProxyProtocol     bool                           `json:"enableProxyProtocol"`
+	Connections             []PrivateLinkServiceConnection `json:"connections"`
+	ProvisioningState       string                         `json:"provisioningState"`
+}
+
+// PrivateLinkServiceIPConfig is a NAT IP configuration of a private link service
+type PrivateLinkServiceIPConfig struct {
+	Name             string `json:"name"`
+	PrivateIPAddress string `json:"privateIpAddress"`
+	AllocationMethod string `json:"allocationMethod"` // Static, Dynamic
+	SubnetID         string `json:"subnetId"`
+	Primary          bool   `json:"primary"`
+}
+
+// PrivateLinkServiceConnection is a consumer's private endpoint connected to a private link service
+type PrivateLinkServiceConnection struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	PrivateEndpointID string `json:"privateEndpointId"` // Usually in the consumer's subscription
+	Status            string `json:"status"`            // Approved, Pending, Rejected, Disconnected
+	Description       string `json:"description"`
+}
+
+// NetworkInterface represents an Azure network interface
+type NetworkInterface struct {
+	ID                          string               `json:"id"`
+	Name                        string               `json:"name"`
+	ResourceGroup               string               `json:"resourceGroup"`
+	SubscriptionID              string               `json:"subscriptionId"`
+	Location                    string               `json:"location"`
+	Tags                        map[string]string    `json:"tags,omitempty"`
+	MACAddress                  string               `json:"macAddress"`
+	PrivateIPAddresses          []string             `json:"privateIpAddresses"`
+	SubnetID                    string               `json:"subnetId"`                    // Subnet of the primary IP configuration
+	NetworkSecurityGroupID      string               `json:"networkSecurityGroupId"`      // NIC-level NSG, if any
+	VirtualMachineID            string               `json:"virtualMachineId,omitempty"`  // Attached VM, if any
+	PrivateEndpointID           string               `json:"privateEndpointId,omitempty"` // Owning private endpoint, if any
+	EnableIPForwarding          bool                 `json:"enableIpForwarding"`
+	EnableAcceleratedNetworking bool                 `json:"enableAcceleratedNetworking"`
+	IPConfigurations            []NICIPConfiguration `json:"ipConfigurations"`
+}
+
+// PublicIPAddress represents an Azure public IP address resource
+type PublicIPAddress struct {
+	ID                   string            `json:"id"`
+	Name                 string            `json:"name"`
+	ResourceGroup        string            `json:"resourceGroup"`
+	SubscriptionID       string            `json:"subscriptionId"`
+	Location             string            `json:"location"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+	IPAddress            string            `json:"ipAddress"` // Empty for dynamic IPs that are not allocated
+	SKU                  string            `json:"sku"`       // Basic, Standard
+	Tier                 string            `json:"tier"`      // Regional, Global
+	AllocationMethod     string            `json:"allocationMethod"`
+	IPVersion            string            `json:"ipVersion"`
+	Zones                []string          `json:"zones"`
+	DNSLabel             string            `json:"dnsLabel,omitempty"`
+	FQDN                 string            `json:"fqdn,omitempty"`
+	DDoSProtection       string            `json:"ddosProtection,omitempty"`       // Basic or Standard coverage
+	IPConfigurationID    string            `json:"ipConfigurationId,omitempty"`    // IP configuration using the address, if any
+	AssociatedResourceID string            `json:"associatedResourceId,omitempty"` // Load balancer, NIC, gateway, NAT gateway, ... using the address
+}
+
+// NICIPConfiguration represents an IP configuration of a network interface
+type NICIPConfiguration struct {
+	ID                        string   `json:"id"`
+	Name                      string   `json:"name"`
+	Primary                   bool     `json:"primary"`
+	PrivateIPAddress          string   `json:"privateIpAddress"`
+	PrivateIPAllocationMethod string   `json:"privateIpAllocationMethod"`
+	SubnetID                  string   `json:"subnetId"`
+	PublicIPAddressID         string   `json:"publicIpAddressId,omitempty"`
+	PublicIPAddress           string   `json:"publicIpAddress,omitempty"` // Resolved from PublicIPAddressID
+	LoadBalancerBackendPools  []string `json:"loadBalancerBackendPools"`  // Backend address pool IDs
+	AppGatewayBackendPools    []string `json:"appGatewayBackendPools"`    // Backend address pool IDs
+	ApplicationSecurityGroups []string `json:"applicationSecurityGroups"` // ASG IDs
+}
+
+// PrivateEndpointDNSConfig is an FQDN served by a private endpoint and the
+// private IPs it should resolve to
+type PrivateEndpointDNSConfig struct {
+	FQDN        string   `json:"fqdn"`
+	IPAddresses []string `json:"ipAddresses"`
 }
 
 // PrivateDNSZone represents an Azure Private DNS Zone
 type PrivateDNSZone struct {
-	ID            string     `json:"id"`
-	Name          string     `json:"name"`
-	ResourceGroup string     `json:"resourceGroup"`
-	VNetLinks     []VNetLink `json:"vnetLinks"`
-	RecordSets    int        `json:"recordSets"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	VNetLinks      []VNetLink        `json:"vnetLinks"`
+	RecordSets     int               `json:"recordSets"`
+	ARecords       []DNSARecord      `json:"aRecords"`
 }
 
 // VNetLink represents a link between a Private DNS Zone and a VNet
 type VNetLink struct {
 	ID                  string `json:"id"`
+	Name                string `json:"name"`
 	VNetID              string `json:"vnetId"`
 	VNetName            string `json:"vnetName"`
 	RegistrationEnabled bool   `json:"registrationEnabled"`
+	LinkState           string `json:"linkState"` // Completed or InProgress
+}
+
+// DNSARecord represents an A record set in a private DNS zone
+type DNSARecord struct {
+	Name           string   `json:"name"` // Relative name, e.g. "sql-server-prod"
+	FQDN           string   `json:"fqdn"`
+	IPAddresses    []string `json:"ipAddresses"`
+	TTL            int64    `json:"ttl"`
+	AutoRegistered bool     `json:"autoRegistered"` // Created by VNet auto-registration
 }
 
 // RouteTable represents an Azure Route Table
 type RouteTable struct {
-	ID                          string   `json:"id"`
-	Name                        string   `json:"name"`
-	ResourceGroup               string   `json:"resourceGroup"`
-	Location                    string   `json:"location"`
-	Routes                      []Route  `json:"routes"`
-	DisableBGPRoutePropagation bool     `json:"disableBgpRoutePropagation"`
-	AssociatedSubnets           []string `json:"associatedSubnets"`
+	ID                         string            `json:"id"`
+	Name                       string            `json:"name"`
+	ResourceGroup              string            `json:"resourceGroup"`
+	SubscriptionID             string            `json:"subscriptionId"`
+	Location                   string            `json:"location"`
+	Tags                       map[string]string `json:"tags,omitempty"`
+	Routes                     []Route           `json:"routes"`
+	DisableBGPRoutePropagation bool              `json:"disableBgpRoutePropagation"`
+	AssociatedSubnets          []string          `json:"associatedSubnets"`
 }
 
 // Route represents a route within a route table
@@ -141,28 +348,36 @@ type Route struct {
 
 // NATGateway represents an Azure NAT Gateway
 type NATGateway struct {
-	ID                 string   `json:"id"`
-	Name               string   `json:"name"`
-	ResourceGroup      string   `json:"resourceGroup"`
-	Location           string   `json:"location"`
-	PublicIPAddresses  []string `json:"publicIpAddresses"`
-	IdleTimeoutMinutes int32    `json:"idleTimeoutMinutes"`
-	AssociatedSubnets  []string `json:"associatedSubnets"`
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	ResourceGroup      string            `json:"resourceGroup"`
+	SubscriptionID     string            `json:"subscriptionId"`
+	Location           string            `json:"location"`
+	Tags               map[string]string `json:"tags,omitempty"`
+	SKU                string            `json:"sku"` // Standard, StandardV2
+	Zones              []string          `json:"zones"`
+	PublicIPAddresses  []string          `json:"publicIpAddresses"`   // Public IP resource IDs
+	PublicIPPrefixes   []string          `json:"publicIpPrefixes"`    // Public IP prefix resource IDs
+	PublicIPs          []string          `json:"publicIps,omitempty"` // Addresses resolved from PublicIPAddresses
+	IdleTimeoutMinutes int32             `json:"idleTimeoutMinutes"`
+	AssociatedSubnets  []string          `json:"associatedSubnets"`
 }
 
 // VPNGateway represents an Azure VPN Gateway
 type VPNGateway struct {
-	ID           string          `json:"id"`
-	Name         string          `json:"name"`
-	ResourceGroup string          `json:"resourceGroup"`
-	Location     string          `json:"location"`
-	VNetID       string          `json:"vnetId"`
-	GatewayType  string          `json:"gatewayType"` // Vpn or ExpressRoute
-	VpnType      string          `json:"vpnType"`     // RouteBased or PolicyBased
-	SKU          string          `json:"sku"`
-	ActiveActive bool            `json:"activeActive"`
-	BGPSettings  *BGPSettings    `json:"bgpSettings,omitempty"`
-	Connections  []VPNConnection `json:"connections"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Location       string            `json:"location"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	VNetID         string            `json:"vnetId"`
+	GatewayType    string            `json:"gatewayType"` // Vpn or ExpressRoute
+	VpnType        string            `json:"vpnType"`     // RouteBased or PolicyBased
+	SKU            string            `json:"sku"`
+	ActiveActive   bool              `json:"activeActive"`
+	BGPSettings    *BGPSettings      `json:"bgpSettings,omitempty"`
+	Connections    []VPNConnection   `json:"connections"`
 }
 
 // BGPSettings represents BGP configuration for a gateway
@@ -181,6 +396,25 @@ type VPNConnection struct {
 	SharedKey        bool   `json:"sharedKey"` // Whether a shared key is configured
 	EnableBGP        bool   `json:"enableBgp"`
 	RemoteEntityID   string `json:"remoteEntityId"`
+	CircuitID        string `json:"circuitId,omitempty"` // ExpressRoute circuit of an ExpressRoute connection
+	FastPath         bool   `json:"fastPath"`            // ExpressRoute traffic bypasses the gateway
+}
+
+// LocalNetworkGateway represents an on-premises VPN device and the address space
+// behind it, as configured for site-to-site connections
+type LocalNetworkGateway struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	ResourceGroup     string            `json:"resourceGroup"`
+	SubscriptionID    string            `json:"subscriptionId"`
+	Location          string            `json:"location"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	GatewayIPAddress  string            `json:"gatewayIpAddress"`
+	FQDN              string            `json:"fqdn"`            // Used instead of GatewayIPAddress for devices without a static IP
+	AddressPrefixes   []string          `json:"addressPrefixes"` // On-premises address space
+	BGPSettings       *BGPSettings      `json:"bgpSettings,omitempty"`
+	Connections       []string          `json:"connections"` // IDs of the VPN connections to this gateway
+	ProvisioningState string            `json:"provisioningState"`
 }
 
 // ExpressRouteCircuit represents an Azure ExpressRoute Circuit
@@ -188,27 +422,68 @@ type ExpressRouteCircuit struct {
 	ID                       string            `json:"id"`
 	Name                     string            `json:"name"`
 	ResourceGroup            string            `json:"resourceGroup"`
+	SubscriptionID           string            `json:"subscriptionId"`
 	Location                 string            `json:"location"`
+	Tags                     map[string]string `json:"tags,omitempty"`
 	ServiceProviderName      string            `json:"serviceProviderName"`
 	PeeringLocation          string            `json:"peeringLocation"`
 	BandwidthInMbps          int32             `json:"bandwidthInMbps"`
 	SKUTier                  string            `json:"skuTier"`
 	SKUFamily                string            `json:"skuFamily"`
 	CircuitProvisioningState string            `json:"circuitProvisioningState"`
+	GlobalReachEnabled       bool              `json:"globalReachEnabled"`
 	Peerings                 []ERPeering       `json:"peerings"`
 	Authorizations           []ERAuthorization `json:"authorizations"`
+	Connections              []string          `json:"connections"`     // IDs of the gateway connections using the circuit
+	Stats                    *ERCircuitStats   `json:"stats,omitempty"` // Traffic counters, when the circuit reports them
+}
+
+// ERCircuitStats holds the bytes carried by an ExpressRoute circuit's primary and
+// secondary links
+type ERCircuitStats struct {
+	PrimaryBytesIn    int64 `json:"primaryBytesIn"`
+	PrimaryBytesOut   int64 `json:"primaryBytesOut"`
+	SecondaryBytesIn  int64 `json:"secondaryBytesIn"`
+	SecondaryBytesOut int64 `json:"secondaryBytesOut"`
 }
 
 // ERPeering represents an ExpressRoute peering
 type ERPeering struct {
-	Name                       string `json:"name"`
-	PeeringType                string `json:"peeringType"` // AzurePrivatePeering, AzurePublicPeering, MicrosoftPeering
-	State                      string `json:"state"`
-	AzureASN                   int32  `json:"azureAsn"`
-	PeerASN                    int64  `json:"peerAsn"`
-	PrimaryPeerAddressPrefix   string `json:"primaryPeerAddressPrefix"`
-	SecondaryPeerAddressPrefix string `json:"secondaryPeerAddressPrefix"`
-	VlanID                     int32  `json:"vlanId"`
+	Name                       string                    `json:"name"`
+	PeeringType                string                    `json:"peeringType"` // AzurePrivatePeering, AzurePublicPeering, MicrosoftPeering
+	State                      string                    `json:"state"`
+	AzureASN                   int32                     `json:"azureAsn"`
+	PeerASN                    int64                     `json:"peerAsn"`
+	PrimaryPeerAddressPrefix   string                    `json:"primaryPeerAddressPrefix"`
+	SecondaryPeerAddressPrefix string                    `json:"secondaryPeerAddressPrefix"`
+	VlanID                     int32                     `json:"vlanId"`
+	RouteFilterID              string                    `json:"routeFilterId,omitempty"` // Microsoft peering route filter
+	RouteFilter                *ERRouteFilter            `json:"routeFilter,omitempty"`
+	GlobalReachConnections     []ERGlobalReachConnection `json:"globalReachConnections,omitempty"`
+}
+
+// ERRouteFilter selects the Microsoft services advertised over Microsoft peering,
+// by BGP community
+type ERRouteFilter struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Rules []RouteFilterRule `json:"rules"`
+}
+
+// RouteFilterRule allows or denies a set of BGP communities
+type RouteFilterRule struct {
+	Name        string   `json:"name"`
+	Access      string   `json:"access"` // Allow or Deny
+	Communities []string `json:"communities"`
+}
+
+// ERGlobalReachConnection links the private peering of one ExpressRoute circuit to
+// another circuit's, so on-premises sites can reach each other through Azure
+type ERGlobalReachConnection struct {
+	Name          string `json:"name"`
+	PeerCircuitID string `json:"peerCircuitId"`
+	AddressPrefix string `json:"addressPrefix"`
+	Status        string `json:"status"`
 }
 
 // ERAuthorization represents an ExpressRoute authorization
@@ -223,7 +498,9 @@ type LoadBalancer struct {
 	ID                  string               `json:"id"`
 	Name                string               `json:"name"`
 	ResourceGroup       string               `json:"resourceGroup"`
+	SubscriptionID      string               `json:"subscriptionId"`
 	Location            string               `json:"location"`
+	Tags                map[string]string    `json:"tags,omitempty"`
 	SKU                 string               `json:"sku"`
 	Type                string               `json:"type"` // Public or Internal
 	FrontendIPConfigs   []FrontendIPConfig   `json:"frontendIpConfigs"`
@@ -238,13 +515,15 @@ type FrontendIPConfig struct {
 	Name              string `json:"name"`
 	PrivateIPAddress  string `json:"privateIpAddress"`
 	PublicIPAddressID string `json:"publicIpAddressId"`
+	PublicIPAddress   string `json:"publicIpAddress,omitempty"` // Resolved from PublicIPAddressID
 	SubnetID          string `json:"subnetId"`
 }
 
 // BackendAddressPool represents a backend address pool for a load balancer
 type BackendAddressPool struct {
-	Name             string   `json:"name"`
-	BackendIPConfigs []string `json:"backendIpConfigs"` // NIC IDs
+	Name              string   `json:"name"`
+	BackendIPConfigs  []string `json:"backendIpConfigs"`  // NIC IP configuration IDs
+	NetworkInterfaces []string `json:"networkInterfaces"` // NIC IDs of the backend IP configurations
 }
 
 // LoadBalancingRule represents a load balancing rule
@@ -279,23 +558,25 @@ type InboundNATRule struct {
 
 // ApplicationGateway represents an Azure Application Gateway
 type ApplicationGateway struct {
-	ID                  string                      `json:"id"`
-	Name                string                      `json:"name"`
-	ResourceGroup       string                      `json:"resourceGroup"`
-	Location            string                      `json:"location"`
-	SKU                 string                      `json:"sku"`
-	Tier                string                      `json:"tier"`
-	Capacity            int32                       `json:"capacity"`
-	SubnetID            string                      `json:"subnetId"`
-	FrontendIPConfigs   []AppGWFrontendIPConfig     `json:"frontendIpConfigs"`
-	FrontendPorts       []AppGWFrontendPort         `json:"frontendPorts"`
-	BackendAddressPools []AppGWBackendAddressPool   `json:"backendAddressPools"`
-	BackendHTTPSettings []AppGWBackendHTTPSettings  `json:"backendHttpSettings"`
-	HTTPListeners       []AppGWHTTPListener         `json:"httpListeners"`
-	RequestRoutingRules []AppGWRequestRoutingRule   `json:"requestRoutingRules"`
-	Probes              []AppGWProbe                `json:"probes"`
-	WAFEnabled          bool                        `json:"wafEnabled"`
-	WAFMode             string                      `json:"wafMode"`
+	ID                  string                     `json:"id"`
+	Name                string                     `json:"name"`
+	ResourceGroup       string                     `json:"resourceGroup"`
+	SubscriptionID      string                     `json:"subscriptionId"`
+	Location            string                     `json:"location"`
+	Tags                map[string]string          `json:"tags,omitempty"`
+	SKU                 string                     `json:"sku"`
+	Tier                string                     `json:"tier"`
+	Capacity            int32                      `json:"capacity"`
+	SubnetID            string                     `json:"subnetId"`
+	FrontendIPConfigs   []AppGWFrontendIPConfig    `json:"frontendIpConfigs"`
+	FrontendPorts       []AppGWFrontendPort        `json:"frontendPorts"`
+	BackendAddressPools []AppGWBackendAddressPool  `json:"backendAddressPools"`
+	BackendHTTPSettings []AppGWBackendHTTPSettings `json:"backendHttpSettings"`
+	HTTPListeners       []AppGWHTTPListener        `json:"httpListeners"`
+	RequestRoutingRules []AppGWRequestRoutingRule  `json:"requestRoutingRules"`
+	Probes              []AppGWProbe               `json:"probes"`
+	WAFEnabled          bool                       `json:"wafEnabled"`
+	WAFMode             string                     `json:"wafMode"`
 }
 
 // AppGWFrontendIPConfig represents a frontend IP configuration for an Application Gateway
@@ -303,6 +584,7 @@ type AppGWFrontendIPConfig struct {
 	Name              string `json:"name"`
 	PrivateIPAddress  string `json:"privateIpAddress"`
 	PublicIPAddressID string `json:"publicIpAddressId"`
+	PublicIPAddress   string `json:"publicIpAddress,omitempty"` // Resolved from PublicIPAddressID
 }
 
 // AppGWFrontendPort represents a frontend port for an Application Gateway
@@ -359,48 +641,312 @@ type AppGWProbe struct {
 
 // AzureFirewall represents an Azure Firewall
 type AzureFirewall struct {
-	ID                string   `json:"id"`
-	Name              string   `json:"name"`
-	ResourceGroup     string   `json:"resourceGroup"`
-	Location          string   `json:"location"`
-	SKU               string   `json:"sku"` // Standard, Premium, Basic
-	SubnetID          string   `json:"subnetId"`
-	PrivateIPAddress  string   `json:"privateIpAddress"`
-	PublicIPAddresses []string `json:"publicIpAddresses"`
-	FirewallPolicyID  string   `json:"firewallPolicyId,omitempty"`
-	ThreatIntelMode   string   `json:"threatIntelMode"`
-	DNSProxyEnabled   bool     `json:"dnsProxyEnabled"`
-	ProvisioningState string   `json:"provisioningState"`
-}
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	ResourceGroup     string            `json:"resourceGroup"`
+	SubscriptionID    string            `json:"subscriptionId"`
+	Location          string            `json:"location"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	SKU               string            `json:"sku"` // Standard, Premium, Basic
+	SubnetID          string            `json:"subnetId"`
+	PrivateIPAddress  string            `json:"privateIpAddress"`
+	PublicIPAddresses []string          `json:"publicIpAddresses"`   // Public IP resource IDs
+	PublicIPs         []string          `json:"publicIps,omitempty"` // Addresses resolved from PublicIPAddresses
+	FirewallPolicyID  string            `json:"firewallPolicyId,omitempty"`
+	VirtualHubID      string            `json:"virtualHubId,omitempty"` // Set for secured Virtual WAN hubs, which have no SubnetID
+	ThreatIntelMode   string            `json:"threatIntelMode"`
+	DNSProxyEnabled   bool              `json:"dnsProxyEnabled"`
+	ProvisioningState string            `json:"provisioningState"`
+}
+
+// FirewallPolicy represents an Azure Firewall Policy. Rule collection groups of the
+// base policy, if any, are inherited and evaluated before the policy's own groups.
+type FirewallPolicy struct {
+	ID                   string                        `json:"id"`
+	Name                 string                        `json:"name"`
+	ResourceGroup        string                        `json:"resourceGroup"`
+	SubscriptionID       string                        `json:"subscriptionId"`
+	Location             string                        `json:"location"`
+	Tags                 map[string]string             `json:"tags,omitempty"`
+	SKU                  string                        `json:"sku"` // Basic, Standard, Premium
+	ThreatIntelMode      string                        `json:"threatIntelMode"`
+	DNSProxyEnabled      bool                          `json:"dnsProxyEnabled"`
+	BasePolicyID         string                        `json:"basePolicyId,omitempty"` // Parent policy whose rules are inherited
+	ChildPolicies        []string                      `json:"childPolicies"`          // IDs of policies inheriting from this one
+	Firewalls            []string                      `json:"firewalls"`              // IDs of firewalls using this policy
+	RuleCollectionGroups []FirewallRuleCollectionGroup `json:"ruleCollectionGroups"`
+}
+
+// FirewallRuleCollectionGroup represents a rule collection group of a firewall policy
+type FirewallRuleCollectionGroup struct {
+	ID              string                   `json:"id"`
+	Name            string                   `json:"name"`
+	Priority        int32                    `json:"priority"`
+	RuleCollections []FirewallRuleCollection `json:"ruleCollections"`
+}
+
+// FirewallRuleCollection represents a filter or NAT rule collection
+type FirewallRuleCollection struct {
+	Name           string         `json:"name"`
+	Priority       int32          `json:"priority"`
+	CollectionType string         `json:"collectionType"` // Filter, NAT
+	Action         string         `json:"action"`         // Allow, Deny, DNAT
+	Rules          []FirewallRule `json:"rules"`
+}
+
+// FirewallRule represents a network, application or NAT rule of a firewall policy.
+// Only the fields that apply to the rule type are set.
+type FirewallRule struct {
+	Name                 string   `json:"name"`
+	Description          string   `json:"description,omitempty"`
+	RuleType             string   `json:"ruleType"` // NetworkRule, ApplicationRule, NatRule
+	SourceAddresses      []string `json:"sourceAddresses"`
+	SourceIPGroups       []string `json:"sourceIpGroups,omitempty"`
+	DestinationAddresses []string `json:"destinationAddresses"`
+	DestinationIPGroups  []string `json:"destinationIpGroups,omitempty"`
+	DestinationFQDNs     []string `json:"destinationFqdns,omitempty"`
+	DestinationPorts     []string `json:"destinationPorts,omitempty"`
+	Protocols            []string `json:"protocols"` // TCP, UDP, ICMP, Any; Http:80, Https:443 for application rules
+	TargetFQDNs          []string `json:"targetFqdns,omitempty"`
+	TargetURLs           []string `json:"targetUrls,omitempty"`
+	FQDNTags             []string `json:"fqdnTags,omitempty"`
+	WebCategories        []string `json:"webCategories,omitempty"`
+	TranslatedAddress    string   `json:"translatedAddress,omitempty"`
+	TranslatedFQDN       string   `json:"translatedFqdn,omitempty"`
+	TranslatedPort       string   `json:"translatedPort,omitempty"`
+}
+
+// BastionHost represents an Azure Bastion host. Bastion is deployed into the
+// AzureBastionSubnet of a VNet and can reach VMs in directly peered VNets too.
+type BastionHost struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	ResourceGroup     string            `json:"resourceGroup"`
+	SubscriptionID    string            `json:"subscriptionId"`
+	Location          string            `json:"location"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	SKU               string            `json:"sku"` // Basic, Standard
+	ScaleUnits        int32             `json:"scaleUnits"`
+	DNSName           string            `json:"dnsName"`
+	SubnetID          string            `json:"subnetId"` // AzureBastionSubnet
+	VNetID            string            `json:"vnetId"`
+	PublicIPAddressID string            `json:"publicIpAddressId"`
+	PublicIPAddress   string            `json:"publicIpAddress,omitempty"` // Address resolved from PublicIPAddressID
+	IPConnect         bool              `json:"ipConnect"`                 // Connect to VMs by private IP
+	Tunneling         bool              `json:"tunneling"`                 // Native client support
+	ShareableLink     bool              `json:"shareableLink"`
+	FileCopy          bool              `json:"fileCopy"`
+	ProvisioningState string            `json:"provisioningState"`
+}
+
+// VirtualWAN represents an Azure Virtual WAN
+type VirtualWAN struct {
+	ID                         string            `json:"id"`
+	Name                       string            `json:"name"`
+	ResourceGroup              string            `json:"resourceGroup"`
+	SubscriptionID             string            `json:"subscriptionId"`
+	Location                   string            `json:"location"`
+	Tags                       map[string]string `json:"tags,omitempty"`
+	Type                       string            `json:"type"` // Basic, Standard
+	AllowBranchToBranchTraffic bool              `json:"allowBranchToBranchTraffic"`
+	AllowVNetToVNetTraffic     bool              `json:"allowVnetToVnetTraffic"`
+	VirtualHubs                []string          `json:"virtualHubs"` // Virtual hub IDs
+}
+
+// VirtualHub represents a Virtual WAN hub. A hub with an AzureFirewallID is a
+// secured hub; its routing intent, if any, decides which traffic the firewall inspects.
+type VirtualHub struct {
+	ID                    string              `json:"id"`
+	Name                  string              `json:"name"`
+	ResourceGroup         string              `json:"resourceGroup"`
+	SubscriptionID        string              `json:"subscriptionId"`
+	Location              string              `json:"location"`
+	Tags                  map[string]string   `json:"tags,omitempty"`
+	VirtualWANID          string              `json:"virtualWanId"`
+	AddressPrefix         string              `json:"addressPrefix"`
+	SKU                   string              `json:"sku"`               // Basic, Standard
+	RoutingPreference     string              `json:"routingPreference"` // ExpressRoute, VpnGateway, ASPath
+	RoutingState          string              `json:"routingState"`
+	VirtualRouterIPs      []string            `json:"virtualRouterIps"`
+	AzureFirewallID       string              `json:"azureFirewallId,omitempty"`
+	VPNGatewayID          string              `json:"vpnGatewayId,omitempty"`
+	ExpressRouteGatewayID string              `json:"expressRouteGatewayId,omitempty"`
+	P2SVPNGatewayID       string              `json:"p2sVpnGatewayId,omitempty"`
+	VNetConnections       []HubVNetConnection `json:"vnetConnections"`
+	RouteTables           []HubRouteTable     `json:"routeTables"`
+	RoutingIntent         *HubRoutingIntent   `json:"routingIntent,omitempty"`
+	Gateways              []HubGateway        `json:"gateways"`
+}
+
+// HubVNetConnection represents the connection of a virtual network to a Virtual WAN hub
+type HubVNetConnection struct {
+	ID                     string   `json:"id"`
+	Name                   string   `json:"name"`
+	RemoteVNetID           string   `json:"remoteVnetId"`
+	EnableInternetSecurity bool     `json:"enableInternetSecurity"` // Propagate the hub's default route to the VNet
+	AssociatedRouteTable   string   `json:"associatedRouteTable"`   // Hub route table ID
+	PropagatedRouteTables  []string `json:"propagatedRouteTables"`  // Hub route table IDs
+	PropagatedLabels       []string `json:"propagatedLabels"`
+	ProvisioningState      string   `json:"provisioningState"`
+}
+
+// HubRouteTable represents a route table of a Virtual WAN hub
+type HubRouteTable struct {
+	ID                     string     `json:"id"`
+	Name                   string     `json:"name"`
+	Labels                 []string   `json:"labels"`
+	Routes                 []HubRoute `json:"routes"`
+	AssociatedConnections  []string   `json:"associatedConnections"`  // Connection IDs
+	PropagatingConnections []string   `json:"propagatingConnections"` // Connection IDs
+}
+
+// HubRoute represents a static route in a Virtual WAN hub route table
+type HubRoute struct {
+	Name            string   `json:"name"`
+	DestinationType string   `json:"destinationType"` // CIDR, ResourceId, Service
+	Destinations    []string `json:"destinations"`
+	NextHopType     string   `json:"nextHopType"` // ResourceId
+	NextHop         string   `json:"nextHop"`     // Resource ID, e.g. the hub firewall
+}
+
+// HubRoutingIntent represents the routing intent of a Virtual WAN hub, which sends
+// internet and/or private traffic through a next hop such as the hub firewall
+type HubRoutingIntent struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	RoutingPolicies []HubRoutingPolicy `json:"routingPolicies"`
+}
+
+// HubRoutingPolicy represents one routing intent policy
+type HubRoutingPolicy struct {
+	Name         string   `json:"name"`
+	Destinations []string `json:"destinations"` // Internet, PrivateTraffic
+	NextHop      string   `json:"nextHop"`      // Resource ID
+}
+
+// Routing intent policy destinations
+const (
+	RoutingIntentInternet       = "Internet"
+	RoutingIntentPrivateTraffic = "PrivateTraffic"
+)
+
+// NextHop returns the next hop of the policy covering the destination, or an empty
+// string when routing intent does not cover it
+func (r *HubRoutingIntent) NextHop(destination string) string {
+	if r == nil {
+		return ""
+	}
+	for _, policy := range r.RoutingPolicies {
+		for _, d := range policy.Destinations {
+			if strings.EqualFold(d, destination) {
+				return policy.NextHop
+			}
+		}
+	}
+	return ""
+}
+
+// HubGateway represents a VPN or ExpressRoute gateway deployed in a Virtual WAN hub
+type HubGateway struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"` // VPN, ExpressRoute
+	ScaleUnits  int32                  `json:"scaleUnits"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Connections []HubGatewayConnection `json:"connections"`
+}
+
+// HubGatewayConnection represents a connection from a hub gateway to a VPN site
+// or an ExpressRoute circuit peering
+type HubGatewayConnection struct {
+	ID                     string `json:"id"`
+	Name                   string `json:"name"`
+	RemoteID               string `json:"remoteId"` // VPN site or ExpressRoute circuit peering ID
+	EnableInternetSecurity bool   `json:"enableInternetSecurity"`
+}
+
+// Hub gateway types
+const (
+	HubGatewayVPN          = "VPN"
+	HubGatewayExpressRoute = "ExpressRoute"
+)
+
+// CollectionError records a resource type that could not be collected from one
+// scope, so that reports can say which parts of the topology are missing
+type CollectionError struct {
+	ResourceType string `json:"resourceType"`
+	Scope        string `json:"scope"` // e.g. "resource group rg1 in subscription sub1"
+	Class        string `json:"class"` // authz, throttled, not found, transient or other
+	Message      string `json:"message"`
+}
+
+// IntegrityWarning records two collected resources whose references to each
+// other disagree, or a reference to a resource outside the collected scope
+type IntegrityWarning struct {
+	ResourceType string `json:"resourceType"` // type of the resource that holds the reference
+	ResourceID   string `json:"resourceId"`
+	Message      string `json:"message"`
+}
+
+// Collection error classes
+const (
+	ErrorClassAuthorization = "authz"
+	ErrorClassThrottled     = "throttled"
+	ErrorClassNotFound      = "not found"
+	ErrorClassTransient     = "transient"
+	ErrorClassOther         = "other"
+)
 
 // NetworkWatcherInsights contains Network Watcher related information
 type NetworkWatcherInsights struct {
 	FlowLogsEnabled    bool                `json:"flowLogsEnabled"`
+	Watchers           []NetworkWatcher    `json:"watchers"`
 	FlowLogs           []FlowLog           `json:"flowLogs"`
 	ConnectionMonitors []ConnectionMonitor `json:"connectionMonitors"`
 	PacketCaptures     []PacketCapture     `json:"packetCaptures"`
+	// MissingRegions lists topology regions that have no Network Watcher
+	MissingRegions []string `json:"missingRegions,omitempty"`
 }
 
-// FlowLog represents an NSG flow log configuration
+// NetworkWatcher represents a regional Network Watcher instance
+type NetworkWatcher struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ResourceGroup  string            `json:"resourceGroup"`
+	SubscriptionID string            `json:"subscriptionId"`
+	Location       string            `json:"location"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// FlowLog represents a flow log configuration. NSGId is set when the flow log
+// targets a network security group; TargetResourceID holds the target of any type.
 type FlowLog struct {
-	ID               string `json:"id"`
-	NSGId            string `json:"nsgId"`
-	StorageAccountID string `json:"storageAccountId"`
-	Enabled          bool   `json:"enabled"`
-	RetentionDays    int32  `json:"retentionDays"`
-	TrafficAnalytics bool   `json:"trafficAnalytics"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Location         string            `json:"location"`
+	Tags             map[string]string `json:"tags,omitempty"`
+	NSGId            string            `json:"nsgId"`
+	TargetResourceID string            `json:"targetResourceId"`
+	StorageAccountID string            `json:"storageAccountId"`
+	Enabled          bool              `json:"enabled"`
+	RetentionDays    int32             `json:"retentionDays"`
+	TrafficAnalytics bool              `json:"trafficAnalytics"`
 }
 
 // ConnectionMonitor represents a Network Watcher connection monitor
 type ConnectionMonitor struct {
-	Name             string `json:"name"`
-	Source           string `json:"source"`
-	Destination      string `json:"destination"`
-	MonitoringStatus string `json:"monitoringStatus"`
+	ID               string            `json:"id"`
+	Location         string            `json:"location"`
+	Tags             map[string]string `json:"tags,omitempty"`
+	Name             string            `json:"name"`
+	Source           string            `json:"source"`
+	Destination      string            `json:"destination"`
+	MonitoringStatus string            `json:"monitoringStatus"`
 }
 
 // PacketCapture represents a Network Watcher packet capture
 type PacketCapture struct {
+	ID     string `json:"id"`
 	Name   string `json:"name"`
 	Target string `json:"target"`
 	Status string `json:"status"`
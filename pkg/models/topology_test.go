@@ -181,6 +181,49 @@ func TestSecurityRuleJSON(t *testing.T) {
 	}
 }
 
+func TestSecurityRuleAccessors(t *testing.T) {
+	rule := SecurityRule{
+		SourceAddressPrefix:   "Internet",
+		SourcePortRange:       "*",
+		DestinationPortRange:  "22",
+		DestinationPortRanges: []string{"80", "443"},
+		DestinationASGs:       []string{"asg-web"},
+	}
+
+	if got := rule.SourcePrefixes(); len(got) != 1 || got[0] != "Internet" {
+		t.Errorf("SourcePrefixes mismatch: got %v", got)
+	}
+	if got := rule.SourcePorts(); len(got) != 1 || got[0] != "*" {
+		t.Errorf("SourcePorts mismatch: got %v", got)
+	}
+	if got := rule.DestinationPrefixes(); len(got) != 0 {
+		t.Errorf("DestinationPrefixes should be empty when only ASGs are set, got %v", got)
+	}
+	if got := rule.DestinationPorts(); len(got) != 3 || got[0] != "22" || got[1] != "80" || got[2] != "443" {
+		t.Errorf("DestinationPorts mismatch: got %v", got)
+	}
+}
+
+func TestHubRoutingIntentNextHop(t *testing.T) {
+	intent := &HubRoutingIntent{
+		RoutingPolicies: []HubRoutingPolicy{
+			{Name: "InternetTraffic", Destinations: []string{"Internet"}, NextHop: "fw-hub"},
+		},
+	}
+
+	if got := intent.NextHop(RoutingIntentInternet); got != "fw-hub" {
+		t.Errorf("Expected internet next hop fw-hub, got %q", got)
+	}
+	if got := intent.NextHop(RoutingIntentPrivateTraffic); got != "" {
+		t.Errorf("Private traffic is not covered, got %q", got)
+	}
+
+	var none *HubRoutingIntent
+	if got := none.NextHop(RoutingIntentInternet); got != "" {
+		t.Errorf("Hub without routing intent should have no next hop, got %q", got)
+	}
+}
+
 func TestSubnetWithOptionalFields(t *testing.T) {
 	nsgID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkSecurityGroups/nsg1"
 	routeTableID := "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/routeTables/rt1"
@@ -419,6 +462,14 @@ func TestExpressRouteCircuitJSON(t *testing.T) {
 func TestNetworkWatcherInsightsJSON(t *testing.T) {
 	insights := NetworkWatcherInsights{
 		FlowLogsEnabled: true,
+		Watchers: []NetworkWatcher{
+			{
+				ID:       "/subscriptions/sub1/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_eastus",
+				Name:     "NetworkWatcher_eastus",
+				Location: "eastus",
+			},
+		},
+		MissingRegions: []string{"westeurope"},
 		FlowLogs: []FlowLog{
 			{
 				ID:               "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/networkWatchers/nw1/flowLogs/fl1",
@@ -468,4 +519,12 @@ func TestNetworkWatcherInsightsJSON(t *testing.T) {
 	if decoded.FlowLogs[0].RetentionDays != 30 {
 		t.Errorf("RetentionDays mismatch: got %d, want 30", decoded.FlowLogs[0].RetentionDays)
 	}
+
+	if len(decoded.Watchers) != 1 || decoded.Watchers[0].Location != "eastus" {
+		t.Errorf("Watchers mismatch: got %+v", decoded.Watchers)
+	}
+
+	if len(decoded.MissingRegions) != 1 || decoded.MissingRegions[0] != "westeurope" {
+		t.Errorf("MissingRegions mismatch: got %v", decoded.MissingRegions)
+	}
 }
@@ -11,6 +11,11 @@ import (
 
 // GenerateHTML creates a rich HTML report with embedded CSS
 func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisReport) string {
+	return GenerateHTMLWithOptions(topology, analysis, ReportOptions{})
+}
+
+// GenerateHTMLWithOptions creates an HTML report with the given options
+func GenerateHTMLWithOptions(topology *models.NetworkTopology, analysis *analyzer.AnalysisReport, opts ReportOptions) string {
 	var html strings.Builder
 
 	critical, high, medium, low := countBySeverity(analysis.SecurityFindings)
@@ -226,6 +231,22 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
             margin: 10px 0;
         }
 
+        .integrity-warnings {
+            background: #fff3cd;
+            border-left: 4px solid var(--medium);
+            padding: 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        .collection-errors {
+            background: #f8d7da;
+            border-left: 4px solid var(--critical);
+            padding: 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
         footer {
             margin-top: 30px;
             padding-top: 20px;
@@ -248,11 +269,73 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
 `, topology.SubscriptionID))
 	html.WriteString(fmt.Sprintf(`            <p><strong>Resource Group:</strong> %s</p>
 `, topology.ResourceGroup))
+	if len(topology.TagFilter) > 0 {
+		html.WriteString(fmt.Sprintf(`            <p><strong>Tag Filter:</strong> %s</p>
+`, models.TagFilter(topology.TagFilter)))
+	}
+	if topology.CachedAt != nil {
+		html.WriteString(fmt.Sprintf(`            <p><strong>Data:</strong> served from cache at %s</p>
+`, topology.CachedAt.Format("2006-01-02 15:04:05 MST")))
+	}
 	html.WriteString(fmt.Sprintf(`            <p><strong>Generated:</strong> %s</p>
 `, time.Now().Format("2006-01-02 15:04:05 MST")))
 	html.WriteString(`        </div>
 `)
 
+	// Incomplete data comes first so nobody mistakes a partial report for a complete one
+	if len(topology.CollectionErrors) > 0 {
+		html.WriteString(fmt.Sprintf(`        <h2>Incomplete Data</h2>
+        <div class="collection-errors">
+            <p><strong>Warning:</strong> %s. These resources are missing from this report and diagram, and findings that depend on them may be incomplete.</p>
+            <table>
+                <tr>
+                    <th>Resource Type</th>
+                    <th>Scope</th>
+                    <th>Error</th>
+                    <th>Message</th>
+                    <th>Suggested Action</th>
+                </tr>
+`, collectionErrorSummary(topology.CollectionErrors)))
+		for _, e := range topology.CollectionErrors {
+			html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                </tr>
+`, e.ResourceType, e.Scope, e.Class, e.Message, collectionErrorAction(e.Class)))
+		}
+		html.WriteString(`            </table>
+        </div>
+`)
+	}
+
+	// References that disagree usually mean a change was in flight during collection
+	if len(topology.IntegrityWarnings) > 0 {
+		html.WriteString(fmt.Sprintf(`        <h2>Data Integrity</h2>
+        <div class="integrity-warnings">
+            <p><strong>Warning:</strong> %d association(s) disagree between collected resources or point outside the collected scope. Re-run the analysis, or widen the scope, to confirm them.</p>
+            <table>
+                <tr>
+                    <th>Resource Type</th>
+                    <th>Resource</th>
+                    <th>Problem</th>
+                </tr>
+`, len(topology.IntegrityWarnings)))
+		for _, w := range topology.IntegrityWarnings {
+			html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                </tr>
+`, w.ResourceType, extractName(w.ResourceID), w.Message))
+		}
+		html.WriteString(`            </table>
+        </div>
+`)
+	}
+
 	// Executive Summary
 	html.WriteString(`        <h2>Executive Summary</h2>
         <div class="summary-grid">
@@ -428,6 +511,7 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
                     <th>Address Prefix</th>
                     <th>NSG</th>
                     <th>Route Table</th>
+                    <th>Workloads</th>
                 </tr>
 `)
 				for _, subnet := range vnet.Subnets {
@@ -439,13 +523,15 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
 					if subnet.RouteTable != nil {
 						rt = extractName(*subnet.RouteTable)
 					}
+					workloads := formatWorkloads(workloadsByNIC(topology, subnetNICs(topology, subnet)))
 					html.WriteString(fmt.Sprintf(`                <tr>
                     <td>%s</td>
                     <td>%s</td>
                     <td>%s</td>
                     <td>%s</td>
+                    <td>%s</td>
                 </tr>
-`, subnet.Name, subnet.AddressPrefix, nsg, rt))
+`, subnet.Name, subnet.AddressPrefix, nsg, rt, workloads))
 				}
 				html.WriteString(`            </table>
 `)
@@ -455,6 +541,138 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
 		}
 	}
 
+	// Subnet IP usage, fullest first
+	if len(analysis.SubnetUtilization) > 0 {
+		html.WriteString(`        <h3>Subnet IP Utilization</h3>
+        <table>
+            <tr>
+                <th>Subnet</th>
+                <th>Address Prefix</th>
+                <th>Used</th>
+                <th>Usable</th>
+                <th>Available</th>
+                <th>Utilization</th>
+                <th>Status</th>
+            </tr>
+`)
+		for _, u := range analysis.SubnetUtilization {
+			status := u.Status
+			switch u.Status {
+			case analyzer.UtilizationCritical:
+				status = `<span class="severity-badge severity-high">` + u.Status + `</span>`
+			case analyzer.UtilizationWarning:
+				status = `<span class="severity-badge severity-medium">` + u.Status + `</span>`
+			}
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s/%s</td>
+                <td>%s</td>
+                <td>%d</td>
+                <td>%d</td>
+                <td>%d</td>
+                <td>%.1f%%</td>
+                <td>%s</td>
+            </tr>
+`, u.VNet, u.Subnet, u.AddressPrefix, u.UsedIPs, u.UsableIPs, u.AvailableIPs, u.PercentUsed, status))
+		}
+		html.WriteString(`        </table>
+        <p><em>Usable IPs exclude the 5 addresses Azure reserves in every subnet.</em></p>
+`)
+	}
+
+	// Network Interfaces
+	if len(topology.NetworkInterfaces) > 0 {
+		html.WriteString(`        <h3>Network Interfaces</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>Workload</th>
+                <th>Private IPs</th>
+                <th>Subnet</th>
+                <th>NIC NSG</th>
+                <th>IP Forwarding</th>
+                <th>Accelerated Networking</th>
+            </tr>
+`)
+		for _, nic := range topology.NetworkInterfaces {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%v</td>
+                <td>%v</td>
+            </tr>
+`, nic.Name, workloadName(nic), valueOrDash(strings.Join(nic.PrivateIPAddresses, ", ")),
+				valueOrDash(extractName(nic.SubnetID)), valueOrDash(extractName(nic.NetworkSecurityGroupID)),
+				nic.EnableIPForwarding, nic.EnableAcceleratedNetworking))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// Public IP Addresses
+	if len(topology.PublicIPAddresses) > 0 {
+		html.WriteString(`        <h3>Public IP Addresses</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>IP Address</th>
+                <th>SKU</th>
+                <th>Allocation</th>
+                <th>DNS Name</th>
+                <th>DDoS Protection</th>
+                <th>Associated With</th>
+            </tr>
+`)
+		for _, pip := range topology.PublicIPAddresses {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, pip.Name, valueOrDash(pip.IPAddress), valueOrDash(pip.SKU), valueOrDash(pip.AllocationMethod),
+				valueOrDash(pip.FQDN), valueOrDash(pip.DDoSProtection), publicIPAssociation(pip)))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// NAT Gateways
+	if len(topology.NATGateways) > 0 {
+		html.WriteString(`        <h3>NAT Gateways</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>SKU</th>
+                <th>Zones</th>
+                <th>Public IPs</th>
+                <th>Public IP Prefixes</th>
+                <th>Idle Timeout</th>
+                <th>Subnets</th>
+            </tr>
+`)
+		for _, nat := range topology.NATGateways {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%d min</td>
+                <td>%s</td>
+            </tr>
+`, nat.Name, valueOrDash(nat.SKU), valueOrDash(strings.Join(nat.Zones, ", ")), natGatewayPublicIPs(nat),
+				valueOrDash(joinNames(nat.PublicIPPrefixes)), nat.IdleTimeoutMinutes, valueOrDash(joinSubnetNames(nat.AssociatedSubnets))))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
 	// NSGs
 	if len(topology.NSGs) > 0 {
 		html.WriteString(`        <h3>Network Security Groups</h3>
@@ -463,8 +681,11 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
 			html.WriteString(`        <div class="resource-section">
 `)
 			html.WriteString(fmt.Sprintf(`            <h4>%s</h4>
-            <p><strong>Location:</strong> %s</p>
-`, nsg.Name, nsg.Location))
+            <p>
+                <strong>Location:</strong> %s<br>
+                <strong>Protects:</strong> %s
+            </p>
+`, nsg.Name, nsg.Location, formatWorkloads(workloadsByNIC(topology, nsgNICs(topology, nsg)))))
 
 			if len(nsg.SecurityRules) > 0 {
 				html.WriteString(`            <table>
@@ -475,7 +696,8 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
                     <th>Access</th>
                     <th>Protocol</th>
                     <th>Source</th>
-                    <th>Dest Port</th>
+                    <th>Destination</th>
+                    <th>Dest Ports</th>
                 </tr>
 `)
 				for _, rule := range nsg.SecurityRules {
@@ -487,8 +709,361 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
                     <td>%s</td>
                     <td>%s</td>
                     <td>%s</td>
+                    <td>%s</td>
+                </tr>
+`, rule.Priority, ruleName(rule), rule.Direction, rule.Access, rule.Protocol,
+						ruleEndpoints(rule.SourcePrefixes(), rule.SourceASGs),
+						ruleEndpoints(rule.DestinationPrefixes(), rule.DestinationASGs),
+						valueOrDash(strings.Join(rule.DestinationPorts(), ", "))))
+				}
+				html.WriteString(`            </table>
+`)
+			}
+			html.WriteString(`        </div>
+`)
+		}
+	}
+
+	// Application Security Groups
+	if len(topology.ASGs) > 0 {
+		html.WriteString(`        <h3>Application Security Groups</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>Location</th>
+                <th>Members</th>
+                <th>Used By Rules</th>
+            </tr>
+`)
+		for _, asg := range topology.ASGs {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, asg.Name, asg.Location, formatWorkloads(workloadsByNIC(topology, asgNICs(topology, asg))),
+				valueOrDash(strings.Join(asgRules(topology, asg), ", "))))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// Firewall Policies
+	if len(topology.FirewallPolicies) > 0 {
+		html.WriteString(`        <h3>Firewall Policies</h3>
+`)
+		for _, policy := range topology.FirewallPolicies {
+			inherits := "-"
+			if policy.BasePolicyID != "" {
+				inherits = extractName(policy.BasePolicyID)
+			}
+
+			html.WriteString(`        <div class="resource-section">
+`)
+			html.WriteString(fmt.Sprintf(`            <h4>%s</h4>
+            <p>
+                <strong>SKU:</strong> %s<br>
+                <strong>Threat Intelligence:</strong> %s<br>
+                <strong>Inherits From:</strong> %s<br>
+                <strong>Used By:</strong> %s
+            </p>
+`, policy.Name, valueOrDash(policy.SKU), valueOrDash(policy.ThreatIntelMode), inherits, valueOrDash(joinNames(policy.Firewalls))))
+
+			if len(policy.RuleCollectionGroups) > 0 {
+				html.WriteString(`            <table>
+                <tr>
+                    <th>Group</th>
+                    <th>Collection</th>
+                    <th>Type</th>
+                    <th>Action</th>
+                    <th>Priority</th>
+                    <th>Rules</th>
+                </tr>
+`)
+				for _, group := range policy.RuleCollectionGroups {
+					for _, collection := range group.RuleCollections {
+						html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s (%d)</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%d</td>
+                    <td>%d</td>
+                </tr>
+`, group.Name, group.Priority, collection.Name, collection.CollectionType, collection.Action, collection.Priority, len(collection.Rules)))
+					}
+				}
+				html.WriteString(`            </table>
+`)
+			}
+			html.WriteString(`        </div>
+`)
+		}
+	}
+
+	// Bastion Hosts
+	if len(topology.BastionHosts) > 0 {
+		html.WriteString(`        <h3>Bastion Hosts</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>SKU</th>
+                <th>VNet</th>
+                <th>Public IP</th>
+                <th>Features</th>
+            </tr>
+`)
+		for _, bastion := range topology.BastionHosts {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, bastion.Name, valueOrDash(bastion.SKU), valueOrDash(extractName(bastion.VNetID)),
+				frontendAddress("", bastion.PublicIPAddressID, bastion.PublicIPAddress), bastionFeatures(bastion)))
+		}
+		html.WriteString(`        </table>
+`)
+
+		if len(analysis.BastionCoverage) > 0 {
+			html.WriteString(`        <h3>Bastion Coverage</h3>
+        <table>
+            <tr>
+                <th>VNet</th>
+                <th>VMs</th>
+                <th>Bastion</th>
+                <th>Access</th>
+            </tr>
+`)
+			for _, c := range analysis.BastionCoverage {
+				html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%d</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, c.VNet, c.VMs, valueOrDash(c.Bastion), bastionAccess(c)))
+			}
+			html.WriteString(`        </table>
+`)
+		}
+	}
+
+	// Virtual WAN hubs and how their connected VNets route traffic
+	if len(topology.VirtualHubs) > 0 {
+		html.WriteString(`        <h3>Virtual WAN Hubs</h3>
+        <table>
+            <tr>
+                <th>Hub</th>
+                <th>Virtual WAN</th>
+                <th>Address Prefix</th>
+                <th>Firewall</th>
+                <th>Routing Intent</th>
+                <th>Gateways</th>
+                <th>VNet Connections</th>
+            </tr>
+`)
+		for _, hub := range topology.VirtualHubs {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%d</td>
+            </tr>
+`, hub.Name, valueOrDash(extractName(hub.VirtualWANID)), valueOrDash(hub.AddressPrefix),
+				valueOrDash(extractName(hub.AzureFirewallID)), hubRoutingIntent(hub), hubGateways(hub), len(hub.VNetConnections)))
+		}
+		html.WriteString(`        </table>
+`)
+
+		if len(analysis.HubConnectivity) > 0 {
+			html.WriteString(`        <h3>Hub Connectivity</h3>
+        <table>
+            <tr>
+                <th>VNet</th>
+                <th>Hub</th>
+                <th>Internet Egress</th>
+                <th>Private Traffic</th>
+                <th>Reaches</th>
+            </tr>
+`)
+			for _, c := range analysis.HubConnectivity {
+				html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, c.VNet, c.Hub, c.InternetEgress, c.PrivateTraffic, formatWorkloads(c.ReachableVNets)))
+			}
+			html.WriteString(`        </table>
+`)
+		}
+	}
+
+	// On-Premises Networks
+	if len(topology.LocalNetworkGateways) > 0 {
+		html.WriteString(`        <h3>On-Premises Networks</h3>
+        <table>
+            <tr>
+                <th>Local Network Gateway</th>
+                <th>Endpoint</th>
+                <th>Address Prefixes</th>
+                <th>BGP</th>
+                <th>Connections</th>
+            </tr>
+`)
+		for _, lng := range topology.LocalNetworkGateways {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, lng.Name, localNetworkEndpoint(lng), valueOrDash(strings.Join(lng.AddressPrefixes, ", ")),
+				localNetworkBGP(lng), valueOrDash(joinNames(lng.Connections))))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// ExpressRoute
+	if len(topology.ERCircuits) > 0 {
+		html.WriteString(`        <h3>ExpressRoute Circuits</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>Provider</th>
+                <th>Bandwidth</th>
+                <th>SKU</th>
+                <th>Gateways</th>
+                <th>Global Reach</th>
+                <th>Traffic</th>
+            </tr>
+`)
+		for _, er := range topology.ERCircuits {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%d Mbps</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, er.Name, expressRouteProvider(er), er.BandwidthInMbps, valueOrDash(strings.TrimSpace(er.SKUTier+" "+er.SKUFamily)),
+				expressRouteGateways(topology, er), expressRouteGlobalReach(er), expressRouteTraffic(er.Stats)))
+		}
+		html.WriteString(`        </table>
+        <h4>ExpressRoute Peerings</h4>
+        <table>
+            <tr>
+                <th>Circuit</th>
+                <th>Peering</th>
+                <th>State</th>
+                <th>Peer ASN</th>
+                <th>VLAN</th>
+                <th>Primary / Secondary</th>
+                <th>Route Filter</th>
+            </tr>
+`)
+		for _, er := range topology.ERCircuits {
+			for _, p := range er.Peerings {
+				html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%d</td>
+                <td>%d</td>
+                <td>%s / %s</td>
+                <td>%s</td>
+            </tr>
+`, er.Name, p.PeeringType, valueOrDash(p.State), p.PeerASN, p.VlanID,
+					valueOrDash(p.PrimaryPeerAddressPrefix), valueOrDash(p.SecondaryPeerAddressPrefix), routeFilterSummary(p)))
+			}
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// Private Link Services
+	if len(topology.PrivateLinkServices) > 0 {
+		html.WriteString(`        <h3>Private Link Services</h3>
+        <table>
+            <tr>
+                <th>Name</th>
+                <th>Load Balancer</th>
+                <th>Frontends</th>
+                <th>NAT IPs</th>
+                <th>Visibility</th>
+                <th>Auto-Approval</th>
+                <th>Connections</th>
+            </tr>
+`)
+		for _, pls := range topology.PrivateLinkServices {
+			html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, pls.Name, valueOrDash(extractName(pls.LoadBalancerID)), valueOrDash(joinNames(pls.LoadBalancerFrontendIDs)),
+				privateLinkServiceNATIPs(pls), privateLinkSubscriptions(pls.Visibility),
+				privateLinkSubscriptions(pls.AutoApproval), privateLinkConnections(pls)))
+		}
+		html.WriteString(`        </table>
+`)
+	}
+
+	// Private DNS Zones
+	if len(topology.PrivateDNSZones) > 0 {
+		html.WriteString(`        <h3>Private DNS Zones</h3>
+`)
+		for _, zone := range topology.PrivateDNSZones {
+			links := make([]string, 0, len(zone.VNetLinks))
+			for _, link := range zone.VNetLinks {
+				links = append(links, link.VNetName)
+			}
+			linked := "none"
+			if len(links) > 0 {
+				linked = strings.Join(links, ", ")
+			}
+
+			html.WriteString(`        <div class="resource-section">
+`)
+			html.WriteString(fmt.Sprintf(`            <h4>%s</h4>
+            <p>
+                <strong>Record Sets:</strong> %d<br>
+                <strong>Linked VNets:</strong> %s
+            </p>
+`, zone.Name, zone.RecordSets, linked))
+
+			if len(zone.ARecords) > 0 {
+				html.WriteString(`            <table>
+                <tr>
+                    <th>A Record</th>
+                    <th>IP Addresses</th>
+                    <th>TTL</th>
+                </tr>
+`)
+				for _, record := range zone.ARecords {
+					html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%d</td>
                 </tr>
-`, rule.Priority, rule.Name, rule.Direction, rule.Access, rule.Protocol, rule.SourceAddressPrefix, rule.DestinationPortRange))
+`, record.Name, strings.Join(record.IPAddresses, ", "), record.TTL))
 				}
 				html.WriteString(`            </table>
 `)
@@ -498,6 +1073,108 @@ func GenerateHTML(topology *models.NetworkTopology, analysis *analyzer.AnalysisR
 		}
 	}
 
+	// Network Watcher
+	if topology.NetworkWatcher != nil {
+		nw := topology.NetworkWatcher
+		watchers := make([]string, 0, len(nw.Watchers))
+		for _, w := range nw.Watchers {
+			watchers = append(watchers, fmt.Sprintf("%s (%s)", w.Name, w.Location))
+		}
+		watcherList := "none"
+		if len(watchers) > 0 {
+			watcherList = strings.Join(watchers, ", ")
+		}
+		missing := "none"
+		if len(nw.MissingRegions) > 0 {
+			missing = strings.Join(nw.MissingRegions, ", ")
+		}
+
+		html.WriteString(`        <h3>Network Watcher</h3>
+        <div class="resource-section">
+`)
+		html.WriteString(fmt.Sprintf(`            <p>
+                <strong>Watchers:</strong> %s<br>
+                <strong>Regions Without Network Watcher:</strong> %s
+            </p>
+`, watcherList, missing))
+
+		if len(analysis.FlowLogCoverage) > 0 {
+			html.WriteString(`            <strong>NSG Flow Log Coverage:</strong>
+            <table>
+                <tr>
+                    <th>NSG</th>
+                    <th>Flow Log</th>
+                    <th>Status</th>
+                    <th>Retention</th>
+                    <th>Traffic Analytics</th>
+                </tr>
+`)
+			for _, c := range analysis.FlowLogCoverage {
+				html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%v</td>
+                </tr>
+`, c.NSG, valueOrDash(c.FlowLog), flowLogStatus(c), flowLogRetention(c), c.TrafficAnalytics))
+			}
+			html.WriteString(`            </table>
+`)
+		}
+
+		if len(nw.ConnectionMonitors) > 0 {
+			html.WriteString(`            <strong>Connection Monitors:</strong>
+            <table>
+                <tr>
+                    <th>Name</th>
+                    <th>Source</th>
+                    <th>Destination</th>
+                    <th>Status</th>
+                </tr>
+`)
+			for _, cm := range nw.ConnectionMonitors {
+				html.WriteString(fmt.Sprintf(`                <tr>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                </tr>
+`, cm.Name, cm.Source, cm.Destination, cm.MonitoringStatus))
+			}
+			html.WriteString(`            </table>
+`)
+		}
+		html.WriteString(`        </div>
+`)
+	}
+
+	// Resources grouped by tag
+	if opts.GroupByTag != "" {
+		html.WriteString(fmt.Sprintf(`        <h2>Resources by Tag: %s</h2>
+`, opts.GroupByTag))
+		for _, group := range groupByTag(topology, opts.GroupByTag) {
+			html.WriteString(fmt.Sprintf(`        <h3>%s (%d)</h3>
+        <table>
+            <tr>
+                <th>Type</th>
+                <th>Name</th>
+                <th>Resource Group</th>
+            </tr>
+`, valueOrDash(group.Value), len(group.Resources)))
+			for _, r := range group.Resources {
+				html.WriteString(fmt.Sprintf(`            <tr>
+                <td>%s</td>
+                <td>%s</td>
+                <td>%s</td>
+            </tr>
+`, r.Type, r.Name, valueOrDash(r.ResourceGroup)))
+			}
+			html.WriteString(`        </table>
+`)
+		}
+	}
+
 	// Footer
 	html.WriteString(`        <footer>
             Generated by Azure Network Topology Analyzer v1.0.0
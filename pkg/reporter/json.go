@@ -17,20 +17,22 @@ type JSONReport struct {
 
 // ReportMetadata contains report generation information
 type ReportMetadata struct {
-	GeneratedAt    time.Time `json:"generated_at"`
-	ToolVersion    string    `json:"tool_version"`
-	SubscriptionID string    `json:"subscription_id"`
-	ResourceGroup  string    `json:"resource_group"`
+	GeneratedAt       time.Time  `json:"generated_at"`
+	ToolVersion       string     `json:"tool_version"`
+	SubscriptionID    string     `json:"subscription_id"`
+	ResourceGroup     string     `json:"resource_group"`
+	ServedFromCacheAt *time.Time `json:"served_from_cache_at,omitempty"`
 }
 
 // GenerateJSON creates a complete JSON report
 func GenerateJSON(topology *models.NetworkTopology, analysis *analyzer.AnalysisReport) ([]byte, error) {
 	report := JSONReport{
 		Metadata: ReportMetadata{
-			GeneratedAt:    time.Now(),
-			ToolVersion:    "1.0.0",
-			SubscriptionID: topology.SubscriptionID,
-			ResourceGroup:  topology.ResourceGroup,
+			GeneratedAt:       time.Now(),
+			ToolVersion:       "1.0.0",
+			SubscriptionID:    topology.SubscriptionID,
+			ResourceGroup:     topology.ResourceGroup,
+			ServedFromCacheAt: topology.CachedAt,
 		},
 		Topology: topology,
 		Analysis: analysis,
@@ -9,16 +9,60 @@ import (
 	"azure-network-analyzer/pkg/models"
 )
 
+// ReportOptions controls optional sections of the Markdown and HTML reports
+type ReportOptions struct {
+	// GroupByTag adds a section listing the resources grouped by this tag's value
+	GroupByTag string
+}
+
 // GenerateMarkdown creates a comprehensive Markdown report
 func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.AnalysisReport) string {
+	return GenerateMarkdownWithOptions(topology, analysis, ReportOptions{})
+}
+
+// GenerateMarkdownWithOptions creates a Markdown report with the given options
+func GenerateMarkdownWithOptions(topology *models.NetworkTopology, analysis *analyzer.AnalysisReport, opts ReportOptions) string {
 	var md strings.Builder
 
 	// Header
 	md.WriteString("# Azure Network Topology Report\n\n")
 	md.WriteString(fmt.Sprintf("**Subscription:** %s  \n", topology.SubscriptionID))
 	md.WriteString(fmt.Sprintf("**Resource Group:** %s  \n", topology.ResourceGroup))
+	if len(topology.TagFilter) > 0 {
+		md.WriteString(fmt.Sprintf("**Tag Filter:** %s  \n", models.TagFilter(topology.TagFilter)))
+	}
+	if topology.CachedAt != nil {
+		md.WriteString(fmt.Sprintf("**Data:** served from cache at %s  \n", topology.CachedAt.Format("2006-01-02 15:04:05 MST")))
+	}
 	md.WriteString(fmt.Sprintf("**Generated:** %s  \n\n", time.Now().Format("2006-01-02 15:04:05 MST")))
 
+	// Incomplete data comes first so nobody mistakes a partial report for a complete one
+	if len(topology.CollectionErrors) > 0 {
+		md.WriteString("## Incomplete Data\n\n")
+		md.WriteString(fmt.Sprintf("> **Warning:** %s. These resources are missing from this report and diagram, and findings that depend on them may be incomplete.\n\n",
+			collectionErrorSummary(topology.CollectionErrors)))
+		md.WriteString("| Resource Type | Scope | Error | Message | Suggested Action |\n")
+		md.WriteString("|---------------|-------|-------|---------|------------------|\n")
+		for _, e := range topology.CollectionErrors {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				e.ResourceType, e.Scope, e.Class, e.Message, collectionErrorAction(e.Class)))
+		}
+		md.WriteString("\n")
+	}
+
+	// References that disagree usually mean a change was in flight during collection
+	if len(topology.IntegrityWarnings) > 0 {
+		md.WriteString("## Data Integrity\n\n")
+		md.WriteString(fmt.Sprintf("> **Warning:** %d association(s) disagree between collected resources or point outside the collected scope. Re-run the analysis, or widen the scope, to confirm them.\n\n",
+			len(topology.IntegrityWarnings)))
+		md.WriteString("| Resource Type | Resource | Problem |\n")
+		md.WriteString("|---------------|----------|---------|\n")
+		for _, w := range topology.IntegrityWarnings {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s |\n", w.ResourceType, extractName(w.ResourceID), w.Message))
+		}
+		md.WriteString("\n")
+	}
+
 	// Executive Summary
 	md.WriteString("## Executive Summary\n\n")
 	md.WriteString(fmt.Sprintf("- **Total VNets:** %d\n", analysis.Summary.TotalVNets))
@@ -26,6 +70,9 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 	md.WriteString(fmt.Sprintf("- **Total NSGs:** %d\n", analysis.Summary.TotalNSGs))
 	md.WriteString(fmt.Sprintf("- **Total Security Rules:** %d\n", analysis.Summary.TotalSecurityRules))
 	md.WriteString(fmt.Sprintf("- **VNet Peerings:** %d\n", analysis.Summary.VNetPeeringCount))
+	if analysis.Summary.TotalVirtualHubs > 0 {
+		md.WriteString(fmt.Sprintf("- **Virtual Hubs:** %d (%d VNet connections)\n", analysis.Summary.TotalVirtualHubs, analysis.Summary.TotalHubConnections))
+	}
 	md.WriteString(fmt.Sprintf("- **Security Findings:** %d\n", len(analysis.SecurityFindings)))
 
 	// Count by severity
@@ -115,8 +162,8 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 
 			if len(vnet.Subnets) > 0 {
 				md.WriteString("\n**Subnets:**\n\n")
-				md.WriteString("| Name | Address Prefix | NSG | Route Table | NAT Gateway |\n")
-				md.WriteString("|------|----------------|-----|-------------|-------------|\n")
+				md.WriteString("| Name | Address Prefix | NSG | Route Table | NAT Gateway | Workloads |\n")
+				md.WriteString("|------|----------------|-----|-------------|-------------|-----------|\n")
 				for _, subnet := range vnet.Subnets {
 					nsg := "-"
 					if subnet.NetworkSecurityGroup != nil {
@@ -130,8 +177,9 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 					if subnet.NATGateway != nil {
 						nat = extractName(*subnet.NATGateway)
 					}
-					md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
-						subnet.Name, subnet.AddressPrefix, nsg, rt, nat))
+					workloads := formatWorkloads(workloadsByNIC(topology, subnetNICs(topology, subnet)))
+					md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+						subnet.Name, subnet.AddressPrefix, nsg, rt, nat, workloads))
 				}
 			}
 
@@ -139,38 +187,141 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 				md.WriteString("\n**Peerings:**\n\n")
 				for _, peer := range vnet.Peerings {
 					md.WriteString(fmt.Sprintf("- %s → %s (State: %s)\n",
-						peer.Name, peer.RemoteVNetName, peer.PeeringState))
+						peer.Name, peeringRemote(peer), peer.PeeringState))
 				}
 			}
 			md.WriteString("\n")
 		}
 	}
 
+	// Subnet IP usage, fullest first
+	if len(analysis.SubnetUtilization) > 0 {
+		md.WriteString("### Subnet IP Utilization\n\n")
+		md.WriteString("| Subnet | Address Prefix | Used | Usable | Available | Utilization | Status |\n")
+		md.WriteString("|--------|----------------|------|--------|-----------|-------------|--------|\n")
+		for _, u := range analysis.SubnetUtilization {
+			md.WriteString(fmt.Sprintf("| %s/%s | %s | %d | %d | %d | %.1f%% | %s |\n",
+				u.VNet, u.Subnet, u.AddressPrefix, u.UsedIPs, u.UsableIPs, u.AvailableIPs, u.PercentUsed, u.Status))
+		}
+		md.WriteString("\n*Usable IPs exclude the 5 addresses Azure reserves in every subnet.*\n\n")
+	}
+
+	// Network Interfaces
+	if len(topology.NetworkInterfaces) > 0 {
+		md.WriteString("### Network Interfaces\n\n")
+		md.WriteString("| Name | Workload | Private IPs | Subnet | NIC NSG | IP Forwarding | Accelerated Networking |\n")
+		md.WriteString("|------|----------|-------------|--------|---------|---------------|------------------------|\n")
+		for _, nic := range topology.NetworkInterfaces {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %v | %v |\n",
+				nic.Name, workloadName(nic), valueOrDash(strings.Join(nic.PrivateIPAddresses, ", ")),
+				valueOrDash(extractName(nic.SubnetID)), valueOrDash(extractName(nic.NetworkSecurityGroupID)),
+				nic.EnableIPForwarding, nic.EnableAcceleratedNetworking))
+		}
+		md.WriteString("\n")
+	}
+
+	// Public IP Addresses
+	if len(topology.PublicIPAddresses) > 0 {
+		md.WriteString("### Public IP Addresses\n\n")
+		md.WriteString("| Name | IP Address | SKU | Allocation | DNS Name | DDoS Protection | Associated With |\n")
+		md.WriteString("|------|------------|-----|------------|----------|-----------------|-----------------|\n")
+		for _, pip := range topology.PublicIPAddresses {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				pip.Name, valueOrDash(pip.IPAddress), valueOrDash(pip.SKU), valueOrDash(pip.AllocationMethod),
+				valueOrDash(pip.FQDN), valueOrDash(pip.DDoSProtection), publicIPAssociation(pip)))
+		}
+		md.WriteString("\n")
+	}
+
+	// NAT Gateways
+	if len(topology.NATGateways) > 0 {
+		md.WriteString("### NAT Gateways\n\n")
+		md.WriteString("| Name | SKU | Zones | Public IPs | Public IP Prefixes | Idle Timeout | Subnets |\n")
+		md.WriteString("|------|-----|-------|------------|--------------------|--------------|---------|\n")
+		for _, nat := range topology.NATGateways {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %d min | %s |\n",
+				nat.Name, valueOrDash(nat.SKU), valueOrDash(strings.Join(nat.Zones, ", ")), natGatewayPublicIPs(nat),
+				valueOrDash(joinNames(nat.PublicIPPrefixes)), nat.IdleTimeoutMinutes, valueOrDash(joinSubnetNames(nat.AssociatedSubnets))))
+		}
+		md.WriteString("\n")
+	}
+
 	// Network Security Groups
 	if len(topology.NSGs) > 0 {
 		md.WriteString("### Network Security Groups\n\n")
 		for _, nsg := range topology.NSGs {
 			md.WriteString(fmt.Sprintf("#### %s\n", nsg.Name))
 			md.WriteString(fmt.Sprintf("- **Location:** %s\n", nsg.Location))
+			md.WriteString(fmt.Sprintf("- **Protects:** %s\n", formatWorkloads(workloadsByNIC(topology, nsgNICs(topology, nsg)))))
 
 			if len(nsg.SecurityRules) > 0 {
 				md.WriteString("\n**Security Rules:**\n\n")
-				md.WriteString("| Priority | Name | Direction | Access | Protocol | Source | Dest Port |\n")
-				md.WriteString("|----------|------|-----------|--------|----------|--------|------------|\n")
+				md.WriteString("| Priority | Name | Direction | Access | Protocol | Source | Destination | Dest Ports |\n")
+				md.WriteString("|----------|------|-----------|--------|----------|--------|-------------|------------|\n")
 				for _, rule := range nsg.SecurityRules {
-					src := rule.SourceAddressPrefix
-					if len(src) > 20 {
-						src = src[:17] + "..."
-					}
-					md.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %s |\n",
-						rule.Priority, rule.Name, rule.Direction, rule.Access,
-						rule.Protocol, src, rule.DestinationPortRange))
+					md.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %s | %s |\n",
+						rule.Priority, ruleName(rule), rule.Direction, rule.Access, rule.Protocol,
+						ruleEndpoints(rule.SourcePrefixes(), rule.SourceASGs),
+						ruleEndpoints(rule.DestinationPrefixes(), rule.DestinationASGs),
+						valueOrDash(strings.Join(rule.DestinationPorts(), ", "))))
 				}
 			}
 			md.WriteString("\n")
 		}
 	}
 
+	// Application Security Groups
+	if len(topology.ASGs) > 0 {
+		md.WriteString("### Application Security Groups\n\n")
+		md.WriteString("| Name | Location | Members | Used By Rules |\n")
+		md.WriteString("|------|----------|---------|---------------|\n")
+		for _, asg := range topology.ASGs {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				asg.Name, asg.Location, formatWorkloads(workloadsByNIC(topology, asgNICs(topology, asg))),
+				valueOrDash(strings.Join(asgRules(topology, asg), ", "))))
+		}
+		md.WriteString("\n")
+	}
+
+	// Network Watcher
+	if topology.NetworkWatcher != nil {
+		nw := topology.NetworkWatcher
+		md.WriteString("### Network Watcher\n\n")
+		watchers := make([]string, 0, len(nw.Watchers))
+		for _, w := range nw.Watchers {
+			watchers = append(watchers, fmt.Sprintf("%s (%s)", w.Name, w.Location))
+		}
+		if len(watchers) > 0 {
+			md.WriteString(fmt.Sprintf("- **Watchers:** %s\n", strings.Join(watchers, ", ")))
+		} else {
+			md.WriteString("- **Watchers:** none\n")
+		}
+		if len(nw.MissingRegions) > 0 {
+			md.WriteString(fmt.Sprintf("- **Regions Without Network Watcher:** %s\n", strings.Join(nw.MissingRegions, ", ")))
+		}
+
+		if len(analysis.FlowLogCoverage) > 0 {
+			md.WriteString("\n**NSG Flow Log Coverage:**\n\n")
+			md.WriteString("| NSG | Flow Log | Status | Retention | Traffic Analytics |\n")
+			md.WriteString("|-----|----------|--------|-----------|-------------------|\n")
+			for _, c := range analysis.FlowLogCoverage {
+				md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %v |\n",
+					c.NSG, valueOrDash(c.FlowLog), flowLogStatus(c), flowLogRetention(c), c.TrafficAnalytics))
+			}
+		}
+
+		if len(nw.ConnectionMonitors) > 0 {
+			md.WriteString("\n**Connection Monitors:**\n\n")
+			md.WriteString("| Name | Source | Destination | Status |\n")
+			md.WriteString("|------|--------|-------------|--------|\n")
+			for _, cm := range nw.ConnectionMonitors {
+				md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+					cm.Name, cm.Source, cm.Destination, cm.MonitoringStatus))
+			}
+		}
+		md.WriteString("\n")
+	}
+
 	// Route Tables
 	if len(topology.RouteTables) > 0 {
 		md.WriteString("### Route Tables\n\n")
@@ -199,17 +350,68 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 	// Private Endpoints
 	if len(topology.PrivateEndpoints) > 0 {
 		md.WriteString("### Private Endpoints\n\n")
-		md.WriteString("| Name | Location | Target Resource | Subnet |\n")
-		md.WriteString("|------|----------|-----------------|--------|\n")
+		md.WriteString("| Name | Location | Target Resource | Subnet | Private IPs | FQDNs |\n")
+		md.WriteString("|------|----------|-----------------|--------|-------------|-------|\n")
 		for _, pe := range topology.PrivateEndpoints {
 			target := extractName(pe.PrivateLinkServiceID)
 			subnet := extractName(pe.SubnetID)
-			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-				pe.Name, pe.Location, target, subnet))
+			fqdns := make([]string, 0, len(pe.DNSConfigs))
+			for _, config := range pe.DNSConfigs {
+				fqdns = append(fqdns, config.FQDN)
+			}
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				pe.Name, pe.Location, target, subnet,
+				valueOrDash(strings.Join(pe.PrivateIPAddresses, ", ")), valueOrDash(strings.Join(fqdns, ", "))))
+		}
+		md.WriteString("\n")
+	}
+
+	// Private Link Services
+	if len(topology.PrivateLinkServices) > 0 {
+		md.WriteString("### Private Link Services\n\n")
+		md.WriteString("| Name | Load Balancer | Frontends | NAT IPs | Visibility | Auto-Approval | Connections |\n")
+		md.WriteString("|------|---------------|-----------|---------|------------|---------------|-------------|\n")
+		for _, pls := range topology.PrivateLinkServices {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				pls.Name, valueOrDash(extractName(pls.LoadBalancerID)), valueOrDash(joinNames(pls.LoadBalancerFrontendIDs)),
+				privateLinkServiceNATIPs(pls), privateLinkSubscriptions(pls.Visibility),
+				privateLinkSubscriptions(pls.AutoApproval), privateLinkConnections(pls)))
 		}
 		md.WriteString("\n")
 	}
 
+	// Private DNS Zones
+	if len(topology.PrivateDNSZones) > 0 {
+		md.WriteString("### Private DNS Zones\n\n")
+		for _, zone := range topology.PrivateDNSZones {
+			md.WriteString(fmt.Sprintf("#### %s\n", zone.Name))
+			md.WriteString(fmt.Sprintf("- **Record Sets:** %d\n", zone.RecordSets))
+			if len(zone.VNetLinks) > 0 {
+				links := make([]string, 0, len(zone.VNetLinks))
+				for _, link := range zone.VNetLinks {
+					label := link.VNetName
+					if link.RegistrationEnabled {
+						label += " (auto-registration)"
+					}
+					links = append(links, label)
+				}
+				md.WriteString(fmt.Sprintf("- **Linked VNets:** %s\n", strings.Join(links, ", ")))
+			} else {
+				md.WriteString("- **Linked VNets:** none\n")
+			}
+
+			if len(zone.ARecords) > 0 {
+				md.WriteString("\n| A Record | IP Addresses | TTL |\n")
+				md.WriteString("|----------|--------------|-----|\n")
+				for _, record := range zone.ARecords {
+					md.WriteString(fmt.Sprintf("| %s | %s | %d |\n",
+						record.Name, strings.Join(record.IPAddresses, ", "), record.TTL))
+				}
+			}
+			md.WriteString("\n")
+		}
+	}
+
 	// Load Balancers
 	if len(topology.LoadBalancers) > 0 {
 		md.WriteString("### Load Balancers\n\n")
@@ -217,7 +419,13 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 			md.WriteString(fmt.Sprintf("#### %s\n", lb.Name))
 			md.WriteString(fmt.Sprintf("- **SKU:** %s\n", lb.SKU))
 			md.WriteString(fmt.Sprintf("- **Frontend IPs:** %d\n", len(lb.FrontendIPConfigs)))
+			for _, fe := range lb.FrontendIPConfigs {
+				md.WriteString(fmt.Sprintf("  - %s: %s\n", fe.Name, frontendAddress(fe.PrivateIPAddress, fe.PublicIPAddressID, fe.PublicIPAddress)))
+			}
 			md.WriteString(fmt.Sprintf("- **Backend Pools:** %d\n", len(lb.BackendAddressPools)))
+			for _, pool := range lb.BackendAddressPools {
+				md.WriteString(fmt.Sprintf("  - %s: %s\n", pool.Name, formatWorkloads(workloadsByNIC(topology, pool.NetworkInterfaces))))
+			}
 			md.WriteString(fmt.Sprintf("- **Load Balancing Rules:** %d\n", len(lb.LoadBalancingRules)))
 			md.WriteString("\n")
 		}
@@ -230,12 +438,93 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 			md.WriteString(fmt.Sprintf("#### %s\n", appgw.Name))
 			md.WriteString(fmt.Sprintf("- **SKU:** %s (Capacity: %d)\n", appgw.SKU, appgw.Capacity))
 			md.WriteString(fmt.Sprintf("- **WAF Enabled:** %v\n", appgw.WAFEnabled))
+			md.WriteString(fmt.Sprintf("- **Frontend IPs:** %d\n", len(appgw.FrontendIPConfigs)))
+			for _, fe := range appgw.FrontendIPConfigs {
+				md.WriteString(fmt.Sprintf("  - %s: %s\n", fe.Name, frontendAddress(fe.PrivateIPAddress, fe.PublicIPAddressID, fe.PublicIPAddress)))
+			}
 			md.WriteString(fmt.Sprintf("- **HTTP Listeners:** %d\n", len(appgw.HTTPListeners)))
 			md.WriteString(fmt.Sprintf("- **Backend Pools:** %d\n", len(appgw.BackendAddressPools)))
 			md.WriteString("\n")
 		}
 	}
 
+	// Firewall Policies
+	if len(topology.FirewallPolicies) > 0 {
+		md.WriteString("### Firewall Policies\n\n")
+		for _, policy := range topology.FirewallPolicies {
+			md.WriteString(fmt.Sprintf("#### %s\n", policy.Name))
+			md.WriteString(fmt.Sprintf("- **SKU:** %s\n", valueOrDash(policy.SKU)))
+			md.WriteString(fmt.Sprintf("- **Threat Intelligence:** %s\n", valueOrDash(policy.ThreatIntelMode)))
+			if policy.BasePolicyID != "" {
+				md.WriteString(fmt.Sprintf("- **Inherits From:** %s\n", extractName(policy.BasePolicyID)))
+			}
+			md.WriteString(fmt.Sprintf("- **Used By:** %s\n", valueOrDash(joinNames(policy.Firewalls))))
+			md.WriteString("\n")
+
+			if len(policy.RuleCollectionGroups) > 0 {
+				md.WriteString("| Group | Collection | Type | Action | Priority | Rules |\n")
+				md.WriteString("|-------|------------|------|--------|----------|-------|\n")
+				for _, group := range policy.RuleCollectionGroups {
+					for _, collection := range group.RuleCollections {
+						md.WriteString(fmt.Sprintf("| %s (%d) | %s | %s | %s | %d | %d |\n",
+							group.Name, group.Priority, collection.Name, collection.CollectionType,
+							collection.Action, collection.Priority, len(collection.Rules)))
+					}
+				}
+				md.WriteString("\n")
+			}
+		}
+	}
+
+	// Bastion Hosts
+	if len(topology.BastionHosts) > 0 {
+		md.WriteString("### Bastion Hosts\n\n")
+		md.WriteString("| Name | SKU | VNet | Public IP | Features |\n")
+		md.WriteString("|------|-----|------|-----------|----------|\n")
+		for _, bastion := range topology.BastionHosts {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				bastion.Name, valueOrDash(bastion.SKU), valueOrDash(extractName(bastion.VNetID)),
+				frontendAddress("", bastion.PublicIPAddressID, bastion.PublicIPAddress), bastionFeatures(bastion)))
+		}
+		md.WriteString("\n")
+	}
+
+	// Remote access: which VNets a Bastion host can reach
+	if len(topology.BastionHosts) > 0 && len(analysis.BastionCoverage) > 0 {
+		md.WriteString("### Bastion Coverage\n\n")
+		md.WriteString("| VNet | VMs | Bastion | Access |\n")
+		md.WriteString("|------|-----|---------|--------|\n")
+		for _, c := range analysis.BastionCoverage {
+			md.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n",
+				c.VNet, c.VMs, valueOrDash(c.Bastion), bastionAccess(c)))
+		}
+		md.WriteString("\n")
+	}
+
+	// Virtual WAN hubs and how their connected VNets route traffic
+	if len(topology.VirtualHubs) > 0 {
+		md.WriteString("### Virtual WAN Hubs\n\n")
+		md.WriteString("| Hub | Virtual WAN | Address Prefix | Firewall | Routing Intent | Gateways | VNet Connections |\n")
+		md.WriteString("|-----|-------------|----------------|----------|----------------|----------|------------------|\n")
+		for _, hub := range topology.VirtualHubs {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %d |\n",
+				hub.Name, valueOrDash(extractName(hub.VirtualWANID)), valueOrDash(hub.AddressPrefix),
+				valueOrDash(extractName(hub.AzureFirewallID)), hubRoutingIntent(hub), hubGateways(hub), len(hub.VNetConnections)))
+		}
+		md.WriteString("\n")
+
+		if len(analysis.HubConnectivity) > 0 {
+			md.WriteString("### Hub Connectivity\n\n")
+			md.WriteString("| VNet | Hub | Internet Egress | Private Traffic | Reaches |\n")
+			md.WriteString("|------|-----|-----------------|-----------------|---------|\n")
+			for _, c := range analysis.HubConnectivity {
+				md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+					c.VNet, c.Hub, c.InternetEgress, c.PrivateTraffic, formatWorkloads(c.ReachableVNets)))
+			}
+			md.WriteString("\n")
+		}
+	}
+
 	// VPN Gateways
 	if len(topology.VPNGateways) > 0 {
 		md.WriteString("### VPN Gateways\n\n")
@@ -251,10 +540,49 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 		}
 	}
 
+	// On-Premises Networks
+	if len(topology.LocalNetworkGateways) > 0 {
+		md.WriteString("### On-Premises Networks\n\n")
+		md.WriteString("| Local Network Gateway | Endpoint | Address Prefixes | BGP | Connections |\n")
+		md.WriteString("|-----------------------|----------|------------------|-----|-------------|\n")
+		for _, lng := range topology.LocalNetworkGateways {
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				lng.Name, localNetworkEndpoint(lng), valueOrDash(strings.Join(lng.AddressPrefixes, ", ")),
+				localNetworkBGP(lng), valueOrDash(joinNames(lng.Connections))))
+		}
+		md.WriteString("\n")
+	}
+
+	// ExpressRoute
+	if len(topology.ERCircuits) > 0 {
+		md.WriteString("### ExpressRoute Circuits\n\n")
+		md.WriteString("| Name | Provider | Bandwidth | SKU | Gateways | Global Reach | Traffic |\n")
+		md.WriteString("|------|----------|-----------|-----|----------|--------------|---------|\n")
+		for _, er := range topology.ERCircuits {
+			md.WriteString(fmt.Sprintf("| %s | %s | %d Mbps | %s | %s | %s | %s |\n",
+				er.Name, expressRouteProvider(er), er.BandwidthInMbps, valueOrDash(strings.TrimSpace(er.SKUTier+" "+er.SKUFamily)),
+				expressRouteGateways(topology, er), expressRouteGlobalReach(er), expressRouteTraffic(er.Stats)))
+		}
+		md.WriteString("\n")
+
+		md.WriteString("**Peerings:**\n\n")
+		md.WriteString("| Circuit | Peering | State | Peer ASN | VLAN | Primary / Secondary | Route Filter |\n")
+		md.WriteString("|---------|---------|-------|----------|------|---------------------|--------------|\n")
+		for _, er := range topology.ERCircuits {
+			for _, p := range er.Peerings {
+				md.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d | %s / %s | %s |\n",
+					er.Name, p.PeeringType, valueOrDash(p.State), p.PeerASN, p.VlanID,
+					valueOrDash(p.PrimaryPeerAddressPrefix), valueOrDash(p.SecondaryPeerAddressPrefix), routeFilterSummary(p)))
+			}
+		}
+		md.WriteString("\n")
+	}
+
 	// Orphaned Resources
 	hasOrphaned := len(analysis.OrphanedResources.UnattachedNSGs) > 0 ||
 		len(analysis.OrphanedResources.UnusedRouteTables) > 0 ||
-		len(analysis.OrphanedResources.IsolatedSubnets) > 0
+		len(analysis.OrphanedResources.IsolatedSubnets) > 0 ||
+		len(analysis.OrphanedResources.UnassociatedPublicIPs) > 0
 
 	if hasOrphaned {
 		md.WriteString("## Orphaned/Unused Resources\n\n")
@@ -279,6 +607,27 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 			}
 			md.WriteString("\n")
 		}
+		if len(analysis.OrphanedResources.UnassociatedPublicIPs) > 0 {
+			md.WriteString("### Unassociated Public IPs\n")
+			for _, pip := range analysis.OrphanedResources.UnassociatedPublicIPs {
+				md.WriteString(fmt.Sprintf("- %s\n", pip))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	// Resources grouped by tag
+	if opts.GroupByTag != "" {
+		md.WriteString(fmt.Sprintf("## Resources by Tag: %s\n\n", opts.GroupByTag))
+		for _, group := range groupByTag(topology, opts.GroupByTag) {
+			md.WriteString(fmt.Sprintf("### %s (%d)\n\n", valueOrDash(group.Value), len(group.Resources)))
+			md.WriteString("| Type | Name | Resource Group |\n")
+			md.WriteString("|------|------|----------------|\n")
+			for _, r := range group.Resources {
+				md.WriteString(fmt.Sprintf("| %s | %s | %s |\n", r.Type, r.Name, valueOrDash(r.ResourceGroup)))
+			}
+			md.WriteString("\n")
+		}
 	}
 
 	// Footer
@@ -290,6 +639,339 @@ func GenerateMarkdown(topology *models.NetworkTopology, analysis *analyzer.Analy
 
 // Helper functions
 
+// flowLogStatus describes the flow log state of an NSG
+func flowLogStatus(c analyzer.NSGFlowLogCoverage) string {
+	switch {
+	case c.FlowLog == "":
+		return "Not configured"
+	case c.Enabled:
+		return "Enabled"
+	default:
+		return "Disabled"
+	}
+}
+
+// flowLogRetention formats the flow log retention period
+func flowLogRetention(c analyzer.NSGFlowLogCoverage) string {
+	if c.FlowLog == "" {
+		return "-"
+	}
+	if c.RetentionDays == 0 {
+		return "Not enforced"
+	}
+	return fmt.Sprintf("%d days", c.RetentionDays)
+}
+
+// publicIPAssociation names the resource a public IP is attached to
+func publicIPAssociation(pip models.PublicIPAddress) string {
+	if pip.AssociatedResourceID == "" {
+		return "Unassociated"
+	}
+	return extractName(pip.AssociatedResourceID)
+}
+
+// frontendAddress describes a frontend IP configuration by its address. Public IPs
+// that could not be resolved are shown by resource name.
+func frontendAddress(privateIP, publicIPID, publicIP string) string {
+	switch {
+	case publicIP != "":
+		return publicIP + " (public)"
+	case publicIPID != "":
+		return extractName(publicIPID) + " (public)"
+	case privateIP != "":
+		return privateIP + " (private)"
+	default:
+		return "-"
+	}
+}
+
+// bastionFeatures lists the optional features enabled on a Bastion host
+func bastionFeatures(bastion models.BastionHost) string {
+	var features []string
+	if bastion.IPConnect {
+		features = append(features, "IP connect")
+	}
+	if bastion.Tunneling {
+		features = append(features, "Native client tunneling")
+	}
+	if bastion.ShareableLink {
+		features = append(features, "Shareable links")
+	}
+	if bastion.FileCopy {
+		features = append(features, "File copy")
+	}
+	return valueOrDash(strings.Join(features, ", "))
+}
+
+// bastionAccess describes how a VNet is reached by its Bastion host
+func bastionAccess(c analyzer.VNetBastionCoverage) string {
+	switch {
+	case c.Bastion == "":
+		return "No Bastion"
+	case c.Via != "":
+		return "Peering with " + c.Via
+	default:
+		return "Direct"
+	}
+}
+
+// peeringRemote names the remote side of a peering; peerings created by a hub
+// connection are named after the hub rather than its managed VNet
+func peeringRemote(peer models.VNetPeering) string {
+	if peer.RemoteVirtualHubID != "" {
+		return extractName(peer.RemoteVirtualHubID) + " (virtual hub)"
+	}
+	return peer.RemoteVNetName
+}
+
+// hubRoutingIntent describes which traffic a hub's routing intent sends where,
+// e.g. "Internet, PrivateTraffic → fw-vhub"
+func hubRoutingIntent(hub models.VirtualHub) string {
+	if hub.RoutingIntent == nil {
+		return "-"
+	}
+	var policies []string
+	for _, policy := range hub.RoutingIntent.RoutingPolicies {
+		policies = append(policies, fmt.Sprintf("%s → %s", strings.Join(policy.Destinations, ", "), extractName(policy.NextHop)))
+	}
+	return valueOrDash(strings.Join(policies, "; "))
+}
+
+// hubGateways lists the gateways deployed in a hub with their connection counts
+func hubGateways(hub models.VirtualHub) string {
+	var gateways []string
+	for _, gw := range hub.Gateways {
+		gateways = append(gateways, fmt.Sprintf("%s (%s, %d connections)", gw.Name, gw.Type, len(gw.Connections)))
+	}
+	return valueOrDash(strings.Join(gateways, ", "))
+}
+
+// localNetworkEndpoint returns the public IP or FQDN of an on-premises VPN device
+func localNetworkEndpoint(lng models.LocalNetworkGateway) string {
+	if lng.GatewayIPAddress != "" {
+		return lng.GatewayIPAddress
+	}
+	return valueOrDash(lng.FQDN)
+}
+
+// localNetworkBGP describes the BGP settings of an on-premises VPN device
+func localNetworkBGP(lng models.LocalNetworkGateway) string {
+	if lng.BGPSettings == nil {
+		return "-"
+	}
+	if lng.BGPSettings.BGPPeeringAddress == "" {
+		return fmt.Sprintf("ASN %d", lng.BGPSettings.ASN)
+	}
+	return fmt.Sprintf("ASN %d, peer %s", lng.BGPSettings.ASN, lng.BGPSettings.BGPPeeringAddress)
+}
+
+// natGatewayPublicIPs lists a NAT gateway's public IPs by address where they
+// were resolved, and by name otherwise
+func natGatewayPublicIPs(nat models.NATGateway) string {
+	if len(nat.PublicIPs) > 0 {
+		return strings.Join(nat.PublicIPs, ", ")
+	}
+	return valueOrDash(joinNames(nat.PublicIPAddresses))
+}
+
+// joinSubnetNames joins subnet IDs as vnet/subnet names
+func joinSubnetNames(subnetIDs []string) string {
+	names := make([]string, 0, len(subnetIDs))
+	for _, id := range subnetIDs {
+		parts := strings.Split(id, "/")
+		if len(parts) >= 3 {
+			names = append(names, parts[len(parts)-3]+"/"+parts[len(parts)-1])
+		} else {
+			names = append(names, extractName(id))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// expressRouteProvider describes where a circuit is provisioned
+func expressRouteProvider(er models.ExpressRouteCircuit) string {
+	if er.PeeringLocation == "" {
+		return valueOrDash(er.ServiceProviderName)
+	}
+	return fmt.Sprintf("%s @ %s", valueOrDash(er.ServiceProviderName), er.PeeringLocation)
+}
+
+// expressRouteGateways lists the virtual network gateways connected to a circuit,
+// with the connection's name, status and whether FastPath is on
+func expressRouteGateways(topology *models.NetworkTopology, er models.ExpressRouteCircuit) string {
+	var gateways []string
+	for _, gw := range topology.VPNGateways {
+		for _, conn := range gw.Connections {
+			if conn.CircuitID == "" || !strings.EqualFold(conn.CircuitID, er.ID) {
+				continue
+			}
+			details := []string{conn.Name, valueOrDash(conn.ConnectionStatus)}
+			if conn.FastPath {
+				details = append(details, "FastPath")
+			}
+			gateways = append(gateways, fmt.Sprintf("%s (%s)", gw.Name, strings.Join(details, ", ")))
+		}
+	}
+	return valueOrDash(strings.Join(gateways, "; "))
+}
+
+// expressRouteGlobalReach lists the circuits a circuit is linked to by Global Reach
+func expressRouteGlobalReach(er models.ExpressRouteCircuit) string {
+	var peers []string
+	for _, p := range er.Peerings {
+		for _, gr := range p.GlobalReachConnections {
+			peers = append(peers, fmt.Sprintf("%s (%s)", extractName(gr.PeerCircuitID), valueOrDash(gr.Status)))
+		}
+	}
+	if len(peers) == 0 && er.GlobalReachEnabled {
+		return "Enabled"
+	}
+	return valueOrDash(strings.Join(peers, ", "))
+}
+
+// expressRouteTraffic summarizes the bytes carried by a circuit's links
+func expressRouteTraffic(stats *models.ERCircuitStats) string {
+	if stats == nil {
+		return "-"
+	}
+	return fmt.Sprintf("in %s / out %s",
+		formatBytes(stats.PrimaryBytesIn+stats.SecondaryBytesIn), formatBytes(stats.PrimaryBytesOut+stats.SecondaryBytesOut))
+}
+
+// routeFilterSummary describes the route filter of a Microsoft peering
+func routeFilterSummary(p models.ERPeering) string {
+	if p.RouteFilter == nil {
+		return valueOrDash(extractName(p.RouteFilterID))
+	}
+	rules := make([]string, 0, len(p.RouteFilter.Rules))
+	for _, rule := range p.RouteFilter.Rules {
+		rules = append(rules, rule.Access+" "+strings.Join(rule.Communities, ", "))
+	}
+	if len(rules) == 0 {
+		return p.RouteFilter.Name + " (no rules)"
+	}
+	return fmt.Sprintf("%s (%s)", p.RouteFilter.Name, strings.Join(rules, "; "))
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// privateLinkServiceNATIPs lists the NAT IPs consumer traffic is sourced from
+func privateLinkServiceNATIPs(pls models.PrivateLinkService) string {
+	ips := make([]string, 0, len(pls.IPConfigurations))
+	for _, ipConfig := range pls.IPConfigurations {
+		ip := ipConfig.PrivateIPAddress
+		if ip == "" {
+			ip = ipConfig.Name + " (dynamic)"
+		}
+		ips = append(ips, ip)
+	}
+	return valueOrDash(strings.Join(ips, ", "))
+}
+
+// privateLinkSubscriptions describes a visibility or auto-approval subscription list
+func privateLinkSubscriptions(subscriptions []string) string {
+	for _, s := range subscriptions {
+		if s == "*" {
+			return "all subscriptions"
+		}
+	}
+	return valueOrDash(strings.Join(subscriptions, ", "))
+}
+
+// privateLinkConnections lists the consumer private endpoints connected to a
+// private link service with their connection status
+func privateLinkConnections(pls models.PrivateLinkService) string {
+	connections := make([]string, 0, len(pls.Connections))
+	for _, conn := range pls.Connections {
+		name := extractName(conn.PrivateEndpointID)
+		if name == "" {
+			name = conn.Name
+		}
+		connections = append(connections, fmt.Sprintf("%s (%s)", name, valueOrDash(conn.Status)))
+	}
+	return valueOrDash(strings.Join(connections, ", "))
+}
+
+// collectionErrorSummary says how many resource types could not be collected,
+// e.g. "2 resource types could not be collected (NSGs, route tables)"
+func collectionErrorSummary(errs []models.CollectionError) string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, e := range errs {
+		if !seen[e.ResourceType] {
+			seen[e.ResourceType] = true
+			types = append(types, e.ResourceType)
+		}
+	}
+	noun := "resource types"
+	if len(types) == 1 {
+		noun = "resource type"
+	}
+	return fmt.Sprintf("%d %s could not be collected (%s)", len(types), noun, strings.Join(types, ", "))
+}
+
+// collectionErrorAction suggests how to fix a collection error of the given class
+func collectionErrorAction(class string) string {
+	switch class {
+	case models.ErrorClassAuthorization:
+		return "Grant the Reader role on the scope"
+	case models.ErrorClassThrottled:
+		return "Re-run later or lower --concurrency"
+	case models.ErrorClassNotFound:
+		return "Check the scope exists and the resource provider is registered"
+	case models.ErrorClassTransient:
+		return "Re-run the analysis"
+	default:
+		return "-"
+	}
+}
+
+// ruleName names a security rule, marking the built-in default rules
+func ruleName(rule models.SecurityRule) string {
+	if rule.IsDefault {
+		return rule.Name + " (default)"
+	}
+	return rule.Name
+}
+
+// ruleEndpoints describes one side of a security rule: its address prefixes
+// followed by the application security groups it references
+func ruleEndpoints(prefixes, asgIDs []string) string {
+	endpoints := append([]string{}, prefixes...)
+	for _, id := range asgIDs {
+		endpoints = append(endpoints, extractName(id)+" (ASG)")
+	}
+	return valueOrDash(strings.Join(endpoints, ", "))
+}
+
+// joinNames joins the names of the given resource IDs
+func joinNames(resourceIDs []string) string {
+	names := make([]string, 0, len(resourceIDs))
+	for _, id := range resourceIDs {
+		names = append(names, extractName(id))
+	}
+	return strings.Join(names, ", ")
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func countBySeverity(findings []analyzer.SecurityFinding) (critical, high, medium, low int) {
 	for _, f := range findings {
 		switch f.Severity {
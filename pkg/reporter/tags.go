@@ -0,0 +1,123 @@
+package reporter
+
+import (
+	"sort"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// untaggedGroup names the group of resources that do not carry the grouping tag
+const untaggedGroup = "(untagged)"
+
+// taggedResource is a top-level resource listed when grouping a report by tag
+type taggedResource struct {
+	Type          string
+	Name          string
+	ResourceGroup string
+	Tags          map[string]string
+}
+
+// tagGroup holds the resources sharing one value of the grouping tag
+type tagGroup struct {
+	Value     string
+	Resources []taggedResource
+}
+
+// taggedResources lists the top-level resources of the topology with their tags
+func taggedResources(topology *models.NetworkTopology) []taggedResource {
+	var resources []taggedResource
+	add := func(resourceType, name, resourceGroup string, tags map[string]string) {
+		resources = append(resources, taggedResource{Type: resourceType, Name: name, ResourceGroup: resourceGroup, Tags: tags})
+	}
+
+	for _, r := range topology.VirtualNetworks {
+		add("Virtual Network", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.NSGs {
+		add("Network Security Group", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.ASGs {
+		add("Application Security Group", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.PrivateEndpoints {
+		add("Private Endpoint", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.PrivateLinkServices {
+		add("Private Link Service", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.NetworkInterfaces {
+		add("Network Interface", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.PublicIPAddresses {
+		add("Public IP Address", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.PrivateDNSZones {
+		add("Private DNS Zone", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.RouteTables {
+		add("Route Table", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.NATGateways {
+		add("NAT Gateway", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.VPNGateways {
+		add("VPN Gateway", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.LocalNetworkGateways {
+		add("Local Network Gateway", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.ERCircuits {
+		add("ExpressRoute Circuit", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.LoadBalancers {
+		add("Load Balancer", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.AppGateways {
+		add("Application Gateway", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.AzureFirewalls {
+		add("Azure Firewall", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.FirewallPolicies {
+		add("Firewall Policy", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.BastionHosts {
+		add("Bastion Host", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.VirtualWANs {
+		add("Virtual WAN", r.Name, r.ResourceGroup, r.Tags)
+	}
+	for _, r := range topology.VirtualHubs {
+		add("Virtual Hub", r.Name, r.ResourceGroup, r.Tags)
+	}
+	return resources
+}
+
+// groupByTag groups the topology's resources by the value of the tag key, sorted
+// by value, with resources that do not carry the tag grouped last
+func groupByTag(topology *models.NetworkTopology, key string) []tagGroup {
+	byValue := make(map[string][]taggedResource)
+	var untagged []taggedResource
+	for _, r := range taggedResources(topology) {
+		if value, ok := models.TagValue(r.Tags, key); ok {
+			byValue[value] = append(byValue[value], r)
+		} else {
+			untagged = append(untagged, r)
+		}
+	}
+
+	values := make([]string, 0, len(byValue))
+	for value := range byValue {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	groups := make([]tagGroup, 0, len(values)+1)
+	for _, value := range values {
+		groups = append(groups, tagGroup{Value: value, Resources: byValue[value]})
+	}
+	if len(untagged) > 0 {
+		groups = append(groups, tagGroup{Value: untaggedGroup, Resources: untagged})
+	}
+	return groups
+}
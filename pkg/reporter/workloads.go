@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"azure-network-analyzer/pkg/models"
+)
+
+// maxListedWorkloads caps how many workloads are named inline before the rest are counted
+const maxListedWorkloads = 5
+
+// workloadName describes what runs behind a network interface: the attached VM,
+// the owning private endpoint, or the NIC itself
+func workloadName(nic models.NetworkInterface) string {
+	switch {
+	case nic.VirtualMachineID != "":
+		return extractName(nic.VirtualMachineID)
+	case nic.PrivateEndpointID != "":
+		return extractName(nic.PrivateEndpointID) + " (private endpoint)"
+	default:
+		return nic.Name
+	}
+}
+
+// workloadsByNIC names the workloads behind the given NIC IDs. NICs that were not
+// collected (e.g. outside the analyzed scope) are named after the NIC.
+func workloadsByNIC(topology *models.NetworkTopology, nicIDs []string) []string {
+	nics := make(map[string]models.NetworkInterface, len(topology.NetworkInterfaces))
+	for _, nic := range topology.NetworkInterfaces {
+		nics[strings.ToLower(nic.ID)] = nic
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, id := range nicIDs {
+		key := strings.ToLower(id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if nic, ok := nics[key]; ok {
+			names = append(names, workloadName(nic))
+		} else {
+			names = append(names, extractName(id))
+		}
+	}
+	return names
+}
+
+// subnetNICs returns the IDs of the NICs with an IP configuration in the subnet
+func subnetNICs(topology *models.NetworkTopology, subnet models.Subnet) []string {
+	ids := append([]string{}, subnet.NetworkInterfaces...)
+	for _, nic := range topology.NetworkInterfaces {
+		for _, ipConfig := range nic.IPConfigurations {
+			if strings.EqualFold(ipConfig.SubnetID, subnet.ID) {
+				ids = append(ids, nic.ID)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// nsgNICs returns the IDs of the NICs an NSG protects, either directly or
+// through one of its subnets
+func nsgNICs(topology *models.NetworkTopology, nsg models.NetworkSecurityGroup) []string {
+	ids := append([]string{}, nsg.Associations.NetworkInterfaces...)
+	for _, nic := range topology.NetworkInterfaces {
+		if strings.EqualFold(nic.NetworkSecurityGroupID, nsg.ID) {
+			ids = append(ids, nic.ID)
+		}
+	}
+
+	for _, vnet := range topology.VirtualNetworks {
+		for _, subnet := range vnet.Subnets {
+			if subnet.NetworkSecurityGroup != nil && strings.EqualFold(*subnet.NetworkSecurityGroup, nsg.ID) {
+				ids = append(ids, subnetNICs(topology, subnet)...)
+			}
+		}
+	}
+	return ids
+}
+
+// asgNICs returns the IDs of the NICs with an IP configuration in the ASG
+func asgNICs(topology *models.NetworkTopology, asg models.ApplicationSecurityGroup) []string {
+	var ids []string
+	for _, nic := range topology.NetworkInterfaces {
+		for _, ipConfig := range nic.IPConfigurations {
+			if containsFold(ipConfig.ApplicationSecurityGroups, asg.ID) {
+				ids = append(ids, nic.ID)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// asgRules names the NSG rules that reference the ASG, as "nsg/rule"
+func asgRules(topology *models.NetworkTopology, asg models.ApplicationSecurityGroup) []string {
+	var rules []string
+	for _, nsg := range topology.NSGs {
+		for _, rule := range nsg.SecurityRules {
+			if containsFold(rule.SourceASGs, asg.ID) || containsFold(rule.DestinationASGs, asg.ID) {
+				rules = append(rules, nsg.Name+"/"+rule.Name)
+			}
+		}
+	}
+	return rules
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatWorkloads joins workload names, summarizing long lists
+func formatWorkloads(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	if len(names) > maxListedWorkloads {
+		return fmt.Sprintf("%s (+%d more)", strings.Join(names[:maxListedWorkloads], ", "), len(names)-maxListedWorkloads)
+	}
+	return strings.Join(names, ", ")
+}
@@ -44,9 +44,13 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	dot.WriteString(fmt.Sprintf("  label=\"Azure Network Topology\\n%s / %s\";\n\n",
 		topology.SubscriptionID, topology.ResourceGroup))
 
-	// Track nodes for connections
+	// Track nodes for connections. VNets are keyed by lower-cased ID because
+	// peerings, particularly across subscriptions, do not always preserve the
+	// casing of the remote VNet's resource ID.
 	subnetNodes := make(map[string]string)
 	vnetNodes := make(map[string]string)
+	multiSubscription := spansSubscriptions(topology)
+	workloads := subnetWorkloads(topology)
 
 	// Deduplicate NAT Gateways, NSGs, and Route Tables
 	natGateways := make(map[string]string) // resource ID -> node ID
@@ -56,10 +60,15 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	// Create clusters for each VNet
 	for i, vnet := range topology.VirtualNetworks {
 		vnetNodeID := fmt.Sprintf("vnet_%d", i)
-		vnetNodes[vnet.ID] = vnetNodeID
+		vnetNodes[strings.ToLower(vnet.ID)] = vnetNodeID
 
-		dot.WriteString(fmt.Sprintf("  subgraph cluster_%s {\n", sanitizeName(vnet.Name)))
-		dot.WriteString(fmt.Sprintf("    label=\"VNet: %s\\n%s\";\n", vnet.Name, strings.Join(vnet.AddressSpace, "\\n")))
+		// VNet names are only unique within a resource group, so the index keeps clusters distinct
+		dot.WriteString(fmt.Sprintf("  subgraph cluster_%d_%s {\n", i, sanitizeName(vnet.Name)))
+		vnetLabel := fmt.Sprintf("VNet: %s\\n%s", vnet.Name, strings.Join(vnet.AddressSpace, "\\n"))
+		if multiSubscription && vnet.SubscriptionID != "" {
+			vnetLabel += fmt.Sprintf("\\nSubscription: %s", vnet.SubscriptionID)
+		}
+		dot.WriteString(fmt.Sprintf("    label=\"%s\";\n", vnetLabel))
 		dot.WriteString("    style=filled;\n")
 		dot.WriteString("    color=lightblue;\n")
 		dot.WriteString("    fillcolor=\"#e6f3ff\";\n")
@@ -81,7 +90,11 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 				color = "#FFB6C1" // Light pink - no NSG (warning)
 			}
 
-			dot.WriteString(fmt.Sprintf("    %s [label=\"%s\\n%s\"", subnetNodeID, subnet.Name, subnet.AddressPrefix))
+			subnetLabel := fmt.Sprintf("%s\\n%s", subnet.Name, subnet.AddressPrefix)
+			if names := workloads[strings.ToLower(subnet.ID)]; len(names) > 0 {
+				subnetLabel += "\\n" + summarizeNames(names, 3)
+			}
+			dot.WriteString(fmt.Sprintf("    %s [label=\"%s\"", subnetNodeID, subnetLabel))
 			dot.WriteString(fmt.Sprintf(", fillcolor=\"%s\"", color))
 			dot.WriteString(", shape=box];\n")
 
@@ -114,10 +127,14 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	}
 
 	// Render deduplicated NSGs (outside clusters)
+	internetPorts := nsgInternetPorts(topology)
 	for nsgID, nsgNodeID := range nsgs {
-		nsgName := extractResourceName(nsgID)
-		dot.WriteString(fmt.Sprintf("  %s [label=\"NSG\\n%s\", fillcolor=\"#FFE4B5\", shape=octagon];\n",
-			nsgNodeID, nsgName))
+		label := "NSG\\n" + extractResourceName(nsgID)
+		if ports := internetPorts[strings.ToLower(nsgID)]; len(ports) > 0 {
+			label += "\\nInternet: " + summarizeNames(ports, 5)
+		}
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#FFE4B5\", shape=octagon];\n",
+			nsgNodeID, label))
 	}
 
 	// Ensure ALL route tables are in the map (including orphaned ones)
@@ -174,12 +191,16 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	// Add VNet peering edges (outside clusters)
 	for _, vnet := range topology.VirtualNetworks {
 		for _, peering := range vnet.Peerings {
-			fromNode := vnetNodes[vnet.ID]
+			// Peerings with a hub's managed VNet are drawn as hub connections
+			if peering.RemoteVirtualHubID != "" {
+				continue
+			}
+			fromNode := vnetNodes[strings.ToLower(vnet.ID)]
 			// Create a node for remote VNet if not in this topology
 			toNode := fmt.Sprintf("remote_%s", sanitizeName(peering.RemoteVNetName))
 
 			// Check if remote VNet is in our topology
-			if remoteID, exists := vnetNodes[peering.RemoteVNetID]; exists {
+			if remoteID, exists := vnetNodes[strings.ToLower(peering.RemoteVNetID)]; exists {
 				toNode = remoteID
 			} else {
 				// Create external VNet node
@@ -198,6 +219,7 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	}
 
 	// Add Load Balancers - grouped for efficient layout
+	lbNodes := make(map[string]string) // lower-cased load balancer ID -> node ID
 	if len(topology.LoadBalancers) > 0 {
 		dot.WriteString("\n  // Load Balancers (grouped for efficient placement)\n")
 
@@ -207,6 +229,7 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 			dot.WriteString("  { rank=same;\n")
 			for i, lb := range topology.LoadBalancers {
 				lbNodeID := fmt.Sprintf("lb_%d", i)
+				lbNodes[strings.ToLower(lb.ID)] = lbNodeID
 				dot.WriteString(fmt.Sprintf("    %s [label=\"LB\\n%s\\n%s\", fillcolor=\"#FFA500\", shape=ellipse];\n",
 					lbNodeID, lb.Name, lb.SKU))
 			}
@@ -215,6 +238,7 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 			// Many load balancers - distribute across multiple ranks for vertical stacking
 			for i, lb := range topology.LoadBalancers {
 				lbNodeID := fmt.Sprintf("lb_%d", i)
+				lbNodes[strings.ToLower(lb.ID)] = lbNodeID
 				dot.WriteString(fmt.Sprintf("  %s [label=\"LB\\n%s\\n%s\", fillcolor=\"#FFA500\", shape=ellipse];\n",
 					lbNodeID, lb.Name, lb.SKU))
 			}
@@ -225,6 +249,34 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 		}
 	}
 
+	// Add Private Link Services next to the internal load balancer they publish
+	for i, pls := range topology.PrivateLinkServices {
+		plsNodeID := fmt.Sprintf("pls_%d", i)
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#DA70D6\", shape=component];\n",
+			plsNodeID, privateLinkServiceLabel(pls)))
+
+		if lbNode, exists := lbNodes[strings.ToLower(pls.LoadBalancerID)]; exists {
+			frontends := make([]string, 0, len(pls.LoadBalancerFrontendIDs))
+			for _, id := range pls.LoadBalancerFrontendIDs {
+				frontends = append(frontends, extractResourceName(id))
+			}
+			dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=\"#DA70D6\", label=\"frontend\\n%s\"];\n",
+				plsNodeID, lbNode, strings.Join(frontends, ", ")))
+		}
+
+		// Consumer traffic is source-NATed to IPs in the service's subnet
+		linked := make(map[string]bool)
+		for _, ipConfig := range pls.IPConfigurations {
+			subnetNode, exists := subnetNodes[ipConfig.SubnetID]
+			if !exists || linked[subnetNode] {
+				continue
+			}
+			linked[subnetNode] = true
+			dot.WriteString(fmt.Sprintf("  %s -> %s [style=dashed, color=\"#DA70D6\", label=\"NAT IPs\"];\n",
+				plsNodeID, subnetNode))
+		}
+	}
+
 	// Add Application Gateways - grouped for efficient layout
 	if len(topology.AppGateways) > 0 {
 		dot.WriteString("\n  // Application Gateways (grouped for efficient placement)\n")
@@ -263,16 +315,118 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	// Add VPN Gateways
 	for i, vpn := range topology.VPNGateways {
 		vpnNodeID := fmt.Sprintf("vpn_%d", i)
-		dot.WriteString(fmt.Sprintf("  %s [label=\"VPN GW\\n%s\\n%s\", fillcolor=\"#9370DB\", shape=diamond];\n",
-			vpnNodeID, vpn.Name, vpn.SKU))
+		kind := "VPN GW"
+		if strings.EqualFold(vpn.GatewayType, "ExpressRoute") {
+			kind = "ER GW"
+		}
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\\n%s\\n%s\", fillcolor=\"#9370DB\", shape=diamond];\n",
+			vpnNodeID, kind, vpn.Name, vpn.SKU))
 
 		// Connect to VNet
-		if vnetNode, exists := vnetNodes[vpn.VNetID]; exists {
+		if vnetNode, exists := vnetNodes[strings.ToLower(vpn.VNetID)]; exists {
 			dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=purple, label=\"gateway\"];\n",
 				vpnNodeID, vnetNode))
 		}
 	}
 
+	// Add on-premises sites behind local network gateways, connected to the VPN
+	// gateways that have a connection to them
+	for i, lng := range topology.LocalNetworkGateways {
+		onpremNodeID := fmt.Sprintf("onprem_%d", i)
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#B0C4DE\", shape=cloud];\n",
+			onpremNodeID, localNetworkLabel(lng)))
+
+		for j, vpn := range topology.VPNGateways {
+			for _, conn := range vpn.Connections {
+				if !strings.EqualFold(conn.RemoteEntityID, lng.ID) {
+					continue
+				}
+				style := "bold"
+				if !strings.EqualFold(conn.ConnectionStatus, "Connected") {
+					style = "dashed"
+				}
+				dot.WriteString(fmt.Sprintf("  vpn_%d -> %s [style=%s, color=purple, label=\"%s\\n%s\", dir=both];\n",
+					j, onpremNodeID, style, conn.Name, conn.ConnectionStatus))
+			}
+		}
+	}
+
+	// Add ExpressRoute circuits between the on-premises network behind them and
+	// the gateways whose connections use them
+	erNodes := make(map[string]string)
+	for i, er := range topology.ERCircuits {
+		erNodeID := fmt.Sprintf("er_%d", i)
+		erNodes[strings.ToLower(er.ID)] = erNodeID
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#8FBC8F\", shape=hexagon];\n",
+			erNodeID, expressRouteLabel(er)))
+
+		onpremNodeID := fmt.Sprintf("er_onprem_%d", i)
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#B0C4DE\", shape=cloud];\n",
+			onpremNodeID, expressRouteOnPremisesLabel(er)))
+		dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=\"#2E8B57\", label=\"%s\", dir=both];\n",
+			onpremNodeID, erNodeID, expressRoutePeeringLabel(er)))
+
+		for j, vpn := range topology.VPNGateways {
+			for _, conn := range vpn.Connections {
+				if conn.CircuitID == "" || !strings.EqualFold(conn.CircuitID, er.ID) {
+					continue
+				}
+				style := "bold"
+				if !strings.EqualFold(conn.ConnectionStatus, "Connected") {
+					style = "dashed"
+				}
+				label := conn.Name + "\\n" + conn.ConnectionStatus
+				if conn.FastPath {
+					label += "\\nFastPath"
+				}
+				dot.WriteString(fmt.Sprintf("  %s -> vpn_%d [style=%s, color=\"#2E8B57\", label=\"%s\", dir=both];\n",
+					erNodeID, j, style, label))
+			}
+		}
+	}
+
+	// Global Reach links collected circuits; each pair is drawn once although
+	// both circuits list the link
+	drawnGlobalReach := make(map[string]bool)
+	for i, er := range topology.ERCircuits {
+		for _, p := range er.Peerings {
+			for _, gr := range p.GlobalReachConnections {
+				peerNode, exists := erNodes[strings.ToLower(gr.PeerCircuitID)]
+				if !exists {
+					continue
+				}
+				from, to := fmt.Sprintf("er_%d", i), peerNode
+				if from > to {
+					from, to = to, from
+				}
+				if drawnGlobalReach[from+"|"+to] {
+					continue
+				}
+				drawnGlobalReach[from+"|"+to] = true
+				dot.WriteString(fmt.Sprintf("  %s -> %s [style=dashed, color=\"#2E8B57\", label=\"Global Reach\", dir=both];\n",
+					from, to))
+			}
+		}
+	}
+
+	// Add Bastion hosts, connected to their subnet and to the peered VNets they can reach
+	for i, bastion := range topology.BastionHosts {
+		bastionNodeID := fmt.Sprintf("bastion_%d", i)
+		dot.WriteString(fmt.Sprintf("  %s [label=\"Bastion\\n%s\\n%s\", fillcolor=\"#20B2AA\", shape=house];\n",
+			bastionNodeID, bastion.Name, bastion.SKU))
+
+		if subnetNode, exists := subnetNodes[bastion.SubnetID]; exists {
+			dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=\"#20B2AA\", label=\"hosted in\"];\n",
+				bastionNodeID, subnetNode))
+		}
+		for _, vnetID := range bastionPeeredVNets(topology, bastion) {
+			if vnetNode, exists := vnetNodes[vnetID]; exists {
+				dot.WriteString(fmt.Sprintf("  %s -> %s [style=dashed, color=\"#20B2AA\", label=\"remote access\"];\n",
+					bastionNodeID, vnetNode))
+			}
+		}
+	}
+
 	// Add Azure Firewalls - grouped for efficient layout
 	firewallNodes := make(map[string]string) // firewall ID -> node ID
 	if len(topology.AzureFirewalls) > 0 {
@@ -351,7 +505,7 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 		// Check if any firewalls have public IPs (Internet egress capability)
 		hasInternetEgress := false
 		for _, fw := range topology.AzureFirewalls {
-			if len(fw.PublicIPAddresses) > 0 {
+			if firewallPublicIPCount(fw) > 0 {
 				hasInternetEgress = true
 				break
 			}
@@ -364,14 +518,17 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 
 			// Connect firewalls with public IPs to Internet
 			for _, fw := range topology.AzureFirewalls {
-				if len(fw.PublicIPAddresses) > 0 {
+				if firewallPublicIPCount(fw) > 0 {
 					fwNode, fwExists := firewallNodes[fw.ID]
 					if fwExists {
-						publicIPCount := len(fw.PublicIPAddresses)
+						publicIPCount := firewallPublicIPCount(fw)
 						label := "Public IP egress"
 						if publicIPCount > 1 {
 							label = fmt.Sprintf("%d Public IPs\\nInternet egress", publicIPCount)
 						}
+						if len(fw.PublicIPs) > 0 {
+							label += "\\n" + summarizeNames(fw.PublicIPs, 3)
+						}
 						dot.WriteString(fmt.Sprintf("  %s -> internet [style=bold, color=\"#4169E1\", penwidth=2.0, label=\"%s\"];\n",
 							fwNode, label))
 					}
@@ -381,6 +538,44 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 		dot.WriteString("\n")
 	}
 
+	// Add Virtual WAN hubs; connected VNets attach to the hub node
+	for i, hub := range topology.VirtualHubs {
+		hubNodeID := fmt.Sprintf("vhub_%d", i)
+		dot.WriteString(fmt.Sprintf("  %s [label=\"%s\", fillcolor=\"#4682B4\", fontcolor=white, shape=doubleoctagon];\n",
+			hubNodeID, hubLabel(hub)))
+
+		internetViaHub := hubInternetNextHop(hub) != ""
+		for _, conn := range hub.VNetConnections {
+			toNode, exists := vnetNodes[strings.ToLower(conn.RemoteVNetID)]
+			if !exists {
+				toNode = fmt.Sprintf("remote_%s", sanitizeName(extractResourceName(conn.RemoteVNetID)))
+				dot.WriteString(fmt.Sprintf("  %s [label=\"%s\\n(External)\", fillcolor=\"#D3D3D3\", shape=box, style=\"filled,dashed\"];\n",
+					toNode, extractResourceName(conn.RemoteVNetID)))
+			}
+
+			label := "hub connection"
+			if internetViaHub && conn.EnableInternetSecurity {
+				label += "\\n0.0.0.0/0 via hub"
+			}
+			dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=\"#4682B4\", label=\"%s\", dir=both];\n",
+				hubNodeID, toNode, label))
+		}
+
+		// A secured hub sends the traffic covered by its routing intent through the firewall
+		for _, fw := range topology.AzureFirewalls {
+			fwNode, exists := firewallNodes[fw.ID]
+			if !exists || !strings.EqualFold(fw.ID, hub.AzureFirewallID) {
+				continue
+			}
+			label := "secured hub"
+			if destinations := hubIntentDestinations(hub); len(destinations) > 0 {
+				label = "routing intent\\n" + strings.Join(destinations, ", ")
+			}
+			dot.WriteString(fmt.Sprintf("  %s -> %s [style=bold, color=\"#FF6B6B\", penwidth=2.0, label=\"%s\"];\n",
+				hubNodeID, fwNode, label))
+		}
+	}
+
 	// Bottom section: Legend and Private Links Table (aligned horizontally)
 	dot.WriteString("\n  // Bottom section - Legend and Private Links Table (top-aligned)\n")
 	dot.WriteString("  {\n")
@@ -395,9 +590,14 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 	dot.WriteString("        <TR><TD BGCOLOR=\"#FFB6C1\">  </TD><TD ALIGN=\"LEFT\">Subnet (no NSG)</TD></TR>\n")
 	dot.WriteString("        <TR><TD BGCOLOR=\"#FFE4B5\">  </TD><TD ALIGN=\"LEFT\">NSG</TD></TR>\n")
 	dot.WriteString("        <TR><TD BGCOLOR=\"#DDA0DD\">  </TD><TD ALIGN=\"LEFT\">Route Table</TD></TR>\n")
-	dot.WriteString("        <TR><TD BGCOLOR=\"#9370DB\">  </TD><TD ALIGN=\"LEFT\">VPN Gateway</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#9370DB\">  </TD><TD ALIGN=\"LEFT\">VPN / ExpressRoute Gateway</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#8FBC8F\">  </TD><TD ALIGN=\"LEFT\">ExpressRoute Circuit</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#B0C4DE\">  </TD><TD ALIGN=\"LEFT\">On-premises Network</TD></TR>\n")
 	dot.WriteString("        <TR><TD BGCOLOR=\"#FFA500\">  </TD><TD ALIGN=\"LEFT\">Load Balancer</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#DA70D6\">  </TD><TD ALIGN=\"LEFT\">Private Link Service</TD></TR>\n")
 	dot.WriteString("        <TR><TD BGCOLOR=\"#FF6B6B\">  </TD><TD ALIGN=\"LEFT\">Azure Firewall</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#20B2AA\">  </TD><TD ALIGN=\"LEFT\">Azure Bastion</TD></TR>\n")
+	dot.WriteString("        <TR><TD BGCOLOR=\"#4682B4\">  </TD><TD ALIGN=\"LEFT\">Virtual WAN Hub</TD></TR>\n")
 	dot.WriteString("      </TABLE>\n")
 	dot.WriteString("    >];\n\n")
 
@@ -421,11 +621,18 @@ func GenerateDOTFileWithOptions(topology *models.NetworkTopology, opts Visualiza
 			if status == "" {
 				status = "N/A"
 			}
+			privateIPs := pe.PrivateIPAddress
+			if len(pe.PrivateIPAddresses) > 0 {
+				privateIPs = strings.Join(pe.PrivateIPAddresses, "<BR/>")
+			}
+			if privateIPs == "" {
+				privateIPs = "N/A"
+			}
 			dot.WriteString("        <TR>\n")
 			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", pe.Name))
 			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", targetName))
 			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", subnetName))
-			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", pe.PrivateIPAddress))
+			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", privateIPs))
 			dot.WriteString(fmt.Sprintf("          <TD ALIGN=\"LEFT\"><FONT POINT-SIZE=\"8\">%s</FONT></TD>\n", status))
 			dot.WriteString("        </TR>\n")
 		}
@@ -514,3 +721,275 @@ func extractResourceName(resourceID string) string {
 	}
 	return resourceID
 }
+
+// spansSubscriptions reports whether the topology's VNets come from more than one subscription
+func spansSubscriptions(topology *models.NetworkTopology) bool {
+	first := ""
+	for _, vnet := range topology.VirtualNetworks {
+		if vnet.SubscriptionID == "" {
+			continue
+		}
+		if first == "" {
+			first = strings.ToLower(vnet.SubscriptionID)
+		} else if strings.ToLower(vnet.SubscriptionID) != first {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetWorkloads maps lower-cased subnet IDs to the VMs (or bare NICs) with an IP
+// in the subnet. Private endpoint NICs are left out as they have their own table.
+func subnetWorkloads(topology *models.NetworkTopology) map[string][]string {
+	workloads := make(map[string][]string)
+	for _, nic := range topology.NetworkInterfaces {
+		if nic.PrivateEndpointID != "" {
+			continue
+		}
+		name := nic.Name
+		if nic.VirtualMachineID != "" {
+			name = extractResourceName(nic.VirtualMachineID)
+		}
+
+		subnetIDs := []string{nic.SubnetID}
+		for _, ipConfig := range nic.IPConfigurations {
+			subnetIDs = append(subnetIDs, ipConfig.SubnetID)
+		}
+
+		seen := make(map[string]bool)
+		for _, subnetID := range subnetIDs {
+			key := strings.ToLower(subnetID)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			workloads[key] = append(workloads[key], name)
+		}
+	}
+	return workloads
+}
+
+// nsgInternetPorts maps lower-cased NSG IDs to the destination ports their custom
+// inbound rules allow from the internet
+func nsgInternetPorts(topology *models.NetworkTopology) map[string][]string {
+	ports := make(map[string][]string)
+	for _, nsg := range topology.NSGs {
+		key := strings.ToLower(nsg.ID)
+		seen := make(map[string]bool)
+		for _, rule := range nsg.SecurityRules {
+			if rule.IsDefault || rule.Access != "Allow" || rule.Direction != "Inbound" || !fromInternet(rule) {
+				continue
+			}
+			for _, port := range rule.DestinationPorts() {
+				if !seen[port] {
+					seen[port] = true
+					ports[key] = append(ports[key], port)
+				}
+			}
+		}
+	}
+	return ports
+}
+
+// fromInternet reports whether any of the rule's source prefixes is the internet
+func fromInternet(rule models.SecurityRule) bool {
+	for _, prefix := range rule.SourcePrefixes() {
+		switch prefix {
+		case "*", "0.0.0.0/0", "Internet", "Any":
+			return true
+		}
+	}
+	return false
+}
+
+// bastionPeeredVNets returns the lower-cased IDs of the VNets directly peered with the
+// Bastion's VNet, which Bastion can reach. The peering may be collected on either side.
+func bastionPeeredVNets(topology *models.NetworkTopology, bastion models.BastionHost) []string {
+	bastionVNet := strings.ToLower(bastion.VNetID)
+	seen := make(map[string]bool)
+	var peered []string
+	add := func(id string) {
+		id = strings.ToLower(id)
+		if id != bastionVNet && !seen[id] {
+			seen[id] = true
+			peered = append(peered, id)
+		}
+	}
+
+	for _, vnet := range topology.VirtualNetworks {
+		for _, peering := range vnet.Peerings {
+			if !strings.EqualFold(peering.PeeringState, "Connected") {
+				continue
+			}
+			switch {
+			case strings.EqualFold(vnet.ID, bastion.VNetID):
+				add(peering.RemoteVNetID)
+			case strings.EqualFold(peering.RemoteVNetID, bastion.VNetID):
+				add(vnet.ID)
+			}
+		}
+	}
+	return peered
+}
+
+// summarizeNames lists up to limit names and counts the rest
+func summarizeNames(names []string, limit int) string {
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s +%d more", strings.Join(names[:limit], ", "), len(names)-limit)
+}
+
+// firewallPublicIPCount returns the number of public IPs of a firewall. Firewalls in
+// a Virtual WAN hub only report their addresses, not public IP resources.
+func firewallPublicIPCount(fw models.AzureFirewall) int {
+	if len(fw.PublicIPAddresses) > len(fw.PublicIPs) {
+		return len(fw.PublicIPAddresses)
+	}
+	return len(fw.PublicIPs)
+}
+
+// hubLabel builds the label of a Virtual WAN hub node: name, address prefix,
+// gateways and routing intent
+func hubLabel(hub models.VirtualHub) string {
+	label := fmt.Sprintf("Virtual Hub\\n%s\\n%s", hub.Name, hub.AddressPrefix)
+
+	var gateways []string
+	for _, gw := range hub.Gateways {
+		switch gw.Type {
+		case models.HubGatewayExpressRoute:
+			gateways = append(gateways, "ER GW")
+		case models.HubGatewayVPN:
+			gateways = append(gateways, "VPN GW")
+		}
+	}
+	if len(gateways) > 0 {
+		label += "\\n" + strings.Join(gateways, ", ")
+	}
+	if destinations := hubIntentDestinations(hub); len(destinations) > 0 {
+		label += "\\nRouting intent: " + strings.Join(destinations, ", ")
+	}
+	return label
+}
+
+// localNetworkLabel describes an on-premises site: the local network gateway, its
+// public endpoint and the address space behind it
+func localNetworkLabel(lng models.LocalNetworkGateway) string {
+	label := "On-premises\\n" + lng.Name
+	switch {
+	case lng.GatewayIPAddress != "":
+		label += "\\n" + lng.GatewayIPAddress
+	case lng.FQDN != "":
+		label += "\\n" + lng.FQDN
+	}
+	if len(lng.AddressPrefixes) > 0 {
+		label += "\\n" + summarizeNames(lng.AddressPrefixes, 3)
+	}
+	if lng.BGPSettings != nil && lng.BGPSettings.ASN != 0 {
+		label += fmt.Sprintf("\\nASN %d", lng.BGPSettings.ASN)
+	}
+	return label
+}
+
+// expressRouteLabel describes an ExpressRoute circuit: its provider, location,
+// bandwidth and SKU
+func expressRouteLabel(er models.ExpressRouteCircuit) string {
+	label := "ExpressRoute\\n" + er.Name
+	if er.ServiceProviderName != "" {
+		label += "\\n" + er.ServiceProviderName
+		if er.PeeringLocation != "" {
+			label += " @ " + er.PeeringLocation
+		}
+	}
+	if er.BandwidthInMbps > 0 {
+		label += fmt.Sprintf("\\n%d Mbps", er.BandwidthInMbps)
+	}
+	if er.SKUTier != "" {
+		label += "\\n" + er.SKUTier
+	}
+	return label
+}
+
+// expressRouteOnPremisesLabel describes the on-premises network at the far end of
+// a circuit, identified by the ASN it peers with
+func expressRouteOnPremisesLabel(er models.ExpressRouteCircuit) string {
+	label := "On-premises\\nvia " + er.Name
+	for _, p := range er.Peerings {
+		if p.PeerASN != 0 {
+			return label + fmt.Sprintf("\\nASN %d", p.PeerASN)
+		}
+	}
+	return label
+}
+
+// expressRoutePeeringLabel lists a circuit's peerings, e.g. "Private, Microsoft"
+func expressRoutePeeringLabel(er models.ExpressRouteCircuit) string {
+	peerings := make([]string, 0, len(er.Peerings))
+	for _, p := range er.Peerings {
+		name := strings.TrimSuffix(strings.TrimPrefix(p.PeeringType, "Azure"), "Peering")
+		if p.RouteFilter != nil || p.RouteFilterID != "" {
+			name += " (filtered)"
+		}
+		peerings = append(peerings, name)
+	}
+	if len(peerings) == 0 {
+		return "no peerings"
+	}
+	return strings.Join(peerings, ", ")
+}
+
+// privateLinkServiceLabel summarizes a private link service: its name, who can see it
+// and how many consumer connections are approved or waiting
+func privateLinkServiceLabel(pls models.PrivateLinkService) string {
+	label := "Private Link Service\\n" + pls.Name
+	for _, s := range pls.Visibility {
+		if s == "*" {
+			label += "\\nvisible to all"
+			break
+		}
+	}
+
+	approved, pending := 0, 0
+	for _, conn := range pls.Connections {
+		switch strings.ToLower(conn.Status) {
+		case "approved":
+			approved++
+		case "pending":
+			pending++
+		}
+	}
+	if approved > 0 || pending > 0 {
+		label += fmt.Sprintf("\\n%d approved, %d pending", approved, pending)
+	}
+	return label
+}
+
+// hubIntentDestinations lists the traffic types covered by the hub's routing intent
+func hubIntentDestinations(hub models.VirtualHub) []string {
+	var destinations []string
+	if hub.RoutingIntent.NextHop(models.RoutingIntentInternet) != "" {
+		destinations = append(destinations, "Internet")
+	}
+	if hub.RoutingIntent.NextHop(models.RoutingIntentPrivateTraffic) != "" {
+		destinations = append(destinations, "Private")
+	}
+	return destinations
+}
+
+// hubInternetNextHop returns where the hub sends internet traffic: the routing
+// intent next hop, or else the next hop of a 0.0.0.0/0 route in a hub route table
+func hubInternetNextHop(hub models.VirtualHub) string {
+	if nextHop := hub.RoutingIntent.NextHop(models.RoutingIntentInternet); nextHop != "" {
+		return nextHop
+	}
+	for _, rt := range hub.RouteTables {
+		for _, route := range rt.Routes {
+			for _, destination := range route.Destinations {
+				if destination == "0.0.0.0/0" && route.NextHop != "" {
+					return route.NextHop
+				}
+			}
+		}
+	}
+	return ""
+}
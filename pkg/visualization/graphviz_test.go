@@ -258,3 +258,351 @@ func TestFirewallEdgeWithMatchingIP(t *testing.T) {
 		t.Error("DOT file should contain edge from route table to firewall")
 	}
 }
+
+// TestCrossSubscriptionPeering tests that peerings to VNets in other subscriptions
+// resolve to the collected VNet rather than an external placeholder
+func TestCrossSubscriptionPeering(t *testing.T) {
+	hubID := "/subscriptions/sub-conn/resourceGroups/rg-hub/providers/Microsoft.Network/virtualNetworks/vnet-hub"
+	spokeID := "/subscriptions/sub-app/resourceGroups/rg-app/providers/Microsoft.Network/virtualNetworks/vnet-hub"
+
+	topology := &models.NetworkTopology{
+		SubscriptionID: "sub-conn, sub-app",
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:             hubID,
+				Name:           "vnet-hub",
+				SubscriptionID: "sub-conn",
+				AddressSpace:   []string{"10.0.0.0/16"},
+				Peerings: []models.VNetPeering{
+					{
+						Name: "hub-to-app",
+						// ARM does not guarantee the casing of the remote ID
+						RemoteVNetID:   strings.ToLower(spokeID),
+						RemoteVNetName: "vnet-hub",
+						PeeringState:   "Connected",
+					},
+				},
+			},
+			{
+				ID:             spokeID,
+				Name:           "vnet-hub",
+				SubscriptionID: "sub-app",
+				AddressSpace:   []string{"10.1.0.0/16"},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if strings.Contains(dot, "(External)") {
+		t.Error("Peering to a collected VNet in another subscription should not be drawn as external")
+	}
+	if !strings.Contains(dot, "vnet_0 -> vnet_1") {
+		t.Error("Expected peering edge between the two VNets")
+	}
+	if !strings.Contains(dot, "cluster_0_vnet_hub") || !strings.Contains(dot, "cluster_1_vnet_hub") {
+		t.Error("VNets with the same name should get separate clusters")
+	}
+	if !strings.Contains(dot, "Subscription: sub-app") {
+		t.Error("VNet labels should include the subscription when the topology spans subscriptions")
+	}
+}
+
+func TestNSGLabelShowsInternetPorts(t *testing.T) {
+	nsgID := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/nsg-web"
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:   "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet1",
+				Name: "vnet1",
+				Subnets: []models.Subnet{
+					{
+						ID:                   "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/web",
+						Name:                 "web",
+						AddressPrefix:        "10.0.1.0/24",
+						NetworkSecurityGroup: &nsgID,
+					},
+				},
+			},
+		},
+		NSGs: []models.NetworkSecurityGroup{
+			{
+				ID:   nsgID,
+				Name: "nsg-web",
+				SecurityRules: []models.SecurityRule{
+					{Name: "AllowWeb", Direction: "Inbound", Access: "Allow", SourceAddressPrefixes: []string{"10.1.0.0/16", "Internet"}, DestinationPortRanges: []string{"80", "443"}},
+					{Name: "AllowSpokes", Direction: "Inbound", Access: "Allow", SourceAddressPrefix: "10.2.0.0/16", DestinationPortRange: "8443"},
+					{Name: "DenySSH", Direction: "Inbound", Access: "Deny", SourceAddressPrefix: "*", DestinationPortRange: "22"},
+					{Name: "AllowInternetOutBound", Direction: "Outbound", Access: "Allow", SourceAddressPrefix: "*", DestinationPortRange: "*", IsDefault: true},
+				},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if !strings.Contains(dot, `label="NSG\nnsg-web\nInternet: 80, 443"`) {
+		t.Errorf("NSG label should list the ports open to the internet, got:\n%s", dot)
+	}
+}
+
+func TestBastionReachesPeeredVNets(t *testing.T) {
+	prefix := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/"
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:   prefix + "vnet-hub",
+				Name: "vnet-hub",
+				Subnets: []models.Subnet{
+					{ID: prefix + "vnet-hub/subnets/AzureBastionSubnet", Name: "AzureBastionSubnet", AddressPrefix: "10.0.254.0/26"},
+				},
+				Peerings: []models.VNetPeering{
+					{RemoteVNetID: prefix + "vnet-spoke1", PeeringState: "Connected"},
+					{RemoteVNetID: prefix + "vnet-spoke2", PeeringState: "Disconnected"},
+				},
+			},
+			{ID: prefix + "vnet-spoke1", Name: "vnet-spoke1"},
+			{ID: prefix + "vnet-spoke2", Name: "vnet-spoke2"},
+			{
+				// Peering only collected on the spoke side
+				ID:       prefix + "vnet-spoke3",
+				Name:     "vnet-spoke3",
+				Peerings: []models.VNetPeering{{RemoteVNetID: prefix + "vnet-hub", PeeringState: "Connected"}},
+			},
+		},
+		BastionHosts: []models.BastionHost{
+			{Name: "bas-hub", SKU: "Standard", SubnetID: prefix + "vnet-hub/subnets/AzureBastionSubnet", VNetID: prefix + "vnet-hub"},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if !strings.Contains(dot, `bastion_0 [label="Bastion\nbas-hub\nStandard"`) {
+		t.Error("DOT should contain the Bastion node")
+	}
+	if !strings.Contains(dot, `bastion_0 -> subnet_0_0 [style=bold`) {
+		t.Error("Bastion should be connected to its subnet")
+	}
+	for _, expected := range []string{"bastion_0 -> vnet_1 [style=dashed", "bastion_0 -> vnet_3 [style=dashed"} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("DOT should contain %q", expected)
+		}
+	}
+	if strings.Contains(dot, "bastion_0 -> vnet_2") {
+		t.Error("Bastion should not reach a VNet over a disconnected peering")
+	}
+}
+
+func TestVirtualHubConnectsVNetsAndFirewall(t *testing.T) {
+	prefix := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/"
+	hubID := prefix + "virtualHubs/vhub-eastus"
+	fwID := prefix + "azureFirewalls/fw-vhub"
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:   prefix + "virtualNetworks/vnet-spoke",
+				Name: "vnet-spoke",
+				// Created by the hub connection; drawn as the connection instead
+				Peerings: []models.VNetPeering{
+					{RemoteVNetName: "HV_vhub-eastus_1a2b", PeeringState: "Connected", RemoteVirtualHubID: hubID},
+				},
+			},
+		},
+		AzureFirewalls: []models.AzureFirewall{
+			{ID: fwID, Name: "fw-vhub", SKU: "Standard", PrivateIPAddress: "10.100.64.4", PublicIPs: []string{"20.84.1.10"}, VirtualHubID: hubID},
+		},
+		VirtualHubs: []models.VirtualHub{
+			{
+				ID:              hubID,
+				Name:            "vhub-eastus",
+				AddressPrefix:   "10.100.0.0/23",
+				AzureFirewallID: fwID,
+				VNetConnections: []models.HubVNetConnection{
+					{Name: "conn-spoke", RemoteVNetID: prefix + "virtualNetworks/vnet-spoke", EnableInternetSecurity: true},
+					{Name: "conn-partner", RemoteVNetID: "/subscriptions/test/resourceGroups/rg-partner/providers/Microsoft.Network/virtualNetworks/vnet-partner"},
+				},
+				RoutingIntent: &models.HubRoutingIntent{
+					RoutingPolicies: []models.HubRoutingPolicy{
+						{Name: "InternetTraffic", Destinations: []string{"Internet"}, NextHop: fwID},
+					},
+				},
+				Gateways: []models.HubGateway{{Name: "vpngw", Type: models.HubGatewayVPN}},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	expected := []string{
+		`vhub_0 [label="Virtual Hub\nvhub-eastus\n10.100.0.0/23\nVPN GW\nRouting intent: Internet"`,
+		`vhub_0 -> vnet_0 [style=bold, color="#4682B4", label="hub connection\n0.0.0.0/0 via hub"`,
+		`vhub_0 -> remote_vnet_partner [style=bold, color="#4682B4", label="hub connection"`,
+		`vhub_0 -> fw_0 [style=bold, color="#FF6B6B", penwidth=2.0, label="routing intent\nInternet"]`,
+		`fw_0 -> internet [style=bold, color="#4169E1", penwidth=2.0, label="Public IP egress\n20.84.1.10"]`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("DOT should contain %q", e)
+		}
+	}
+	if strings.Contains(dot, "HV_vhub-eastus") {
+		t.Error("Peering with the hub's managed VNet should not be drawn")
+	}
+}
+
+func TestPrivateLinkServiceLinksToLoadBalancer(t *testing.T) {
+	prefix := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/"
+	subnetID := prefix + "virtualNetworks/vnet-app/subnets/subnet-app"
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:      prefix + "virtualNetworks/vnet-app",
+				Name:    "vnet-app",
+				Subnets: []models.Subnet{{ID: subnetID, Name: "subnet-app", AddressPrefix: "10.1.1.0/24"}},
+			},
+		},
+		LoadBalancers: []models.LoadBalancer{
+			{ID: prefix + "loadBalancers/lb-web", Name: "lb-web", SKU: "Standard"},
+			{ID: prefix + "loadBalancers/lb-app-internal", Name: "lb-app-internal", SKU: "Standard"},
+		},
+		PrivateLinkServices: []models.PrivateLinkService{
+			{
+				Name:                    "pls-app",
+				LoadBalancerID:          prefix + "loadBalancers/LB-APP-INTERNAL",
+				LoadBalancerFrontendIDs: []string{prefix + "loadBalancers/lb-app-internal/frontendIPConfigurations/frontend-app"},
+				IPConfigurations: []models.PrivateLinkServiceIPConfig{
+					{Name: "nat1", PrivateIPAddress: "10.1.1.20", SubnetID: subnetID},
+					{Name: "nat2", PrivateIPAddress: "10.1.1.21", SubnetID: subnetID},
+				},
+				Visibility: []string{"*"},
+				Connections: []models.PrivateLinkServiceConnection{
+					{Name: "pe1", Status: "Approved"},
+					{Name: "pe2", Status: "Pending"},
+				},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	expected := []string{
+		`pls_0 [label="Private Link Service\npls-app\nvisible to all\n1 approved, 1 pending", fillcolor="#DA70D6", shape=component]`,
+		`pls_0 -> lb_1 [style=bold, color="#DA70D6", label="frontend\nfrontend-app"]`,
+		`pls_0 -> subnet_0_0 [style=dashed, color="#DA70D6", label="NAT IPs"]`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("DOT should contain %q", e)
+		}
+	}
+	if n := strings.Count(dot, `label="NAT IPs"`); n != 1 {
+		t.Errorf("Expected one NAT IPs edge per subnet, got %d", n)
+	}
+}
+
+func TestLocalNetworkGatewayDrawnAsOnPremisesCloud(t *testing.T) {
+	prefix := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/"
+	lngID := prefix + "localNetworkGateways/lng-onprem"
+	topology := &models.NetworkTopology{
+		VPNGateways: []models.VPNGateway{
+			{
+				Name: "vpn-gateway",
+				SKU:  "VpnGw2",
+				Connections: []models.VPNConnection{
+					{Name: "vpn-to-onprem", ConnectionStatus: "Connected", RemoteEntityID: strings.ToUpper(lngID)},
+					{Name: "vpn-to-other", ConnectionStatus: "Connected", RemoteEntityID: prefix + "localNetworkGateways/lng-other"},
+				},
+			},
+		},
+		LocalNetworkGateways: []models.LocalNetworkGateway{
+			{
+				ID:               lngID,
+				Name:             "lng-onprem",
+				GatewayIPAddress: "52.168.1.100",
+				AddressPrefixes:  []string{"192.168.0.0/16", "10.1.128.0/20"},
+				BGPSettings:      &models.BGPSettings{ASN: 65010},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	expected := []string{
+		`onprem_0 [label="On-premises\nlng-onprem\n52.168.1.100\n192.168.0.0/16, 10.1.128.0/20\nASN 65010", fillcolor="#B0C4DE", shape=cloud]`,
+		`vpn_0 -> onprem_0 [style=bold, color=purple, label="vpn-to-onprem\nConnected", dir=both]`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("DOT should contain %q", e)
+		}
+	}
+	if strings.Contains(dot, "vpn-to-other") {
+		t.Error("Connection to a local network gateway that was not collected should not be drawn")
+	}
+}
+
+func TestExpressRouteCircuitDrawnBetweenOnPremisesAndGateway(t *testing.T) {
+	prefix := "/subscriptions/test/resourceGroups/rg/providers/Microsoft.Network/"
+	dcID := prefix + "expressRouteCircuits/er-dc"
+	londonID := prefix + "expressRouteCircuits/er-london"
+	topology := &models.NetworkTopology{
+		VPNGateways: []models.VPNGateway{
+			{
+				Name:        "ergw-hub",
+				GatewayType: "ExpressRoute",
+				SKU:         "UltraPerformance",
+				Connections: []models.VPNConnection{
+					{Name: "er-to-dc", ConnectionStatus: "Connected", CircuitID: strings.ToUpper(dcID), FastPath: true},
+					{Name: "er-to-other", ConnectionStatus: "Connected", CircuitID: prefix + "expressRouteCircuits/er-other"},
+				},
+			},
+		},
+		ERCircuits: []models.ExpressRouteCircuit{
+			{
+				ID:                  dcID,
+				Name:                "er-dc",
+				ServiceProviderName: "Equinix",
+				PeeringLocation:     "Washington DC",
+				BandwidthInMbps:     1000,
+				Peerings: []models.ERPeering{
+					{
+						PeeringType:            "AzurePrivatePeering",
+						PeerASN:                65020,
+						GlobalReachConnections: []models.ERGlobalReachConnection{{Name: "gr", PeerCircuitID: londonID, Status: "Connected"}},
+					},
+					{PeeringType: "MicrosoftPeering", RouteFilterID: prefix + "routeFilters/rf-m365"},
+				},
+			},
+			{
+				ID:   londonID,
+				Name: "er-london",
+				Peerings: []models.ERPeering{
+					{
+						PeeringType:            "AzurePrivatePeering",
+						GlobalReachConnections: []models.ERGlobalReachConnection{{Name: "gr", PeerCircuitID: dcID, Status: "Connected"}},
+					},
+				},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	expected := []string{
+		`er_0 [label="ExpressRoute\ner-dc\nEquinix @ Washington DC\n1000 Mbps", fillcolor="#8FBC8F", shape=hexagon]`,
+		`er_onprem_0 [label="On-premises\nvia er-dc\nASN 65020", fillcolor="#B0C4DE", shape=cloud]`,
+		`er_onprem_0 -> er_0 [style=bold, color="#2E8B57", label="Private, Microsoft (filtered)", dir=both]`,
+		`er_0 -> vpn_0 [style=bold, color="#2E8B57", label="er-to-dc\nConnected\nFastPath", dir=both]`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("DOT should contain %q", e)
+		}
+	}
+	if n := strings.Count(dot, `label="Global Reach"`); n != 1 {
+		t.Errorf("Global Reach between two circuits should be drawn once, got %d", n)
+	}
+	if strings.Contains(dot, "er-to-other") {
+		t.Error("Connection to a circuit that was not collected should not be drawn")
+	}
+}
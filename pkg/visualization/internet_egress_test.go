@@ -78,6 +78,28 @@ func TestFirewallWithPublicIPEgress(t *testing.T) {
 	}
 }
 
+func TestFirewallEgressShowsResolvedIPs(t *testing.T) {
+	topology := &models.NetworkTopology{
+		AzureFirewalls: []models.AzureFirewall{
+			{
+				ID:               "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/azureFirewalls/fw1",
+				Name:             "fw1",
+				PrivateIPAddress: "10.0.1.4",
+				PublicIPAddresses: []string{
+					"/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/publicIPAddresses/fw-pip",
+				},
+				PublicIPs: []string{"20.0.0.7"},
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if !strings.Contains(dot, "Public IP egress\\n20.0.0.7") {
+		t.Error("Firewall egress edge should show the resolved public IP")
+	}
+}
+
 // TestFirewallWithoutPublicIP tests that firewalls without public IPs don't show Internet egress
 func TestFirewallWithoutPublicIP(t *testing.T) {
 	topology := &models.NetworkTopology{
@@ -198,3 +198,63 @@ func TestVNetLabels(t *testing.T) {
 
 	t.Logf("✓ VNet labels show actual names with proper formatting")
 }
+
+func TestPrivateEndpointsTableMultipleIPs(t *testing.T) {
+	topology := &models.NetworkTopology{
+		PrivateEndpoints: []models.PrivateEndpoint{
+			{
+				ID:                 "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/privateEndpoints/pe-cosmos",
+				Name:               "pe-cosmos",
+				PrivateIPAddress:   "10.0.1.20",
+				PrivateIPAddresses: []string{"10.0.1.20", "10.0.1.21"},
+			},
+			{
+				ID:   "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/privateEndpoints/pe-unresolved",
+				Name: "pe-unresolved",
+			},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if !strings.Contains(dot, "10.0.1.20<BR/>10.0.1.21") {
+		t.Error("Every private IP of an endpoint should be listed")
+	}
+	if !strings.Contains(dot, ">N/A<") {
+		t.Error("Endpoints without a private IP should show N/A")
+	}
+}
+
+func TestSubnetWorkloadLabels(t *testing.T) {
+	subnetID := "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/vnet-prod/subnets/subnet-app"
+	vmPrefix := "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Compute/virtualMachines/"
+
+	topology := &models.NetworkTopology{
+		VirtualNetworks: []models.VirtualNetwork{
+			{
+				ID:           "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/vnet-prod",
+				Name:         "vnet-prod",
+				AddressSpace: []string{"10.0.0.0/16"},
+				Subnets: []models.Subnet{
+					{ID: subnetID, Name: "subnet-app", AddressPrefix: "10.0.1.0/24"},
+				},
+			},
+		},
+		NetworkInterfaces: []models.NetworkInterface{
+			{Name: "nic-app-1", SubnetID: subnetID, VirtualMachineID: vmPrefix + "vm-app-01"},
+			{Name: "nic-app-2", SubnetID: subnetID, VirtualMachineID: vmPrefix + "vm-app-02"},
+			{Name: "nic-app-3", SubnetID: subnetID, VirtualMachineID: vmPrefix + "vm-app-03"},
+			{Name: "nic-app-4", SubnetID: subnetID, VirtualMachineID: vmPrefix + "vm-app-04"},
+			{Name: "pe-sql.nic", SubnetID: subnetID, PrivateEndpointID: "pe-sql"},
+		},
+	}
+
+	dot := GenerateDOTFile(topology)
+
+	if !strings.Contains(dot, "vm-app-01, vm-app-02, vm-app-03 +1 more") {
+		t.Error("Subnet label should summarize the attached VMs")
+	}
+	if strings.Contains(dot, "pe-sql.nic") {
+		t.Error("Private endpoint NICs should not be listed as subnet workloads")
+	}
+}
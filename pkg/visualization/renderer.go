@@ -30,6 +30,7 @@ func CheckTopologySize(topology *models.NetworkTopology) TopologySizeWarning {
 	nodes += len(topology.NSGs)
 	nodes += len(topology.RouteTables)
 	nodes += len(topology.LoadBalancers)
+	nodes += len(topology.PrivateLinkServices)
 	nodes += len(topology.AppGateways)
 
 	// Estimate edges (connections between nodes)